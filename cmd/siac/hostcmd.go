@@ -232,6 +232,7 @@ RPC Stats:
 	Download Calls:     %v
 	Renew Calls:        %v
 	Revise Calls:       %v
+	SectorRoots Calls:  %v
 	Settings Calls:     %v
 	FormContract Calls: %v
 `,
@@ -268,7 +269,7 @@ RPC Stats:
 			currencyUnits(fm.PotentialUploadBandwidthRevenue),
 
 			nm.ErrorCalls, nm.UnrecognizedCalls, nm.DownloadCalls,
-			nm.RenewCalls, nm.ReviseCalls, nm.SettingsCalls,
+			nm.RenewCalls, nm.ReviseCalls, nm.SectorRootsCalls, nm.SettingsCalls,
 			nm.FormContractCalls)
 	} else {
 		fmt.Printf(`Host info:
@@ -567,6 +567,7 @@ Contract %v
   Upload Spending:   %v
   Storage Spending:  %v
   Download Spending: %v
+  Renter Funds:      %v
   Remaining Funds:   %v
 
   File Size: %v
@@ -578,6 +579,7 @@ Contract %v
 				currencyUnits(rc.StorageSpending),
 				currencyUnits(rc.DownloadSpending),
 				currencyUnits(rc.RenterFunds),
+				currencyUnits(rc.RemainingFunds),
 				filesizeUnits(int64(rc.Size)))
 
 			printScoreBreakdown(&hostInfo)
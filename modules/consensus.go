@@ -2,6 +2,8 @@ package modules
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"gitlab.com/NebulousLabs/Sia/crypto"
 	"gitlab.com/NebulousLabs/Sia/types"
@@ -175,6 +177,38 @@ type (
 		Adjusted  types.Currency
 	}
 
+	// ConsensusStats contains the number of entries in the consensus database
+	// and the size of the database on disk. Blocks includes blocks that are
+	// not on the current path, e.g. blocks of abandoned forks.
+	ConsensusStats struct {
+		Blocks         uint64 `json:"blocks"`
+		SiacoinOutputs uint64 `json:"siacoinoutputs"`
+		SiafundOutputs uint64 `json:"siafundoutputs"`
+		FileContracts  uint64 `json:"filecontracts"`
+		DatabaseSize   uint64 `json:"databasesize"`
+	}
+
+	// A BlockTiming records when the consensus set received a block that
+	// extended its current chain. Delay is the time between the block's
+	// timestamp and its receipt. It can be negative, since timestamps are
+	// chosen by miners and may be slightly in the future.
+	BlockTiming struct {
+		ID        types.BlockID     `json:"id"`
+		Height    types.BlockHeight `json:"height"`
+		Timestamp types.Timestamp   `json:"timestamp"`
+		Received  time.Time         `json:"received"`
+		Delay     time.Duration     `json:"delay"`
+	}
+
+	// A TransactionSetError is returned by ValidTransactionSet if a
+	// transaction of the set is invalid. Index is the position of the
+	// transaction in the set, and Err is the reason it is invalid.
+	TransactionSetError struct {
+		Index int
+		ID    types.TransactionID
+		Err   error
+	}
+
 	// A ConsensusSet accepts blocks and builds an understanding of network
 	// consensus.
 	ConsensusSet interface {
@@ -186,6 +220,14 @@ type (
 		// still be returned.
 		AcceptBlock(types.Block) error
 
+		// BlockTimings returns the receipt times of the most recent blocks
+		// that extended the current chain, starting with the oldest.
+		BlockTimings() []BlockTiming
+
+		// CheckBlock returns an error if the block would not be accepted as
+		// the new current block. The consensus set is not modified.
+		CheckBlock(types.Block) error
+
 		// BlockAtHeight returns the block found at the input height, with a
 		// bool to indicate whether that block exists.
 		BlockAtHeight(types.BlockHeight) (types.Block, bool)
@@ -203,6 +245,10 @@ type (
 		// run any required closing routines.
 		Close() error
 
+		// ConsensusChangeSince returns a single consensus change that
+		// brings a subscriber from the provided change to the current block.
+		ConsensusChangeSince(ConsensusChangeID) (ConsensusChange, error)
+
 		// ConsensusSetSubscribe adds a subscriber to the list of subscribers
 		// and gives them every consensus change that has occurred since the
 		// change with the provided id. There are a few special cases,
@@ -214,6 +260,13 @@ type (
 		// blockchain.
 		CurrentBlock() types.Block
 
+		// SubscribeBlocks returns a channel that receives every block that
+		// is applied to the consensus set from now on, and a function that
+		// ends the subscription. Blocks are dropped if the subscriber falls
+		// too far behind. The channel is closed when the subscription ends
+		// or the consensus set shuts down.
+		SubscribeBlocks() (<-chan types.Block, func())
+
 		// Flush will cause the consensus set to finish all in-progress
 		// routines.
 		Flush() error
@@ -221,29 +274,96 @@ type (
 		// Height returns the current height of consensus.
 		Height() types.BlockHeight
 
+		// PeerHeight returns the highest block height that the connected
+		// peers have sent or announced, or the current height if it is
+		// higher.
+		PeerHeight() types.BlockHeight
+
+		// CumulativeWork returns the total difficulty of all blocks in the
+		// current fork.
+		CumulativeWork() types.Currency
+
 		// Synced returns true if the consensus set is synced with the network.
 		Synced() bool
 
+		// TipRecent returns true if the current block is no more than six
+		// block intervals old, indicating that new blocks are still arriving.
+		TipRecent() bool
+
 		// InCurrentPath returns true if the block id presented is found in the
 		// current path, false otherwise.
 		InCurrentPath(types.BlockID) bool
 
+		// ForEachFileContract calls fn for every open file contract in the
+		// consensus set, stopping early if fn returns an error.
+		ForEachFileContract(fn func(types.FileContractID, types.FileContract) error) error
+
+		// ForEachSiafundOutput calls fn for every unspent siafund output in
+		// the consensus set, in order of their ids, stopping early if fn
+		// returns an error.
+		ForEachSiafundOutput(fn func(types.SiafundOutputID, types.SiafundOutput) error) error
+
+		// SiafundClaimValue returns the siacoins that spending the siafund
+		// output with the given id would currently claim. False is returned
+		// if the output does not exist.
+		SiafundClaimValue(types.SiafundOutputID) (types.Currency, bool)
+
+		// Stats returns the number of blocks, unspent outputs and file
+		// contracts in the consensus database, along with its size on disk.
+		Stats() (ConsensusStats, error)
+
 		// MinimumValidChildTimestamp returns the earliest timestamp that is
 		// valid on the current longest fork according to the consensus set. This is
 		// a required piece of information for the miner, who could otherwise be at
 		// risk of mining invalid blocks.
 		MinimumValidChildTimestamp(types.BlockID) (types.Timestamp, bool)
 
+		// SiacoinOutput returns the unspent siacoin output with the given id.
+		SiacoinOutput(types.SiacoinOutputID) (types.SiacoinOutput, error)
+
+		// OutputSpendable returns whether the unspent siacoin output with
+		// the given id can be spent at the current height with the provided
+		// unlock conditions, i.e. whether the output exists and the timelock
+		// of the unlock conditions has passed. An error is returned if the
+		// unlock conditions don't belong to the output.
+		OutputSpendable(types.SiacoinOutputID, types.UnlockConditions) (bool, error)
+
+		// SiacoinOutputBlock returns the id and height of the block in the
+		// current path that created the siacoin output with the given id.
+		SiacoinOutputBlock(types.SiacoinOutputID) (types.BlockID, types.BlockHeight, bool)
+
 		// StorageProofSegment returns the segment to be used in the storage proof for
 		// a given file contract.
 		StorageProofSegment(types.FileContractID) (uint64, error)
 
+		// MedianTimestampWindow returns the timestamps of the current block
+		// and its parents that are used to compute the earliest valid
+		// timestamp of the next block, along with their median.
+		MedianTimestampWindow() (window []types.Timestamp, median types.Timestamp)
+
+		// TimestampBounds returns the earliest and the latest timestamp that a
+		// child of the current block can have in order to be accepted right
+		// away.
+		TimestampBounds() (earliest, latest types.Timestamp)
+
+		// TransactionEffect returns the siacoin output diffs that the
+		// transaction would produce if it were added in the next block. An
+		// error is returned if the transaction is not valid in the current
+		// consensus set.
+		TransactionEffect(types.Transaction) ([]SiacoinOutputDiff, error)
+
 		// TryTransactionSet checks whether the transaction set would be valid if
 		// it were added in the next block. A consensus change is returned
 		// detailing the diffs that would result from the application of the
 		// transaction.
 		TryTransactionSet([]types.Transaction) (ConsensusChange, error)
 
+		// ValidTransactionSet checks whether the transaction set would be
+		// valid if it were added in the next block. Transactions may spend
+		// the outputs of earlier transactions in the set. If a transaction is
+		// invalid, a TransactionSetError identifying it is returned.
+		ValidTransactionSet([]types.Transaction) error
+
 		// Unsubscribe removes a subscriber from the list of subscribers,
 		// allowing for garbage collection and rescanning. If the subscriber is
 		// not found in the subscriber database, no action is taken.
@@ -251,6 +371,11 @@ type (
 	}
 )
 
+// Error implements the error interface.
+func (tse TransactionSetError) Error() string {
+	return fmt.Sprintf("transaction %v (%v) of the set is invalid: %v", tse.Index, tse.ID, tse.Err)
+}
+
 // Append takes to ConsensusChange objects and adds all of their diffs together.
 //
 // NOTE: It is possible for diffs to overlap or be inconsistent. This function
@@ -219,6 +219,9 @@ func (cs *ConsensusSet) threadedSleepOnFutureBlock(b types.Block) {
 // consecutive calls to AcceptBlock with each successive call accepting the
 // child block of the previous call.
 func (cs *ConsensusSet) managedAcceptBlocks(blocks []types.Block) (blockchainExtended bool, err error) {
+	// Note the receipt time before waiting for the lock.
+	received := time.Now()
+
 	// Grab a lock on the consensus set.
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
@@ -240,6 +243,7 @@ func (cs *ConsensusSet) managedAcceptBlocks(blocks []types.Block) (blockchainExt
 	// invalid blocks (which includes the children of invalid blocks).
 	chainExtended := false
 	changes := make([]changeEntry, 0, len(blocks))
+	var timings []modules.BlockTiming
 	setErr := cs.db.Update(func(tx *bolt.Tx) error {
 		for i := 0; i < len(blocks); i++ {
 			// Start by checking the header of the block.
@@ -261,6 +265,7 @@ func (cs *ConsensusSet) managedAcceptBlocks(blocks []types.Block) (blockchainExt
 			if err == nil {
 				changes = append(changes, changeEntry)
 				chainExtended = true
+				timings = append(timings, newBlockTiming(blocks[i], blockIDs[i], parent.Height+1, received))
 				var applied, reverted []string
 				for _, b := range changeEntry.AppliedBlocks {
 					applied = append(applied, b.String()[:6])
@@ -303,6 +308,7 @@ func (cs *ConsensusSet) managedAcceptBlocks(blocks []types.Block) (blockchainExt
 	if !chainExtended {
 		return false, modules.ErrNonExtendingBlock
 	}
+	cs.recordBlockTimings(timings)
 	// Send any changes to subscribers.
 	for i := 0; i < len(changes); i++ {
 		cs.updateSubscribers(changes[i])
@@ -333,3 +339,50 @@ func (cs *ConsensusSet) AcceptBlock(b types.Block) error {
 	}
 	return nil
 }
+
+// CheckBlock checks whether b would be accepted as the new current block
+// without adding it to the consensus set. It performs the same validation as
+// AcceptBlock, including the validation of the block's transactions, so
+// miners can catch invalid blocks before broadcasting them. Only blocks that
+// extend the current block can be checked.
+func (cs *ConsensusSet) CheckBlock(b types.Block) error {
+	err := cs.tg.Add()
+	if err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	// The transactions are applied to the database to validate them, since a
+	// transaction may depend on outputs created earlier in the block.
+	// Returning errSuccess rolls the changes back, just like
+	// tryTransactionSet does.
+	errSuccess := errors.New("success")
+	err = cs.db.Update(func(tx *bolt.Tx) error {
+		parent, err := cs.validateHeaderAndBlock(boltTxWrapper{tx}, b, b.ID())
+		if err != nil {
+			return err
+		}
+		if parent.Block.ID() != currentBlockID(tx) {
+			return modules.ErrNonExtendingBlock
+		}
+
+		diffHolder := &processedBlock{
+			Block:  b,
+			Height: parent.Height + 1,
+		}
+		createDSCOBucket(tx, diffHolder.Height+types.MaturityDelay)
+		for _, txn := range b.Transactions {
+			if err := validTransaction(tx, txn); err != nil {
+				return err
+			}
+			applyTransaction(tx, diffHolder, txn)
+		}
+		return errSuccess
+	})
+	if err != errSuccess {
+		return err
+	}
+	return nil
+}
@@ -538,6 +538,64 @@ func TestIntegrationDoSBlockHandling(t *testing.T) {
 	}
 }
 
+// TestCheckBlock probes the CheckBlock method of the consensus set.
+func TestCheckBlock(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	// A valid block should pass the check without being added.
+	height := cst.cs.Height()
+	b, _ := cst.miner.FindBlock()
+	if err := cst.cs.CheckBlock(b); err != nil {
+		t.Fatal(err)
+	}
+	if cst.cs.Height() != height {
+		t.Fatal("CheckBlock changed the height of the consensus set")
+	}
+
+	// A block with an invalid transaction should fail the check.
+	txnBuilder, err := cst.wallet.StartTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = txnBuilder.FundSiacoins(types.NewCurrency64(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txnSet, err := txnBuilder.Sign(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, target, err := cst.miner.BlockForWork()
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Transactions = append(block.Transactions, txnSet...)
+	badBlock, _ := cst.miner.SolveBlock(block, target)
+	if err := cst.cs.CheckBlock(badBlock); err != errSiacoinInputOutputMismatch {
+		t.Fatalf("expected %v, got %v", errSiacoinInputOutputMismatch, err)
+	}
+	// The failed check must not mark the block as a DoS block.
+	if _, exists := cst.cs.dosBlocks[badBlock.ID()]; exists {
+		t.Fatal("CheckBlock marked the block as a DoS block")
+	}
+
+	// The valid block can still be accepted, after which it is known.
+	if err := cst.cs.AcceptBlock(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := cst.cs.CheckBlock(b); err != modules.ErrBlockKnown {
+		t.Fatalf("expected %v, got %v", modules.ErrBlockKnown, err)
+	}
+}
+
 // TestBlockKnownHandling submits known blocks to the consensus set.
 func TestBlockKnownHandling(t *testing.T) {
 	if testing.Short() {
@@ -23,7 +23,17 @@ type stdBlockRuleHelper struct{}
 // To boost performance, minimumValidChildTimestamp is passed a bucket that it
 // can use from inside of a boltdb transaction.
 func (rh stdBlockRuleHelper) minimumValidChildTimestamp(blockMap dbBucket, pb *processedBlock) types.Timestamp {
-	// Get the previous MedianTimestampWindow timestamps.
+	windowTimes := timestampWindow(blockMap, pb)
+	sort.Sort(windowTimes)
+
+	// Return the median of the sorted timestamps.
+	return windowTimes[len(windowTimes)/2]
+}
+
+// timestampWindow returns the timestamps of pb and its MedianTimestampWindow-1
+// parents, starting with pb. If the window reaches past the genesis block, the
+// genesis timestamp is repeated.
+func timestampWindow(blockMap dbBucket, pb *processedBlock) types.TimestampSlice {
 	windowTimes := make(types.TimestampSlice, types.MedianTimestampWindow)
 	windowTimes[0] = pb.Block.Timestamp
 	parent := pb.Block.ParentID
@@ -44,8 +54,5 @@ func (rh stdBlockRuleHelper) minimumValidChildTimestamp(blockMap dbBucket, pb *p
 		copy(parent[:], parentBytes[:32])
 		windowTimes[i] = types.Timestamp(encoding.DecUint64(parentBytes[40:48]))
 	}
-	sort.Sort(windowTimes)
-
-	// Return the median of the sorted timestamps.
-	return windowTimes[len(windowTimes)/2]
+	return windowTimes
 }
@@ -0,0 +1,113 @@
+package consensus
+
+import (
+	"sync"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/persist"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// maxQueuedBlocks is the maximum number of blocks that are queued for a block
+// subscriber that doesn't keep up with the consensus set. Further blocks are
+// dropped until the subscriber catches up, so that a stalled subscriber can't
+// exhaust the memory of the node.
+const maxQueuedBlocks = 100
+
+// A blockSubscriber is a consensus set subscriber that forwards every applied
+// block to a channel. Consensus changes are delivered while the consensus set
+// is locked, so the blocks are queued and forwarded by a separate goroutine
+// instead of blocking the consensus set on a slow reader.
+type blockSubscriber struct {
+	blocks  chan types.Block
+	queue   []types.Block
+	notify  chan struct{}
+	stop    chan struct{}
+	stopped sync.Once
+	log     *persist.Logger
+	mu      sync.Mutex
+}
+
+// ProcessConsensusChange queues the blocks applied by cc. If the queue is
+// full, the blocks that don't fit are dropped and a warning is logged.
+func (bs *blockSubscriber) ProcessConsensusChange(cc modules.ConsensusChange) {
+	if len(cc.AppliedBlocks) == 0 {
+		return
+	}
+	bs.mu.Lock()
+	blocks := cc.AppliedBlocks
+	if free := maxQueuedBlocks - len(bs.queue); len(blocks) > free {
+		bs.log.Printf("WARN: dropped %v blocks for a block subscriber: subscriber is not keeping up", len(blocks)-free)
+		blocks = blocks[:free]
+	}
+	bs.queue = append(bs.queue, blocks...)
+	bs.mu.Unlock()
+	select {
+	case bs.notify <- struct{}{}:
+	default:
+	}
+}
+
+// threadedForwardBlocks sends the queued blocks to the subscriber's channel
+// until the subscription is stopped or the consensus set shuts down. The
+// channel is closed afterwards.
+func (bs *blockSubscriber) threadedForwardBlocks(shutdown <-chan struct{}) {
+	defer close(bs.blocks)
+	for {
+		bs.mu.Lock()
+		var next types.Block
+		pending := len(bs.queue) > 0
+		if pending {
+			next = bs.queue[0]
+			bs.queue = bs.queue[1:]
+		}
+		bs.mu.Unlock()
+
+		if !pending {
+			select {
+			case <-bs.notify:
+				continue
+			case <-bs.stop:
+				return
+			case <-shutdown:
+				return
+			}
+		}
+		select {
+		case bs.blocks <- next:
+		case <-bs.stop:
+			return
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// SubscribeBlocks returns a channel that receives every block that is applied
+// to the consensus set from now on, along with a function that ends the
+// subscription. Blocks that are reverted are not reported, but the blocks of
+// the new fork are. Up to maxQueuedBlocks blocks are queued while the
+// subscriber isn't reading from the channel; further blocks are dropped. The
+// channel is closed once the subscription is ended or the consensus set shuts
+// down. If the consensus set is already shutting down, the channel is closed
+// right away.
+func (cs *ConsensusSet) SubscribeBlocks() (<-chan types.Block, func()) {
+	bs := &blockSubscriber{
+		blocks: make(chan types.Block),
+		notify: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		log:    cs.log,
+	}
+	err := cs.ConsensusSetSubscribe(bs, modules.ConsensusChangeRecent, cs.tg.StopChan())
+	if err != nil {
+		close(bs.blocks)
+		return bs.blocks, func() {}
+	}
+	go bs.threadedForwardBlocks(cs.tg.StopChan())
+	return bs.blocks, func() {
+		bs.stopped.Do(func() {
+			cs.Unsubscribe(bs)
+			close(bs.stop)
+		})
+	}
+}
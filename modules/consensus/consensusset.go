@@ -8,6 +8,8 @@ package consensus
 
 import (
 	"errors"
+	"sort"
+	"sync"
 
 	"gitlab.com/NebulousLabs/Sia/encoding"
 	"gitlab.com/NebulousLabs/Sia/modules"
@@ -81,6 +83,23 @@ type ConsensusSet struct {
 	// whether the consensus set is synced with the network.
 	synced bool
 
+	// peerHeights contains the highest block height that each peer has sent
+	// or announced to the consensus set. It has its own mutex, since it is
+	// updated while blocks are received from peers.
+	peerHeights   map[modules.NetAddress]types.BlockHeight
+	peerHeightsMu sync.Mutex
+
+	// blockTimings is a ring buffer containing the receipt times of the most
+	// recent blocks that extended the current chain. blockTimingsIndex is
+	// the position of the oldest entry once the buffer is full.
+	blockTimings      []modules.BlockTiming
+	blockTimingsIndex int
+
+	// staticReplaySlots limits the number of subscribers that are fed the
+	// blockchain from the genesis block at the same time. A replay holds a
+	// slot by sending to the channel.
+	staticReplaySlots chan struct{}
+
 	// Interfaces to abstract the dependencies of the ConsensusSet.
 	marshaler       marshaler
 	blockRuleHelper blockRuleHelper
@@ -123,12 +142,15 @@ func NewCustomConsensusSet(gateway modules.Gateway, bootstrap bool, persistDir s
 			DiffsGenerated: true,
 		},
 
-		dosBlocks: make(map[types.BlockID]struct{}),
+		dosBlocks:   make(map[types.BlockID]struct{}),
+		peerHeights: make(map[modules.NetAddress]types.BlockHeight),
 
 		marshaler:       stdMarshaler{},
 		blockRuleHelper: stdBlockRuleHelper{},
 		blockValidator:  NewBlockValidator(),
 
+		staticReplaySlots: make(chan struct{}, maxConcurrentReplays),
+
 		staticDeps: deps,
 		persistDir: persistDir,
 	}
@@ -288,6 +310,30 @@ func (cs *ConsensusSet) CurrentBlock() (block types.Block) {
 	return block
 }
 
+// CumulativeWork returns the total amount of work that went into the current
+// fork, i.e. the sum of the difficulties of all blocks up to and including the
+// current block. Of two forks, the one with more cumulative work is the longer
+// one.
+func (cs *ConsensusSet) CumulativeWork() (work types.Currency) {
+	// A call to a closed database can cause undefined behavior.
+	err := cs.tg.Add()
+	if err != nil {
+		return types.ZeroCurrency
+	}
+	defer cs.tg.Done()
+
+	// Block until a lock can be grabbed on the consensus set, indicating that
+	// all modules have received the most recent block.
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		work = currentProcessedBlock(tx).Depth.Difficulty()
+		return nil
+	})
+	return work
+}
+
 // Flush will block until the consensus set has finished all in-progress
 // routines.
 func (cs *ConsensusSet) Flush() error {
@@ -343,6 +389,89 @@ func (cs *ConsensusSet) InCurrentPath(id types.BlockID) (inPath bool) {
 	return inPath
 }
 
+// ForEachFileContract calls fn for every open file contract in the consensus
+// set. Iteration stops early if fn returns an error, which is then returned.
+// The contracts are read in a single database transaction, so fn must not
+// call back into the consensus set.
+func (cs *ConsensusSet) ForEachFileContract(fn func(types.FileContractID, types.FileContract) error) error {
+	// A call to a closed database can cause undefined behavior.
+	err := cs.tg.Add()
+	if err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+
+	return cs.db.View(func(tx *bolt.Tx) error {
+		return forEachFileContract(tx, fn)
+	})
+}
+
+// ForEachSiafundOutput calls fn for every unspent siafund output in the
+// consensus set, in order of their ids. Iteration stops early if fn returns an
+// error, which is then returned. The outputs are read in a single database
+// transaction, so fn must not call back into the consensus set.
+func (cs *ConsensusSet) ForEachSiafundOutput(fn func(types.SiafundOutputID, types.SiafundOutput) error) error {
+	// A call to a closed database can cause undefined behavior.
+	err := cs.tg.Add()
+	if err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+
+	return cs.db.View(func(tx *bolt.Tx) error {
+		return forEachSiafundOutput(tx, fn)
+	})
+}
+
+// SiafundClaimValue returns the siacoins that spending the siafund output with
+// the given id would currently claim, computed the same way as when the claim
+// output is created. False is returned if the output does not exist.
+func (cs *ConsensusSet) SiafundClaimValue(id types.SiafundOutputID) (claim types.Currency, exists bool) {
+	// A call to a closed database can cause undefined behavior.
+	err := cs.tg.Add()
+	if err != nil {
+		return types.ZeroCurrency, false
+	}
+	defer cs.tg.Done()
+
+	// Error is not checked because it does not matter.
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		sfo, err := getSiafundOutput(tx, id)
+		if err != nil {
+			return err
+		}
+		exists = true
+		pool := getSiafundPool(tx)
+		if pool.Cmp(sfo.ClaimStart) > 0 {
+			claim = pool.Sub(sfo.ClaimStart).Div(types.SiafundCount).Mul(sfo.Value)
+		}
+		return nil
+	})
+	return claim, exists
+}
+
+// Stats returns the number of blocks, unspent siacoin and siafund outputs and
+// file contracts in the consensus database, along with the size of the
+// database on disk.
+func (cs *ConsensusSet) Stats() (stats modules.ConsensusStats, err error) {
+	// A call to a closed database can cause undefined behavior.
+	err = cs.tg.Add()
+	if err != nil {
+		return modules.ConsensusStats{}, err
+	}
+	defer cs.tg.Done()
+
+	err = cs.db.View(func(tx *bolt.Tx) error {
+		stats.Blocks = uint64(tx.Bucket(BlockMap).Stats().KeyN)
+		stats.SiacoinOutputs = uint64(tx.Bucket(SiacoinOutputs).Stats().KeyN)
+		stats.SiafundOutputs = uint64(tx.Bucket(SiafundOutputs).Stats().KeyN)
+		stats.FileContracts = uint64(tx.Bucket(FileContracts).Stats().KeyN)
+		stats.DatabaseSize = uint64(tx.Size())
+		return nil
+	})
+	return stats, err
+}
+
 // MinimumValidChildTimestamp returns the earliest timestamp that the next block
 // can have in order for it to be considered valid.
 func (cs *ConsensusSet) MinimumValidChildTimestamp(id types.BlockID) (timestamp types.Timestamp, exists bool) {
@@ -366,6 +495,60 @@ func (cs *ConsensusSet) MinimumValidChildTimestamp(id types.BlockID) (timestamp
 	return timestamp, exists
 }
 
+// TimestampBounds returns the earliest and the latest timestamp that a child
+// of the current block can have in order to be accepted right away. Blocks
+// with an earlier timestamp are invalid, blocks with a later timestamp are
+// held back until they are no longer too far in the future.
+func (cs *ConsensusSet) TimestampBounds() (earliest, latest types.Timestamp) {
+	// A call to a closed database can cause undefined behavior.
+	err := cs.tg.Add()
+	if err != nil {
+		return 0, 0
+	}
+	defer cs.tg.Done()
+
+	// Error is not checked because it does not matter.
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		pb, err := getBlockMap(tx, currentBlockID(tx))
+		if err != nil {
+			return err
+		}
+		earliest = cs.blockRuleHelper.minimumValidChildTimestamp(tx.Bucket(BlockMap), pb)
+		return nil
+	})
+	latest = types.CurrentTimestamp() + types.FutureThreshold
+	return earliest, latest
+}
+
+// MedianTimestampWindow returns the timestamps of the current block and its
+// parents that make up the median-time-past window, starting with the current
+// block, along with their median. The median is the earliest timestamp that a
+// child of the current block can have.
+func (cs *ConsensusSet) MedianTimestampWindow() (window []types.Timestamp, median types.Timestamp) {
+	// A call to a closed database can cause undefined behavior.
+	err := cs.tg.Add()
+	if err != nil {
+		return nil, 0
+	}
+	defer cs.tg.Done()
+
+	// Error is not checked because it does not matter.
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		pb, err := getBlockMap(tx, currentBlockID(tx))
+		if err != nil {
+			return err
+		}
+		window = timestampWindow(tx.Bucket(BlockMap), pb)
+		return nil
+	})
+	if len(window) == 0 {
+		return nil, 0
+	}
+	sorted := append(types.TimestampSlice(nil), window...)
+	sort.Sort(sorted)
+	return window, sorted[len(sorted)/2]
+}
+
 // StorageProofSegment returns the segment to be used in the storage proof for
 // a given file contract.
 func (cs *ConsensusSet) StorageProofSegment(fcid types.FileContractID) (index uint64, err error) {
@@ -1,6 +1,8 @@
 package consensus
 
 import (
+	"bytes"
+	"errors"
 	"path/filepath"
 	"testing"
 
@@ -202,3 +204,342 @@ func TestDatabaseClosing(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// TestTimestampBounds checks that TimestampBounds reports the timestamp range
+// that the next block has to be in.
+func TestTimestampBounds(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	before := types.CurrentTimestamp()
+	earliest, latest := cst.cs.TimestampBounds()
+	after := types.CurrentTimestamp()
+
+	minTimestamp, exists := cst.cs.MinimumValidChildTimestamp(cst.cs.CurrentBlock().ID())
+	if !exists {
+		t.Fatal("current block not found")
+	}
+	if earliest != minTimestamp {
+		t.Fatalf("expected earliest timestamp %v, got %v", minTimestamp, earliest)
+	}
+	if latest < before+types.FutureThreshold || latest > after+types.FutureThreshold {
+		t.Fatalf("latest timestamp %v is not FutureThreshold in the future", latest)
+	}
+	if earliest > latest {
+		t.Fatal("earliest timestamp is after the latest timestamp")
+	}
+}
+
+// TestMedianTimestampWindow checks that the median timestamp window starts with
+// the current block and that its median is the earliest valid timestamp.
+func TestMedianTimestampWindow(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	window, median := cst.cs.MedianTimestampWindow()
+	if uint64(len(window)) != types.MedianTimestampWindow {
+		t.Fatalf("expected %v timestamps, got %v", types.MedianTimestampWindow, len(window))
+	}
+	if window[0] != cst.cs.CurrentBlock().Timestamp {
+		t.Fatal("window doesn't start with the timestamp of the current block")
+	}
+	earliest, _ := cst.cs.TimestampBounds()
+	if median != earliest {
+		t.Fatalf("expected median %v, got %v", earliest, median)
+	}
+}
+
+// TestConsensusStats checks that Stats counts the blocks and outputs of the
+// consensus database.
+func TestConsensusStats(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	stats, err := cst.cs.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Blocks < uint64(cst.cs.Height())+1 {
+		t.Fatalf("expected at least %v blocks, got %v", cst.cs.Height()+1, stats.Blocks)
+	}
+	if stats.SiacoinOutputs == 0 {
+		t.Fatal("expected siacoin outputs in the consensus database")
+	}
+	if stats.SiafundOutputs == 0 {
+		t.Fatal("expected the genesis siafund outputs in the consensus database")
+	}
+	if stats.DatabaseSize == 0 {
+		t.Fatal("expected a non-zero database size")
+	}
+}
+
+// TestTipRecent checks that TipRecent reports a freshly mined block as recent.
+func TestTipRecent(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	if _, err := cst.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if !cst.cs.TipRecent() {
+		t.Fatal("freshly mined block is not recent")
+	}
+	if cst.cs.CurrentBlock().Timestamp+tipRecentThreshold < types.CurrentTimestamp() {
+		t.Fatal("current block is older than the threshold")
+	}
+}
+
+// TestCumulativeWork checks that the cumulative work grows by the difficulty
+// of each new block.
+func TestCumulativeWork(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	before := cst.cs.CumulativeWork()
+	target, _ := cst.cs.ChildTarget(cst.cs.CurrentBlock().ID())
+	if _, err := cst.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	after := cst.cs.CumulativeWork()
+	if after.Cmp(before) <= 0 {
+		t.Fatal("cumulative work didn't increase")
+	}
+	// The difficulties are rounded, so allow for a small error.
+	diff := after.Sub(before)
+	if diff.Cmp(target.Difficulty().Add(types.NewCurrency64(2))) > 0 || diff.Add(types.NewCurrency64(2)).Cmp(target.Difficulty()) < 0 {
+		t.Fatalf("expected cumulative work to increase by %v, got %v", target.Difficulty(), diff)
+	}
+}
+
+// TestForEachFileContract checks that ForEachFileContract visits every open
+// file contract and stops when the callback returns an error.
+func TestForEachFileContract(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	// Create two file contracts.
+	payout := types.NewCurrency64(400e6)
+	height := cst.cs.dbBlockHeight()
+	fcids := make(map[types.FileContractID]struct{})
+	for i := 0; i < 2; i++ {
+		fc := types.FileContract{
+			WindowStart: height + 10,
+			WindowEnd:   height + 20,
+			Payout:      payout,
+			ValidProofOutputs: []types.SiacoinOutput{{
+				Value: types.PostTax(height, payout),
+			}},
+			MissedProofOutputs: []types.SiacoinOutput{{
+				Value: types.PostTax(height, payout),
+			}},
+		}
+		txnBuilder, err := cst.wallet.StartTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := txnBuilder.FundSiacoins(payout); err != nil {
+			t.Fatal(err)
+		}
+		fcIndex := txnBuilder.AddFileContract(fc)
+		txnSet, err := txnBuilder.Sign(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cst.tpool.AcceptTransactionSet(txnSet); err != nil {
+			t.Fatal(err)
+		}
+		fcids[txnSet[len(txnSet)-1].FileContractID(fcIndex)] = struct{}{}
+	}
+	if _, err := cst.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	// All contracts should be visited.
+	seen := make(map[types.FileContractID]struct{})
+	err = cst.cs.ForEachFileContract(func(id types.FileContractID, fc types.FileContract) error {
+		if !fc.Payout.Equals(payout) {
+			t.Error("wrong payout for contract", id)
+		}
+		seen[id] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id := range fcids {
+		if _, ok := seen[id]; !ok {
+			t.Fatal("contract was not visited:", id)
+		}
+	}
+
+	// Returning an error should stop the iteration.
+	errStop := errors.New("stop")
+	visited := 0
+	err = cst.cs.ForEachFileContract(func(types.FileContractID, types.FileContract) error {
+		visited++
+		return errStop
+	})
+	if err != errStop {
+		t.Fatal("expected errStop, got", err)
+	}
+	if visited != 1 {
+		t.Fatal("iteration should have stopped after the first contract, visited", visited)
+	}
+}
+
+// TestForEachSiafundOutput checks that ForEachSiafundOutput visits every
+// siafund output in order of their ids.
+func TestForEachSiafundOutput(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	// The outputs should add up to the total number of siafunds.
+	total := types.ZeroCurrency
+	var prev types.SiafundOutputID
+	visited := 0
+	err = cst.cs.ForEachSiafundOutput(func(id types.SiafundOutputID, sfo types.SiafundOutput) error {
+		if visited > 0 && bytes.Compare(prev[:], id[:]) >= 0 {
+			t.Fatal("siafund outputs are not sorted by id")
+		}
+		prev = id
+		visited++
+		total = total.Add(sfo.Value)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if visited == 0 {
+		t.Fatal("no siafund outputs were visited")
+	}
+	if !total.Equals(types.SiafundCount) {
+		t.Fatalf("expected %v siafunds, got %v", types.SiafundCount, total)
+	}
+}
+
+// TestSiafundClaimValue checks that SiafundClaimValue reports the share of the
+// siafund pool that each siafund output can claim.
+func TestSiafundClaimValue(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	// Form a file contract to grow the siafund pool.
+	payout := types.NewCurrency64(400e9)
+	height := cst.cs.dbBlockHeight()
+	fc := types.FileContract{
+		WindowStart: height + 10,
+		WindowEnd:   height + 20,
+		Payout:      payout,
+		ValidProofOutputs: []types.SiacoinOutput{{
+			Value: types.PostTax(height, payout),
+		}},
+		MissedProofOutputs: []types.SiacoinOutput{{
+			Value: types.PostTax(height, payout),
+		}},
+	}
+	txnBuilder, err := cst.wallet.StartTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := txnBuilder.FundSiacoins(payout); err != nil {
+		t.Fatal(err)
+	}
+	txnBuilder.AddFileContract(fc)
+	txnSet, err := txnBuilder.Sign(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cst.tpool.AcceptTransactionSet(txnSet); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cst.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every output should be able to claim its share of the pool.
+	pool := cst.cs.dbGetSiafundPool()
+	outputs := make(map[types.SiafundOutputID]types.SiafundOutput)
+	err = cst.cs.ForEachSiafundOutput(func(id types.SiafundOutputID, sfo types.SiafundOutput) error {
+		outputs[id] = sfo
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := types.ZeroCurrency
+	for id, sfo := range outputs {
+		claim, exists := cst.cs.SiafundClaimValue(id)
+		if !exists {
+			t.Fatal("siafund output not found:", id)
+		}
+		expected := pool.Sub(sfo.ClaimStart).Div(types.SiafundCount).Mul(sfo.Value)
+		if !claim.Equals(expected) {
+			t.Fatalf("expected claim %v, got %v", expected, claim)
+		}
+		total = total.Add(claim)
+	}
+	if total.IsZero() {
+		t.Fatal("expected the siafund pool to be claimable")
+	}
+
+	// Unknown outputs can't be claimed.
+	if _, exists := cst.cs.SiafundClaimValue(types.SiafundOutputID{}); exists {
+		t.Fatal("unknown siafund output should not exist")
+	}
+}
@@ -192,6 +192,7 @@ func commitDiffSet(tx *bolt.Tx, pb *processedBlock, dir modules.DiffDirection) {
 
 	createUpcomingDelayedOutputMaps(tx, pb, dir)
 	commitNodeDiffs(tx, pb, dir)
+	indexSiacoinOutputBlocks(tx, pb, dir)
 	deleteObsoleteDelayedOutputMaps(tx, pb, dir)
 	updateCurrentPath(tx, pb, dir)
 }
@@ -229,6 +230,7 @@ func generateAndApplyDiff(tx *bolt.Tx, pb *processedBlock) error {
 	// maturity, applying any contracts with missed storage proofs, and adding
 	// the miner payouts to the list of delayed outputs.
 	applyMaintenance(tx, pb)
+	indexSiacoinOutputBlocks(tx, pb, modules.DiffApply)
 
 	// DiffsGenerated are only set to true after the block has been fully
 	// validated and integrated. This is required to prevent later blocks from
@@ -0,0 +1,141 @@
+package consensus
+
+import (
+	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/encoding"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+
+	"github.com/coreos/bbolt"
+)
+
+var (
+	// SiacoinOutputBlocks is a database bucket that maps the id of every
+	// siacoin output created in the current path to the id and height of the
+	// block that created it. Unlike SiacoinOutputs, spent outputs are kept.
+	SiacoinOutputBlocks = []byte("SiacoinOutputBlocks")
+)
+
+// indexSiacoinOutputBlocks adds or removes the siacoin outputs created by pb
+// from the SiacoinOutputBlocks bucket. Diffs that spend an output are ignored,
+// the output keeps pointing to the block that created it.
+func indexSiacoinOutputBlocks(tx *bolt.Tx, pb *processedBlock, dir modules.DiffDirection) {
+	bucket := tx.Bucket(SiacoinOutputBlocks)
+	bid := pb.Block.ID()
+	for _, scod := range pb.SiacoinOutputDiffs {
+		if scod.Direction != modules.DiffApply {
+			continue
+		}
+		var err error
+		if dir == modules.DiffApply {
+			err = bucket.Put(scod.ID[:], encoding.MarshalAll(bid, pb.Height))
+		} else {
+			err = bucket.Delete(scod.ID[:])
+		}
+		if build.DEBUG && err != nil {
+			panic(err)
+		}
+	}
+}
+
+// initSiacoinOutputBlocks creates the SiacoinOutputBlocks bucket if it does
+// not exist yet. Older consensus databases don't have the bucket, so the
+// current path is scanned to fill it.
+func (cs *ConsensusSet) initSiacoinOutputBlocks(tx *bolt.Tx) error {
+	if tx.Bucket(SiacoinOutputBlocks) != nil {
+		return nil
+	}
+	if _, err := tx.CreateBucket(SiacoinOutputBlocks); err != nil {
+		return err
+	}
+	height := blockHeight(tx)
+	for i := types.BlockHeight(0); i <= height; i++ {
+		id, err := getPath(tx, i)
+		if err != nil {
+			return err
+		}
+		pb, err := getBlockMap(tx, id)
+		if err != nil {
+			return err
+		}
+		indexSiacoinOutputBlocks(tx, pb, modules.DiffApply)
+	}
+	return nil
+}
+
+// SiacoinOutput returns the unspent siacoin output with the given id. An error
+// is returned if the output doesn't exist or has been spent.
+func (cs *ConsensusSet) SiacoinOutput(id types.SiacoinOutputID) (sco types.SiacoinOutput, err error) {
+	// A call to a closed database can cause undefined behavior.
+	err = cs.tg.Add()
+	if err != nil {
+		return types.SiacoinOutput{}, err
+	}
+	defer cs.tg.Done()
+
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		sco, err = getSiacoinOutput(tx, id)
+		return nil
+	})
+	return sco, err
+}
+
+// OutputSpendable returns whether the unspent siacoin output with the given id
+// can be spent at the current height using the unlock conditions uc. The
+// unlock conditions, including their timelock, are only revealed when the
+// output is spent, so they have to be provided by the caller; an error is
+// returned if they don't match the output's unlock hash. False is returned
+// if the output doesn't exist or has been spent already.
+func (cs *ConsensusSet) OutputSpendable(id types.SiacoinOutputID, uc types.UnlockConditions) (spendable bool, err error) {
+	// A call to a closed database can cause undefined behavior.
+	err = cs.tg.Add()
+	if err != nil {
+		return false, err
+	}
+	defer cs.tg.Done()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	err = cs.db.View(func(tx *bolt.Tx) error {
+		sco, err := getSiacoinOutput(tx, id)
+		if err == errNilItem {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if uc.UnlockHash() != sco.UnlockHash {
+			return errWrongUnlockConditions
+		}
+		// Transactions are validated against the current height, see
+		// validTransaction.
+		spendable = uc.Timelock <= blockHeight(tx)
+		return nil
+	})
+	return spendable, err
+}
+
+// SiacoinOutputBlock returns the id and height of the block in the current
+// path that created the siacoin output with the given id. Outputs that are
+// created by maturing delayed outputs, such as miner payouts, are reported at
+// the block in which they matured. false is returned if no block in the
+// current path created the output.
+func (cs *ConsensusSet) SiacoinOutputBlock(id types.SiacoinOutputID) (bid types.BlockID, height types.BlockHeight, exists bool) {
+	// A call to a closed database can cause undefined behavior.
+	if err := cs.tg.Add(); err != nil {
+		return types.BlockID{}, 0, false
+	}
+	defer cs.tg.Done()
+
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(SiacoinOutputBlocks).Get(id[:])
+		if b == nil {
+			return nil
+		}
+		if err := encoding.UnmarshalAll(b, &bid, &height); err != nil {
+			return err
+		}
+		exists = true
+		return nil
+	})
+	return bid, height, exists
+}
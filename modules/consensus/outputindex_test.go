@@ -0,0 +1,160 @@
+package consensus
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/types"
+
+	"github.com/coreos/bbolt"
+)
+
+// TestSiacoinOutputBlock checks that the consensus set reports the block that
+// created a siacoin output, also after the output was spent and after the
+// index was rebuilt from the current path.
+func TestSiacoinOutputBlock(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	// Send coins to a random address and mine the transaction.
+	txnValue := types.NewCurrency64(1200)
+	txnBuilder, err := cst.wallet.StartTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = txnBuilder.FundSiacoins(txnValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputIndex := txnBuilder.AddSiacoinOutput(types.SiacoinOutput{Value: txnValue, UnlockHash: randAddress()})
+	txnSet, err := txnBuilder.Sign(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cst.tpool.AcceptTransactionSet(txnSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := cst.miner.AddBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	createdID := txnSet[len(txnSet)-1].SiacoinOutputID(outputIndex)
+	spentID := txnSet[0].SiacoinInputs[0].ParentID
+
+	checkIndex := func() {
+		// The new output should point to the mined block and be unspent.
+		bid, height, exists := cst.cs.SiacoinOutputBlock(createdID)
+		if !exists {
+			t.Fatal("created output is not indexed")
+		}
+		if bid != b.ID() || height != cst.cs.Height() {
+			t.Fatalf("wrong block for created output: got %v at %v, expected %v at %v", bid, height, b.ID(), cst.cs.Height())
+		}
+		if _, err := cst.cs.SiacoinOutput(createdID); err != nil {
+			t.Fatal("created output should be unspent:", err)
+		}
+
+		// The spent output should point to an earlier block and be spent.
+		bid, height, exists = cst.cs.SiacoinOutputBlock(spentID)
+		if !exists {
+			t.Fatal("spent output is not indexed")
+		}
+		if height >= cst.cs.Height() {
+			t.Fatal("spent output should have been created in an earlier block, got height", height)
+		}
+		if pathBlock, ok := cst.cs.BlockAtHeight(height); !ok || pathBlock.ID() != bid {
+			t.Fatal("spent output points to a block that is not in the current path")
+		}
+		if _, err := cst.cs.SiacoinOutput(spentID); err != errNilItem {
+			t.Fatal("expected errNilItem for spent output, got", err)
+		}
+	}
+	checkIndex()
+
+	// Unknown outputs should not be found.
+	if _, _, exists := cst.cs.SiacoinOutputBlock(types.SiacoinOutputID{}); exists {
+		t.Fatal("unknown output should not be indexed")
+	}
+
+	// Rebuilding the index from the current path should give the same result.
+	err = cst.cs.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(SiacoinOutputBlocks); err != nil {
+			return err
+		}
+		return cst.cs.initSiacoinOutputBlocks(tx)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkIndex()
+}
+
+// TestOutputSpendable checks that an output is only reported as spendable
+// once the timelock of its unlock conditions has passed.
+func TestOutputSpendable(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	// Send coins to unlock conditions with a timelock and mine the
+	// transaction.
+	uc := types.UnlockConditions{Timelock: cst.cs.Height() + 3}
+	txnValue := types.NewCurrency64(1200)
+	txnBuilder, err := cst.wallet.StartTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = txnBuilder.FundSiacoins(txnValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputIndex := txnBuilder.AddSiacoinOutput(types.SiacoinOutput{Value: txnValue, UnlockHash: uc.UnlockHash()})
+	txnSet, err := txnBuilder.Sign(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cst.tpool.AcceptTransactionSet(txnSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cst.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	id := txnSet[len(txnSet)-1].SiacoinOutputID(outputIndex)
+
+	// The output exists but the timelock hasn't passed yet.
+	if spendable, err := cst.cs.OutputSpendable(id, uc); err != nil || spendable {
+		t.Fatal("output should not be spendable yet", spendable, err)
+	}
+	// Unlock conditions of a different address are rejected.
+	if _, err := cst.cs.OutputSpendable(id, types.UnlockConditions{}); err != errWrongUnlockConditions {
+		t.Fatal("expected errWrongUnlockConditions, got", err)
+	}
+	// Unknown outputs are not spendable.
+	if spendable, err := cst.cs.OutputSpendable(types.SiacoinOutputID{}, uc); err != nil || spendable {
+		t.Fatal("unknown output should not be spendable", spendable, err)
+	}
+
+	// Once the timelock has passed the output is spendable.
+	for cst.cs.Height() < uc.Timelock {
+		if _, err := cst.miner.AddBlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if spendable, err := cst.cs.OutputSpendable(id, uc); err != nil || !spendable {
+		t.Fatal("output should be spendable", spendable, err)
+	}
+}
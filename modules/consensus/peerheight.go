@@ -0,0 +1,76 @@
+package consensus
+
+import (
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+
+	"github.com/coreos/bbolt"
+)
+
+// managedNotePeerHeight records that the peer at addr has a block at the
+// given height.
+func (cs *ConsensusSet) managedNotePeerHeight(addr modules.NetAddress, height types.BlockHeight) {
+	cs.peerHeightsMu.Lock()
+	defer cs.peerHeightsMu.Unlock()
+	if height > cs.peerHeights[addr] {
+		cs.peerHeights[addr] = height
+	}
+}
+
+// managedNoteBlockFromPeer records the height of a block that the peer at addr
+// has sent, if the block is part of the block tree.
+func (cs *ConsensusSet) managedNoteBlockFromPeer(addr modules.NetAddress, id types.BlockID) {
+	var height types.BlockHeight
+	cs.mu.RLock()
+	err := cs.db.View(func(tx *bolt.Tx) error {
+		pb, err := getBlockMap(tx, id)
+		if err != nil {
+			return err
+		}
+		height = pb.Height
+		return nil
+	})
+	cs.mu.RUnlock()
+	if err == nil {
+		cs.managedNotePeerHeight(addr, height)
+	}
+}
+
+// PeerHeight returns the highest block height that the connected peers have
+// sent or announced to the consensus set. Peers don't advertise their height
+// directly, so the height of a peer is only known once it has sent a block or
+// announced a header whose parent is known. The returned height is never lower
+// than the local height, so that it can be used to report the progress of the
+// initial blockchain download.
+func (cs *ConsensusSet) PeerHeight() types.BlockHeight {
+	if err := cs.tg.Add(); err != nil {
+		return 0
+	}
+	defer cs.tg.Done()
+
+	peers := cs.gateway.Peers()
+	var height types.BlockHeight
+	cs.mu.RLock()
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		height = blockHeight(tx)
+		return nil
+	})
+	cs.mu.RUnlock()
+
+	cs.peerHeightsMu.Lock()
+	defer cs.peerHeightsMu.Unlock()
+	connected := make(map[modules.NetAddress]struct{}, len(peers))
+	for _, p := range peers {
+		connected[p.NetAddress] = struct{}{}
+		if h := cs.peerHeights[p.NetAddress]; h > height {
+			height = h
+		}
+	}
+	// Forget the peers that are no longer connected.
+	for addr := range cs.peerHeights {
+		if _, ok := connected[addr]; !ok {
+			delete(cs.peerHeights, addr)
+		}
+	}
+	return height
+}
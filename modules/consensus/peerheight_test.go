@@ -0,0 +1,73 @@
+package consensus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/Sia/build"
+)
+
+// TestPeerHeight checks that the consensus set reports the height of the
+// blocks that its peers have sent.
+func TestPeerHeight(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	cst1, err := createConsensusSetTester(t.Name() + "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst1.Close()
+	cst2, err := createConsensusSetTester(t.Name() + "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst2.Close()
+
+	// Without peers, the peer height is the local height.
+	if h := cst1.cs.PeerHeight(); h != cst1.cs.dbBlockHeight() {
+		t.Fatalf("expected peer height %v, got %v", cst1.cs.dbBlockHeight(), h)
+	}
+
+	// Mine on cst2 until it is above cst1.
+	for cst1.cs.dbBlockHeight() >= cst2.cs.dbBlockHeight() {
+		b, _ := cst2.miner.FindBlock()
+		if err := cst2.cs.AcceptBlock(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Connecting the gateways triggers a synchronization, after which cst1
+	// knows the height of cst2.
+	if err := cst1.gateway.Connect(cst2.gateway.Address()); err != nil {
+		t.Fatal(err)
+	}
+	err = build.Retry(50, 250*time.Millisecond, func() error {
+		if h := cst1.cs.PeerHeight(); h != cst2.cs.dbBlockHeight() {
+			return errors.New("peer height doesn't match the height of the peer")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cst1.cs.peerHeightsMu.Lock()
+	_, known := cst1.cs.peerHeights[cst2.gateway.Address()]
+	cst1.cs.peerHeightsMu.Unlock()
+	if !known {
+		t.Fatal("height of the peer was not recorded")
+	}
+
+	// The height of a disconnected peer is forgotten.
+	if err := cst1.gateway.Disconnect(cst2.gateway.Address()); err != nil {
+		t.Fatal(err)
+	}
+	cst1.cs.PeerHeight()
+	cst1.cs.peerHeightsMu.Lock()
+	_, known = cst1.cs.peerHeights[cst2.gateway.Address()]
+	cst1.cs.peerHeightsMu.Unlock()
+	if known {
+		t.Fatal("height of the disconnected peer was not forgotten")
+	}
+}
@@ -47,6 +47,13 @@ func (cs *ConsensusSet) loadDB() error {
 			return err
 		}
 
+		// Older consensus databases don't index the blocks that created
+		// siacoin outputs, so the index is built from the current path.
+		err = cs.initSiacoinOutputBlocks(tx)
+		if err != nil {
+			return err
+		}
+
 		// Check that the genesis block is correct - typically only incorrect
 		// in the event of developer binaries vs. release binaires.
 		genesisID, err := getPath(tx, 0)
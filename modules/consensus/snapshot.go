@@ -0,0 +1,189 @@
+package consensus
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/types"
+
+	"github.com/coreos/bbolt"
+)
+
+var (
+	// errSnapshotClosed is returned when a snapshot is used after it was
+	// closed.
+	errSnapshotClosed = errors.New("consensus snapshot has been closed")
+
+	// errSnapshotExpired is returned when a snapshot is used after it was
+	// closed automatically because it exceeded snapshotMaxLifetime.
+	errSnapshotExpired = errors.New("consensus snapshot has exceeded its maximum lifetime")
+
+	// snapshotMaxLifetime is the time after which an open snapshot is closed
+	// automatically. The read transaction of a snapshot prevents the
+	// database from growing its memory map, which stalls block processing
+	// once the database needs to grow, and the snapshot delays the shutdown
+	// of the consensus set. The lifetime therefore bounds these stalls and is
+	// kept short; snapshots are meant for a burst of queries, not for holding
+	// on to a view of the consensus set.
+	snapshotMaxLifetime = build.Select(build.Var{
+		Standard: 10 * time.Second,
+		Dev:      10 * time.Second,
+		Testing:  5 * time.Second,
+	}).(time.Duration)
+)
+
+// A Snapshot is an immutable, point-in-time view of the consensus set. It is
+// backed by a read-only database transaction, so taking a snapshot doesn't
+// copy any data; the database keeps the pages of the snapshot alive until it
+// is closed. Blocks that are accepted after the snapshot was taken are not
+// visible to it.
+//
+// A snapshot must be closed as soon as the queries that need a consistent view
+// are done. Open snapshots prevent the database from growing its memory map
+// and block the consensus set from shutting down, so a snapshot that is still
+// open after snapshotMaxLifetime is closed automatically and fails all further
+// queries. Callers that need the state for longer must copy it out of the
+// snapshot. For this reason, snapshots are only available on the
+// ConsensusSet itself and not through the modules.ConsensusSet interface.
+type Snapshot struct {
+	closed  bool
+	expired bool
+	mu      sync.Mutex
+	timer   *time.Timer
+	tx      *bolt.Tx
+
+	cs *ConsensusSet
+}
+
+// Snapshot returns a point-in-time view of the consensus set that can be
+// queried without being affected by blocks accepted later on. The caller must
+// close the snapshot promptly; see Snapshot for details.
+func (cs *ConsensusSet) Snapshot() (*Snapshot, error) {
+	// The thread group is released when the snapshot is closed, so that the
+	// database isn't closed while the snapshot is in use.
+	if err := cs.tg.Add(); err != nil {
+		return nil, err
+	}
+	tx, err := cs.db.Begin(false)
+	if err != nil {
+		cs.tg.Done()
+		return nil, err
+	}
+	s := &Snapshot{
+		tx: tx,
+		cs: cs,
+	}
+	s.mu.Lock()
+	s.timer = time.AfterFunc(snapshotMaxLifetime, s.expire)
+	s.mu.Unlock()
+	return s, nil
+}
+
+// view calls fn with the snapshot's database transaction, returning an error
+// if the snapshot has been closed.
+func (s *Snapshot) view(fn func(tx *bolt.Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.expired {
+		return errSnapshotExpired
+	} else if s.closed {
+		return errSnapshotClosed
+	}
+	return fn(s.tx)
+}
+
+// release rolls back the snapshot's transaction and releases the thread
+// group. The caller must hold the lock.
+func (s *Snapshot) release() error {
+	s.closed = true
+	err := s.tx.Rollback()
+	s.cs.tg.Done()
+	return err
+}
+
+// expire closes the snapshot once it has exceeded snapshotMaxLifetime.
+func (s *Snapshot) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.expired = true
+	s.cs.log.Printf("WARN: closing a consensus snapshot that was open for more than %v", snapshotMaxLifetime)
+	if err := s.release(); err != nil {
+		s.cs.log.Println("Unable to close expired consensus snapshot:", err)
+	}
+}
+
+// Close releases the snapshot. The snapshot can't be used afterwards.
+func (s *Snapshot) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.expired {
+		return errSnapshotExpired
+	} else if s.closed {
+		return errSnapshotClosed
+	}
+	s.timer.Stop()
+	return s.release()
+}
+
+// Height returns the height of the current block at the time of the
+// snapshot.
+func (s *Snapshot) Height() (height types.BlockHeight, err error) {
+	err = s.view(func(tx *bolt.Tx) error {
+		height = blockHeight(tx)
+		return nil
+	})
+	return height, err
+}
+
+// CurrentBlock returns the current block at the time of the snapshot.
+func (s *Snapshot) CurrentBlock() (b types.Block, err error) {
+	err = s.view(func(tx *bolt.Tx) error {
+		b = currentProcessedBlock(tx).Block
+		return nil
+	})
+	return b, err
+}
+
+// SiacoinOutput returns the siacoin output with the given id if it was unspent
+// at the time of the snapshot.
+func (s *Snapshot) SiacoinOutput(id types.SiacoinOutputID) (sco types.SiacoinOutput, err error) {
+	err = s.view(func(tx *bolt.Tx) error {
+		sco, err = getSiacoinOutput(tx, id)
+		return err
+	})
+	return sco, err
+}
+
+// SiafundOutput returns the siafund output with the given id if it was unspent
+// at the time of the snapshot.
+func (s *Snapshot) SiafundOutput(id types.SiafundOutputID) (sfo types.SiafundOutput, err error) {
+	err = s.view(func(tx *bolt.Tx) error {
+		sfo, err = getSiafundOutput(tx, id)
+		return err
+	})
+	return sfo, err
+}
+
+// FileContract returns the file contract with the given id if it was open at
+// the time of the snapshot.
+func (s *Snapshot) FileContract(id types.FileContractID) (fc types.FileContract, err error) {
+	err = s.view(func(tx *bolt.Tx) error {
+		fc, err = getFileContract(tx, id)
+		return err
+	})
+	return fc, err
+}
+
+// ForEachFileContract calls fn for every file contract that was open at the
+// time of the snapshot, stopping early if fn returns an error. fn must not use
+// the snapshot.
+func (s *Snapshot) ForEachFileContract(fn func(types.FileContractID, types.FileContract) error) error {
+	return s.view(func(tx *bolt.Tx) error {
+		return forEachFileContract(tx, fn)
+	})
+}
@@ -0,0 +1,175 @@
+package consensus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestSnapshot checks that a snapshot isn't affected by blocks that are
+// accepted after it was taken.
+func TestSnapshot(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	// Create a transaction spending one of the wallet's outputs, but take the
+	// snapshot before it is mined.
+	txnValue := types.NewCurrency64(1200)
+	txnBuilder, err := cst.wallet.StartTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := txnBuilder.FundSiacoins(txnValue); err != nil {
+		t.Fatal(err)
+	}
+	txnBuilder.AddSiacoinOutput(types.SiacoinOutput{Value: txnValue, UnlockHash: randAddress()})
+	txnSet, err := txnBuilder.Sign(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spentID := txnSet[0].SiacoinInputs[0].ParentID
+
+	snapshot, err := cst.cs.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	height := cst.cs.Height()
+	currentID := cst.cs.CurrentBlock().ID()
+
+	if err := cst.tpool.AcceptTransactionSet(txnSet); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cst.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if cst.cs.Height() != height+1 {
+		t.Fatal("block was not accepted")
+	}
+	if _, err := cst.cs.SiacoinOutput(spentID); err != errNilItem {
+		t.Fatal("output should have been spent, got", err)
+	}
+
+	// The snapshot should still see the old state.
+	if h, err := snapshot.Height(); err != nil || h != height {
+		t.Fatalf("expected snapshot height %v, got %v (%v)", height, h, err)
+	}
+	if b, err := snapshot.CurrentBlock(); err != nil || b.ID() != currentID {
+		t.Fatalf("expected snapshot block %v, got %v (%v)", currentID, b.ID(), err)
+	}
+	if _, err := snapshot.SiacoinOutput(spentID); err != nil {
+		t.Fatal("output should be unspent in the snapshot:", err)
+	}
+
+	// A closed snapshot can't be used anymore.
+	if err := snapshot.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := snapshot.Height(); err != errSnapshotClosed {
+		t.Fatal("expected errSnapshotClosed, got", err)
+	}
+	if err := snapshot.Close(); err != errSnapshotClosed {
+		t.Fatal("expected errSnapshotClosed, got", err)
+	}
+}
+
+// TestSnapshotAcceptBlock checks that blocks are accepted while a snapshot is
+// open, instead of waiting for the snapshot to be closed.
+func TestSnapshotAcceptBlock(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	snapshot, err := cst.cs.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	height := cst.cs.Height()
+
+	// Mine blocks in the background. They must be accepted before the
+	// snapshot expires.
+	done := make(chan error)
+	go func() {
+		for i := 0; i < 5; i++ {
+			if _, err := cst.miner.AddBlock(); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(snapshotMaxLifetime):
+		t.Fatal("AcceptBlock stalled while a snapshot was open")
+	}
+	if cst.cs.Height() != height+5 {
+		t.Fatalf("expected height %v, got %v", height+5, cst.cs.Height())
+	}
+
+	// The snapshot was open the whole time and still sees the old state.
+	if h, err := snapshot.Height(); err != nil || h != height {
+		t.Fatalf("expected snapshot height %v, got %v (%v)", height, h, err)
+	}
+	if err := snapshot.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSnapshotExpiry checks that a snapshot is closed automatically once it
+// exceeds snapshotMaxLifetime.
+func TestSnapshotExpiry(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	snapshot, err := cst.cs.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := snapshot.Height(); err != nil {
+		t.Fatal(err)
+	}
+	err = build.Retry(int(2*snapshotMaxLifetime/(100*time.Millisecond)), 100*time.Millisecond, func() error {
+		if _, err := snapshot.Height(); err != errSnapshotExpired {
+			return errors.New("snapshot didn't expire")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := snapshot.Close(); err != errSnapshotExpired {
+		t.Fatal("expected errSnapshotExpired, got", err)
+	}
+
+	// The expired snapshot released the database, so blocks are accepted and
+	// the consensus set can shut down.
+	if _, err := cst.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -11,6 +11,24 @@ import (
 	siasync "gitlab.com/NebulousLabs/Sia/sync"
 )
 
+var (
+	// errConsensusChangeCurrent is returned by ConsensusChangeSince if the
+	// provided change is the most recent change.
+	errConsensusChangeCurrent = errors.New("consensus change is already the most recent change")
+
+	// maxConcurrentReplays is the number of subscribers that may be fed the
+	// blockchain from the genesis block at the same time. Modules tend to
+	// resubscribe from the beginning all at once, e.g. after a rescan, and
+	// replaying the whole chain to each of them in parallel saturates the CPU.
+	// Further replays wait for a running one to finish. Subscribers that
+	// catch up from a recent change are never delayed.
+	maxConcurrentReplays = build.Select(build.Var{
+		Standard: 1,
+		Dev:      1,
+		Testing:  2,
+	}).(int)
+)
+
 // computeConsensusChange computes the consensus change from the change entry
 // at index 'i' in the change log. If i is out of bounds, an error is returned.
 func (cs *ConsensusSet) computeConsensusChange(tx *bolt.Tx, ce changeEntry) (modules.ConsensusChange, error) {
@@ -177,6 +195,20 @@ func (cs *ConsensusSet) managedInitializeSubscribe(subscriber modules.ConsensusS
 		return start, nil
 	}
 
+	// Replays from the genesis block are limited to maxConcurrentReplays at a
+	// time. This only changes when the changes are sent, not which changes
+	// are sent.
+	if start == modules.ConsensusChangeBeginning {
+		select {
+		case cs.staticReplaySlots <- struct{}{}:
+			defer func() { <-cs.staticReplaySlots }()
+		case <-cancel:
+			return modules.ConsensusChangeID{}, siasync.ErrStopped
+		case <-cs.tg.StopChan():
+			return modules.ConsensusChangeID{}, siasync.ErrStopped
+		}
+	}
+
 	// Send all remaining consensus changes to the subscriber.
 	latestChangeID := entry.ID()
 	for exists {
@@ -223,6 +255,89 @@ func (cs *ConsensusSet) recentConsensusChangeID() (cid modules.ConsensusChangeID
 	return
 }
 
+// ConsensusChangeSince returns a single consensus change that takes a
+// subscriber from the change with the provided id to the current block. Unlike
+// resubscribing, the intermediate changes are not replayed one by one. Instead,
+// the change reverts the blocks of the subscriber's fork back to the common
+// ancestor with the current path and applies the blocks from there to the
+// current block, bundling the diffs of all of these blocks. The ID of the
+// returned change is the id of the most recent change, so that the subscriber
+// can resubscribe with it afterwards.
+//
+// As a special case, using an empty id returns a change that applies all blocks
+// starting with the genesis block.
+func (cs *ConsensusSet) ConsensusChangeSince(start modules.ConsensusChangeID) (cc modules.ConsensusChange, err error) {
+	if err := cs.tg.Add(); err != nil {
+		return modules.ConsensusChange{}, err
+	}
+	defer cs.tg.Done()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	recentID, err := cs.recentConsensusChangeID()
+	if err != nil {
+		return modules.ConsensusChange{}, err
+	}
+	if start == recentID || start == modules.ConsensusChangeRecent {
+		return modules.ConsensusChange{}, errConsensusChangeCurrent
+	}
+
+	err = cs.db.View(func(tx *bolt.Tx) error {
+		// Find the block that the subscriber's state ends with.
+		var ce changeEntry
+		ancestor := cs.blockRoot
+		if start != modules.ConsensusChangeBeginning {
+			entry, exists := getEntry(tx, start)
+			if !exists {
+				return modules.ErrInvalidConsensusChangeID
+			}
+			pb, err := getBlockMap(tx, entry.AppliedBlocks[len(entry.AppliedBlocks)-1])
+			if err != nil {
+				return err
+			}
+			// Walk back until the block is on the current path.
+			for {
+				id, err := getPath(tx, pb.Height)
+				if err == nil && id == pb.Block.ID() {
+					break
+				}
+				ce.RevertedBlocks = append(ce.RevertedBlocks, pb.Block.ID())
+				pb, err = getBlockMap(tx, pb.Block.ParentID)
+				if err != nil {
+					return err
+				}
+			}
+			ancestor = *pb
+		} else {
+			ce.AppliedBlocks = append(ce.AppliedBlocks, ancestor.Block.ID())
+		}
+
+		// The common ancestor may be the current block, e.g. if the
+		// subscriber is on a longer fork. A consensus change always applies at
+		// least one block, so the ancestor is reverted and applied again,
+		// which doesn't affect the outcome.
+		if ancestor.Height == blockHeight(tx) && len(ce.AppliedBlocks) == 0 {
+			ce.RevertedBlocks = append(ce.RevertedBlocks, ancestor.Block.ID())
+			ancestor.Height--
+		}
+		for height := ancestor.Height + 1; height <= blockHeight(tx); height++ {
+			id, err := getPath(tx, height)
+			if err != nil {
+				return err
+			}
+			ce.AppliedBlocks = append(ce.AppliedBlocks, id)
+		}
+
+		cc, err = cs.computeConsensusChange(tx, ce)
+		return err
+	})
+	if err != nil {
+		return modules.ConsensusChange{}, err
+	}
+	cc.ID = recentID
+	return cc, nil
+}
+
 // ConsensusSetSubscribe adds a subscriber to the list of subscribers, and
 // gives them every consensus change that has occurred since the change with
 // the provided id.
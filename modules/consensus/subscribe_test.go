@@ -1,11 +1,17 @@
 package consensus
 
 import (
+	"io/ioutil"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	bolt "github.com/coreos/bbolt"
 	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/persist"
+	"gitlab.com/NebulousLabs/Sia/types"
 )
 
 // mockSubscriber receives and holds changes to the consensus set, remembering
@@ -151,6 +157,73 @@ func TestUnsubscribe(t *testing.T) {
 	}
 }
 
+// TestSubscribeBlocks checks that SubscribeBlocks reports new blocks and
+// closes the channel when the subscription ends.
+func TestSubscribeBlocks(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	blocks, unsubscribe := cst.cs.SubscribeBlocks()
+	for i := 0; i < 3; i++ {
+		b, err := cst.miner.AddBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case received := <-blocks:
+			if received.ID() != b.ID() {
+				t.Fatal("received the wrong block")
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("block was not received")
+		}
+	}
+
+	// After unsubscribing, the channel is closed and no more blocks are
+	// received.
+	unsubscribe()
+	unsubscribe()
+	if _, err := cst.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case _, ok := <-blocks:
+		if ok {
+			t.Fatal("received a block after unsubscribing")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("channel was not closed")
+	}
+}
+
+// TestBlockSubscriberQueueLimit checks that a block subscriber drops blocks
+// once maxQueuedBlocks blocks are queued.
+func TestBlockSubscriberQueueLimit(t *testing.T) {
+	bs := &blockSubscriber{
+		notify: make(chan struct{}, 1),
+		log:    persist.NewLogger(ioutil.Discard),
+	}
+	bs.ProcessConsensusChange(modules.ConsensusChange{AppliedBlocks: make([]types.Block, maxQueuedBlocks-1)})
+	bs.ProcessConsensusChange(modules.ConsensusChange{AppliedBlocks: []types.Block{{Timestamp: 1}, {Timestamp: 2}}})
+	if len(bs.queue) != maxQueuedBlocks {
+		t.Fatalf("expected %v queued blocks, got %v", maxQueuedBlocks, len(bs.queue))
+	}
+	if bs.queue[maxQueuedBlocks-1].Timestamp != 1 {
+		t.Fatal("the wrong block was dropped")
+	}
+	bs.ProcessConsensusChange(modules.ConsensusChange{AppliedBlocks: []types.Block{{}}})
+	if len(bs.queue) != maxQueuedBlocks {
+		t.Fatalf("expected %v queued blocks, got %v", maxQueuedBlocks, len(bs.queue))
+	}
+}
+
 // TestModuletDesync is a reproduction test for the bug that caused a module to
 // desync while subscribing to the consensus set.
 func TestModuleDesync(t *testing.T) {
@@ -237,3 +310,148 @@ func TestModuleDesync(t *testing.T) {
 		t.Fatal("last update doesn't equal recentChangeID")
 	}
 }
+
+// TestConsensusChangeSince checks that ConsensusChangeSince returns a change
+// that brings a subscriber from an old change to the current block, even if
+// the subscriber's blocks were reverted in the meantime.
+func TestConsensusChangeSince(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	rs := createReorgSets(t.Name())
+	defer rs.Close()
+	cs := rs.cstMain.cs
+
+	// outputs returns the siacoin outputs that result from applying the diffs
+	// of the updates in order.
+	outputs := func(updates []modules.ConsensusChange) map[types.SiacoinOutputID]struct{} {
+		scos := make(map[types.SiacoinOutputID]struct{})
+		for _, cc := range updates {
+			for _, diff := range cc.SiacoinOutputDiffs {
+				if diff.Direction == modules.DiffApply {
+					scos[diff.ID] = struct{}{}
+				} else {
+					delete(scos, diff.ID)
+				}
+			}
+		}
+		return scos
+	}
+
+	// The most recent change has nothing to catch up on, and unknown changes
+	// are rejected.
+	ms := newMockSubscriber()
+	err := cs.ConsensusSetSubscribe(&ms, modules.ConsensusChangeBeginning, cs.tg.StopChan())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.Unsubscribe(&ms)
+	lastChange := ms.updates[len(ms.updates)-1].ID
+	if _, err := cs.ConsensusChangeSince(lastChange); err != errConsensusChangeCurrent {
+		t.Fatal("expected errConsensusChangeCurrent, got", err)
+	}
+	if _, err := cs.ConsensusChangeSince(modules.ConsensusChangeID{255, 255, 255}); err != modules.ErrInvalidConsensusChangeID {
+		t.Fatal("expected ErrInvalidConsensusChangeID, got", err)
+	}
+
+	// Reorg the consensus set and catch up with a single change.
+	oldHeight := cs.dbBlockHeight()
+	rs.extend()
+	cc, err := cs.ConsensusChangeSince(lastChange)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cc.RevertedBlocks) != int(oldHeight) {
+		t.Fatalf("expected %v reverted blocks, got %v", oldHeight, len(cc.RevertedBlocks))
+	}
+	if len(cc.AppliedBlocks) != int(cs.dbBlockHeight()) {
+		t.Fatalf("expected %v applied blocks, got %v", cs.dbBlockHeight(), len(cc.AppliedBlocks))
+	}
+	if cc.AppliedBlocks[len(cc.AppliedBlocks)-1].ID() != cs.dbCurrentBlockID() {
+		t.Fatal("change doesn't end with the current block")
+	}
+
+	// The change should leave the subscriber with the same outputs as a
+	// subscriber that starts from scratch.
+	caughtUp := outputs(append(ms.updates, cc))
+	fresh := newMockSubscriber()
+	err = cs.ConsensusSetSubscribe(&fresh, modules.ConsensusChangeBeginning, cs.tg.StopChan())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.Unsubscribe(&fresh)
+	if !reflect.DeepEqual(caughtUp, outputs(fresh.updates)) {
+		t.Fatal("outputs of caught up subscriber don't match the consensus set")
+	}
+	if cc.ID != fresh.updates[len(fresh.updates)-1].ID {
+		t.Fatal("change doesn't have the id of the most recent change")
+	}
+}
+
+// replaySubscriber is a mockSubscriber that tracks how many subscribers are
+// processing changes at the same time.
+type replaySubscriber struct {
+	mockSubscriber
+	active    *int32
+	maxActive *int32
+}
+
+// ProcessConsensusChange adds a consensus change to the subscriber and
+// records the number of subscribers that are processing changes.
+func (rs *replaySubscriber) ProcessConsensusChange(cc modules.ConsensusChange) {
+	active := atomic.AddInt32(rs.active, 1)
+	defer atomic.AddInt32(rs.active, -1)
+	for {
+		prev := atomic.LoadInt32(rs.maxActive)
+		if active <= prev || atomic.CompareAndSwapInt32(rs.maxActive, prev, active) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	rs.mockSubscriber.ProcessConsensusChange(cc)
+}
+
+// TestConcurrentReplays checks that no more than maxConcurrentReplays
+// subscribers are fed the blockchain from the genesis block at the same time,
+// and that every subscriber still receives all changes.
+func TestConcurrentReplays(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	var active, maxActive int32
+	subs := make([]*replaySubscriber, maxConcurrentReplays+2)
+	var wg sync.WaitGroup
+	for i := range subs {
+		subs[i] = &replaySubscriber{active: &active, maxActive: &maxActive}
+		wg.Add(1)
+		go func(rs *replaySubscriber) {
+			defer wg.Done()
+			if err := cst.cs.ConsensusSetSubscribe(rs, modules.ConsensusChangeBeginning, cst.cs.tg.StopChan()); err != nil {
+				t.Error(err)
+			}
+		}(subs[i])
+	}
+	wg.Wait()
+
+	if maxActive > int32(maxConcurrentReplays) {
+		t.Fatalf("expected at most %v concurrent replays, got %v", maxConcurrentReplays, maxActive)
+	}
+	for _, rs := range subs[1:] {
+		if len(rs.updates) != len(subs[0].updates) {
+			t.Fatal("subscribers received a different number of changes")
+		}
+		for i := range rs.updates {
+			if rs.updates[i].ID != subs[0].updates[i].ID {
+				t.Fatal("subscribers received different changes")
+			}
+		}
+	}
+}
@@ -26,6 +26,12 @@ var (
 	errNilProcBlock      = errors.New("nil processed block was fetched from the database")
 	errSendBlocksStalled = errors.New("SendBlocks RPC timed and never received any blocks")
 
+	// tipRecentThreshold is the maximum age, in seconds, of the current block
+	// for which TipRecent reports true. Blocks are found every BlockFrequency
+	// seconds on average, so a gap of six intervals happens by chance in less
+	// than 0.3% of the cases.
+	tipRecentThreshold = types.Timestamp(6 * types.BlockFrequency)
+
 	// ibdLoopDelay is the time that threadedInitialBlockchainDownload waits
 	// between attempts to synchronize with the network if the last attempt
 	// failed.
@@ -226,6 +232,7 @@ func (cs *ConsensusSet) managedReceiveBlocks(conn modules.PeerConn) (returnErr e
 		if extended {
 			chainExtended = true
 		}
+		cs.managedNoteBlockFromPeer(conn.RPCAddr(), newBlocks[len(newBlocks)-1].ID())
 		// ErrNonExtendingBlock must be ignored until headers-first block
 		// sharing is implemented, block already in database should also be
 		// ignored.
@@ -420,12 +427,26 @@ func (cs *ConsensusSet) threadedRPCRelayHeader(conn modules.PeerConn) error {
 	}
 
 	// Start verification inside of a bolt View tx.
+	var parentHeight types.BlockHeight
 	cs.mu.RLock()
 	err = cs.db.View(func(tx *bolt.Tx) error {
 		// Do some relatively inexpensive checks to validate the header
-		return cs.validateHeader(boltTxWrapper{tx}, h)
+		if err := cs.validateHeader(boltTxWrapper{tx}, h); err != nil {
+			return err
+		}
+		parent, err := getBlockMap(tx, h.ParentID)
+		if err != nil {
+			return err
+		}
+		parentHeight = parent.Height
+		return nil
 	})
 	cs.mu.RUnlock()
+	if err == nil {
+		// The peer announced a valid header, so it has a block at the height
+		// after the header's parent.
+		cs.managedNotePeerHeight(conn.RPCAddr(), parentHeight+1)
+	}
 	// WARN: orphan multithreading logic (dangerous areas, see below)
 	//
 	// If the header is valid and extends the heaviest chain, fetch the
@@ -530,6 +551,7 @@ func (cs *ConsensusSet) managedReceiveBlock(id types.BlockID) modules.RPCFunc {
 		if chainExtended {
 			cs.managedBroadcastBlock(block)
 		}
+		cs.managedNoteBlockFromPeer(conn.RPCAddr(), block.ID())
 		if err != nil {
 			return err
 		}
@@ -639,3 +661,22 @@ func (cs *ConsensusSet) Synced() bool {
 	defer cs.mu.RUnlock()
 	return cs.synced
 }
+
+// TipRecent returns true if the timestamp of the current block is no more than
+// six block intervals in the past. Unlike Synced, which only reports whether
+// the initial blockchain download has finished, TipRecent detects a consensus
+// set that stopped receiving blocks, e.g. because it lost all of its peers.
+func (cs *ConsensusSet) TipRecent() bool {
+	err := cs.tg.Add()
+	if err != nil {
+		return false
+	}
+	defer cs.tg.Done()
+
+	var timestamp types.Timestamp
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		timestamp = currentProcessedBlock(tx).Block.Timestamp
+		return nil
+	})
+	return timestamp+tipRecentThreshold >= types.CurrentTimestamp()
+}
@@ -371,6 +371,65 @@ func (cs *ConsensusSet) TryTransactionSet(txns []types.Transaction) (modules.Con
 	return cs.tryTransactionSet(txns)
 }
 
+// ValidTransactionSet checks whether the transactions would be valid if they
+// were added in the next block, in order. Each transaction is applied to a
+// temporary copy of the consensus set after it has been validated, so that
+// later transactions can spend the outputs of earlier ones. The consensus set
+// itself is not modified. If a transaction is invalid, a
+// modules.TransactionSetError identifying it is returned. The size of the
+// transactions and the set is not checked.
+func (cs *ConsensusSet) ValidTransactionSet(txns []types.Transaction) error {
+	err := cs.tg.Add()
+	if err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	// As in tryTransactionSet, the bolt tx is always rolled back by returning
+	// an error.
+	errSuccess := errors.New("success")
+	err = cs.db.Update(func(tx *bolt.Tx) error {
+		diffHolder := new(processedBlock)
+		diffHolder.Height = blockHeight(tx)
+		for i, txn := range txns {
+			if err := validTransaction(tx, txn); err != nil {
+				return modules.TransactionSetError{
+					Index: i,
+					ID:    txn.ID(),
+					Err:   err,
+				}
+			}
+			applyTransaction(tx, diffHolder, txn)
+		}
+		return errSuccess
+	})
+	if err == errSuccess {
+		return nil
+	}
+	return err
+}
+
+// TransactionEffect returns the siacoin output diffs that the transaction would
+// produce if it were added in the next block, without changing the consensus
+// set. An error is returned IFF the transaction is not valid in the current
+// consensus set. The size of the transaction is not checked.
+func (cs *ConsensusSet) TransactionEffect(t types.Transaction) ([]modules.SiacoinOutputDiff, error) {
+	err := cs.tg.Add()
+	if err != nil {
+		return nil, err
+	}
+	defer cs.tg.Done()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	cc, err := cs.tryTransactionSet([]types.Transaction{t})
+	if err != nil {
+		return nil, err
+	}
+	return cc.SiacoinOutputDiffs, nil
+}
+
 // LockedTryTransactionSet calls fn while under read-lock, passing it a
 // version of TryTransactionSet that can be called under read-lock. This fixes
 // an edge case in the transaction pool.
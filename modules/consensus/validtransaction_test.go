@@ -5,6 +5,7 @@ import (
 
 	"gitlab.com/NebulousLabs/Sia/build"
 	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/types"
 	"gitlab.com/NebulousLabs/fastrand"
 
@@ -79,6 +80,120 @@ func TestTryInvalidTransactionSet(t *testing.T) {
 	}
 }
 
+// TestTransactionEffect checks that TransactionEffect reports the siacoin
+// output diffs of a transaction without changing the consensus set.
+func TestTransactionEffect(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+	initialHash := cst.cs.dbConsensusChecksum()
+
+	// The wallet sends coins using a parent transaction that creates an
+	// output of the exact amount, which is then spent by the second
+	// transaction.
+	txns, err := cst.wallet.SendSiacoins(types.NewCurrency64(1e6), types.UnlockHash{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := txns[0]
+	diffs, err := cst.cs.TransactionEffect(parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cst.cs.dbConsensusChecksum() != initialHash {
+		t.Error("TransactionEffect did not restore order")
+	}
+	if len(diffs) != len(parent.SiacoinInputs)+len(parent.SiacoinOutputs) {
+		t.Fatalf("expected %v diffs, got %v", len(parent.SiacoinInputs)+len(parent.SiacoinOutputs), len(diffs))
+	}
+	var created bool
+	for _, diff := range diffs {
+		if diff.Direction == modules.DiffApply && diff.ID == parent.SiacoinOutputID(0) {
+			created = diff.SiacoinOutput.Value.Equals(parent.SiacoinOutputs[0].Value)
+		}
+	}
+	if !created {
+		t.Error("diffs do not contain the output created by the transaction")
+	}
+
+	// The child spends an output that is not in the consensus set yet.
+	if _, err := cst.cs.TransactionEffect(txns[len(txns)-1]); err == nil {
+		t.Error("transaction with an unconfirmed parent was not rejected")
+	}
+
+	// An invalid transaction should be rejected.
+	_, err = cst.cs.TransactionEffect(types.Transaction{
+		SiacoinInputs: []types.SiacoinInput{{}},
+	})
+	if err == nil {
+		t.Error("invalid transaction was not rejected")
+	}
+}
+
+// TestValidTransactionSet checks that ValidTransactionSet validates dependent
+// transactions together and reports the invalid transaction of a set.
+func TestValidTransactionSet(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cst, err := createConsensusSetTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+	initialHash := cst.cs.dbConsensusChecksum()
+
+	// The wallet sends coins using a parent transaction, so the child is
+	// only valid together with the parent.
+	txns, err := cst.wallet.SendSiacoins(types.NewCurrency64(1e6), types.UnlockHash{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) < 2 {
+		t.Fatal("expected a dependent transaction set")
+	}
+	if err := cst.cs.ValidTransactionSet(txns); err != nil {
+		t.Fatal(err)
+	}
+	if cst.cs.dbConsensusChecksum() != initialHash {
+		t.Error("ValidTransactionSet did not restore order")
+	}
+
+	// Without its parent, the child is invalid.
+	child := txns[len(txns)-1]
+	err = cst.cs.ValidTransactionSet([]types.Transaction{child})
+	tse, ok := err.(modules.TransactionSetError)
+	if !ok {
+		t.Fatal("expected a TransactionSetError, got", err)
+	}
+	if tse.Index != 0 || tse.ID != child.ID() {
+		t.Errorf("expected transaction 0 (%v) to be reported, got %v (%v)", child.ID(), tse.Index, tse.ID)
+	}
+
+	// An invalid transaction after the valid set is reported with its index.
+	invalid := types.Transaction{
+		SiacoinInputs: []types.SiacoinInput{{}},
+	}
+	err = cst.cs.ValidTransactionSet(append(txns, invalid))
+	tse, ok = err.(modules.TransactionSetError)
+	if !ok {
+		t.Fatal("expected a TransactionSetError, got", err)
+	}
+	if tse.Index != len(txns) || tse.ID != invalid.ID() {
+		t.Errorf("expected transaction %v to be reported, got %v", len(txns), tse.Index)
+	}
+	if cst.cs.dbConsensusChecksum() != initialHash {
+		t.Error("ValidTransactionSet did not restore order")
+	}
+}
+
 // TestStorageProofBoundaries creates file contracts and submits storage proofs
 // for them, probing segment boundaries (first segment, last segment,
 // incomplete segment, etc.).
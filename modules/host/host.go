@@ -122,6 +122,7 @@ type Host struct {
 	atomicFormContractCalls uint64
 	atomicRenewCalls        uint64
 	atomicReviseCalls       uint64
+	atomicSectorRootsCalls  uint64
 	atomicSettingsCalls     uint64
 	atomicUnrecognizedCalls uint64
 
@@ -317,6 +318,11 @@ func New(cs modules.ConsensusSet, g modules.Gateway, tpool modules.TransactionPo
 	return newHost(modules.ProdDependencies, cs, g, tpool, wallet, address, persistDir)
 }
 
+// NewCustomHost returns an initialized Host using the provided dependencies.
+func NewCustomHost(deps modules.Dependencies, cs modules.ConsensusSet, g modules.Gateway, tpool modules.TransactionPool, wallet modules.Wallet, address string, persistDir string) (*Host, error) {
+	return newHost(deps, cs, g, tpool, wallet, address, persistDir)
+}
+
 // Close shuts down the host.
 func (h *Host) Close() error {
 	return h.tg.Stop()
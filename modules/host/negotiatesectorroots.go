@@ -0,0 +1,37 @@
+package host
+
+import (
+	"net"
+	"time"
+
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/encoding"
+	"gitlab.com/NebulousLabs/Sia/modules"
+)
+
+// managedRPCSectorRoots sends the sector roots of a file contract to the
+// renter, allowing the renter to rebuild its local copy of the roots. The
+// renter has to prove that it owns the contract in the same way as for the
+// recent revision exchange, which also provides the renter with the revision
+// that the roots belong to.
+func (h *Host) managedRPCSectorRoots(conn net.Conn) error {
+	// Perform the recent revision exchange, which authenticates the renter
+	// and returns the storage obligation with a lock on it.
+	_, so, err := h.managedRPCRecentRevision(conn)
+	if err != nil {
+		return extendErr("failed RPCRecentRevision during RPCSectorRoots: ", err)
+	}
+	defer h.managedUnlockStorageObligation(so.id())
+
+	// Send the roots.
+	roots := so.SectorRoots
+	if h.dependencies.Disrupt("CorruptSectorRoots") && len(roots) > 0 {
+		roots = append([]crypto.Hash{{}}, roots[1:]...)
+	}
+	conn.SetDeadline(time.Now().Add(modules.NegotiateSectorRootsTime))
+	err = encoding.WriteObject(conn, roots)
+	if err != nil {
+		return extendErr("failed to write sector roots: ", ErrorConnection(err.Error()))
+	}
+	return nil
+}
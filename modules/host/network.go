@@ -290,6 +290,9 @@ func (h *Host) threadedHandleConn(conn net.Conn) {
 	case modules.RPCReviseContract:
 		atomic.AddUint64(&h.atomicReviseCalls, 1)
 		err = extendErr("incoming RPCReviseContract failed: ", h.managedRPCReviseContract(conn))
+	case modules.RPCSectorRoots:
+		atomic.AddUint64(&h.atomicSectorRootsCalls, 1)
+		err = extendErr("incoming RPCSectorRoots failed: ", h.managedRPCSectorRoots(conn))
 	case modules.RPCSettings:
 		atomic.AddUint64(&h.atomicSettingsCalls, 1)
 		err = extendErr("incoming RPCSettings failed: ", h.managedRPCSettings(conn))
@@ -351,6 +354,7 @@ func (h *Host) NetworkMetrics() modules.HostNetworkMetrics {
 		FormContractCalls: atomic.LoadUint64(&h.atomicFormContractCalls),
 		RenewCalls:        atomic.LoadUint64(&h.atomicRenewCalls),
 		ReviseCalls:       atomic.LoadUint64(&h.atomicReviseCalls),
+		SectorRootsCalls:  atomic.LoadUint64(&h.atomicSectorRootsCalls),
 		SettingsCalls:     atomic.LoadUint64(&h.atomicSettingsCalls),
 		UnrecognizedCalls: atomic.LoadUint64(&h.atomicUnrecognizedCalls),
 	}
@@ -96,6 +96,12 @@ const (
 	// that both the host and the renter can have time to process large Merkle
 	// tree calculations that may be involved with renewing a file contract.
 	NegotiateRenewContractTime = 600 * time.Second
+
+	// NegotiateSectorRootsTime defines the amount of time that the renter and
+	// host have to transfer the sector roots of a contract. The time is set
+	// high enough that the roots of a large contract can be piped through a
+	// connection that is running over Tor.
+	NegotiateSectorRootsTime = 600 * time.Second
 )
 
 var (
@@ -164,6 +170,11 @@ var (
 	// contract.
 	RPCReviseContract = types.Specifier{'R', 'e', 'v', 'i', 's', 'e', 'C', 'o', 'n', 't', 'r', 'a', 'c', 't', 2}
 
+	// RPCSectorRoots is the specifier for requesting the sector roots of a
+	// contract from the host. It is only supported by hosts running v1.3.3 or
+	// later.
+	RPCSectorRoots = types.Specifier{'S', 'e', 'c', 't', 'o', 'r', 'R', 'o', 'o', 't', 's'}
+
 	// RPCSettings is the specifier for requesting settings from the host.
 	RPCSettings = types.Specifier{'S', 'e', 't', 't', 'i', 'n', 'g', 's', 2}
 
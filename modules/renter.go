@@ -67,12 +67,94 @@ type Allowance struct {
 	RenewWindow types.BlockHeight `json:"renewwindow"`
 }
 
+// AutoTopUp controls how the contractor extends the allowance with wallet
+// funds when the unspent allowance runs low. Whenever the unspent allowance
+// drops below Threshold, the allowance funds are increased by Amount, until a
+// total of MaxSpend has been added. Spent is the amount that has been added
+// since the settings were last changed. A zero MaxSpend disables the top-up.
+type AutoTopUp struct {
+	Threshold types.Currency `json:"threshold"`
+	Amount    types.Currency `json:"amount"`
+	MaxSpend  types.Currency `json:"maxspend"`
+	Spent     types.Currency `json:"spent"`
+}
+
+// PeriodAlignment controls whether the contractor aligns the boundaries of
+// allowance periods to multiples of the period length, counted from Epoch,
+// instead of starting the first period when the allowance is set. Aligned
+// periods start at predictable heights, regardless of when the allowance was
+// set or changed.
+type PeriodAlignment struct {
+	Enabled bool              `json:"enabled"`
+	Epoch   types.BlockHeight `json:"epoch"`
+}
+
+// SpendingAlert reports how much of the allowance has been spent in the
+// current period relative to the spending alert threshold. The contractor
+// warns once SpentFraction reaches Threshold; Triggered indicates that the
+// warning has been sent. A zero Threshold disables the alert.
+type SpendingAlert struct {
+	Threshold     float64 `json:"threshold"`
+	SpentFraction float64 `json:"spentfraction"`
+	Triggered     bool    `json:"triggered"`
+}
+
+// PriceCeilings are hard limits on the prices of hosts that the contractor
+// forms and renews contracts with, regardless of how the hosts are ranked.
+// MaxStoragePrice is per byte per block, MaxDownloadPrice and MaxUploadPrice
+// are per byte. A zero ceiling is disabled.
+type PriceCeilings struct {
+	MaxStoragePrice  types.Currency `json:"maxstorageprice"`
+	MaxDownloadPrice types.Currency `json:"maxdownloadprice"`
+	MaxUploadPrice   types.Currency `json:"maxuploadprice"`
+}
+
+// HostCandidate describes a host that the contractor considered during its
+// most recent contract formation pass. Score is the host's score at the time.
+// Reason explains why no contract was formed with the host; it is empty for
+// selected hosts.
+type HostCandidate struct {
+	PublicKey  types.SiaPublicKey `json:"publickey"`
+	NetAddress NetAddress         `json:"netaddress"`
+	Score      types.Currency     `json:"score"`
+	Selected   bool               `json:"selected"`
+	Reason     string             `json:"reason"`
+}
+
+// ContractRedundancyStatus reports whether enough contracts are good for upload
+// for the renter's data to be safely distributed. Can be one of "healthy" or
+// "insufficient redundancy".
+type ContractRedundancyStatus string
+
+var (
+	// ContractRedundancyStatusHealthy is reported if at least the minimum
+	// number of contracts are good for upload.
+	ContractRedundancyStatusHealthy = ContractRedundancyStatus("healthy")
+
+	// ContractRedundancyStatusInsufficient is reported if fewer than the
+	// minimum number of contracts are good for upload.
+	ContractRedundancyStatusInsufficient = ContractRedundancyStatus("insufficient redundancy")
+)
+
+// ContractRedundancy reports how many contracts are good for upload compared
+// to the minimum number of contracts. When comparing, the minimum is capped at
+// the number of hosts in the allowance.
+type ContractRedundancy struct {
+	MinContracts    uint64                   `json:"mincontracts"`
+	UploadContracts uint64                   `json:"uploadcontracts"`
+	Status          ContractRedundancyStatus `json:"status"`
+}
+
 // ContractUtility contains metrics internal to the contractor that reflect the
 // utility of a given contract.
 type ContractUtility struct {
 	GoodForUpload bool
 	GoodForRenew  bool
 	Locked        bool // Locked utilities can only be set to false.
+
+	// Reason explains why the contract is not GoodForUpload or not
+	// GoodForRenew. It is empty for contracts in good standing.
+	Reason string
 }
 
 // DownloadInfo provides information about a file that has been requested for
@@ -258,6 +340,12 @@ type RenterContract struct {
 	StorageSpending  types.Currency
 	UploadSpending   types.Currency
 
+	// DownloadedBytes and UploadedBytes are the amounts of data that were
+	// transferred using the contract. Together with the spending fields they
+	// allow checking the effective price per byte charged by the host.
+	DownloadedBytes uint64
+	UploadedBytes   uint64
+
 	// Utility contains utility information about the renter.
 	Utility ContractUtility
 
@@ -283,6 +371,35 @@ type RenterContract struct {
 	SiafundFee  types.Currency
 }
 
+// RemainingFunds returns the amount of money that the renter can still spend
+// on storage, uploads and downloads before the contract needs to be renewed.
+// It is the part of TotalCost that was neither paid as fees nor spent, which
+// never includes the host's collateral. Because the host's collateral and the
+// fees are not part of the renter's payout, the result is capped at the
+// renter's payout in the most recent revision.
+func (rc RenterContract) RemainingFunds() types.Currency {
+	used := rc.ContractFee.Add(rc.TxnFee).Add(rc.SiafundFee).
+		Add(rc.StorageSpending).Add(rc.UploadSpending).Add(rc.DownloadSpending)
+	if used.Cmp(rc.TotalCost) >= 0 {
+		return types.ZeroCurrency
+	}
+	remaining := rc.TotalCost.Sub(used)
+	if remaining.Cmp(rc.RenterFunds) > 0 {
+		return rc.RenterFunds
+	}
+	return remaining
+}
+
+// RenterContractStatus pairs a RenterContract with the reachability of the
+// host that the contract was formed with.
+type RenterContractStatus struct {
+	RenterContract
+
+	// HostOnline indicates whether the host is currently considered online
+	// according to the scan history of the hostdb.
+	HostOnline bool
+}
+
 // ContractorSpending contains the metrics about how much the Contractor has
 // spent during the current billing period.
 type ContractorSpending struct {
@@ -326,22 +443,48 @@ type Renter interface {
 	// AllHosts returns the full list of hosts known to the renter.
 	AllHosts() []HostDBEntry
 
+	// CancelContract cancels a specific contract of the renter. The contract
+	// will no longer be used for uploads or renewed, but can still be used to
+	// download existing data.
+	CancelContract(id types.FileContractID) error
+
 	// Close closes the Renter.
 	Close() error
 
+	// AllContracts returns the active contracts of the renter's
+	// hostContractor along with the online status of their hosts.
+	AllContracts() []RenterContractStatus
+
 	// Contracts returns the staticContracts of the renter's hostContractor.
 	Contracts() []RenterContract
 
 	// OldContracts returns the oldContracts of the renter's hostContractor.
 	OldContracts() []RenterContract
 
+	// OldContractReasons returns why each of the oldContracts was retired,
+	// e.g. because it expired, was canceled or was renewed.
+	OldContractReasons() map[types.FileContractID]string
+
+	// ContractHealth returns a score between 0 and 1 that combines the
+	// remaining funds, host status, time to expiry and utility of a
+	// contract, along with a bool indicating if the contract exists.
+	ContractHealth(id types.FileContractID) (float64, bool)
+
 	// ContractUtility provides the contract utility for a given host key.
 	ContractUtility(pk types.SiaPublicKey) (ContractUtility, bool)
 
+	// ContractRedundancy reports whether enough contracts are good for
+	// upload for the renter's data to be safely distributed.
+	ContractRedundancy() ContractRedundancy
+
 	// CurrentPeriod returns the height at which the current allowance period
 	// began.
 	CurrentPeriod() types.BlockHeight
 
+	// CurrentPeriodEnd returns the height at which the current allowance
+	// period ends and the next one begins.
+	CurrentPeriodEnd() types.BlockHeight
+
 	// PeriodSpending returns the amount spent on contracts in the current
 	// billing period.
 	PeriodSpending() ContractorSpending
@@ -373,6 +516,22 @@ type Renter interface {
 	// Host provides the DB entry and score breakdown for the requested host.
 	Host(pk types.SiaPublicKey) (HostDBEntry, bool)
 
+	// AutoTopUp returns the settings of the automatic allowance top-up.
+	AutoTopUp() AutoTopUp
+
+	// FormationCandidates returns the hosts that were considered in the most
+	// recent contract formation pass, along with their scores and whether
+	// they were selected.
+	FormationCandidates() []HostCandidate
+
+	// FormationWorkers returns the maximum number of hosts that the renter
+	// negotiates new contracts with at the same time.
+	FormationWorkers() uint64
+
+	// HostBlacklist returns the hosts that the renter will not form or renew
+	// contracts with.
+	HostBlacklist() []types.SiaPublicKey
+
 	// InitialScanComplete returns a boolean indicating if the initial scan of the
 	// hostdb is completed.
 	InitialScanComplete() (bool, error)
@@ -385,10 +544,33 @@ type Renter interface {
 	// renter.
 	LoadSharedFilesASCII(asciiSia string) ([]string, error)
 
+	// MinContracts returns the number of contracts that need to be good for
+	// upload for the renter's data to be considered safely distributed.
+	MinContracts() uint64
+
+	// PeriodAlignment returns the settings that align the renter's allowance
+	// periods to a fixed epoch.
+	PeriodAlignment() PeriodAlignment
+
+	// PersistInterval returns the interval at which the renter flushes
+	// changes to its contract metadata to disk. Zero means that changes are
+	// written immediately.
+	PersistInterval() time.Duration
+
+	// PriceCeilings returns the maximum prices of hosts that the renter forms
+	// and renews contracts with.
+	PriceCeilings() PriceCeilings
+
 	// PriceEstimation estimates the cost in siacoins of performing various
 	// storage and data operations.
 	PriceEstimation() RenterPriceEstimation
 
+	// RecoverContracts scans the blockchain for unexpired contracts that were
+	// signed with keys derived from the wallet seed and restores them by
+	// contacting their hosts. It is meant to be used after the renter's
+	// contracts were lost.
+	RecoverContracts(seed Seed) error
+
 	// RenameFile changes the path of a file.
 	RenameFile(path, newPath string) error
 
@@ -403,9 +585,50 @@ type Renter interface {
 	// Settings returns the Renter's current settings.
 	Settings() RenterSettings
 
+	// SpendingAlert returns the spending alert threshold and the fraction of
+	// the allowance that has been spent in the current period.
+	SpendingAlert() SpendingAlert
+
+	// SetAutoTopUp sets the settings of the automatic allowance top-up. The
+	// amount that has been added so far is reset.
+	SetAutoTopUp(AutoTopUp) error
+
+	// SetFormationWorkers sets the maximum number of hosts that the renter
+	// negotiates new contracts with at the same time.
+	SetFormationWorkers(uint64) error
+
+	// SetHostBlacklist sets the hosts that the renter will not form or renew
+	// contracts with. Existing contracts with these hosts will not be renewed.
+	SetHostBlacklist(hosts []types.SiaPublicKey) error
+
+	// SetMinContracts sets the number of contracts that need to be good for
+	// upload for the renter's data to be considered safely distributed. While
+	// there are fewer, new contracts are formed before renewing existing ones.
+	SetMinContracts(uint64) error
+
+	// SetPeriodAlignment sets the settings that align the renter's allowance
+	// periods to a fixed epoch. If alignment is enabled, the current period
+	// is realigned immediately.
+	SetPeriodAlignment(PeriodAlignment) error
+
+	// SetPersistInterval sets the interval at which the renter flushes
+	// changes to its contract metadata to disk. A non-zero interval batches
+	// frequent changes; contract revisions are always synced immediately.
+	SetPersistInterval(time.Duration) error
+
+	// SetPriceCeilings sets the maximum prices of hosts that the renter forms
+	// and renews contracts with. Existing contracts with hosts that exceed a
+	// ceiling will not be renewed.
+	SetPriceCeilings(PriceCeilings) error
+
 	// SetSettings sets the Renter's settings.
 	SetSettings(RenterSettings) error
 
+	// SetSpendingAlertThreshold sets the fraction of the allowance that can
+	// be spent in a period before the renter warns about it. A value of zero
+	// disables the alert.
+	SetSpendingAlertThreshold(float64) error
+
 	// ShareFiles creates a '.sia' file that can be shared with others.
 	ShareFiles(paths []string, shareDest string) error
 
@@ -11,6 +11,7 @@ var (
 	errAllowanceNoHosts    = errors.New("hosts must be non-zero")
 	errAllowanceNotSynced  = errors.New("you must be synced to set an allowance")
 	errAllowanceWindowSize = errors.New("renew window must be less than period")
+	errAllowanceWindowTiny = errors.New("renew window is too small to safely renew contracts")
 	errAllowanceZeroPeriod = errors.New("period must be non-zero")
 
 	// ErrAllowanceZeroWindow is returned when the caller requests a
@@ -48,14 +49,8 @@ func (c *Contractor) SetAllowance(a modules.Allowance) error {
 	}
 
 	// sanity checks
-	if a.Hosts == 0 {
-		return errAllowanceNoHosts
-	} else if a.Period == 0 {
-		return errAllowanceZeroPeriod
-	} else if a.RenewWindow == 0 {
-		return ErrAllowanceZeroWindow
-	} else if a.RenewWindow >= a.Period {
-		return errAllowanceWindowSize
+	if err := validateAllowance(a); err != nil {
+		return err
 	} else if !c.cs.Synced() {
 		return errAllowanceNotSynced
 	}
@@ -69,6 +64,11 @@ func (c *Contractor) SetAllowance(a modules.Allowance) error {
 	if reflect.DeepEqual(c.allowance, modules.Allowance{}) {
 		c.currentPeriod = c.blockHeight - a.RenewWindow
 	}
+	// if the periods are aligned, the current period starts at the last
+	// boundary instead, which also realigns it if the period length changed.
+	if c.periodAlign.Enabled {
+		c.currentPeriod = alignedPeriodStart(c.blockHeight, a.Period, c.periodAlign.Epoch)
+	}
 	c.allowance = a
 	err := c.saveSync()
 	c.mu.Unlock()
@@ -77,9 +77,13 @@ func (c *Contractor) SetAllowance(a modules.Allowance) error {
 	}
 
 	// Cycle through all contracts and unlock them again since they might have
-	// been locked by managedCancelAllowance previously.
+	// been locked by managedCancelAllowance previously. Contracts that were
+	// canceled by the user stay locked, and pinned contracts are left as-is.
 	ids := c.staticContracts.IDs()
 	for _, id := range ids {
+		if c.managedIsCanceled(id) || c.managedIsPinned(id) {
+			continue
+		}
 		contract, exists := c.staticContracts.Acquire(id)
 		if !exists {
 			continue
@@ -100,6 +104,23 @@ func (c *Contractor) SetAllowance(a modules.Allowance) error {
 	return nil
 }
 
+// validateAllowance checks that the parameters of a non-empty allowance are
+// sane.
+func validateAllowance(a modules.Allowance) error {
+	if a.Hosts == 0 {
+		return errAllowanceNoHosts
+	} else if a.Period == 0 {
+		return errAllowanceZeroPeriod
+	} else if a.RenewWindow == 0 {
+		return ErrAllowanceZeroWindow
+	} else if a.RenewWindow >= a.Period {
+		return errAllowanceWindowSize
+	} else if a.RenewWindow < minRenewWindow {
+		return errAllowanceWindowTiny
+	}
+	return nil
+}
+
 // managedCancelAllowance handles the special case where the allowance is empty.
 func (c *Contractor) managedCancelAllowance() error {
 	c.log.Println("INFO: canceling allowance")
@@ -142,9 +163,13 @@ func (c *Contractor) managedCancelAllowance() error {
 	// Issue an interrupt to any in-progress contract maintenance thread.
 	c.managedInterruptContractMaintenance()
 
-	// Cycle through all contracts and mark them as !goodForRenew and !goodForUpload
+	// Cycle through all contracts and mark them as !goodForRenew and
+	// !goodForUpload. Pinned contracts are left as-is.
 	ids = c.staticContracts.IDs()
 	for _, id := range ids {
+		if c.managedIsPinned(id) {
+			continue
+		}
 		contract, exists := c.staticContracts.Acquire(id)
 		if !exists {
 			continue
@@ -153,6 +178,7 @@ func (c *Contractor) managedCancelAllowance() error {
 		utility.GoodForRenew = false
 		utility.GoodForUpload = false
 		utility.Locked = true
+		utility.Reason = "allowance was canceled"
 		err := contract.UpdateUtility(utility)
 		c.staticContracts.Return(contract)
 		if err != nil {
@@ -0,0 +1,76 @@
+package contractor
+
+import (
+	"errors"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errZeroTopUpAmount is returned by SetAutoTopUp if the top-up is enabled
+	// but the amount to add is zero.
+	errZeroTopUpAmount = errors.New("auto top-up amount must be greater than zero")
+)
+
+// AutoTopUp returns the settings of the automatic allowance top-up.
+func (c *Contractor) AutoTopUp() modules.AutoTopUp {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.autoTopUp
+}
+
+// SetAutoTopUp sets the settings of the automatic allowance top-up. The amount
+// that has been added to the allowance so far is reset, so the full MaxSpend
+// is available again. A zero MaxSpend disables the top-up.
+func (c *Contractor) SetAutoTopUp(atu modules.AutoTopUp) error {
+	if !atu.MaxSpend.IsZero() && atu.Amount.IsZero() {
+		return errZeroTopUpAmount
+	}
+	atu.Spent = types.ZeroCurrency
+
+	c.mu.Lock()
+	c.autoTopUp = atu
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.log.Printf("INFO: set auto top-up to %v below %v, up to %v", atu.Amount.HumanString(), atu.Threshold.HumanString(), atu.MaxSpend.HumanString())
+	return nil
+}
+
+// managedAutoTopUp increases the allowance funds if the auto top-up is enabled
+// and fundsRemaining dropped below its threshold. The increase is limited by
+// the amount that the top-up may still spend. The top-up is skipped if the
+// wallet is locked, since no contracts could be funded anyways. The amount
+// that was added to the allowance is returned.
+func (c *Contractor) managedAutoTopUp(fundsRemaining types.Currency) types.Currency {
+	c.mu.RLock()
+	atu := c.autoTopUp
+	c.mu.RUnlock()
+	if atu.MaxSpend.IsZero() || fundsRemaining.Cmp(atu.Threshold) >= 0 || atu.Spent.Cmp(atu.MaxSpend) >= 0 {
+		return types.ZeroCurrency
+	}
+	amount := atu.Amount
+	if left := atu.MaxSpend.Sub(atu.Spent); amount.Cmp(left) > 0 {
+		amount = left
+	}
+
+	// The wallet is called without holding the lock.
+	unlocked, err := c.wallet.Unlocked()
+	if err != nil || !unlocked {
+		c.log.Println("WARN: unspent allowance is below the auto top-up threshold, but the wallet is locked")
+		return types.ZeroCurrency
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.allowance.Funds = c.allowance.Funds.Add(amount)
+	c.autoTopUp.Spent = c.autoTopUp.Spent.Add(amount)
+	if err := c.saveSync(); err != nil {
+		c.log.Println("Unable to save the contractor:", err)
+	}
+	c.log.Printf("INFO: auto top-up added %v to the allowance, %v of %v spent", amount.HumanString(), c.autoTopUp.Spent.HumanString(), c.autoTopUp.MaxSpend.HumanString())
+	return amount
+}
@@ -0,0 +1,22 @@
+package contractor
+
+import (
+	"gitlab.com/NebulousLabs/Sia/modules"
+)
+
+// The reasons that are reported for formation candidates that no negotiation
+// was attempted with.
+const (
+	candidateNotAttempted = "not attempted"
+	candidateLowFunds     = "not attempted, not enough allowance funds remaining"
+)
+
+// FormationCandidates returns the hosts that were considered in the most
+// recent contract formation pass, in the order in which they were returned by
+// the hostdb, along with their scores and whether a contract was formed with
+// them.
+func (c *Contractor) FormationCandidates() []modules.HostCandidate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]modules.HostCandidate(nil), c.formationCandidates...)
+}
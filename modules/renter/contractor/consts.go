@@ -16,6 +16,19 @@ var (
 		Testing:  types.BlockHeight(12),
 	}).(types.BlockHeight)
 
+	// defaultContractFormationWorkers is the default maximum number of hosts
+	// that the contractor will negotiate new contracts with at the same time.
+	defaultContractFormationWorkers = build.Select(build.Var{
+		Dev:      uint64(5),
+		Standard: uint64(10),
+		Testing:  uint64(3),
+	}).(uint64)
+
+	// maxContractFormationWorkers is the highest number of hosts that the
+	// contractor can be configured to negotiate new contracts with at the
+	// same time.
+	maxContractFormationWorkers = uint64(50)
+
 	// fileContractMinimumFunding is the lowest percentage of an allowace (on a
 	// per-contract basis) that is allowed to go into funding a contract. If the
 	// allowance is 100 SC per contract (5,000 SC total for 50 contracts, or
@@ -24,6 +37,15 @@ var (
 	// 100SC.
 	fileContractMinimumFunding = float64(0.15)
 
+	// minRenewWindow is the smallest renew window that the contractor will
+	// accept. A smaller window leaves too few blocks to retry a failed renewal
+	// before the contract expires.
+	minRenewWindow = build.Select(build.Var{
+		Dev:      types.BlockHeight(1),
+		Standard: types.BlockHeight(288), // ~2 days
+		Testing:  types.BlockHeight(1),
+	}).(types.BlockHeight)
+
 	// minContractFundRenewalThreshold defines the ratio of remaining funds to
 	// total contract cost below which the contractor will prematurely renew a
 	// contract.
@@ -130,6 +130,21 @@ func (c *Contractor) managedEstimateRenewFundingRequirements(contract modules.Re
 	return estimatedCost, nil
 }
 
+// InterruptMaintenance stops any contract maintenance that is currently in
+// progress and returns once it has stopped. Negotiations that have already
+// started are allowed to finish, so no contract is left half-formed. Calling
+// InterruptMaintenance while no maintenance is running is a no-op. Maintenance
+// resumes with the current settings on the next block or when the allowance is
+// changed.
+func (c *Contractor) InterruptMaintenance() {
+	if err := c.tg.Add(); err != nil {
+		return
+	}
+	defer c.tg.Done()
+	c.log.Println("INFO: interrupting contract maintenance")
+	c.managedInterruptContractMaintenance()
+}
+
 // managedInterruptContractMaintenance will issue an interrupt signal to any
 // running maintenance, stopping that maintenance. If there are multiple threads
 // running maintenance, they will all be stopped.
@@ -186,14 +201,22 @@ func (c *Contractor) managedMarkContractsUtility() error {
 		minScore = lowestScore.Div(scoreLeeway)
 	}
 
-	// Update utility fields for each contract.
+	// Update utility fields for each contract. Pinned contracts and contracts
+	// that were canceled by the user keep their utility.
 	for _, contract := range c.staticContracts.ViewAll() {
+		if c.managedIsPinned(contract.ID) || c.managedIsCanceled(contract.ID) {
+			continue
+		}
 		utility := func() (u modules.ContractUtility) {
+			// Record the current utility of the contract.
+			u = contract.Utility
+
 			// Start the contract in good standing if the utility wasn't
 			// locked.
 			if !u.Locked {
 				u.GoodForUpload = true
 				u.GoodForRenew = true
+				u.Reason = ""
 			}
 
 			host, exists := c.hdb.Host(contract.HostPublicKey)
@@ -201,20 +224,48 @@ func (c *Contractor) managedMarkContractsUtility() error {
 			if !exists {
 				u.GoodForUpload = false
 				u.GoodForRenew = false
+				u.Reason = "host is not in the hostdb"
 				return
 			}
 			// Contract has no utility if the score is poor.
 			if !minScore.IsZero() && c.hdb.ScoreBreakdown(host).Score.Cmp(minScore) < 0 {
 				u.GoodForUpload = false
 				u.GoodForRenew = false
+				u.Reason = "host score is too low"
 				return
 			}
 			// Contract has no utility if the host is offline.
 			if isOffline(host) {
 				u.GoodForUpload = false
 				u.GoodForRenew = false
+				u.Reason = "host is offline"
 				return
 			}
+			// The checks below don't return early, so the reason of the
+			// first check that fails is kept.
+			setReason := func(reason string) {
+				if u.Reason == "" {
+					u.Reason = reason
+				}
+			}
+			// Contract should not be renewed if the host is blacklisted. It
+			// can still be used until it expires.
+			if c.managedIsBlacklisted(contract.HostPublicKey) {
+				u.GoodForRenew = false
+				setReason("host is blacklisted")
+			}
+			// Contract should not be renewed if the host is offline too
+			// often.
+			if c.managedHasLowUptime(host) {
+				u.GoodForRenew = false
+				setReason("host uptime is too low")
+			}
+			// Contract should not be renewed if the host's prices exceed the
+			// price ceilings.
+			if c.managedExceedsPriceCeilings(host) {
+				u.GoodForRenew = false
+				setReason("host prices exceed the price ceilings")
+			}
 			// Contract should not be used for uploading if the time has come to
 			// renew the contract.
 			c.mu.RLock()
@@ -223,6 +274,7 @@ func (c *Contractor) managedMarkContractsUtility() error {
 			c.mu.RUnlock()
 			if blockHeight+renewWindow >= contract.EndHeight {
 				u.GoodForUpload = false
+				setReason("contract is within the renew window")
 				return
 			}
 			return
@@ -233,6 +285,13 @@ func (c *Contractor) managedMarkContractsUtility() error {
 		if err != nil {
 			return err
 		}
+		if utility.GoodForUpload != contract.Utility.GoodForUpload || utility.GoodForRenew != contract.Utility.GoodForRenew {
+			reason := utility.Reason
+			if reason == "" {
+				reason = "contract is in good standing"
+			}
+			c.managedRecordMaintenance(MaintenanceUtilityChanged, contract.ID, contract.HostPublicKey, reason)
+		}
 	}
 	return nil
 }
@@ -240,10 +299,24 @@ func (c *Contractor) managedMarkContractsUtility() error {
 // managedNewContract negotiates an initial file contract with the specified
 // host, saves it, and returns it.
 func (c *Contractor) managedNewContract(host modules.HostDBEntry, contractFunding types.Currency, endHeight types.BlockHeight) (types.Currency, modules.RenterContract, error) {
+	// reject hosts that are blacklisted
+	if c.managedIsBlacklisted(host.PublicKey) {
+		return types.ZeroCurrency, modules.RenterContract{}, errHostBlacklisted
+	}
+	// reject hosts that are offline too often
+	if c.managedHasLowUptime(host) {
+		return types.ZeroCurrency, modules.RenterContract{}, errHostUptimeTooLow
+	}
+	// reject hosts whose prices exceed the price ceilings
+	if c.managedExceedsPriceCeilings(host) {
+		return types.ZeroCurrency, modules.RenterContract{}, errPriceCeilingExceeded
+	}
 	// reject hosts that are too expensive
 	if host.StoragePrice.Cmp(maxStoragePrice) > 0 {
 		return types.ZeroCurrency, modules.RenterContract{}, errTooExpensive
 	}
+	// remember the settings the host advertised before capping them
+	settings := host.HostExternalSettings
 	// cap host.MaxCollateral
 	if host.MaxCollateral.Cmp(maxCollateral) > 0 {
 		host.MaxCollateral = maxCollateral
@@ -255,6 +328,13 @@ func (c *Contractor) managedNewContract(host modules.HostDBEntry, contractFundin
 		return types.ZeroCurrency, modules.RenterContract{}, err
 	}
 
+	// derive the contract key from the wallet seed, so that the contract can
+	// be recovered if the contractor's state is lost
+	sk, err := c.managedContractKey(host.PublicKey, endHeight)
+	if err != nil {
+		return types.ZeroCurrency, modules.RenterContract{}, err
+	}
+
 	// create contract params
 	c.mu.RLock()
 	params := proto.ContractParams{
@@ -263,6 +343,7 @@ func (c *Contractor) managedNewContract(host modules.HostDBEntry, contractFundin
 		StartHeight:   c.blockHeight,
 		EndHeight:     endHeight,
 		RefundAddress: uc.UnlockHash(),
+		SecretKey:     sk,
 	}
 	c.mu.RUnlock()
 
@@ -290,6 +371,7 @@ func (c *Contractor) managedNewContract(host modules.HostDBEntry, contractFundin
 		return contractFunding, modules.RenterContract{}, fmt.Errorf("We already have a contract with host %v", contract.HostPublicKey)
 	}
 	c.pubKeysToContractID[string(contract.HostPublicKey.Key)] = contract.ID
+	c.hostSettings[contract.ID] = settings
 	c.mu.Unlock()
 
 	contractValue := contract.RenterFunds
@@ -331,9 +413,17 @@ func (c *Contractor) managedRenew(sc *proto.SafeContract, contractFunding types.
 	host, ok := c.hdb.Host(contract.HostPublicKey)
 	if !ok {
 		return modules.RenterContract{}, errors.New("no record of that host")
+	} else if c.managedIsBlacklisted(host.PublicKey) {
+		return modules.RenterContract{}, errHostBlacklisted
+	} else if c.managedHasLowUptime(host) {
+		return modules.RenterContract{}, errHostUptimeTooLow
+	} else if c.managedExceedsPriceCeilings(host) {
+		return modules.RenterContract{}, errPriceCeilingExceeded
 	} else if host.StoragePrice.Cmp(maxStoragePrice) > 0 {
 		return modules.RenterContract{}, errTooExpensive
 	}
+	// remember the settings the host advertised before capping them
+	settings := host.HostExternalSettings
 	// cap host.MaxCollateral
 	if host.MaxCollateral.Cmp(maxCollateral) > 0 {
 		host.MaxCollateral = maxCollateral
@@ -345,6 +435,12 @@ func (c *Contractor) managedRenew(sc *proto.SafeContract, contractFunding types.
 		return modules.RenterContract{}, err
 	}
 
+	// derive the key of the renewed contract from the wallet seed
+	sk, err := c.managedContractKey(host.PublicKey, newEndHeight)
+	if err != nil {
+		return modules.RenterContract{}, err
+	}
+
 	// create contract params
 	c.mu.RLock()
 	params := proto.ContractParams{
@@ -353,6 +449,7 @@ func (c *Contractor) managedRenew(sc *proto.SafeContract, contractFunding types.
 		StartHeight:   c.blockHeight,
 		EndHeight:     newEndHeight,
 		RefundAddress: uc.UnlockHash(),
+		SecretKey:     sk,
 	}
 	c.mu.RUnlock()
 
@@ -373,6 +470,7 @@ func (c *Contractor) managedRenew(sc *proto.SafeContract, contractFunding types.
 	c.mu.Lock()
 	c.contractIDToPubKey[newContract.ID] = newContract.HostPublicKey
 	c.pubKeysToContractID[string(newContract.HostPublicKey.Key)] = newContract.ID
+	c.hostSettings[newContract.ID] = settings
 	c.mu.Unlock()
 
 	return newContract, nil
@@ -386,8 +484,13 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 	amount := renewInstructions.amount
 
 	// Mark the contract as being renewed, and defer logic to unmark it
-	// once renewing is complete.
+	// once renewing is complete. A contract that is already being renewed,
+	// e.g. by RenewContract, is skipped.
 	c.mu.Lock()
+	if c.renewing[id] {
+		c.mu.Unlock()
+		return types.ZeroCurrency, errContractRenewing
+	}
 	c.renewing[id] = true
 	c.mu.Unlock()
 	defer func() {
@@ -430,6 +533,13 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 	// on renewing it and set goodForRenew to false.
 	newContract, errRenew := c.managedRenew(oldContract, amount, endHeight)
 	if errRenew != nil {
+		c.managedNotifySubscribers(ContractEvent{
+			Type:          ContractFailed,
+			ID:            id,
+			HostPublicKey: oldContract.Metadata().HostPublicKey,
+			Err:           errRenew,
+		})
+
 		// Increment the number of failed renews for the contract if it
 		// was the host's fault.
 		if modules.IsHostsFault(errRenew) {
@@ -449,6 +559,7 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 			oldUtility.GoodForRenew = false
 			oldUtility.GoodForUpload = false
 			oldUtility.Locked = true
+			oldUtility.Reason = "too many consecutive failed renew attempts"
 			err := oldContract.UpdateUtility(oldUtility)
 			if err != nil {
 				c.log.Println("WARN: failed to mark contract as !goodForRenew:", err)
@@ -467,6 +578,11 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 		return types.ZeroCurrency, errors.AddContext(errRenew, "contract renewal with host was unsuccessful")
 	}
 	c.log.Printf("Renewed contract %v\n", id)
+	c.managedNotifySubscribers(ContractEvent{
+		Type:          ContractRenewed,
+		ID:            newContract.ID,
+		HostPublicKey: newContract.HostPublicKey,
+	})
 
 	// Update the utility values for the new contract, and for the old
 	// contract.
@@ -480,6 +596,7 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 	}
 	oldUtility.GoodForRenew = false
 	oldUtility.GoodForUpload = false
+	oldUtility.Reason = "contract was renewed"
 	if err := oldContract.UpdateUtility(oldUtility); err != nil {
 		c.log.Println("Failed to update the contract utilities", err)
 		return amount, nil // Error is not returned because the renew succeeded.
@@ -493,6 +610,7 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 	c.staticContracts.Delete(oldContract)
 	// Store the contract in the record of historic contracts.
 	c.oldContracts[id] = oldContract.Metadata()
+	c.oldContractReasons[id] = oldContractRenewed
 	// Link Contracts
 	c.renewedFrom[newContract.ID] = id
 	c.renewedTo[id] = newContract.ID
@@ -582,7 +700,7 @@ func (c *Contractor) threadedContractMaintenance() {
 		// Skip any contracts which do not exist or are otherwise unworthy for
 		// renewal.
 		utility, ok := c.managedContractUtility(contract.ID)
-		if !ok || !utility.GoodForRenew {
+		if !ok || !utility.GoodForRenew || c.managedIsPinned(contract.ID) {
 			continue
 		}
 
@@ -661,6 +779,41 @@ func (c *Contractor) threadedContractMaintenance() {
 		fundsRemaining = allowance.Funds.Sub(spending.TotalAllocated)
 	}
 
+	// Extend the allowance with wallet funds if the auto top-up is enabled and
+	// the remaining funds are running low.
+	if added := c.managedAutoTopUp(fundsRemaining); !added.IsZero() {
+		allowance.Funds = allowance.Funds.Add(added)
+		fundsRemaining = fundsRemaining.Add(added)
+	}
+
+	// If fewer contracts than the minimum are good for upload, the data is
+	// not safely distributed yet. Forming new contracts then takes priority
+	// over renewing existing ones.
+	c.mu.RLock()
+	minContracts := c.effectiveMinContracts()
+	c.mu.RUnlock()
+	formFirst := uint64(c.managedUploadContracts()) < minContracts
+	if formFirst {
+		c.log.Printf("fewer than %v contracts are good for upload, forming new contracts before renewing", minContracts)
+		c.managedFormNeededContracts(fundsRemaining, endHeight)
+
+		// Return here if an interrupt or kill signal has been sent.
+		select {
+		case <-c.tg.StopChan():
+			return
+		case <-c.interruptMaintenance:
+			return
+		default:
+		}
+
+		// Update the remaining funds to account for the new contracts.
+		spending = c.PeriodSpending()
+		fundsRemaining = types.ZeroCurrency
+		if spending.TotalAllocated.Cmp(allowance.Funds) < 0 {
+			fundsRemaining = allowance.Funds.Sub(spending.TotalAllocated)
+		}
+	}
+
 	// Go through the contracts we've assembled for renewal. Any contracts that
 	// need to be renewed because they are expiring (renewSet) get priority over
 	// contracts that need to be renewed because they have exhausted their funds
@@ -671,16 +824,24 @@ func (c *Contractor) threadedContractMaintenance() {
 	// user to the fact that they do not have enough money to keep their
 	// contracts going in the event that we run out of funds.
 	for _, renewal := range renewSet {
+		contract, _ := c.staticContracts.View(renewal.id)
+
 		// Skip this renewal if we don't have enough funds remaining.
 		if renewal.amount.Cmp(fundsRemaining) > 0 {
+			c.managedRecordMaintenance(MaintenanceRenewSkipped, renewal.id, contract.HostPublicKey, "not enough allowance funds remaining")
 			continue
 		}
 
-		// Renew one contract. The error is ignored because the renew function
-		// already will have logged the error, and in the event of an error,
-		// 'fundsSpent' will return '0'.
-		fundsSpent, _ := c.managedRenewContract(renewal, currentPeriod, allowance, blockHeight, endHeight)
+		// Renew one contract. The error is only recorded because the renew
+		// function already will have logged the error, and in the event of an
+		// error, 'fundsSpent' will return '0'.
+		fundsSpent, err := c.managedRenewContract(renewal, currentPeriod, allowance, blockHeight, endHeight)
 		fundsRemaining = fundsRemaining.Sub(fundsSpent)
+		if err != nil {
+			c.managedRecordMaintenance(MaintenanceRenewFailed, renewal.id, contract.HostPublicKey, err.Error())
+		} else {
+			c.managedRecordMaintenance(MaintenanceRenewed, renewal.id, contract.HostPublicKey, "contract is about to expire")
+		}
 
 		// Return here if an interrupt or kill signal has been sent.
 		select {
@@ -692,16 +853,24 @@ func (c *Contractor) threadedContractMaintenance() {
 		}
 	}
 	for _, renewal := range refreshSet {
+		contract, _ := c.staticContracts.View(renewal.id)
+
 		// Skip this renewal if we don't have enough funds remaining.
 		if renewal.amount.Cmp(fundsRemaining) > 0 {
+			c.managedRecordMaintenance(MaintenanceRenewSkipped, renewal.id, contract.HostPublicKey, "not enough allowance funds remaining")
 			continue
 		}
 
-		// Renew one contract. The error is ignored because the renew function
-		// already will have logged the error, and in the event of an error,
-		// 'fundsSpent' will return '0'.
-		fundsSpent, _ := c.managedRenewContract(renewal, currentPeriod, allowance, blockHeight, endHeight)
+		// Renew one contract. The error is only recorded because the renew
+		// function already will have logged the error, and in the event of an
+		// error, 'fundsSpent' will return '0'.
+		fundsSpent, err := c.managedRenewContract(renewal, currentPeriod, allowance, blockHeight, endHeight)
 		fundsRemaining = fundsRemaining.Sub(fundsSpent)
+		if err != nil {
+			c.managedRecordMaintenance(MaintenanceRenewFailed, renewal.id, contract.HostPublicKey, err.Error())
+		} else {
+			c.managedRecordMaintenance(MaintenanceRenewed, renewal.id, contract.HostPublicKey, "contract is running out of funds")
+		}
 
 		// Return here if an interrupt or kill signal has been sent.
 		select {
@@ -713,14 +882,18 @@ func (c *Contractor) threadedContractMaintenance() {
 		}
 	}
 
-	// Count the number of contracts which are good for uploading, and then make
-	// more as needed to fill the gap.
-	uploadContracts := 0
-	for _, id := range c.staticContracts.IDs() {
-		if cu, ok := c.managedContractUtility(id); ok && cu.GoodForUpload {
-			uploadContracts++
-		}
+	// Make more contracts as needed, unless that already happened before the
+	// renewals.
+	if !formFirst {
+		c.managedFormNeededContracts(fundsRemaining, endHeight)
 	}
+}
+
+// managedFormNeededContracts counts the number of contracts which are good for
+// uploading, and then forms new contracts as needed to fill the gap to the
+// number of hosts in the allowance.
+func (c *Contractor) managedFormNeededContracts(fundsRemaining types.Currency, endHeight types.BlockHeight) {
+	uploadContracts := c.managedUploadContracts()
 	c.mu.RLock()
 	neededContracts := int(c.allowance.Hosts) - uploadContracts
 	c.mu.RUnlock()
@@ -729,13 +902,16 @@ func (c *Contractor) threadedContractMaintenance() {
 	}
 
 	// Assemble an exclusion list that includes all of the hosts that we already
-	// have contracts with, then select a new batch of hosts to attempt contract
-	// formation with.
+	// have contracts with as well as all blacklisted hosts, then select a new
+	// batch of hosts to attempt contract formation with.
 	c.mu.RLock()
 	var exclude []types.SiaPublicKey
 	for _, contract := range c.staticContracts.ViewAll() {
 		exclude = append(exclude, contract.HostPublicKey)
 	}
+	for _, pk := range c.hostBlacklist {
+		exclude = append(exclude, pk)
+	}
 	initialContractFunds := c.allowance.Funds.Div64(c.allowance.Hosts).Div64(3)
 	c.mu.RUnlock()
 	hosts, err := c.hdb.RandomHosts(neededContracts*2+randomHostsBufferForScore, exclude)
@@ -744,31 +920,146 @@ func (c *Contractor) threadedContractMaintenance() {
 		return
 	}
 
-	// Form contracts with the hosts one at a time, until we have enough
-	// contracts.
-	for _, host := range hosts {
-		// Determine if we have enough money to form a new contract.
-		if fundsRemaining.Cmp(initialContractFunds) < 0 {
-			c.log.Println("WARN: need to form new contracts, but unable to because of a low allowance")
-			break
+	// Form contracts with the hosts, negotiating with several hosts at once,
+	// until we have enough contracts.
+	c.managedFormNewContracts(hosts, neededContracts, fundsRemaining, initialContractFunds, endHeight)
+}
+
+// managedFormNewContracts attempts to form neededContracts new contracts with
+// the provided hosts, negotiating with up to formationWorkers hosts in
+// parallel. Funds for a contract are reserved before negotiation starts so
+// that the parallel negotiations never exceed fundsRemaining. A failed
+// negotiation only releases its reserved funds and frees up the slot for the
+// next host. The outcome for every host is kept as the formation candidates
+// of this pass.
+func (c *Contractor) managedFormNewContracts(hosts []modules.HostDBEntry, neededContracts int, fundsRemaining, initialContractFunds types.Currency, endHeight types.BlockHeight) {
+	type formationResult struct {
+		index      int
+		host       modules.HostDBEntry
+		fundsSpent types.Currency
+		contract   modules.RenterContract
+		err        error
+	}
+	results := make(chan formationResult)
+
+	candidates := make([]modules.HostCandidate, len(hosts))
+	for i, host := range hosts {
+		candidates[i] = modules.HostCandidate{
+			PublicKey:  host.PublicKey,
+			NetAddress: host.NetAddress,
+			Score:      c.hdb.ScoreBreakdown(host).Score,
+			Reason:     candidateNotAttempted,
 		}
+	}
+	defer func() {
+		c.mu.Lock()
+		c.formationCandidates = candidates
+		c.mu.Unlock()
+	}()
 
-		// Attempt forming a contract with this host.
-		fundsSpent, newContract, err := c.managedNewContract(host, initialContractFunds, endHeight)
-		if err != nil {
-			c.log.Printf("Attempted to form a contract with %v, but negotiation failed: %v\n", host.NetAddress, err)
+	c.mu.RLock()
+	workers := int(c.formationWorkers)
+	c.mu.RUnlock()
+
+	var fundsSpent, fundsReserved types.Currency
+	formed, pending, next := 0, 0, 0
+	stopped := false
+	for {
+		// Start negotiating with as many hosts as there are free workers and
+		// missing contracts.
+		for !stopped && next < len(hosts) && pending < workers && formed+pending < neededContracts {
+			// Stop forming contracts if an interrupt or kill signal has been
+			// sent.
+			select {
+			case <-c.tg.StopChan():
+				stopped = true
+				continue
+			case <-c.interruptMaintenance:
+				stopped = true
+				continue
+			default:
+			}
+
+			// Determine if we have enough money to form a new contract.
+			if fundsRemaining.Cmp(fundsSpent.Add(fundsReserved).Add(initialContractFunds)) < 0 {
+				c.log.Println("WARN: need to form new contracts, but unable to because of a low allowance")
+				c.managedRecordMaintenance(MaintenanceFormSkipped, types.FileContractID{}, types.SiaPublicKey{}, "not enough allowance funds remaining")
+				for i := next; i < len(candidates); i++ {
+					candidates[i].Reason = candidateLowFunds
+				}
+				stopped = true
+				continue
+			}
+			fundsReserved = fundsReserved.Add(initialContractFunds)
+
+			index := next
+			host := hosts[next]
+			next++
+			pending++
+			go func() {
+				spent, contract, err := c.managedNewContract(host, initialContractFunds, endHeight)
+				results <- formationResult{
+					index:      index,
+					host:       host,
+					fundsSpent: spent,
+					contract:   contract,
+					err:        err,
+				}
+			}()
+		}
+		if pending == 0 {
+			return
+		}
+
+		// Wait for a negotiation to finish. An interrupt stops new
+		// negotiations from starting, but the pending ones are allowed to
+		// finish so that no contract is left half-formed.
+		var res formationResult
+		if stopped {
+			res = <-results
+		} else {
+			select {
+			case res = <-results:
+			case <-c.tg.StopChan():
+				stopped = true
+				continue
+			case <-c.interruptMaintenance:
+				stopped = true
+				continue
+			}
+		}
+		pending--
+		fundsReserved = fundsReserved.Sub(initialContractFunds)
+		fundsSpent = fundsSpent.Add(res.fundsSpent)
+		if res.err != nil {
+			c.log.Printf("Attempted to form a contract with %v, but negotiation failed: %v\n", res.host.NetAddress, res.err)
+			c.managedNotifySubscribers(ContractEvent{
+				Type:          ContractFailed,
+				HostPublicKey: res.host.PublicKey,
+				Err:           res.err,
+			})
+			c.managedRecordMaintenance(MaintenanceFormFailed, types.FileContractID{}, res.host.PublicKey, res.err.Error())
+			candidates[res.index].Reason = res.err.Error()
 			continue
 		}
-		fundsRemaining = fundsRemaining.Sub(fundsSpent)
+		formed++
+		candidates[res.index].Selected = true
+		candidates[res.index].Reason = ""
+		c.managedNotifySubscribers(ContractEvent{
+			Type:          ContractFormed,
+			ID:            res.contract.ID,
+			HostPublicKey: res.contract.HostPublicKey,
+		})
+		c.managedRecordMaintenance(MaintenanceFormed, res.contract.ID, res.contract.HostPublicKey, "not enough contracts are good for upload")
 
 		// Add this contract to the contractor and save.
-		err = c.managedUpdateContractUtility(newContract.ID, modules.ContractUtility{
+		err := c.managedUpdateContractUtility(res.contract.ID, modules.ContractUtility{
 			GoodForUpload: true,
 			GoodForRenew:  true,
 		})
 		if err != nil {
 			c.log.Println("Failed to update the contract utilities", err)
-			return
+			continue
 		}
 		c.mu.Lock()
 		err = c.saveSync()
@@ -776,21 +1067,6 @@ func (c *Contractor) threadedContractMaintenance() {
 		if err != nil {
 			c.log.Println("Unable to save the contractor:", err)
 		}
-
-		// Quit the loop if we've replaced all needed contracts.
-		neededContracts--
-		if neededContracts <= 0 {
-			break
-		}
-
-		// Soft sleep before making the next contract.
-		select {
-		case <-c.tg.StopChan():
-			return
-		case <-c.interruptMaintenance:
-			return
-		default:
-		}
 	}
 }
 
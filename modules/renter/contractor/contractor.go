@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/modules/renter/proto"
@@ -50,10 +51,40 @@ type Contractor struct {
 	interruptMaintenance chan struct{}
 	maintenanceLock      siasync.TryMutex
 
-	allowance     modules.Allowance
-	blockHeight   types.BlockHeight
-	currentPeriod types.BlockHeight
-	lastChange    modules.ConsensusChangeID
+	allowance        modules.Allowance
+	autoTopUp        modules.AutoTopUp
+	blockHeight      types.BlockHeight
+	currentPeriod    types.BlockHeight
+	formationWorkers uint64
+	hostBlacklist    map[string]types.SiaPublicKey
+	lastChange       modules.ConsensusChangeID
+	minContracts     uint64
+	minHostUptime    float64
+	periodAlign      modules.PeriodAlignment
+	priceCeilings    modules.PriceCeilings
+
+	// spendingAlertThreshold is the fraction of the allowance that can be
+	// spent in a period before a warning is sent. spendingAlerted indicates
+	// that the warning has been sent for the current spending.
+	spendingAlertThreshold float64
+	spendingAlerted        bool
+
+	// persistInterval is the interval at which changes to the persisted data
+	// are flushed to disk. If it is zero, changes are written immediately.
+	// persistDirty indicates that there are changes which haven't been
+	// written yet. persistIntervalChanged wakes threadedFlushPersist when
+	// the interval is changed.
+	persistInterval        time.Duration
+	persistDirty           bool
+	persistIntervalChanged chan struct{}
+
+	// formationCandidates are the hosts that were considered in the most
+	// recent contract formation pass.
+	formationCandidates []modules.HostCandidate
+
+	// maintenanceHistory is a bounded log of the actions taken by contract
+	// maintenance, ordered from oldest to newest.
+	maintenanceHistory []MaintenanceRecord
 
 	downloaders         map[types.FileContractID]*hostDownloader
 	editors             map[types.FileContractID]*hostEditor
@@ -69,6 +100,29 @@ type Contractor struct {
 	oldContracts    map[types.FileContractID]modules.RenterContract
 	renewedFrom     map[types.FileContractID]types.FileContractID
 	renewedTo       map[types.FileContractID]types.FileContractID
+
+	// oldContractReasons explains why each contract in oldContracts was moved
+	// there.
+	oldContractReasons map[types.FileContractID]string
+
+	// hostSettings contains the settings that the host advertised when a
+	// contract was formed or renewed with it.
+	hostSettings map[types.FileContractID]modules.HostExternalSettings
+
+	// pinnedContracts contains the contracts that contract maintenance must
+	// not renew or change the utility of.
+	pinnedContracts map[types.FileContractID]struct{}
+
+	// canceledContracts contains the contracts that were canceled by the
+	// user. They stay !GoodForUpload and !GoodForRenew until they expire,
+	// regardless of changes to the allowance.
+	canceledContracts map[types.FileContractID]struct{}
+
+	// subscribers receive the events emitted when contracts are formed,
+	// renewed, canceled, or fail to form or renew. They are protected by
+	// their own mutex so that events can be emitted while holding mu.
+	subscribers   []chan<- ContractEvent
+	subscribersMu sync.Mutex
 }
 
 // Allowance returns the current allowance.
@@ -151,6 +205,14 @@ func (c *Contractor) CurrentPeriod() types.BlockHeight {
 	return c.currentPeriod
 }
 
+// CurrentPeriodEnd returns the height at which the current allowance period
+// ends and the next one begins. PeriodSpending is reset at this height.
+func (c *Contractor) CurrentPeriodEnd() types.BlockHeight {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.currentPeriod + c.allowance.Period
+}
+
 // RateLimits sets the bandwidth limits for connections created by the
 // contractSet.
 func (c *Contractor) RateLimits() (readBPW int64, writeBPS int64, packetSize uint64) {
@@ -219,12 +281,19 @@ func NewCustomContractor(cs consensusSet, w wallet, tp transactionPool, hdb host
 		tpool:      tp,
 		wallet:     w,
 
-		interruptMaintenance: make(chan struct{}),
+		formationWorkers:       defaultContractFormationWorkers,
+		interruptMaintenance:   make(chan struct{}),
+		persistIntervalChanged: make(chan struct{}, 1),
 
 		staticContracts:     contractSet,
 		downloaders:         make(map[types.FileContractID]*hostDownloader),
 		editors:             make(map[types.FileContractID]*hostEditor),
+		hostBlacklist:       make(map[string]types.SiaPublicKey),
+		hostSettings:        make(map[types.FileContractID]modules.HostExternalSettings),
 		oldContracts:        make(map[types.FileContractID]modules.RenterContract),
+		oldContractReasons:  make(map[types.FileContractID]string),
+		pinnedContracts:     make(map[types.FileContractID]struct{}),
+		canceledContracts:   make(map[types.FileContractID]struct{}),
 		contractIDToPubKey:  make(map[types.FileContractID]types.SiaPublicKey),
 		pubKeysToContractID: make(map[string]types.FileContractID),
 		renewing:            make(map[types.FileContractID]bool),
@@ -260,10 +329,23 @@ func NewCustomContractor(cs consensusSet, w wallet, tp transactionPool, hdb host
 	if err != nil {
 		return nil, errors.New("contractor subscription failed: " + err.Error())
 	}
-	// Unsubscribe from the consensus set upon shutdown.
+	// Unsubscribe from the consensus set and drop the contract event
+	// subscribers upon shutdown.
 	c.tg.OnStop(func() {
 		cs.Unsubscribe(c)
+		c.subscribersMu.Lock()
+		c.subscribers = nil
+		c.subscribersMu.Unlock()
+	})
+
+	// Flush any batched changes upon shutdown. This runs before the contract
+	// set and the logger are closed.
+	c.tg.AfterStop(func() {
+		if err := c.managedFlushPersist(); err != nil {
+			c.log.Println("Unable to flush the contractor persistence:", err)
+		}
 	})
+	go c.threadedFlushPersist()
 
 	// We may have upgraded persist or resubscribed. Save now so that we don't
 	// lose our work.
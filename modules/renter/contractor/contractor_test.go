@@ -2,12 +2,15 @@ package contractor
 
 import (
 	"errors"
+	"io/ioutil"
 	"os"
 	"testing"
 	"time"
 
 	"gitlab.com/NebulousLabs/Sia/build"
 	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/modules/renter/proto"
+	"gitlab.com/NebulousLabs/Sia/persist"
 	"gitlab.com/NebulousLabs/Sia/types"
 )
 
@@ -24,7 +27,9 @@ func (newStub) Unsubscribe(modules.ConsensusSetSubscriber) { return }
 
 // wallet stubs
 func (newStub) NextAddress() (uc types.UnlockConditions, err error)          { return }
+func (newStub) PrimarySeed() (s modules.Seed, n uint64, err error)           { return }
 func (newStub) StartTransaction() (tb modules.TransactionBuilder, err error) { return }
+func (newStub) Unlocked() (bool, error)                                      { return true, nil }
 
 // transaction pool stubs
 func (newStub) AcceptTransactionSet([]types.Transaction) error      { return nil }
@@ -99,6 +104,104 @@ func TestAllowance(t *testing.T) {
 	}
 }
 
+// TestValidateAllowanceRenewWindow tests that SetAllowance's validation
+// rejects renew windows below minRenewWindow and accepts the minimum itself.
+func TestValidateAllowanceRenewWindow(t *testing.T) {
+	a := modules.Allowance{
+		Funds:       types.SiacoinPrecision,
+		Hosts:       1,
+		Period:      minRenewWindow + 1,
+		RenewWindow: minRenewWindow,
+	}
+	if err := validateAllowance(a); err != nil {
+		t.Fatal("minimum renew window was rejected:", err)
+	}
+
+	// In testing builds, the minimum is a single block, so the window below
+	// it is rejected as a zero window.
+	a.RenewWindow = minRenewWindow - 1
+	expected := errAllowanceWindowTiny
+	if a.RenewWindow == 0 {
+		expected = ErrAllowanceZeroWindow
+	}
+	if err := validateAllowance(a); err != expected {
+		t.Fatalf("expected %q, got %q", expected, err)
+	}
+}
+
+// TestSubscribe tests that contract events are delivered to subscribers
+// without blocking on slow subscribers.
+func TestSubscribe(t *testing.T) {
+	c := &Contractor{
+		log: persist.NewLogger(ioutil.Discard),
+	}
+	events := make(chan ContractEvent, 1)
+	if err := c.Subscribe(events); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second event should be dropped because the buffer is full.
+	c.managedNotifySubscribers(ContractEvent{Type: ContractFormed, ID: types.FileContractID{1}})
+	c.managedNotifySubscribers(ContractEvent{Type: ContractRenewed, ID: types.FileContractID{2}})
+	select {
+	case ev := <-events:
+		if ev.Type != ContractFormed || ev.ID != (types.FileContractID{1}) {
+			t.Fatal("wrong event received:", ev.Type, ev.ID)
+		}
+	default:
+		t.Fatal("expected an event")
+	}
+	select {
+	case ev := <-events:
+		t.Fatal("expected the second event to be dropped, got", ev.Type)
+	default:
+	}
+
+	// Unsubscribed channels should not receive any more events.
+	c.Unsubscribe(events)
+	c.managedNotifySubscribers(ContractEvent{Type: ContractCanceled})
+	select {
+	case ev := <-events:
+		t.Fatal("received event after unsubscribing:", ev.Type)
+	default:
+	}
+
+	// Subscribing after shutdown should fail.
+	if err := c.tg.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Subscribe(events); err == nil {
+		t.Fatal("expected subscribing to a stopped contractor to fail")
+	}
+}
+
+// TestInterruptMaintenance tests that InterruptMaintenance stops running
+// maintenance and is a no-op if no maintenance is running.
+func TestInterruptMaintenance(t *testing.T) {
+	c := &Contractor{
+		log: persist.NewLogger(ioutil.Discard),
+	}
+
+	// Interrupting without running maintenance should return immediately.
+	c.InterruptMaintenance()
+	c.InterruptMaintenance()
+
+	// Simulate maintenance that runs until it is interrupted.
+	c.maintenanceLock.Lock()
+	interrupted := make(chan struct{})
+	go func() {
+		<-c.interruptMaintenance
+		close(interrupted)
+		c.maintenanceLock.Unlock()
+	}()
+	c.InterruptMaintenance()
+	select {
+	case <-interrupted:
+	default:
+		t.Fatal("InterruptMaintenance returned before maintenance was interrupted")
+	}
+}
+
 // stubHostDB mocks the hostDB dependency using zero-valued implementations of
 // its methods.
 type stubHostDB struct{}
@@ -317,6 +420,11 @@ func TestIntegrationSetAllowance(t *testing.T) {
 	if err != errAllowanceWindowSize {
 		t.Errorf("expected %q, got %q", errAllowanceWindowSize, err)
 	}
+	a.RenewWindow = minRenewWindow - 1
+	err = c.SetAllowance(a)
+	if err == nil {
+		t.Error("expected a renew window below the minimum to be rejected")
+	}
 
 	// reasonable values; should succeed
 	a.Funds = types.SiacoinPrecision.Mul64(100)
@@ -345,6 +453,19 @@ func TestIntegrationSetAllowance(t *testing.T) {
 		t.Fatal("expected 1 contract, got", clen)
 	}
 
+	// the host of the contract should be reported as a selected candidate
+	err = build.Retry(50, 100*time.Millisecond, func() error {
+		for _, hc := range c.FormationCandidates() {
+			if hc.Selected && hc.PublicKey.String() == c.Contracts()[0].HostPublicKey.String() {
+				return nil
+			}
+		}
+		return errors.New("host of the new contract is not a selected candidate")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	_, err = m.AddBlock()
 	if err != nil {
 		t.Fatal(err)
@@ -497,6 +618,7 @@ func TestLinkedContracts(t *testing.T) {
 
 // testWalletShim is used to test the walletBridge type.
 type testWalletShim struct {
+	locked            bool
 	nextAddressCalled bool
 	startTxnCalled    bool
 }
@@ -507,10 +629,16 @@ func (ws *testWalletShim) NextAddress() (types.UnlockConditions, error) {
 	ws.nextAddressCalled = true
 	return types.UnlockConditions{}, nil
 }
+func (ws *testWalletShim) PrimarySeed() (modules.Seed, uint64, error) {
+	return modules.Seed{}, 0, nil
+}
 func (ws *testWalletShim) StartTransaction() (modules.TransactionBuilder, error) {
 	ws.startTxnCalled = true
 	return nil, nil
 }
+func (ws *testWalletShim) Unlocked() (bool, error) {
+	return !ws.locked, nil
+}
 
 // TestWalletBridge tests the walletBridge type.
 func TestWalletBridge(t *testing.T) {
@@ -525,3 +653,454 @@ func TestWalletBridge(t *testing.T) {
 		t.Error("StartTransaction was not called on the shim")
 	}
 }
+
+// TestAutoTopUp tests that the allowance is topped up according to the
+// auto top-up settings.
+func TestAutoTopUp(t *testing.T) {
+	shim := new(testWalletShim)
+	c := &Contractor{
+		allowance: modules.Allowance{
+			Funds: types.NewCurrency64(100),
+		},
+		log:     persist.NewLogger(ioutil.Discard),
+		persist: new(memPersist),
+		wallet:  &WalletBridge{W: shim},
+	}
+
+	// The top-up is disabled by default.
+	if added := c.managedAutoTopUp(types.ZeroCurrency); !added.IsZero() {
+		t.Fatal("disabled top-up added funds:", added)
+	}
+
+	// A zero amount should be rejected.
+	err := c.SetAutoTopUp(modules.AutoTopUp{
+		Threshold: types.NewCurrency64(10),
+		MaxSpend:  types.NewCurrency64(50),
+	})
+	if err != errZeroTopUpAmount {
+		t.Fatalf("expected %v, got %v", errZeroTopUpAmount, err)
+	}
+	err = c.SetAutoTopUp(modules.AutoTopUp{
+		Threshold: types.NewCurrency64(10),
+		Amount:    types.NewCurrency64(30),
+		MaxSpend:  types.NewCurrency64(50),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing should be added above the threshold.
+	if added := c.managedAutoTopUp(types.NewCurrency64(10)); !added.IsZero() {
+		t.Fatal("top-up added funds above the threshold:", added)
+	}
+
+	// Nothing should be added while the wallet is locked.
+	shim.locked = true
+	if added := c.managedAutoTopUp(types.NewCurrency64(5)); !added.IsZero() {
+		t.Fatal("top-up added funds while the wallet was locked:", added)
+	}
+	shim.locked = false
+
+	// Below the threshold, the amount should be added.
+	if added := c.managedAutoTopUp(types.NewCurrency64(5)); added.Cmp64(30) != 0 {
+		t.Fatal("expected 30 to be added, got", added)
+	}
+	if c.Allowance().Funds.Cmp64(130) != 0 {
+		t.Fatal("allowance wasn't topped up:", c.Allowance().Funds)
+	}
+
+	// The second top-up is limited by MaxSpend.
+	if added := c.managedAutoTopUp(types.NewCurrency64(5)); added.Cmp64(20) != 0 {
+		t.Fatal("expected 20 to be added, got", added)
+	}
+	if added := c.managedAutoTopUp(types.NewCurrency64(5)); !added.IsZero() {
+		t.Fatal("top-up exceeded MaxSpend:", added)
+	}
+	if atu := c.AutoTopUp(); atu.Spent.Cmp(atu.MaxSpend) != 0 {
+		t.Fatal("expected the full MaxSpend to be spent, got", atu.Spent)
+	}
+
+	// Changing the settings resets the amount spent.
+	err = c.SetAutoTopUp(modules.AutoTopUp{
+		Threshold: types.NewCurrency64(10),
+		Amount:    types.NewCurrency64(30),
+		MaxSpend:  types.NewCurrency64(50),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.AutoTopUp().Spent.IsZero() {
+		t.Fatal("spent amount wasn't reset")
+	}
+}
+
+// TestRecentUptime tests that the recent uptime of a host is weighted by the
+// time between its scans.
+func TestRecentUptime(t *testing.T) {
+	now := time.Now()
+	step := uptimeWindow / 10
+	scan := func(n int, success bool) modules.HostDBScan {
+		return modules.HostDBScan{Timestamp: now.Add(-time.Duration(n) * step), Success: success}
+	}
+
+	// Too few scans within the window can't be judged.
+	var host modules.HostDBEntry
+	host.ScanHistory = modules.HostDBScans{scan(20, false), scan(4, true), scan(2, true)}
+	if _, ok := recentUptime(host, now); ok {
+		t.Fatal("uptime was judged with too few scans")
+	}
+
+	// The host was offline from 8 to 2 steps ago and online otherwise.
+	host.ScanHistory = modules.HostDBScans{scan(20, false), scan(9, true), scan(8, false), scan(2, true)}
+	uptime, ok := recentUptime(host, now)
+	if !ok {
+		t.Fatal("uptime wasn't judged")
+	}
+	if uptime < 0.32 || uptime > 0.34 {
+		t.Fatal("expected an uptime of 1/3, got", uptime)
+	}
+
+	// A host below the minimum should be rejected, but only once a minimum is
+	// set.
+	c := &Contractor{
+		log:     persist.NewLogger(ioutil.Discard),
+		persist: new(memPersist),
+	}
+	if c.managedHasLowUptime(host) {
+		t.Fatal("host was rejected without a minimum uptime")
+	}
+	c.minHostUptime = 0.5
+	if !c.managedHasLowUptime(host) {
+		t.Fatal("host with low uptime wasn't rejected")
+	}
+	if err := c.SetMinHostUptime(1.5); err != errInvalidMinHostUptime {
+		t.Fatalf("expected %v, got %v", errInvalidMinHostUptime, err)
+	}
+}
+
+// TestExceedsPriceCeilings tests that hosts are rejected if any of their prices
+// exceeds the corresponding ceiling, and that zero ceilings are ignored.
+func TestExceedsPriceCeilings(t *testing.T) {
+	var host modules.HostDBEntry
+	host.StoragePrice = types.NewCurrency64(10)
+	host.DownloadBandwidthPrice = types.NewCurrency64(20)
+	host.UploadBandwidthPrice = types.NewCurrency64(30)
+
+	tests := []struct {
+		pc      modules.PriceCeilings
+		exceeds bool
+	}{
+		{modules.PriceCeilings{}, false},
+		{modules.PriceCeilings{MaxStoragePrice: types.NewCurrency64(10)}, false},
+		{modules.PriceCeilings{MaxStoragePrice: types.NewCurrency64(9)}, true},
+		{modules.PriceCeilings{MaxDownloadPrice: types.NewCurrency64(19)}, true},
+		{modules.PriceCeilings{MaxUploadPrice: types.NewCurrency64(29)}, true},
+		{modules.PriceCeilings{
+			MaxStoragePrice:  types.NewCurrency64(10),
+			MaxDownloadPrice: types.NewCurrency64(20),
+			MaxUploadPrice:   types.NewCurrency64(30),
+		}, false},
+	}
+	for i, test := range tests {
+		if exceedsPriceCeilings(host, test.pc) != test.exceeds {
+			t.Errorf("test %v: expected %v", i, test.exceeds)
+		}
+	}
+}
+
+// TestHostBlacklist tests that the contractor refuses to form or renew
+// contracts with a blacklisted host, and that existing contracts with the host
+// are marked as !GoodForRenew so that they wind down.
+func TestHostBlacklist(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, _, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	defer c.Close()
+
+	// get the host's entry from the db
+	hostEntry, ok := c.hdb.Host(h.PublicKey())
+	if !ok {
+		t.Fatal("no entry for host in db")
+	}
+
+	// form a contract with the host before it is blacklisted
+	_, contract, err := c.managedNewContract(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.managedUpdateContractUtility(contract.ID, modules.ContractUtility{GoodForUpload: true, GoodForRenew: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Blacklist the host without triggering contract maintenance, so that
+	// the contract is still good for renew when it is renewed below.
+	pk := h.PublicKey()
+	c.mu.Lock()
+	c.hostBlacklist = map[string]types.SiaPublicKey{pk.String(): pk}
+	c.mu.Unlock()
+
+	// New contracts with the host are refused.
+	_, _, err = c.managedNewContract(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+100)
+	if err != errHostBlacklisted {
+		t.Fatalf("expected %v, got %v", errHostBlacklisted, err)
+	}
+
+	// The existing contract can't be renewed.
+	oldContract, ok := c.staticContracts.Acquire(contract.ID)
+	if !ok {
+		t.Fatal("failed to acquire contract")
+	}
+	_, err = c.managedRenew(oldContract, types.SiacoinPrecision.Mul64(50), c.blockHeight+200)
+	c.staticContracts.Return(oldContract)
+	if err != errHostBlacklisted {
+		t.Fatalf("expected %v, got %v", errHostBlacklisted, err)
+	}
+
+	// The existing contract is no longer renewed, but can still be used
+	// until it expires.
+	if err := c.managedMarkContractsUtility(); err != nil {
+		t.Fatal(err)
+	}
+	utility, ok := c.managedContractUtility(contract.ID)
+	if !ok {
+		t.Fatal("contract is not in the contract set")
+	}
+	if !utility.GoodForUpload || utility.GoodForRenew {
+		t.Fatal("contract with blacklisted host should be !GoodForRenew only:", utility)
+	}
+	if utility.Reason != "host is blacklisted" {
+		t.Fatal("wrong reason:", utility.Reason)
+	}
+}
+
+// knownHostDB is a stubHostDB whose Host method returns a fixed set of hosts.
+type knownHostDB struct {
+	stubHostDB
+	hosts map[string]modules.HostDBEntry
+}
+
+func (hdb knownHostDB) Host(pk types.SiaPublicKey) (modules.HostDBEntry, bool) {
+	host, ok := hdb.hosts[pk.String()]
+	return host, ok
+}
+
+// TestAllContracts tests that AllContracts returns every active contract,
+// flagging whether its host is online, and no old contracts.
+func TestAllContracts(t *testing.T) {
+	cs, err := proto.NewContractSet(build.TempDir("contractor", t.Name()), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	// Form contracts with an online host, an offline host and a host that
+	// the hostdb doesn't know.
+	hostKey := func(b byte) types.SiaPublicKey {
+		return types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{b}}
+	}
+	for i := byte(1); i <= 3; i++ {
+		txn := types.Transaction{FileContractRevisions: []types.FileContractRevision{{
+			ParentID:             types.FileContractID{i},
+			NewValidProofOutputs: []types.SiacoinOutput{{}, {}},
+			UnlockConditions: types.UnlockConditions{
+				PublicKeys: []types.SiaPublicKey{{}, hostKey(i)},
+			},
+		}}}
+		if err := cs.ConvertV130Contract(proto.V130Contract{LastRevisionTxn: txn}, proto.V130CachedRevision{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	online := modules.HostDBEntry{PublicKey: hostKey(1), ScanHistory: modules.HostDBScans{{Success: true}}}
+	offline := modules.HostDBEntry{PublicKey: hostKey(2), ScanHistory: modules.HostDBScans{{Success: false}, {Success: false}}}
+	c := &Contractor{
+		hdb: knownHostDB{hosts: map[string]modules.HostDBEntry{
+			online.PublicKey.String():  online,
+			offline.PublicKey.String(): offline,
+		}},
+		staticContracts: cs,
+		oldContracts: map[types.FileContractID]modules.RenterContract{
+			{4}: {ID: types.FileContractID{4}, HostPublicKey: hostKey(1)},
+		},
+	}
+
+	expected := map[types.FileContractID]bool{
+		{1}: true,
+		{2}: false,
+		{3}: false,
+	}
+	statuses := c.AllContracts()
+	if len(statuses) != len(expected) {
+		t.Fatalf("expected %v contracts, got %v", len(expected), len(statuses))
+	}
+	for _, status := range statuses {
+		hostOnline, ok := expected[status.ID]
+		if !ok {
+			t.Fatal("unexpected contract", status.ID)
+		}
+		if status.HostOnline != hostOnline {
+			t.Errorf("contract %v: expected HostOnline %v, got %v", status.ID, hostOnline, status.HostOnline)
+		}
+	}
+}
+
+// TestContractRedundancy tests that the contract redundancy is insufficient
+// while fewer contracts than the minimum are good for upload, and that the
+// minimum is capped at the number of hosts in the allowance.
+func TestContractRedundancy(t *testing.T) {
+	cs, err := proto.NewContractSet(build.TempDir("contractor", t.Name()), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	c := &Contractor{
+		allowance:       modules.Allowance{Hosts: 2},
+		log:             persist.NewLogger(ioutil.Discard),
+		persist:         new(memPersist),
+		staticContracts: cs,
+	}
+
+	// The minimum is disabled by default.
+	if cr := c.ContractRedundancy(); cr.Status != modules.ContractRedundancyStatusHealthy {
+		t.Fatal("expected healthy redundancy without a minimum, got", cr.Status)
+	}
+
+	c.mu.Lock()
+	c.minContracts = 5
+	c.mu.Unlock()
+	cr := c.ContractRedundancy()
+	if cr.Status != modules.ContractRedundancyStatusInsufficient {
+		t.Fatal("expected insufficient redundancy, got", cr.Status)
+	}
+	if cr.MinContracts != 5 || cr.UploadContracts != 0 {
+		t.Fatal("wrong contract counts:", cr)
+	}
+
+	// Without hosts in the allowance, no contracts can be required.
+	c.mu.Lock()
+	c.allowance.Hosts = 0
+	c.mu.Unlock()
+	if cr := c.ContractRedundancy(); cr.Status != modules.ContractRedundancyStatusHealthy {
+		t.Fatal("minimum wasn't capped at the allowance hosts:", cr.Status)
+	}
+}
+
+// TestFormationWorkers tests that the number of formation workers is
+// validated and persisted, and that persist data without a number of workers
+// keeps the default.
+func TestFormationWorkers(t *testing.T) {
+	p := new(memPersist)
+	c := &Contractor{
+		formationWorkers: defaultContractFormationWorkers,
+		log:              persist.NewLogger(ioutil.Discard),
+		persist:          p,
+	}
+
+	for _, n := range []uint64{0, maxContractFormationWorkers + 1} {
+		if err := c.SetFormationWorkers(n); err != errInvalidFormationWorkers {
+			t.Fatalf("%v workers: expected %v, got %v", n, errInvalidFormationWorkers, err)
+		}
+	}
+	if err := c.SetFormationWorkers(7); err != nil {
+		t.Fatal(err)
+	}
+	if c.FormationWorkers() != 7 || p.FormationWorkers != 7 {
+		t.Fatal("number of formation workers wasn't set:", c.FormationWorkers(), p.FormationWorkers)
+	}
+
+	// The number of workers is restored.
+	c.formationWorkers = defaultContractFormationWorkers
+	if err := c.load(); err != nil {
+		t.Fatal(err)
+	}
+	if c.FormationWorkers() != 7 {
+		t.Fatal("number of formation workers wasn't restored:", c.FormationWorkers())
+	}
+
+	// Older persist data keeps the default.
+	p.FormationWorkers = 0
+	c.formationWorkers = defaultContractFormationWorkers
+	if err := c.load(); err != nil {
+		t.Fatal(err)
+	}
+	if c.FormationWorkers() != defaultContractFormationWorkers {
+		t.Fatal("expected the default number of formation workers, got", c.FormationWorkers())
+	}
+}
+
+// TestSpendingAlert tests that a spending alert is sent once the spent
+// fraction of the allowance reaches the threshold, and that it is rearmed when
+// the spending falls below the threshold again.
+func TestSpendingAlert(t *testing.T) {
+	cs, err := proto.NewContractSet(build.TempDir("contractor", t.Name()), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	id := types.FileContractID{1}
+	c := &Contractor{
+		allowance: modules.Allowance{
+			Funds: types.NewCurrency64(100),
+		},
+		hdb:     stubHostDB{},
+		log:     persist.NewLogger(ioutil.Discard),
+		persist: new(memPersist),
+		oldContracts: map[types.FileContractID]modules.RenterContract{
+			id: {ID: id, DownloadSpending: types.NewCurrency64(50)},
+		},
+		staticContracts: cs,
+	}
+	events := make(chan ContractEvent, 2)
+	if err := c.Subscribe(events); err != nil {
+		t.Fatal(err)
+	}
+
+	// Invalid thresholds should be rejected.
+	if err := c.SetSpendingAlertThreshold(1.5); err != errInvalidSpendingAlertThreshold {
+		t.Fatalf("expected %v, got %v", errInvalidSpendingAlertThreshold, err)
+	}
+
+	// The alert is disabled by default.
+	c.managedCheckSpendingAlert()
+	if sa := c.SpendingAlert(); sa.Triggered || sa.SpentFraction != 0.5 {
+		t.Fatal("unexpected spending alert:", sa)
+	}
+
+	// Setting a threshold below the spent fraction triggers the alert
+	// immediately, but only once.
+	if err := c.SetSpendingAlertThreshold(0.4); err != nil {
+		t.Fatal(err)
+	}
+	c.managedCheckSpendingAlert()
+	if sa := c.SpendingAlert(); !sa.Triggered || sa.Threshold != 0.4 {
+		t.Fatal("expected the alert to be triggered:", sa)
+	}
+	select {
+	case ev := <-events:
+		if ev.Type != SpendingAlertTriggered {
+			t.Fatal("wrong event received:", ev.Type)
+		}
+	default:
+		t.Fatal("expected a spending alert event")
+	}
+	select {
+	case ev := <-events:
+		t.Fatal("expected a single spending alert, got", ev.Type)
+	default:
+	}
+
+	// Increasing the allowance rearms the alert.
+	c.mu.Lock()
+	c.allowance.Funds = types.NewCurrency64(200)
+	c.mu.Unlock()
+	c.managedCheckSpendingAlert()
+	if sa := c.SpendingAlert(); sa.Triggered || sa.SpentFraction != 0.25 {
+		t.Fatal("expected the alert to be rearmed:", sa)
+	}
+}
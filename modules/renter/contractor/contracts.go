@@ -1,10 +1,22 @@
 package contractor
 
 import (
+	"errors"
+
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/types"
 )
 
+const (
+	// oldContractExpired is the reason recorded for contracts that were
+	// retired because they expired.
+	oldContractExpired = "contract expired"
+
+	// oldContractRenewed is the reason recorded for contracts that were
+	// retired because they were renewed.
+	oldContractRenewed = "contract was renewed"
+)
+
 // contractEndHeight returns the height at which the Contractor's contracts
 // end. If there are no contracts, it returns zero.
 func (c *Contractor) contractEndHeight() types.BlockHeight {
@@ -20,6 +32,47 @@ func (c *Contractor) managedContractUtility(id types.FileContractID) (modules.Co
 	return rc.Utility, true
 }
 
+// CancelContract marks the contract with the given id as !GoodForUpload and
+// !GoodForRenew. The contract is recorded as canceled and its utility is
+// locked, so that neither contract maintenance nor a change of the allowance
+// will mark the contract as useful again. The contract can still be used to
+// download data that is already stored on the host.
+func (c *Contractor) CancelContract(id types.FileContractID) error {
+	c.log.Println("INFO: canceling contract", id)
+	c.mu.Lock()
+	c.canceledContracts[id] = struct{}{}
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	err = c.managedUpdateContractUtility(id, modules.ContractUtility{
+		GoodForUpload: false,
+		GoodForRenew:  false,
+		Locked:        true,
+		Reason:        "contract was canceled",
+	})
+	if err != nil {
+		return err
+	}
+	contract, _ := c.staticContracts.View(id)
+	c.managedNotifySubscribers(ContractEvent{
+		Type:          ContractCanceled,
+		ID:            id,
+		HostPublicKey: contract.HostPublicKey,
+	})
+	return nil
+}
+
+// managedIsCanceled returns true if the contract with the given id was
+// canceled by the user.
+func (c *Contractor) managedIsCanceled(id types.FileContractID) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, canceled := c.canceledContracts[id]
+	return canceled
+}
+
 // ContractByPublicKey returns the contract with the key specified, if it
 // exists. The contract will be resolved if possible to the most recent child
 // contract.
@@ -33,9 +86,23 @@ func (c *Contractor) ContractByPublicKey(pk types.SiaPublicKey) (modules.RenterC
 	return c.staticContracts.View(id)
 }
 
+// AllContracts returns every active contract of the contractor, regardless of
+// whether the host is currently reachable. Each contract is paired with a flag
+// indicating whether the hostdb considers the host to be online.
+func (c *Contractor) AllContracts() []modules.RenterContractStatus {
+	contracts := c.staticContracts.ViewAll()
+	statuses := make([]modules.RenterContractStatus, 0, len(contracts))
+	for _, contract := range contracts {
+		statuses = append(statuses, modules.RenterContractStatus{
+			RenterContract: contract,
+			HostOnline:     !c.IsOffline(contract.HostPublicKey),
+		})
+	}
+	return statuses
+}
+
 // Contracts returns the contracts formed by the contractor in the current
-// allowance period. Only contracts formed with currently online hosts are
-// returned.
+// allowance period.
 func (c *Contractor) Contracts() []modules.RenterContract {
 	return c.staticContracts.ViewAll()
 }
@@ -52,6 +119,37 @@ func (c *Contractor) OldContracts() []modules.RenterContract {
 	return contracts
 }
 
+// OldContractReasons returns the reasons why the contracts returned by
+// OldContracts were retired, keyed by contract id. Contracts that were retired
+// before the reasons were recorded have no entry.
+func (c *Contractor) OldContractReasons() map[types.FileContractID]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	reasons := make(map[types.FileContractID]string, len(c.oldContractReasons))
+	for id, reason := range c.oldContractReasons {
+		reasons[id] = reason
+	}
+	return reasons
+}
+
+// expiredReason returns the reason recorded for a contract that is retired
+// because it expired. If the contract was not going to be renewed, the reason
+// for that is included, which tells apart contracts that were canceled or
+// dropped because of their host from contracts that simply ran out.
+func expiredReason(u modules.ContractUtility) string {
+	if u.GoodForRenew || u.Reason == "" {
+		return oldContractExpired
+	}
+	return oldContractExpired + ": " + u.Reason
+}
+
+// ContractUtilityByID returns the utility fields for the contract with the
+// given id, including the reason why the contract is not GoodForUpload or
+// GoodForRenew.
+func (c *Contractor) ContractUtilityByID(id types.FileContractID) (modules.ContractUtility, bool) {
+	return c.managedContractUtility(id)
+}
+
 // ContractUtility returns the utility fields for the given contract.
 func (c *Contractor) ContractUtility(pk types.SiaPublicKey) (modules.ContractUtility, bool) {
 	c.mu.RLock()
@@ -63,6 +161,126 @@ func (c *Contractor) ContractUtility(pk types.SiaPublicKey) (modules.ContractUti
 	return c.managedContractUtility(id)
 }
 
+// ContractHostSettings returns the settings that the host advertised when the
+// contract with the given id was formed or renewed. Comparing them to the
+// host's current settings reveals whether the host changed its prices since.
+func (c *Contractor) ContractHostSettings(id types.FileContractID) (modules.HostExternalSettings, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	settings, ok := c.hostSettings[id]
+	return settings, ok
+}
+
+// RecoverContract resynchronizes the contract with the given id with the most
+// recent revision reported by its host. It is used to salvage a contract after
+// the renter and the host disagree on the contract's revision number. Editor
+// and Downloader recover contracts automatically when they detect such a
+// mismatch.
+func (c *Contractor) RecoverContract(id types.FileContractID) error {
+	if err := c.tg.Add(); err != nil {
+		return err
+	}
+	defer c.tg.Done()
+
+	contract, haveContract := c.staticContracts.View(id)
+	if !haveContract {
+		return errors.New("no record of that contract")
+	}
+	host, haveHost := c.hdb.Host(contract.HostPublicKey)
+	if !haveHost {
+		return errors.New("no record of that host")
+	}
+
+	// Acquire the revising lock so that the contract isn't revised while it
+	// is being recovered.
+	c.mu.Lock()
+	if c.renewing[id] {
+		c.mu.Unlock()
+		return errors.New("currently renewing that contract")
+	} else if c.revising[id] {
+		c.mu.Unlock()
+		return errors.New("already revising that contract")
+	}
+	c.revising[id] = true
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.revising, id)
+		c.mu.Unlock()
+	}()
+
+	return c.managedRecoverContract(host, id, c.tg.StopChan())
+}
+
+// managedRecoverContract resynchronizes the contract with the given id with the
+// most recent revision of host. The caller must hold the revising lock of the
+// contract.
+func (c *Contractor) managedRecoverContract(host modules.HostDBEntry, id types.FileContractID, cancel <-chan struct{}) error {
+	if _, err := c.staticContracts.RecoverContract(host, id, cancel); err != nil {
+		c.log.Printf("WARN: failed to recover contract %v: %v", id, err)
+		return err
+	}
+	c.log.Println("INFO: recovered contract", id)
+	return nil
+}
+
+// SyncMerkleRoots rebuilds the sector roots of the contract with the given id
+// from the roots stored by its host. It can be used if the renter's copy of
+// the roots was lost or corrupted. The roots are verified against the Merkle
+// root of the contract's latest revision before they are accepted.
+func (c *Contractor) SyncMerkleRoots(id types.FileContractID) error {
+	if err := c.tg.Add(); err != nil {
+		return err
+	}
+	defer c.tg.Done()
+
+	contract, haveContract := c.staticContracts.View(id)
+	if !haveContract {
+		return errors.New("no record of that contract")
+	}
+	host, haveHost := c.hdb.Host(contract.HostPublicKey)
+	if !haveHost {
+		return errors.New("no record of that host")
+	}
+
+	// Acquire the revising lock so that the contract isn't revised while its
+	// roots are being replaced.
+	c.mu.Lock()
+	if c.renewing[id] {
+		c.mu.Unlock()
+		return errors.New("currently renewing that contract")
+	} else if c.revising[id] {
+		c.mu.Unlock()
+		return errors.New("already revising that contract")
+	}
+	c.revising[id] = true
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.revising, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.staticContracts.SyncMerkleRoots(host, id, c.tg.StopChan()); err != nil {
+		c.log.Printf("WARN: failed to sync Merkle roots of contract %v: %v", id, err)
+		return err
+	}
+	c.log.Println("INFO: synced Merkle roots of contract", id)
+	return nil
+}
+
+// resolveID returns the ID of the most recent renewal of id. If the contract
+// was never renewed, id is returned. The caller must hold the lock.
+func (c *Contractor) resolveID(id types.FileContractID) types.FileContractID {
+	for {
+		newID, renewed := c.renewedTo[id]
+		if !renewed || newID == id {
+			return id
+		}
+		id = newID
+	}
+}
+
 // ResolveIDToPubKey returns the ID of the most recent renewal of id.
 func (c *Contractor) ResolveIDToPubKey(id types.FileContractID) types.SiaPublicKey {
 	c.mu.RLock()
@@ -159,8 +159,12 @@ func (c *Contractor) Downloader(pk types.SiaPublicKey, cancel <-chan struct{}) (
 		}
 	}()
 
-	// create downloader
+	// create downloader. If we have desynced from the host, recover the
+	// contract and try again.
 	d, err := c.staticContracts.NewDownloader(host, contract.ID, c.hdb, cancel)
+	if proto.IsRevisionMismatch(err) && c.managedRecoverContract(host, contract.ID, cancel) == nil {
+		d, err = c.staticContracts.NewDownloader(host, contract.ID, c.hdb, cancel)
+	}
 	if err != nil {
 		return nil, err
 	}
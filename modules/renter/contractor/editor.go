@@ -172,8 +172,12 @@ func (c *Contractor) Editor(pk types.SiaPublicKey, cancel <-chan struct{}) (_ Ed
 		}
 	}()
 
-	// Create the editor.
+	// Create the editor. If we have desynced from the host, recover the
+	// contract and try again.
 	e, err := c.staticContracts.NewEditor(host, contract.ID, height, c.hdb, cancel)
+	if proto.IsRevisionMismatch(err) && c.managedRecoverContract(host, contract.ID, cancel) == nil {
+		e, err = c.staticContracts.NewEditor(host, contract.ID, height, c.hdb, cancel)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,109 @@
+package contractor
+
+import (
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// The types of events that are sent to contract event subscribers.
+const (
+	// ContractFormed is sent after a new contract was formed with a host.
+	ContractFormed ContractEventType = iota
+
+	// ContractRenewed is sent after a contract was renewed. The ID of the
+	// event is the ID of the new contract.
+	ContractRenewed
+
+	// ContractCanceled is sent after a contract was canceled by the user.
+	ContractCanceled
+
+	// ContractFailed is sent if forming or renewing a contract with a host
+	// failed. When forming a contract fails, the ID of the event is empty.
+	ContractFailed
+
+	// ContractRecovered is sent after a contract was recovered from the
+	// wallet seed.
+	ContractRecovered
+
+	// SpendingAlertTriggered is sent when the fraction of the allowance that
+	// has been spent in the current period reaches the spending alert
+	// threshold. The ID of the event is empty.
+	SpendingAlertTriggered
+)
+
+type (
+	// ContractEventType identifies the kind of a ContractEvent.
+	ContractEventType int
+
+	// A ContractEvent describes a change to the contracts of the contractor.
+	ContractEvent struct {
+		Type          ContractEventType
+		ID            types.FileContractID
+		HostPublicKey types.SiaPublicKey
+
+		// Err is the reason for a ContractFailed event.
+		Err error
+	}
+)
+
+// String implements the fmt.Stringer interface.
+func (t ContractEventType) String() string {
+	switch t {
+	case ContractFormed:
+		return "formed"
+	case ContractRenewed:
+		return "renewed"
+	case ContractCanceled:
+		return "canceled"
+	case ContractFailed:
+		return "failed"
+	case ContractRecovered:
+		return "recovered"
+	case SpendingAlertTriggered:
+		return "spendingalert"
+	default:
+		return "unknown"
+	}
+}
+
+// Subscribe registers ch to receive contract events. Events are sent without
+// blocking; if ch is not ready to receive an event, the event is dropped and a
+// warning is logged. Subscribers should therefore use a buffered channel. ch
+// must not be closed before it has been passed to Unsubscribe.
+func (c *Contractor) Subscribe(ch chan<- ContractEvent) error {
+	if err := c.tg.Add(); err != nil {
+		return err
+	}
+	defer c.tg.Done()
+
+	c.subscribersMu.Lock()
+	c.subscribers = append(c.subscribers, ch)
+	c.subscribersMu.Unlock()
+	return nil
+}
+
+// Unsubscribe stops sending contract events to ch.
+func (c *Contractor) Unsubscribe(ch chan<- ContractEvent) {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+	for i := range c.subscribers {
+		if c.subscribers[i] == ch {
+			c.subscribers = append(c.subscribers[:i], c.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// managedNotifySubscribers sends ev to all subscribers. Subscribers that are
+// not ready to receive the event are skipped so that a slow subscriber can't
+// stall contract maintenance.
+func (c *Contractor) managedNotifySubscribers(ev ContractEvent) {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+	for _, ch := range c.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			c.log.Printf("WARN: dropped %v event for contract %v: subscriber is not ready", ev.Type, ev.ID)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package contractor
+
+import (
+	"fmt"
+)
+
+// errInvalidFormationWorkers is returned by SetFormationWorkers if the number
+// of workers is zero or exceeds maxContractFormationWorkers.
+var errInvalidFormationWorkers = fmt.Errorf("number of formation workers must be between 1 and %v", maxContractFormationWorkers)
+
+// FormationWorkers returns the maximum number of hosts that the contractor
+// negotiates new contracts with at the same time.
+func (c *Contractor) FormationWorkers() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.formationWorkers
+}
+
+// SetFormationWorkers sets the maximum number of hosts that the contractor
+// negotiates new contracts with at the same time. More workers form missing
+// contracts faster at the cost of more concurrent connections. The new value
+// applies to the next contract formation pass.
+func (c *Contractor) SetFormationWorkers(n uint64) error {
+	if n == 0 || n > maxContractFormationWorkers {
+		return errInvalidFormationWorkers
+	}
+	c.mu.Lock()
+	c.formationWorkers = n
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.log.Printf("INFO: set number of formation workers to %v", n)
+	return nil
+}
@@ -0,0 +1,90 @@
+package contractor
+
+import (
+	"math/big"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// The health of a contract is a weighted average of several factors that are
+// each scored between 0 and 1. The weights add up to 1.
+const (
+	// healthWeightFunds is the weight of the fraction of the contract's
+	// spendable funds that is left.
+	healthWeightFunds = 0.3
+
+	// healthWeightOnline is the weight of the host being online.
+	healthWeightOnline = 0.3
+
+	// healthWeightExpiry is the weight of the fraction of the contract's
+	// duration that is left.
+	healthWeightExpiry = 0.2
+
+	// healthWeightUtility is the weight of the contract being good for upload
+	// and renewal.
+	healthWeightUtility = 0.2
+)
+
+// fraction returns x/y clamped to [0, 1]. If y is zero, 0 is returned.
+func fraction(x, y *big.Int) float64 {
+	if y.Sign() <= 0 || x.Sign() <= 0 {
+		return 0
+	}
+	f, _ := new(big.Rat).SetFrac(x, y).Float64()
+	if f > 1 {
+		return 1
+	}
+	return f
+}
+
+// contractHealth scores the health of contract between 0 and 1 at
+// blockHeight. A contract with a host that is online, with most of its funds
+// and duration left and that is good for upload and renewal has a health of 1.
+func contractHealth(contract modules.RenterContract, hostOnline bool, blockHeight types.BlockHeight) float64 {
+	// Fraction of the funds that the renter could spend when the contract was
+	// formed which is still left.
+	fees := contract.ContractFee.Add(contract.TxnFee).Add(contract.SiafundFee)
+	var funds float64
+	if contract.TotalCost.Cmp(fees) > 0 {
+		funds = fraction(contract.RemainingFunds().Big(), contract.TotalCost.Sub(fees).Big())
+	}
+
+	var online float64
+	if hostOnline {
+		online = 1
+	}
+
+	// Fraction of the contract's duration that is left.
+	var expiry float64
+	if contract.EndHeight > blockHeight && contract.EndHeight > contract.StartHeight {
+		left := new(big.Int).SetUint64(uint64(contract.EndHeight - blockHeight))
+		duration := new(big.Int).SetUint64(uint64(contract.EndHeight - contract.StartHeight))
+		expiry = fraction(left, duration)
+	}
+
+	var utility float64
+	if contract.Utility.GoodForUpload {
+		utility += 0.5
+	}
+	if contract.Utility.GoodForRenew {
+		utility += 0.5
+	}
+
+	return healthWeightFunds*funds + healthWeightOnline*online + healthWeightExpiry*expiry + healthWeightUtility*utility
+}
+
+// ContractHealth returns a score between 0 and 1 that summarizes the health
+// of the contract with the given id. It combines the contract's remaining
+// funds, whether its host is online, the time until the contract expires and
+// its utility. The bool is false if the contractor has no such contract.
+func (c *Contractor) ContractHealth(id types.FileContractID) (float64, bool) {
+	contract, exists := c.staticContracts.View(id)
+	if !exists {
+		return 0, false
+	}
+	c.mu.RLock()
+	blockHeight := c.blockHeight
+	c.mu.RUnlock()
+	return contractHealth(contract, !c.IsOffline(contract.HostPublicKey), blockHeight), true
+}
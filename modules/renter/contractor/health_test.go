@@ -0,0 +1,55 @@
+package contractor
+
+import (
+	"math"
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestContractHealth tests the contractHealth function.
+func TestContractHealth(t *testing.T) {
+	contract := modules.RenterContract{
+		StartHeight: 100,
+		EndHeight:   200,
+		TotalCost:   types.NewCurrency64(110),
+		ContractFee: types.NewCurrency64(10),
+		RenterFunds: types.NewCurrency64(100),
+		Utility: modules.ContractUtility{
+			GoodForUpload: true,
+			GoodForRenew:  true,
+		},
+	}
+	equal := func(a, b float64) bool {
+		return math.Abs(a-b) < 1e-9
+	}
+
+	// A fresh contract with an online host is perfectly healthy.
+	if h := contractHealth(contract, true, 100); !equal(h, 1) {
+		t.Fatal("expected health 1, got", h)
+	}
+	// An offline host loses the online weight.
+	if h := contractHealth(contract, false, 100); !equal(h, 1-healthWeightOnline) {
+		t.Fatal("wrong health for offline host:", h)
+	}
+	// Half of the funds spent and half of the duration passed.
+	spent := contract
+	spent.UploadSpending = types.NewCurrency64(50)
+	spent.RenterFunds = types.NewCurrency64(50)
+	expected := healthWeightFunds*0.5 + healthWeightOnline + healthWeightExpiry*0.5 + healthWeightUtility
+	if h := contractHealth(spent, true, 150); !equal(h, expected) {
+		t.Fatalf("expected health %v, got %v", expected, h)
+	}
+	// A contract that is neither good for upload nor renew and has expired
+	// only scores for its funds and host.
+	bad := contract
+	bad.Utility = modules.ContractUtility{}
+	if h := contractHealth(bad, true, 200); !equal(h, healthWeightFunds+healthWeightOnline) {
+		t.Fatal("wrong health for unusable contract:", h)
+	}
+	// A contract without funds doesn't divide by zero.
+	if h := contractHealth(modules.RenterContract{}, false, 0); h != 0 {
+		t.Fatal("expected health 0, got", h)
+	}
+}
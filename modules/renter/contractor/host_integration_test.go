@@ -3,9 +3,11 @@ package contractor
 import (
 	"bytes"
 	"errors"
+	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -18,8 +20,10 @@ import (
 	"gitlab.com/NebulousLabs/Sia/modules/host"
 	"gitlab.com/NebulousLabs/Sia/modules/miner"
 	"gitlab.com/NebulousLabs/Sia/modules/renter/hostdb"
+	"gitlab.com/NebulousLabs/Sia/modules/renter/proto"
 	"gitlab.com/NebulousLabs/Sia/modules/transactionpool"
 	modWallet "gitlab.com/NebulousLabs/Sia/modules/wallet"
+	"gitlab.com/NebulousLabs/Sia/persist"
 	"gitlab.com/NebulousLabs/Sia/types"
 	"gitlab.com/NebulousLabs/fastrand"
 )
@@ -62,6 +66,12 @@ func newTestingWallet(testdir string, cs modules.ConsensusSet, tp modules.Transa
 
 // newTestingHost is a helper function that creates a ready-to-use host.
 func newTestingHost(testdir string, cs modules.ConsensusSet, tp modules.TransactionPool) (modules.Host, error) {
+	return newCustomTestingHost(testdir, cs, tp, modules.ProdDependencies)
+}
+
+// newCustomTestingHost is a helper function that creates a ready-to-use host
+// using the provided dependencies.
+func newCustomTestingHost(testdir string, cs modules.ConsensusSet, tp modules.TransactionPool, deps modules.Dependencies) (modules.Host, error) {
 	g, err := gateway.New("localhost:0", false, filepath.Join(testdir, modules.GatewayDir))
 	if err != nil {
 		return nil, err
@@ -70,7 +80,7 @@ func newTestingHost(testdir string, cs modules.ConsensusSet, tp modules.Transact
 	if err != nil {
 		return nil, err
 	}
-	h, err := host.New(cs, g, tp, w, "localhost:0", filepath.Join(testdir, modules.HostDir))
+	h, err := host.NewCustomHost(deps, cs, g, tp, w, "localhost:0", filepath.Join(testdir, modules.HostDir))
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +124,13 @@ func newTestingContractor(testdir string, g modules.Gateway, cs modules.Consensu
 // newTestingTrio creates a Host, Contractor, and TestMiner that can be used
 // for testing host/renter interactions.
 func newTestingTrio(name string) (modules.Host, *Contractor, modules.TestMiner, error) {
+	return newCustomTestingTrio(name, modules.ProdDependencies)
+}
+
+// newCustomTestingTrio creates a Host, Contractor, and TestMiner that can be
+// used for testing host/renter interactions. The host uses the provided
+// dependencies.
+func newCustomTestingTrio(name string, hostDeps modules.Dependencies) (modules.Host, *Contractor, modules.TestMiner, error) {
 	testdir := build.TempDir("contractor", name)
 
 	// create miner
@@ -154,7 +171,7 @@ func newTestingTrio(name string) (modules.Host, *Contractor, modules.TestMiner,
 	}
 
 	// create host and contractor, using same consensus set and gateway
-	h, err := newTestingHost(filepath.Join(testdir, "Host"), cs, tp)
+	h, err := newCustomTestingHost(filepath.Join(testdir, "Host"), cs, tp, hostDeps)
 	if err != nil {
 		return nil, nil, nil, build.ExtendErr("error creating testing host", err)
 	}
@@ -428,6 +445,88 @@ func TestIntegrationRenew(t *testing.T) {
 	}
 }
 
+// TestIntegrationRenewContract tests that a contract can be renewed on
+// request, and that overlapping renewals are rejected.
+func TestIntegrationRenewContract(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, _, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	defer c.Close()
+
+	// get the host's entry from the db
+	hostEntry, ok := c.hdb.Host(h.PublicKey())
+	if !ok {
+		t.Fatal("no entry for host in db")
+	}
+
+	// form a contract with the host
+	_, contract, err := c.managedNewContract(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.managedUpdateContractUtility(contract.ID, modules.ContractUtility{GoodForRenew: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without an allowance the contract can't be renewed. The allowance is
+	// set directly so that contract maintenance doesn't run.
+	if _, err := c.RenewContract(contract.ID); err != errNoAllowance {
+		t.Fatal("expected errNoAllowance, got", err)
+	}
+	c.mu.Lock()
+	c.allowance = modules.Allowance{
+		Funds:       types.SiacoinPrecision.Mul64(500),
+		Hosts:       1,
+		Period:      100,
+		RenewWindow: 10,
+	}
+	c.currentPeriod = c.blockHeight
+	c.mu.Unlock()
+
+	// A contract that is already being renewed is rejected.
+	c.mu.Lock()
+	c.renewing[contract.ID] = true
+	c.mu.Unlock()
+	if _, err := c.RenewContract(contract.ID); err != errContractRenewing {
+		t.Fatal("expected errContractRenewing, got", err)
+	}
+	c.mu.Lock()
+	delete(c.renewing, contract.ID)
+	c.mu.Unlock()
+
+	// Renew the contract.
+	newID, err := c.RenewContract(contract.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newContract, ok := c.staticContracts.View(newID)
+	if !ok {
+		t.Fatal("renewed contract is not in the contract set")
+	}
+	if newContract.EndHeight != c.contractEndHeight() {
+		t.Fatalf("expected end height %v, got %v", c.contractEndHeight(), newContract.EndHeight)
+	}
+	if _, ok := c.staticContracts.View(contract.ID); ok {
+		t.Fatal("old contract is still in the contract set")
+	}
+	c.mu.RLock()
+	renewedFrom := c.renewedFrom[newID]
+	c.mu.RUnlock()
+	if renewedFrom != contract.ID {
+		t.Fatal("contracts were not linked")
+	}
+	if reason := c.OldContractReasons()[contract.ID]; reason != oldContractRenewed {
+		t.Fatalf("expected retired reason %q, got %q", oldContractRenewed, reason)
+	}
+}
+
 // TestIntegrationDownloaderCaching tests that downloaders are properly cached
 // by the contractor. When two downloaders are requested for the same
 // contract, only one underlying downloader should be created.
@@ -674,3 +773,475 @@ func TestContractPresenceLeak(t *testing.T) {
 		t.Fatalf("Expected to get equal errors, got %q and %q.", errors[0], errors[1])
 	}
 }
+
+// TestIntegrationCancelContract tests that a canceled contract stays
+// !GoodForUpload and !GoodForRenew when the allowance is changed and contract
+// maintenance runs.
+func TestIntegrationCancelContract(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, _, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	defer c.Close()
+
+	// get the host's entry from the db
+	hostEntry, ok := c.hdb.Host(h.PublicKey())
+	if !ok {
+		t.Fatal("no entry for host in db")
+	}
+
+	// form a contract with the host and cancel it
+	_, contract, err := c.managedNewContract(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CancelContract(contract.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Setting an allowance unlocks the contracts that were locked by
+	// canceling the allowance, but not the canceled contract.
+	err = c.SetAllowance(modules.Allowance{
+		Funds:       types.SiacoinPrecision.Mul64(500),
+		Hosts:       1,
+		Period:      100,
+		RenewWindow: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.managedMarkContractsUtility(); err != nil {
+		t.Fatal(err)
+	}
+	utility, ok := c.managedContractUtility(contract.ID)
+	if !ok {
+		t.Fatal("canceled contract is not in the contract set")
+	}
+	if utility.GoodForUpload || utility.GoodForRenew || !utility.Locked {
+		t.Fatal("canceled contract became usable again:", utility)
+	}
+
+	// The contract stays canceled after a restart.
+	data := c.persistData()
+	if len(data.CanceledContracts) != 1 || data.CanceledContracts[0] != contract.ID {
+		t.Fatal("canceled contract was not persisted:", data.CanceledContracts)
+	}
+}
+
+// TestIntegrationPinContract tests that a pinned contract is neither renewed
+// nor changed by contract maintenance or by changes to the allowance.
+func TestIntegrationPinContract(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, _, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	defer c.Close()
+
+	// get the host's entry from the db
+	hostEntry, ok := c.hdb.Host(h.PublicKey())
+	if !ok {
+		t.Fatal("no entry for host in db")
+	}
+
+	// Unknown contracts can't be pinned.
+	if err := c.PinContract(types.FileContractID{1}, true); err != errPinUnknownContract {
+		t.Fatal("expected errPinUnknownContract, got", err)
+	}
+
+	// Form a contract that is within the renew window of the allowance below,
+	// so that maintenance would mark it !GoodForUpload and renew it.
+	_, contract, err := c.managedNewContract(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinnedUtility := modules.ContractUtility{GoodForUpload: true, GoodForRenew: true}
+	if err := c.managedUpdateContractUtility(contract.ID, pinnedUtility); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PinContract(contract.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	checkPinned := func(step string) {
+		t.Helper()
+		utility, ok := c.managedContractUtility(contract.ID)
+		if !ok {
+			t.Fatalf("%v: pinned contract is not in the contract set", step)
+		}
+		if utility != pinnedUtility {
+			t.Fatalf("%v: utility of pinned contract changed: %v", step, utility)
+		}
+	}
+
+	// Canceling the allowance leaves the pinned contract as-is.
+	if err := c.managedCancelAllowance(); err != nil {
+		t.Fatal(err)
+	}
+	checkPinned("cancel allowance")
+
+	// Setting an allowance and running maintenance doesn't touch it either.
+	err = c.SetAllowance(modules.Allowance{
+		Funds:       types.SiacoinPrecision.Mul64(500),
+		Hosts:       1,
+		Period:      100,
+		RenewWindow: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPinned("set allowance")
+	// Wait for the maintenance launched by SetAllowance, then run another
+	// round.
+	c.maintenanceLock.Lock()
+	c.maintenanceLock.Unlock()
+	c.threadedContractMaintenance()
+	checkPinned("maintenance")
+	c.mu.RLock()
+	_, renewed := c.renewedTo[contract.ID]
+	c.mu.RUnlock()
+	if renewed {
+		t.Fatal("pinned contract was renewed")
+	}
+}
+
+// dependencyCorruptSectorRoots makes the host replace the first sector root
+// it sends in RPCSectorRoots.
+type dependencyCorruptSectorRoots struct {
+	modules.ProductionDependencies
+}
+
+// Disrupt returns true for the CorruptSectorRoots disrupt.
+func (*dependencyCorruptSectorRoots) Disrupt(s string) bool {
+	return s == "CorruptSectorRoots"
+}
+
+// uploadTestSectors forms a contract with the host of the testing trio and
+// uploads n sectors to it. It returns the contract, the path of its contract
+// file and the uploaded sector roots.
+func uploadTestSectors(t *testing.T, h modules.Host, c *Contractor, n int) (modules.RenterContract, string, []crypto.Hash) {
+	t.Helper()
+	hostEntry, ok := c.hdb.Host(h.PublicKey())
+	if !ok {
+		t.Fatal("no entry for host in db")
+	}
+	_, contract, err := c.managedNewContract(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	editor, err := c.Editor(contract.HostPublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roots []crypto.Hash
+	for i := 0; i < n; i++ {
+		root, err := editor.Upload(fastrand.Bytes(int(modules.SectorSize)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+	}
+	if err := editor.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(build.TempDir("contractor", t.Name()), "Contractor", "contractor", "contracts", contract.ID.String()+".contract")
+	return contract, path, roots
+}
+
+// readContractRoots returns the sector roots stored in the contract file at
+// path, assuming that it contains n roots.
+func readContractRoots(t *testing.T, path string, n int) []crypto.Hash {
+	t.Helper()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = data[len(data)-n*crypto.HashSize:]
+	roots := make([]crypto.Hash, n)
+	for i := range roots {
+		copy(roots[i][:], data[i*crypto.HashSize:])
+	}
+	return roots
+}
+
+// TestIntegrationSyncMerkleRoots tests that the contractor rebuilds corrupted
+// or lost sector roots of a contract from the roots stored by the host.
+func TestIntegrationSyncMerkleRoots(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, _, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	defer c.Close()
+
+	const numSectors = 3
+	contract, path, roots := uploadTestSectors(t, h, c, numSectors)
+	if stored := readContractRoots(t, path, numSectors); !reflect.DeepEqual(stored, roots) {
+		t.Fatal("stored roots don't match the uploaded roots")
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootsOffset := fi.Size() - numSectors*crypto.HashSize
+
+	// Corrupt the local roots and sync them with the host.
+	f, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt(fastrand.Bytes(numSectors*crypto.HashSize), rootsOffset); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SyncMerkleRoots(contract.ID); err != nil {
+		t.Fatal(err)
+	}
+	if stored := readContractRoots(t, path, numSectors); !reflect.DeepEqual(stored, roots) {
+		t.Fatal("corrupted roots weren't restored")
+	}
+
+	// Drop the local roots and sync them with the host.
+	if err := os.Truncate(path, rootsOffset); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SyncMerkleRoots(contract.ID); err != nil {
+		t.Fatal(err)
+	}
+	if stored := readContractRoots(t, path, numSectors); !reflect.DeepEqual(stored, roots) {
+		t.Fatal("dropped roots weren't restored")
+	}
+
+	// The restored roots can be used to revise the contract again.
+	editor, err := c.Editor(contract.HostPublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := editor.Upload(fastrand.Bytes(int(modules.SectorSize))); err != nil {
+		t.Fatal(err)
+	}
+	if err := editor.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestIntegrationSyncMerkleRootsInvalid tests that the contractor rejects
+// sector roots that don't match the contract's Merkle root and keeps its own
+// roots.
+func TestIntegrationSyncMerkleRootsInvalid(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, _, err := newCustomTestingTrio(t.Name(), &dependencyCorruptSectorRoots{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	defer c.Close()
+
+	const numSectors = 3
+	contract, path, roots := uploadTestSectors(t, h, c, numSectors)
+	if err := c.SyncMerkleRoots(contract.ID); err != proto.ErrSectorRootsInvalid {
+		t.Fatal("expected ErrSectorRootsInvalid, got", err)
+	}
+	if stored := readContractRoots(t, path, numSectors); !reflect.DeepEqual(stored, roots) {
+		t.Fatal("local roots were replaced by invalid roots")
+	}
+}
+
+// reloadContractor closes c and creates a new contractor from the persist
+// directory of the testing trio, using the same modules.
+func reloadContractor(t *testing.T, c *Contractor) *Contractor {
+	t.Helper()
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(build.TempDir("contractor", t.Name()), "Contractor", "contractor")
+	contractSet, err := proto.NewContractSet(filepath.Join(dir, "contracts"), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newC, err := NewCustomContractor(c.cs, c.wallet, c.tpool, c.hdb, contractSet, NewPersist(dir), persist.NewLogger(ioutil.Discard), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newC
+}
+
+// TestIntegrationRecoverContract tests that the contractor adopts the host's
+// revision of a contract after the renter lost the revision of a download.
+func TestIntegrationRecoverContract(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, _, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if err := c.RecoverContract(types.FileContractID{1}); err == nil {
+		t.Fatal("expected an error for an unknown contract")
+	}
+
+	contract, path, roots := uploadTestSectors(t, h, c, 1)
+	before, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Download the sector, then roll back the contract file to lose the
+	// revision of the download.
+	downloader, err := c.Downloader(contract.HostPublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := downloader.Sector(roots[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := downloader.Close(); err != nil {
+		t.Fatal(err)
+	}
+	downloaded, ok := c.staticContracts.View(contract.ID)
+	if !ok {
+		t.Fatal("contract is not in the contract set")
+	}
+	if err := ioutil.WriteFile(path, before, 0600); err != nil {
+		t.Fatal(err)
+	}
+	c = reloadContractor(t, c)
+	defer c.Close()
+	desynced, ok := c.staticContracts.View(contract.ID)
+	if !ok {
+		t.Fatal("contract is not in the contract set")
+	}
+	if desynced.Transaction.FileContractRevisions[0].NewRevisionNumber >= downloaded.Transaction.FileContractRevisions[0].NewRevisionNumber {
+		t.Fatal("contract wasn't rolled back")
+	}
+
+	// Recovering the contract adopts the host's revision, including the
+	// money spent on the download.
+	if err := c.RecoverContract(contract.ID); err != nil {
+		t.Fatal(err)
+	}
+	recovered, ok := c.staticContracts.View(contract.ID)
+	if !ok {
+		t.Fatal("contract is not in the contract set")
+	}
+	if recovered.Transaction.FileContractRevisions[0].NewRevisionNumber != downloaded.Transaction.FileContractRevisions[0].NewRevisionNumber {
+		t.Fatalf("expected revision %v, got %v", downloaded.Transaction.FileContractRevisions[0].NewRevisionNumber, recovered.Transaction.FileContractRevisions[0].NewRevisionNumber)
+	}
+	if !recovered.RenterFunds.Equals(downloaded.RenterFunds) {
+		t.Fatalf("expected renter funds %v, got %v", downloaded.RenterFunds, recovered.RenterFunds)
+	}
+	if !recovered.DownloadSpending.Equals(downloaded.DownloadSpending) {
+		t.Fatalf("expected download spending %v, got %v", downloaded.DownloadSpending, recovered.DownloadSpending)
+	}
+
+	// The contract can be used again.
+	downloader, err = c.Downloader(contract.HostPublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := downloader.Sector(roots[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := downloader.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestIntegrationRecoverContractAutomatically tests that the contractor
+// recovers a contract that lost the revision of an upload when an editor is
+// requested, re-downloading the sector roots that were added by the upload.
+func TestIntegrationRecoverContractAutomatically(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, _, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	contract, path, roots := uploadTestSectors(t, h, c, 1)
+	before, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Upload another sector, then roll back the contract file to lose the
+	// revision of the upload.
+	editor, err := c.Editor(contract.HostPublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := editor.Upload(fastrand.Bytes(int(modules.SectorSize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := editor.Close(); err != nil {
+		t.Fatal(err)
+	}
+	roots = append(roots, root)
+	uploaded, ok := c.staticContracts.View(contract.ID)
+	if !ok {
+		t.Fatal("contract is not in the contract set")
+	}
+	if err := ioutil.WriteFile(path, before, 0600); err != nil {
+		t.Fatal(err)
+	}
+	c = reloadContractor(t, c)
+	defer c.Close()
+
+	// Requesting an editor recovers the contract.
+	editor, err = c.Editor(contract.HostPublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := editor.Close(); err != nil {
+		t.Fatal(err)
+	}
+	recovered, ok := c.staticContracts.View(contract.ID)
+	if !ok {
+		t.Fatal("contract is not in the contract set")
+	}
+	if recovered.Transaction.FileContractRevisions[0].NewRevisionNumber != uploaded.Transaction.FileContractRevisions[0].NewRevisionNumber {
+		t.Fatalf("expected revision %v, got %v", uploaded.Transaction.FileContractRevisions[0].NewRevisionNumber, recovered.Transaction.FileContractRevisions[0].NewRevisionNumber)
+	}
+	if !recovered.RenterFunds.Equals(uploaded.RenterFunds) {
+		t.Fatalf("expected renter funds %v, got %v", uploaded.RenterFunds, recovered.RenterFunds)
+	}
+	if stored := readContractRoots(t, path, len(roots)); !reflect.DeepEqual(stored, roots) {
+		t.Fatal("recovered roots don't match the uploaded roots")
+	}
+
+	// The sector of the lost upload can be downloaded.
+	downloader, err := c.Downloader(contract.HostPublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := downloader.Sector(root); err != nil {
+		t.Fatal(err)
+	}
+	if err := downloader.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+package contractor
+
+import (
+	"errors"
+
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errHostBlacklisted is returned when the contractor is asked to form or
+	// renew a contract with a host that is on the blacklist.
+	errHostBlacklisted = errors.New("host is blacklisted")
+)
+
+// HostBlacklist returns the public keys of the hosts that the contractor will
+// not form or renew contracts with.
+func (c *Contractor) HostBlacklist() []types.SiaPublicKey {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	hosts := make([]types.SiaPublicKey, 0, len(c.hostBlacklist))
+	for _, pk := range c.hostBlacklist {
+		hosts = append(hosts, pk)
+	}
+	return hosts
+}
+
+// SetHostBlacklist replaces the set of hosts that the contractor will not form
+// or renew contracts with. Existing contracts with blacklisted hosts are
+// marked as !GoodForRenew during the next round of contract maintenance, which
+// is triggered immediately.
+func (c *Contractor) SetHostBlacklist(hosts []types.SiaPublicKey) error {
+	c.mu.Lock()
+	c.hostBlacklist = make(map[string]types.SiaPublicKey)
+	for _, pk := range hosts {
+		c.hostBlacklist[pk.String()] = pk
+	}
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.log.Printf("INFO: set host blacklist to %v hosts", len(hosts))
+
+	// Interrupt any existing maintenance and launch a new round of
+	// maintenance so that the blacklist is applied to existing contracts.
+	c.managedInterruptContractMaintenance()
+	go c.threadedContractMaintenance()
+	return nil
+}
+
+// managedIsBlacklisted returns true if the host with the given public key is
+// on the contractor's host blacklist.
+func (c *Contractor) managedIsBlacklisted(pk types.SiaPublicKey) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, exists := c.hostBlacklist[pk.String()]
+	return exists
+}
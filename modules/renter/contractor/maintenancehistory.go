@@ -0,0 +1,103 @@
+package contractor
+
+import (
+	"time"
+
+	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// The actions that are recorded in the maintenance history.
+const (
+	MaintenanceFormed         MaintenanceAction = "formed"
+	MaintenanceFormFailed     MaintenanceAction = "formfailed"
+	MaintenanceFormSkipped    MaintenanceAction = "formskipped"
+	MaintenanceRenewed        MaintenanceAction = "renewed"
+	MaintenanceRenewFailed    MaintenanceAction = "renewfailed"
+	MaintenanceRenewSkipped   MaintenanceAction = "renewskipped"
+	MaintenanceUtilityChanged MaintenanceAction = "utilitychanged"
+)
+
+var (
+	// maxMaintenanceHistory is the maximum number of records that are kept in
+	// the maintenance history. Older records are dropped first.
+	maxMaintenanceHistory = build.Select(build.Var{
+		Dev:      500,
+		Standard: 1000,
+		Testing:  20,
+	}).(int)
+
+	// maintenanceHistoryMaxAge is the age after which a record is no longer
+	// loaded from disk when the contractor starts up.
+	maintenanceHistoryMaxAge = build.Select(build.Var{
+		Dev:      24 * time.Hour,
+		Standard: 7 * 24 * time.Hour, // 1 week
+		Testing:  time.Minute,
+	}).(time.Duration)
+)
+
+type (
+	// MaintenanceAction describes what the contractor did during contract
+	// maintenance.
+	MaintenanceAction string
+
+	// A MaintenanceRecord describes a single action taken by the contractor
+	// during contract maintenance. ID is the contract that the action was
+	// taken on; it is empty if no contract was involved, e.g. when contract
+	// formation failed.
+	MaintenanceRecord struct {
+		Action        MaintenanceAction    `json:"action"`
+		BlockHeight   types.BlockHeight    `json:"blockheight"`
+		HostPublicKey types.SiaPublicKey   `json:"hostpublickey"`
+		ID            types.FileContractID `json:"id"`
+		Reason        string               `json:"reason"`
+		Time          time.Time            `json:"time"`
+	}
+)
+
+// MaintenanceHistory returns the most recent actions taken by contract
+// maintenance, ordered from oldest to newest.
+func (c *Contractor) MaintenanceHistory() []MaintenanceRecord {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]MaintenanceRecord(nil), c.maintenanceHistory...)
+}
+
+// managedRecordMaintenance adds a record to the maintenance history, dropping
+// the oldest records if the history has grown too large.
+func (c *Contractor) managedRecordMaintenance(action MaintenanceAction, id types.FileContractID, hpk types.SiaPublicKey, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordMaintenance(action, id, hpk, reason)
+}
+
+// recordMaintenance adds a record to the maintenance history. The caller must
+// hold the contractor lock.
+func (c *Contractor) recordMaintenance(action MaintenanceAction, id types.FileContractID, hpk types.SiaPublicKey, reason string) {
+	c.maintenanceHistory = append(c.maintenanceHistory, MaintenanceRecord{
+		Action:        action,
+		BlockHeight:   c.blockHeight,
+		HostPublicKey: hpk,
+		ID:            id,
+		Reason:        reason,
+		Time:          time.Now(),
+	})
+	if len(c.maintenanceHistory) > maxMaintenanceHistory {
+		c.maintenanceHistory = c.maintenanceHistory[len(c.maintenanceHistory)-maxMaintenanceHistory:]
+	}
+}
+
+// recentMaintenanceHistory returns the records of history that are younger
+// than maintenanceHistoryMaxAge, capped at maxMaintenanceHistory records.
+func recentMaintenanceHistory(history []MaintenanceRecord) []MaintenanceRecord {
+	var recent []MaintenanceRecord
+	for _, r := range history {
+		if time.Since(r.Time) < maintenanceHistoryMaxAge {
+			recent = append(recent, r)
+		}
+	}
+	if len(recent) > maxMaintenanceHistory {
+		recent = recent[len(recent)-maxMaintenanceHistory:]
+	}
+	return recent
+}
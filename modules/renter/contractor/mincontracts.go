@@ -0,0 +1,77 @@
+package contractor
+
+import (
+	"gitlab.com/NebulousLabs/Sia/modules"
+)
+
+// MinContracts returns the number of contracts that need to be good for upload
+// for the contractor to consider its contracts sufficiently redundant. A value
+// of zero disables the minimum.
+func (c *Contractor) MinContracts() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.minContracts
+}
+
+// SetMinContracts sets the number of contracts that need to be good for upload
+// for the contractor to consider its contracts sufficiently redundant. While
+// there are fewer, contract maintenance forms new contracts before renewing
+// existing ones. A new round of contract maintenance is triggered immediately.
+func (c *Contractor) SetMinContracts(n uint64) error {
+	c.mu.Lock()
+	c.minContracts = n
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.log.Printf("INFO: set minimum number of contracts to %v", n)
+
+	// Interrupt any existing maintenance and launch a new round of
+	// maintenance so that missing contracts are formed right away.
+	c.managedInterruptContractMaintenance()
+	go c.threadedContractMaintenance()
+	return nil
+}
+
+// ContractRedundancy reports whether enough contracts are good for upload to
+// satisfy the minimum number of contracts.
+func (c *Contractor) ContractRedundancy() modules.ContractRedundancy {
+	uploadContracts := c.managedUploadContracts()
+	c.mu.RLock()
+	minContracts := c.minContracts
+	effectiveMin := c.effectiveMinContracts()
+	c.mu.RUnlock()
+
+	status := modules.ContractRedundancyStatusHealthy
+	if uint64(uploadContracts) < effectiveMin {
+		status = modules.ContractRedundancyStatusInsufficient
+	}
+	return modules.ContractRedundancy{
+		MinContracts:    minContracts,
+		UploadContracts: uint64(uploadContracts),
+		Status:          status,
+	}
+}
+
+// effectiveMinContracts returns the minimum number of contracts capped at the
+// number of hosts in the allowance, since contract maintenance never forms
+// more contracts than that.
+func (c *Contractor) effectiveMinContracts() uint64 {
+	if c.minContracts > c.allowance.Hosts {
+		return c.allowance.Hosts
+	}
+	return c.minContracts
+}
+
+// managedUploadContracts returns the number of contracts that are good for
+// upload.
+func (c *Contractor) managedUploadContracts() int {
+	uploadContracts := 0
+	for _, id := range c.staticContracts.IDs() {
+		if cu, ok := c.managedContractUtility(id); ok && cu.GoodForUpload {
+			uploadContracts++
+		}
+	}
+	return uploadContracts
+}
@@ -0,0 +1,60 @@
+package contractor
+
+import (
+	"reflect"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// alignedPeriodStart returns the start of the aligned period that contains
+// height. Aligned periods start at the multiples of period counted from epoch.
+// An epoch in the future is aligned to the same boundaries, and heights
+// before the first boundary belong to a period that starts at 0.
+func alignedPeriodStart(height, period, epoch types.BlockHeight) types.BlockHeight {
+	if period == 0 {
+		return height
+	}
+	offset := epoch % period
+	if height < offset {
+		return 0
+	}
+	return offset + (height-offset)/period*period
+}
+
+// PeriodAlignment returns the settings that align the contractor's allowance
+// periods to a fixed epoch.
+func (c *Contractor) PeriodAlignment() modules.PeriodAlignment {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.periodAlign
+}
+
+// SetPeriodAlignment sets the settings that align the contractor's allowance
+// periods to a fixed epoch. If alignment is enabled and an allowance is set,
+// the current period is moved to the last aligned boundary right away, which
+// resets the period spending if the boundary lies after the current period's
+// start. Disabling the alignment keeps the current period, and the following
+// periods continue from it.
+func (c *Contractor) SetPeriodAlignment(pa modules.PeriodAlignment) error {
+	c.mu.Lock()
+	c.periodAlign = pa
+	realign := pa.Enabled && !reflect.DeepEqual(c.allowance, modules.Allowance{})
+	if realign {
+		c.currentPeriod = alignedPeriodStart(c.blockHeight, c.allowance.Period, pa.Epoch)
+	}
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.log.Printf("INFO: set period alignment to enabled: %v, epoch: %v", pa.Enabled, pa.Epoch)
+
+	// The end height of renewed contracts depends on the current period, so
+	// launch a new round of maintenance if the period moved.
+	if realign {
+		c.managedInterruptContractMaintenance()
+		go c.threadedContractMaintenance()
+	}
+	return nil
+}
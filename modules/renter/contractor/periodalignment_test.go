@@ -0,0 +1,29 @@
+package contractor
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestAlignedPeriodStart tests the alignedPeriodStart function.
+func TestAlignedPeriodStart(t *testing.T) {
+	tests := []struct {
+		height, period, epoch, start types.BlockHeight
+	}{
+		{0, 100, 0, 0},
+		{99, 100, 0, 0},
+		{100, 100, 0, 100},
+		{250, 100, 0, 200},
+		{250, 100, 30, 230},
+		{229, 100, 30, 130},
+		{20, 100, 30, 0},
+		{250, 100, 1030, 230}, // epoch in the future
+		{250, 0, 30, 250},
+	}
+	for _, test := range tests {
+		if start := alignedPeriodStart(test.height, test.period, test.epoch); start != test.start {
+			t.Errorf("height %v, period %v, epoch %v: expected %v, got %v", test.height, test.period, test.epoch, test.start, start)
+		}
+	}
+}
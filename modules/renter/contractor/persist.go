@@ -3,6 +3,7 @@ package contractor
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/modules/renter/proto"
@@ -12,26 +13,59 @@ import (
 	"gitlab.com/NebulousLabs/errors"
 )
 
+var (
+	// errNegativePersistInterval is returned by SetPersistInterval if the
+	// interval is negative.
+	errNegativePersistInterval = errors.New("persist interval can't be negative")
+)
+
 // contractorPersist defines what Contractor data persists across sessions.
 type contractorPersist struct {
-	Allowance     modules.Allowance               `json:"allowance"`
-	BlockHeight   types.BlockHeight               `json:"blockheight"`
-	CurrentPeriod types.BlockHeight               `json:"currentperiod"`
-	LastChange    modules.ConsensusChangeID       `json:"lastchange"`
-	OldContracts  []modules.RenterContract        `json:"oldcontracts"`
-	RenewedFrom   map[string]types.FileContractID `json:"renewedfrom"`
-	RenewedTo     map[string]types.FileContractID `json:"renewedto"`
+	Allowance              modules.Allowance                       `json:"allowance"`
+	AutoTopUp              modules.AutoTopUp                       `json:"autotopup"`
+	BlockHeight            types.BlockHeight                       `json:"blockheight"`
+	CanceledContracts      []types.FileContractID                  `json:"canceledcontracts"`
+	CurrentPeriod          types.BlockHeight                       `json:"currentperiod"`
+	FormationWorkers       uint64                                  `json:"formationworkers"`
+	HostBlacklist          []types.SiaPublicKey                    `json:"hostblacklist"`
+	HostSettings           map[string]modules.HostExternalSettings `json:"hostsettings"`
+	LastChange             modules.ConsensusChangeID               `json:"lastchange"`
+	MinContracts           uint64                                  `json:"mincontracts"`
+	MinHostUptime          float64                                 `json:"minhostuptime"`
+	OldContracts           []modules.RenterContract                `json:"oldcontracts"`
+	OldContractReasons     map[string]string                       `json:"oldcontractreasons"`
+	PeriodAlignment        modules.PeriodAlignment                 `json:"periodalignment"`
+	PersistInterval        time.Duration                           `json:"persistinterval"`
+	PinnedContracts        []types.FileContractID                  `json:"pinnedcontracts"`
+	PriceCeilings          modules.PriceCeilings                   `json:"priceceilings"`
+	RenewedFrom            map[string]types.FileContractID         `json:"renewedfrom"`
+	RenewedTo              map[string]types.FileContractID         `json:"renewedto"`
+	SpendingAlertThreshold float64                                 `json:"spendingalertthreshold"`
+
+	MaintenanceHistory []MaintenanceRecord `json:"maintenancehistory"`
 }
 
 // persistData returns the data in the Contractor that will be saved to disk.
 func (c *Contractor) persistData() contractorPersist {
 	data := contractorPersist{
-		Allowance:     c.allowance,
-		BlockHeight:   c.blockHeight,
-		CurrentPeriod: c.currentPeriod,
-		LastChange:    c.lastChange,
-		RenewedFrom:   make(map[string]types.FileContractID),
-		RenewedTo:     make(map[string]types.FileContractID),
+		Allowance:              c.allowance,
+		AutoTopUp:              c.autoTopUp,
+		BlockHeight:            c.blockHeight,
+		CurrentPeriod:          c.currentPeriod,
+		FormationWorkers:       c.formationWorkers,
+		HostSettings:           make(map[string]modules.HostExternalSettings),
+		LastChange:             c.lastChange,
+		MinContracts:           c.minContracts,
+		MinHostUptime:          c.minHostUptime,
+		OldContractReasons:     make(map[string]string),
+		PeriodAlignment:        c.periodAlign,
+		PersistInterval:        c.persistInterval,
+		PriceCeilings:          c.priceCeilings,
+		RenewedFrom:            make(map[string]types.FileContractID),
+		RenewedTo:              make(map[string]types.FileContractID),
+		SpendingAlertThreshold: c.spendingAlertThreshold,
+
+		MaintenanceHistory: c.maintenanceHistory,
 	}
 	for k, v := range c.renewedFrom {
 		data.RenewedFrom[k.String()] = v
@@ -39,9 +73,24 @@ func (c *Contractor) persistData() contractorPersist {
 	for k, v := range c.renewedTo {
 		data.RenewedTo[k.String()] = v
 	}
+	for k, v := range c.hostSettings {
+		data.HostSettings[k.String()] = v
+	}
 	for _, contract := range c.oldContracts {
 		data.OldContracts = append(data.OldContracts, contract)
 	}
+	for k, v := range c.oldContractReasons {
+		data.OldContractReasons[k.String()] = v
+	}
+	for _, pk := range c.hostBlacklist {
+		data.HostBlacklist = append(data.HostBlacklist, pk)
+	}
+	for id := range c.pinnedContracts {
+		data.PinnedContracts = append(data.PinnedContracts, id)
+	}
+	for id := range c.canceledContracts {
+		data.CanceledContracts = append(data.CanceledContracts, id)
+	}
 	return data
 }
 
@@ -53,9 +102,21 @@ func (c *Contractor) load() error {
 		return err
 	}
 	c.allowance = data.Allowance
+	c.autoTopUp = data.AutoTopUp
 	c.blockHeight = data.BlockHeight
 	c.currentPeriod = data.CurrentPeriod
+	// Contractors persisted before the number of formation workers was
+	// configurable keep the default.
+	if data.FormationWorkers != 0 {
+		c.formationWorkers = data.FormationWorkers
+	}
 	c.lastChange = data.LastChange
+	c.minContracts = data.MinContracts
+	c.minHostUptime = data.MinHostUptime
+	c.periodAlign = data.PeriodAlignment
+	c.persistInterval = data.PersistInterval
+	c.priceCeilings = data.PriceCeilings
+	c.spendingAlertThreshold = data.SpendingAlertThreshold
 	var fcid types.FileContractID
 	for k, v := range data.RenewedFrom {
 		if err := fcid.LoadString(k); err != nil {
@@ -69,23 +130,133 @@ func (c *Contractor) load() error {
 		}
 		c.renewedTo[fcid] = v
 	}
+	for k, v := range data.HostSettings {
+		if err := fcid.LoadString(k); err != nil {
+			return err
+		}
+		c.hostSettings[fcid] = v
+	}
 	for _, contract := range data.OldContracts {
 		c.oldContracts[contract.ID] = contract
 	}
+	for k, v := range data.OldContractReasons {
+		if err := fcid.LoadString(k); err != nil {
+			return err
+		}
+		c.oldContractReasons[fcid] = v
+	}
+	for _, pk := range data.HostBlacklist {
+		c.hostBlacklist[pk.String()] = pk
+	}
+	for _, id := range data.PinnedContracts {
+		c.pinnedContracts[id] = struct{}{}
+	}
+	for _, id := range data.CanceledContracts {
+		c.canceledContracts[id] = struct{}{}
+	}
+	c.maintenanceHistory = recentMaintenanceHistory(data.MaintenanceHistory)
 
 	return nil
 }
 
-// save saves the Contractor persistence data to disk.
+// save saves the Contractor persistence data to disk. If a persist interval
+// is set, the data is only marked as dirty and written by
+// threadedFlushPersist later on.
 func (c *Contractor) save() error {
+	if c.persistInterval > 0 {
+		c.persistDirty = true
+		return nil
+	}
 	return c.persist.save(c.persistData())
 }
 
 // saveSync saves the Contractor persistence data to disk and then syncs to disk.
+// It ignores the persist interval.
 func (c *Contractor) saveSync() error {
+	c.persistDirty = false
 	return c.persist.save(c.persistData())
 }
 
+// managedFlushPersist writes the persistence data to disk if there are changes
+// that haven't been written yet.
+func (c *Contractor) managedFlushPersist() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.persistDirty {
+		return nil
+	}
+	return c.saveSync()
+}
+
+// threadedFlushPersist periodically flushes the changes batched by save to
+// disk. The changes that are pending when the contractor shuts down are
+// flushed by the thread group.
+func (c *Contractor) threadedFlushPersist() {
+	if err := c.tg.Add(); err != nil {
+		return
+	}
+	defer c.tg.Done()
+
+	for {
+		c.mu.RLock()
+		interval := c.persistInterval
+		c.mu.RUnlock()
+
+		// Without an interval, changes are written immediately and there is
+		// nothing to flush until the interval changes.
+		var flush <-chan time.Time
+		if interval > 0 {
+			flush = time.After(interval)
+		}
+		select {
+		case <-c.tg.StopChan():
+			return
+		case <-c.persistIntervalChanged:
+			continue
+		case <-flush:
+		}
+		if err := c.managedFlushPersist(); err != nil {
+			c.log.Println("Unable to flush the contractor persistence:", err)
+		}
+	}
+}
+
+// PersistInterval returns the interval at which the contractor flushes changes
+// to disk. Zero means that changes are written immediately.
+func (c *Contractor) PersistInterval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.persistInterval
+}
+
+// SetPersistInterval sets the interval at which the contractor flushes changes
+// to disk. A non-zero interval batches frequent changes to the contractor's
+// metadata, trading a window in which they can be lost on a crash for fewer
+// disk writes. Contract revisions are always synced by the contract set, and
+// changes that affect funds, like a new allowance or a renewed contract, are
+// always written immediately. Setting the interval to zero writes pending
+// changes and restores synchronous persistence.
+func (c *Contractor) SetPersistInterval(interval time.Duration) error {
+	if interval < 0 {
+		return errNegativePersistInterval
+	}
+	c.mu.Lock()
+	c.persistInterval = interval
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.log.Printf("INFO: set persist interval to %v", interval)
+
+	// Wake up the flush thread so that it picks up the new interval.
+	select {
+	case c.persistIntervalChanged <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
 // convertPersist converts the pre-v1.3.1 contractor persist formats to the new
 // formats.
 func convertPersist(dir string) error {
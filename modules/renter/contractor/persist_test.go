@@ -1,14 +1,17 @@
 package contractor
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"gitlab.com/NebulousLabs/Sia/build"
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/modules/renter/proto"
+	"gitlab.com/NebulousLabs/Sia/persist"
 	"gitlab.com/NebulousLabs/Sia/types"
 )
 
@@ -31,6 +34,10 @@ func TestSaveLoad(t *testing.T) {
 		{2}: {ID: types.FileContractID{2}, HostPublicKey: types.SiaPublicKey{Key: []byte("baz")}},
 	}
 
+	c.oldContractReasons = map[types.FileContractID]string{
+		{1}: oldContractRenewed,
+	}
+
 	c.renewedFrom = map[types.FileContractID]types.FileContractID{
 		{1}: {2},
 	}
@@ -38,6 +45,23 @@ func TestSaveLoad(t *testing.T) {
 		{1}: {2},
 	}
 
+	blacklisted := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte("qux")}
+	c.hostBlacklist = map[string]types.SiaPublicKey{
+		blacklisted.String(): blacklisted,
+	}
+
+	c.hostSettings = map[types.FileContractID]modules.HostExternalSettings{
+		{2}: {StoragePrice: types.NewCurrency64(3)},
+	}
+
+	c.pinnedContracts = map[types.FileContractID]struct{}{
+		{3}: {},
+	}
+
+	c.minContracts = 4
+	c.minHostUptime = 0.75
+	c.priceCeilings = modules.PriceCeilings{MaxStoragePrice: types.NewCurrency64(5)}
+
 	// save, clear, and reload
 	err := c.save()
 	if err != nil {
@@ -45,8 +69,15 @@ func TestSaveLoad(t *testing.T) {
 	}
 	c.hdb = stubHostDB{}
 	c.oldContracts = make(map[types.FileContractID]modules.RenterContract)
+	c.oldContractReasons = make(map[types.FileContractID]string)
 	c.renewedFrom = make(map[types.FileContractID]types.FileContractID)
 	c.renewedTo = make(map[types.FileContractID]types.FileContractID)
+	c.hostBlacklist = make(map[string]types.SiaPublicKey)
+	c.hostSettings = make(map[types.FileContractID]modules.HostExternalSettings)
+	c.pinnedContracts = make(map[types.FileContractID]struct{})
+	c.minContracts = 0
+	c.minHostUptime = 0
+	c.priceCeilings = modules.PriceCeilings{}
 	err = c.load()
 	if err != nil {
 		t.Fatal(err)
@@ -59,12 +90,33 @@ func TestSaveLoad(t *testing.T) {
 		t.Fatal("oldContracts were not restored properly:", c.oldContracts)
 	}
 	id := types.FileContractID{2}
+	if c.oldContractReasons[types.FileContractID{1}] != oldContractRenewed {
+		t.Fatal("oldContractReasons not restored properly:", c.oldContractReasons)
+	}
 	if c.renewedFrom[types.FileContractID{1}] != id {
 		t.Fatal("renewedFrom not restored properly:", c.renewedFrom)
 	}
 	if c.renewedTo[types.FileContractID{1}] != id {
 		t.Fatal("renewedTo not restored properly:", c.renewedTo)
 	}
+	if _, ok := c.hostBlacklist[blacklisted.String()]; !ok || len(c.hostBlacklist) != 1 {
+		t.Fatal("hostBlacklist not restored properly:", c.hostBlacklist)
+	}
+	if settings, ok := c.ContractHostSettings(types.FileContractID{2}); !ok || settings.StoragePrice.Cmp64(3) != 0 {
+		t.Fatal("hostSettings not restored properly:", c.hostSettings)
+	}
+	if !c.managedIsPinned(types.FileContractID{3}) || len(c.pinnedContracts) != 1 {
+		t.Fatal("pinnedContracts not restored properly:", c.pinnedContracts)
+	}
+	if c.minContracts != 4 {
+		t.Fatal("minContracts not restored properly:", c.minContracts)
+	}
+	if c.minHostUptime != 0.75 {
+		t.Fatal("minHostUptime not restored properly:", c.minHostUptime)
+	}
+	if c.priceCeilings.MaxStoragePrice.Cmp64(5) != 0 {
+		t.Fatal("priceCeilings not restored properly:", c.priceCeilings)
+	}
 	// use stdPersist instead of mock
 	c.persist = NewPersist(build.TempDir("contractor", t.Name()))
 	os.MkdirAll(build.TempDir("contractor", t.Name()), 0700)
@@ -75,8 +127,15 @@ func TestSaveLoad(t *testing.T) {
 		t.Fatal(err)
 	}
 	c.oldContracts = make(map[types.FileContractID]modules.RenterContract)
+	c.oldContractReasons = make(map[types.FileContractID]string)
 	c.renewedFrom = make(map[types.FileContractID]types.FileContractID)
 	c.renewedTo = make(map[types.FileContractID]types.FileContractID)
+	c.hostBlacklist = make(map[string]types.SiaPublicKey)
+	c.hostSettings = make(map[types.FileContractID]modules.HostExternalSettings)
+	c.pinnedContracts = make(map[types.FileContractID]struct{})
+	c.minContracts = 0
+	c.minHostUptime = 0
+	c.priceCeilings = modules.PriceCeilings{}
 	err = c.load()
 	if err != nil {
 		t.Fatal(err)
@@ -88,12 +147,70 @@ func TestSaveLoad(t *testing.T) {
 	if !ok0 || !ok1 || !ok2 {
 		t.Fatal("oldContracts were not restored properly:", c.oldContracts)
 	}
+	if c.oldContractReasons[types.FileContractID{1}] != oldContractRenewed {
+		t.Fatal("oldContractReasons not restored properly:", c.oldContractReasons)
+	}
 	if c.renewedFrom[types.FileContractID{1}] != id {
 		t.Fatal("renewedFrom not restored properly:", c.renewedFrom)
 	}
 	if c.renewedTo[types.FileContractID{1}] != id {
 		t.Fatal("renewedTo not restored properly:", c.renewedTo)
 	}
+	if _, ok := c.hostBlacklist[blacklisted.String()]; !ok || len(c.hostBlacklist) != 1 {
+		t.Fatal("hostBlacklist not restored properly:", c.hostBlacklist)
+	}
+	if settings, ok := c.ContractHostSettings(types.FileContractID{2}); !ok || settings.StoragePrice.Cmp64(3) != 0 {
+		t.Fatal("hostSettings not restored properly:", c.hostSettings)
+	}
+	if !c.managedIsPinned(types.FileContractID{3}) || len(c.pinnedContracts) != 1 {
+		t.Fatal("pinnedContracts not restored properly:", c.pinnedContracts)
+	}
+	if c.minHostUptime != 0.75 {
+		t.Fatal("minHostUptime not restored properly:", c.minHostUptime)
+	}
+	if c.priceCeilings.MaxStoragePrice.Cmp64(5) != 0 {
+		t.Fatal("priceCeilings not restored properly:", c.priceCeilings)
+	}
+}
+
+// TestMaintenanceHistoryPersist tests that the maintenance history is capped
+// and that only recent records are restored after a restart.
+func TestMaintenanceHistoryPersist(t *testing.T) {
+	c := &Contractor{
+		persist:            new(memPersist),
+		hostBlacklist:      make(map[string]types.SiaPublicKey),
+		oldContracts:       make(map[types.FileContractID]modules.RenterContract),
+		oldContractReasons: make(map[types.FileContractID]string),
+		renewedFrom:        make(map[types.FileContractID]types.FileContractID),
+		renewedTo:          make(map[types.FileContractID]types.FileContractID),
+	}
+
+	// Record more actions than the history can hold.
+	for i := 0; i < maxMaintenanceHistory+5; i++ {
+		c.managedRecordMaintenance(MaintenanceRenewed, types.FileContractID{byte(i)}, types.SiaPublicKey{}, "")
+	}
+	history := c.MaintenanceHistory()
+	if len(history) != maxMaintenanceHistory {
+		t.Fatalf("expected %v records, got %v", maxMaintenanceHistory, len(history))
+	} else if history[0].ID != (types.FileContractID{5}) {
+		t.Fatal("oldest records should have been dropped first")
+	}
+
+	// Age the oldest record so that it is dropped on load.
+	c.maintenanceHistory[0].Time = time.Now().Add(-2 * maintenanceHistoryMaxAge)
+	if err := c.save(); err != nil {
+		t.Fatal(err)
+	}
+	c.maintenanceHistory = nil
+	if err := c.load(); err != nil {
+		t.Fatal(err)
+	}
+	history = c.MaintenanceHistory()
+	if len(history) != maxMaintenanceHistory-1 {
+		t.Fatalf("expected %v records, got %v", maxMaintenanceHistory-1, len(history))
+	} else if history[0].ID != (types.FileContractID{6}) {
+		t.Fatal("expected the aged record to be dropped")
+	}
 }
 
 // TestConvertPersist tests that contracts previously stored in the
@@ -140,3 +257,82 @@ func TestConvertPersist(t *testing.T) {
 		t.Fatal("recovered contract has wrong ID", m.ID)
 	}
 }
+
+// TestPersistInterval tests that changes are batched while a persist interval
+// is set and flushed periodically and upon shutdown.
+func TestPersistInterval(t *testing.T) {
+	p := new(memPersist)
+	c := &Contractor{
+		log:                    persist.NewLogger(ioutil.Discard),
+		persist:                p,
+		persistIntervalChanged: make(chan struct{}, 1),
+	}
+	c.tg.AfterStop(func() {
+		if err := c.managedFlushPersist(); err != nil {
+			t.Error(err)
+		}
+	})
+	go c.threadedFlushPersist()
+
+	// persistedHeight returns the block height that was written to disk.
+	persistedHeight := func() types.BlockHeight {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return p.BlockHeight
+	}
+
+	if err := c.SetPersistInterval(-time.Second); err != errNegativePersistInterval {
+		t.Fatalf("expected %v, got %v", errNegativePersistInterval, err)
+	}
+
+	// Without an interval, changes are written immediately.
+	c.mu.Lock()
+	c.blockHeight = 1
+	c.save()
+	c.mu.Unlock()
+	if persistedHeight() != 1 {
+		t.Fatal("change wasn't written immediately")
+	}
+
+	// With an interval, changes are written by the flush thread.
+	if err := c.SetPersistInterval(100 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	c.mu.RLock()
+	interval := p.PersistInterval
+	c.mu.RUnlock()
+	if interval != 100*time.Millisecond {
+		t.Fatal("persist interval wasn't persisted:", interval)
+	}
+	c.mu.Lock()
+	c.blockHeight = 2
+	c.save()
+	c.mu.Unlock()
+	if persistedHeight() != 1 {
+		t.Fatal("change wasn't batched")
+	}
+	err := build.Retry(50, 100*time.Millisecond, func() error {
+		if persistedHeight() != 2 {
+			return errors.New("change wasn't flushed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pending changes are flushed upon shutdown.
+	if err := c.SetPersistInterval(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	c.blockHeight = 3
+	c.save()
+	c.mu.Unlock()
+	if err := c.tg.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if persistedHeight() != 3 {
+		t.Fatal("pending change wasn't flushed upon shutdown")
+	}
+}
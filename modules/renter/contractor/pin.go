@@ -0,0 +1,60 @@
+package contractor
+
+import (
+	"errors"
+
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errPinUnknownContract is returned by PinContract if the contractor has
+	// no active contract with the given id.
+	errPinUnknownContract = errors.New("no active contract with that id")
+)
+
+// PinContract pins or unpins the contract with the given id. Contract
+// maintenance neither renews a pinned contract nor changes its utility, so the
+// contract is left as-is until it expires. Pinning doesn't prevent the
+// contract from being canceled manually.
+func (c *Contractor) PinContract(id types.FileContractID, pinned bool) error {
+	if _, exists := c.staticContracts.View(id); !exists && pinned {
+		return errPinUnknownContract
+	}
+
+	c.mu.Lock()
+	if pinned {
+		c.pinnedContracts[id] = struct{}{}
+	} else {
+		delete(c.pinnedContracts, id)
+	}
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if pinned {
+		c.log.Println("INFO: pinned contract", id)
+	} else {
+		c.log.Println("INFO: unpinned contract", id)
+	}
+	return nil
+}
+
+// PinnedContracts returns the ids of the pinned contracts.
+func (c *Contractor) PinnedContracts() []types.FileContractID {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ids := make([]types.FileContractID, 0, len(c.pinnedContracts))
+	for id := range c.pinnedContracts {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// managedIsPinned returns true if the contract with the given id is pinned.
+func (c *Contractor) managedIsPinned(id types.FileContractID) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, pinned := c.pinnedContracts[id]
+	return pinned
+}
@@ -0,0 +1,66 @@
+package contractor
+
+import (
+	"errors"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+)
+
+var (
+	// errPriceCeilingExceeded is returned when the contractor is asked to form
+	// or renew a contract with a host whose prices exceed the price ceilings.
+	errPriceCeilingExceeded = errors.New("host prices exceed the price ceilings")
+)
+
+// PriceCeilings returns the maximum prices of hosts that the contractor forms
+// and renews contracts with.
+func (c *Contractor) PriceCeilings() modules.PriceCeilings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.priceCeilings
+}
+
+// SetPriceCeilings sets the maximum prices of hosts that the contractor forms
+// and renews contracts with. Existing contracts with hosts that exceed a
+// ceiling are marked as !GoodForRenew during the next round of contract
+// maintenance, which is triggered immediately.
+func (c *Contractor) SetPriceCeilings(pc modules.PriceCeilings) error {
+	c.mu.Lock()
+	c.priceCeilings = pc
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.log.Printf("INFO: set price ceilings to %v storage, %v download, %v upload", pc.MaxStoragePrice.HumanString(), pc.MaxDownloadPrice.HumanString(), pc.MaxUploadPrice.HumanString())
+
+	// Interrupt any existing maintenance and launch a new round of
+	// maintenance so that the ceilings are applied to existing contracts.
+	c.managedInterruptContractMaintenance()
+	go c.threadedContractMaintenance()
+	return nil
+}
+
+// exceedsPriceCeilings returns true if any of the host's prices is above the
+// corresponding ceiling. Zero ceilings are ignored.
+func exceedsPriceCeilings(host modules.HostDBEntry, pc modules.PriceCeilings) bool {
+	if !pc.MaxStoragePrice.IsZero() && host.StoragePrice.Cmp(pc.MaxStoragePrice) > 0 {
+		return true
+	}
+	if !pc.MaxDownloadPrice.IsZero() && host.DownloadBandwidthPrice.Cmp(pc.MaxDownloadPrice) > 0 {
+		return true
+	}
+	if !pc.MaxUploadPrice.IsZero() && host.UploadBandwidthPrice.Cmp(pc.MaxUploadPrice) > 0 {
+		return true
+	}
+	return false
+}
+
+// managedExceedsPriceCeilings returns true if any of the host's prices is above
+// the contractor's price ceilings.
+func (c *Contractor) managedExceedsPriceCeilings(host modules.HostDBEntry) bool {
+	c.mu.RLock()
+	pc := c.priceCeilings
+	c.mu.RUnlock()
+	return exceedsPriceCeilings(host, pc)
+}
@@ -0,0 +1,127 @@
+package contractor
+
+import (
+	"errors"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errNoViableHosts is returned by ProjectAllowance if none of the hosts in
+	// the hostdb meet the price criteria of the contractor.
+	errNoViableHosts = errors.New("no hosts in the hostdb meet the contractor's price criteria")
+)
+
+// An AllowanceProjection describes how the funds of an allowance would be
+// allocated when forming the initial set of contracts. All per-contract values
+// are averages over the hosts that were sampled from the hostdb.
+type AllowanceProjection struct {
+	// Contracts is the number of contracts that would be formed. It can be
+	// lower than the number of hosts of the allowance if not enough hosts
+	// meet the price criteria.
+	Contracts uint64
+
+	// ContractFunding is the amount of money that is put into each new
+	// contract, including fees.
+	ContractFunding types.Currency
+
+	// ContractFee, TxnFee and SiafundFee are the estimated fees paid for
+	// forming a single contract.
+	ContractFee types.Currency
+	TxnFee      types.Currency
+	SiafundFee  types.Currency
+
+	// RenterFunds is the estimated amount of money that the renter can spend
+	// on storage, uploads and downloads in each contract.
+	RenterFunds types.Currency
+
+	// TotalFees is the sum of all fees paid for forming the contracts.
+	TotalFees types.Currency
+
+	// Unallocated is the part of the allowance that is not used by the
+	// initial contracts and remains available for renewals and refreshes.
+	Unallocated types.Currency
+}
+
+// ProjectAllowance estimates how the funds of allowance a would be split
+// between contracts using the current prices of the hosts in the hostdb. No
+// contracts are formed and the current allowance is not changed.
+func (c *Contractor) ProjectAllowance(a modules.Allowance) (AllowanceProjection, error) {
+	if err := validateAllowance(a); err != nil {
+		return AllowanceProjection{}, err
+	}
+
+	// Sample the hosts that contracts would be formed with, ignoring
+	// blacklisted hosts.
+	c.mu.RLock()
+	blockHeight := c.blockHeight
+	var exclude []types.SiaPublicKey
+	for _, pk := range c.hostBlacklist {
+		exclude = append(exclude, pk)
+	}
+	c.mu.RUnlock()
+	hosts, err := c.hdb.RandomHosts(int(a.Hosts), exclude)
+	if err != nil {
+		return AllowanceProjection{}, err
+	}
+
+	// Use the same funding per contract as contract maintenance does.
+	funding := a.Funds.Div64(a.Hosts).Div64(3)
+	_, maxFee := c.tpool.FeeEstimation()
+	txnFee := maxFee.Mul64(modules.EstimatedFileContractTransactionSetSize)
+
+	var viable, affordable uint64
+	var contractFees, siafundFees, renterFunds types.Currency
+	for _, host := range hosts {
+		// Skip hosts that managedNewContract would reject.
+		if host.StoragePrice.Cmp(maxStoragePrice) > 0 || host.UploadBandwidthPrice.Cmp(maxUploadPrice) > 0 {
+			continue
+		}
+		viable++
+
+		// Mirror the payout calculation of the contract formation protocol.
+		if funding.Cmp(host.ContractPrice.Add(txnFee)) <= 0 {
+			continue
+		}
+		storagePrice := host.StoragePrice
+		if storagePrice.IsZero() {
+			storagePrice = types.NewCurrency64(1)
+		}
+		maxHostCollateral := host.MaxCollateral
+		if maxHostCollateral.Cmp(maxCollateral) > 0 {
+			maxHostCollateral = maxCollateral
+		}
+		renterPayout := funding.Sub(host.ContractPrice).Sub(txnFee)
+		hostCollateral := renterPayout.Div(storagePrice).Mul(host.Collateral)
+		if hostCollateral.Cmp(maxHostCollateral) > 0 {
+			hostCollateral = maxHostCollateral
+		}
+		hostPayout := hostCollateral.Add(host.ContractPrice)
+		totalPayout := renterPayout.Add(hostPayout)
+		if types.PostTax(blockHeight, totalPayout).Cmp(hostPayout) < 0 {
+			continue
+		}
+		affordable++
+		contractFees = contractFees.Add(host.ContractPrice)
+		siafundFees = siafundFees.Add(types.Tax(blockHeight, totalPayout))
+		renterFunds = renterFunds.Add(types.PostTax(blockHeight, totalPayout).Sub(hostPayout))
+	}
+	if viable == 0 {
+		return AllowanceProjection{}, errNoViableHosts
+	} else if affordable == 0 {
+		return AllowanceProjection{}, ErrInsufficientAllowance
+	}
+
+	p := AllowanceProjection{
+		Contracts:       affordable,
+		ContractFunding: funding,
+		ContractFee:     contractFees.Div64(affordable),
+		TxnFee:          txnFee,
+		SiafundFee:      siafundFees.Div64(affordable),
+		RenterFunds:     renterFunds.Div64(affordable),
+	}
+	p.TotalFees = p.ContractFee.Add(p.TxnFee).Add(p.SiafundFee).Mul64(affordable)
+	p.Unallocated = a.Funds.Sub(funding.Mul64(affordable))
+	return p, nil
+}
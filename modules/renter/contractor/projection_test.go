@@ -0,0 +1,82 @@
+package contractor
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// hostsHostDB is a stubHostDB whose RandomHosts method returns a fixed set of
+// hosts.
+type hostsHostDB struct {
+	stubHostDB
+	hosts []modules.HostDBEntry
+}
+
+func (hdb hostsHostDB) RandomHosts(n int, _ []types.SiaPublicKey) ([]modules.HostDBEntry, error) {
+	if n > len(hdb.hosts) {
+		n = len(hdb.hosts)
+	}
+	return hdb.hosts[:n], nil
+}
+
+// TestProjectAllowance tests the ProjectAllowance method.
+func TestProjectAllowance(t *testing.T) {
+	var host modules.HostDBEntry
+	host.ContractPrice = types.SiacoinPrecision
+	host.StoragePrice = types.NewCurrency64(1)
+	host.Collateral = types.NewCurrency64(1)
+	host.MaxCollateral = types.SiacoinPrecision.Mul64(10)
+	expensiveHost := host
+	expensiveHost.StoragePrice = maxStoragePrice.Add(types.NewCurrency64(1))
+
+	a := modules.Allowance{
+		Funds:       types.SiacoinPrecision.Mul64(3000),
+		Hosts:       3,
+		Period:      100,
+		RenewWindow: 10,
+	}
+
+	// Hosts that are too expensive should not be considered.
+	c := &Contractor{
+		hdb:   hostsHostDB{hosts: []modules.HostDBEntry{expensiveHost}},
+		tpool: newStub{},
+	}
+	if _, err := c.ProjectAllowance(a); err != errNoViableHosts {
+		t.Fatal("expected errNoViableHosts, got", err)
+	}
+
+	// Two viable hosts should result in two contracts.
+	c.hdb = hostsHostDB{hosts: []modules.HostDBEntry{host, expensiveHost, host}}
+	p, err := c.ProjectAllowance(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funding := a.Funds.Div64(a.Hosts).Div64(3)
+	if p.Contracts != 2 {
+		t.Fatal("expected 2 contracts, got", p.Contracts)
+	} else if !p.ContractFunding.Equals(funding) {
+		t.Fatal("wrong contract funding:", p.ContractFunding)
+	} else if !p.ContractFee.Equals(host.ContractPrice) {
+		t.Fatal("wrong contract fee:", p.ContractFee)
+	} else if !p.Unallocated.Equals(a.Funds.Sub(funding.Mul64(2))) {
+		t.Fatal("wrong unallocated funds:", p.Unallocated)
+	} else if !p.TotalFees.Equals(p.ContractFee.Add(p.TxnFee).Add(p.SiafundFee).Mul64(2)) {
+		t.Fatal("wrong total fees:", p.TotalFees)
+	} else if p.RenterFunds.Cmp(funding.Sub(host.ContractPrice)) >= 0 {
+		t.Fatal("renter funds should not include the fees:", p.RenterFunds)
+	}
+
+	// An allowance that can't cover the contract fees should be rejected.
+	a.Funds = host.ContractPrice.Mul64(a.Hosts)
+	if _, err := c.ProjectAllowance(a); err != ErrInsufficientAllowance {
+		t.Fatal("expected ErrInsufficientAllowance, got", err)
+	}
+
+	// Invalid allowances should be rejected.
+	a.Hosts = 0
+	if _, err := c.ProjectAllowance(a); err != errAllowanceNoHosts {
+		t.Fatal("expected errAllowanceNoHosts, got", err)
+	}
+}
@@ -0,0 +1,194 @@
+package contractor
+
+import (
+	"fmt"
+
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// contractKeySpecifier is used to derive the renter's contract keys from the
+// wallet seed, so that they are distinct from the wallet's own keys.
+var contractKeySpecifier = types.Specifier{'c', 'o', 'n', 't', 'r', 'a', 'c', 't', 'k', 'e', 'y'}
+
+// contractKey derives the key that the renter uses to sign a contract with the
+// host that ends at endHeight. Since the key only depends on data that is
+// stored in the blockchain or the hostdb, contracts can be recovered from the
+// seed if the contractor's state is lost.
+func contractKey(seed modules.Seed, hostKey types.SiaPublicKey, endHeight types.BlockHeight) crypto.SecretKey {
+	sk, _ := crypto.GenerateKeyPairDeterministic(crypto.HashAll(contractKeySpecifier, seed, hostKey, endHeight))
+	return sk
+}
+
+// managedContractKey derives the key for a new contract with host from the
+// wallet's primary seed.
+func (c *Contractor) managedContractKey(host types.SiaPublicKey, endHeight types.BlockHeight) (crypto.SecretKey, error) {
+	seed, _, err := c.wallet.PrimarySeed()
+	if err != nil {
+		return crypto.SecretKey{}, err
+	}
+	return contractKey(seed, host, endHeight), nil
+}
+
+// A recoverableContract is an unexpired file contract that was signed with a
+// key derived from the seed being scanned for.
+type recoverableContract struct {
+	id          types.FileContractID
+	host        modules.HostDBEntry
+	secretKey   crypto.SecretKey
+	startHeight types.BlockHeight
+	endHeight   types.BlockHeight
+}
+
+// A contractScanner scans the blockchain for file contracts that were signed
+// with keys derived from a given seed.
+type contractScanner struct {
+	blockHeight types.BlockHeight
+	contracts   map[types.FileContractID]recoverableContract
+	hosts       map[types.UnlockHash][]modules.HostDBEntry // host payout address to hosts
+	seed        modules.Seed
+}
+
+// ProcessConsensusChange scans the blockchain for file contracts that belong
+// to the contractScanner's seed.
+func (s *contractScanner) ProcessConsensusChange(cc modules.ConsensusChange) {
+	for _, block := range cc.RevertedBlocks {
+		if block.ID() != types.GenesisID {
+			s.blockHeight--
+		}
+	}
+	for _, block := range cc.AppliedBlocks {
+		if block.ID() != types.GenesisID {
+			s.blockHeight++
+		}
+	}
+
+	// A revision reverts the contract and applies it again within the same
+	// change. Remember the reverted contracts so that revised contracts keep
+	// their start height.
+	reverted := make(map[types.FileContractID]recoverableContract)
+	for _, diff := range cc.FileContractDiffs {
+		if diff.Direction == modules.DiffRevert {
+			// NOTE: DiffRevert means the contract was either revised, expired
+			// or in a block that was reverted.
+			if rc, exists := s.contracts[diff.ID]; exists {
+				reverted[diff.ID] = rc
+				delete(s.contracts, diff.ID)
+			}
+			continue
+		}
+		if rc, exists := reverted[diff.ID]; exists {
+			s.contracts[diff.ID] = rc
+			continue
+		}
+		fc := diff.FileContract
+		if len(fc.ValidProofOutputs) < 2 {
+			continue
+		}
+		// Only hosts that are paid by the contract need to be checked, which
+		// avoids deriving a key for every host and contract.
+		for _, host := range s.hosts[fc.ValidProofOutputs[1].UnlockHash] {
+			sk := contractKey(s.seed, host.PublicKey, fc.WindowStart)
+			uc := types.UnlockConditions{
+				PublicKeys: []types.SiaPublicKey{
+					types.Ed25519PublicKey(sk.PublicKey()),
+					host.PublicKey,
+				},
+				SignaturesRequired: 2,
+			}
+			if uc.UnlockHash() == fc.UnlockHash {
+				s.contracts[diff.ID] = recoverableContract{
+					id:          diff.ID,
+					host:        host,
+					secretKey:   sk,
+					startHeight: s.blockHeight,
+					endHeight:   fc.WindowStart,
+				}
+				break
+			}
+		}
+	}
+}
+
+// newContractScanner returns a contractScanner that looks for contracts with
+// any of hosts.
+func newContractScanner(seed modules.Seed, hosts []modules.HostDBEntry) *contractScanner {
+	s := &contractScanner{
+		contracts: make(map[types.FileContractID]recoverableContract),
+		hosts:     make(map[types.UnlockHash][]modules.HostDBEntry),
+		seed:      seed,
+	}
+	for _, host := range hosts {
+		s.hosts[host.UnlockHash] = append(s.hosts[host.UnlockHash], host)
+	}
+	return s
+}
+
+// RecoverContracts scans the blockchain for unexpired file contracts that were
+// signed with keys derived from seed and adds them to the contractor. The most
+// recent revision and the sector roots of each contract are retrieved from
+// its host. Only contracts with hosts in the hostdb can be found, and only if
+// the host still uses the payout address of the contract. If there are
+// several contracts with the same host, only the one that ends last is
+// recovered.
+func (c *Contractor) RecoverContracts(seed modules.Seed) error {
+	if err := c.tg.Add(); err != nil {
+		return err
+	}
+	defer c.tg.Done()
+
+	s := newContractScanner(seed, c.hdb.AllHosts())
+	if err := c.cs.ConsensusSetSubscribe(s, modules.ConsensusChangeBeginning, c.tg.StopChan()); err != nil {
+		return err
+	}
+	c.cs.Unsubscribe(s)
+
+	// Keep the contract that ends last for every host.
+	latest := make(map[string]recoverableContract)
+	for _, rc := range s.contracts {
+		if _, exists := c.staticContracts.View(rc.id); exists {
+			continue
+		}
+		if rc.endHeight <= s.blockHeight {
+			continue
+		}
+		pk := string(rc.host.PublicKey.Key)
+		if prev, exists := latest[pk]; !exists || rc.endHeight > prev.endHeight {
+			latest[pk] = rc
+		}
+	}
+	c.log.Printf("INFO: found %v recoverable contracts", len(latest))
+
+	var failed int
+	for _, rc := range latest {
+		c.mu.RLock()
+		_, haveContract := c.pubKeysToContractID[string(rc.host.PublicKey.Key)]
+		c.mu.RUnlock()
+		if haveContract {
+			// We already have a contract with this host.
+			continue
+		}
+		contract, err := c.staticContracts.RecoverContractFromHost(rc.host, rc.id, rc.secretKey, rc.startHeight, c.tg.StopChan())
+		if err != nil {
+			c.log.Printf("WARN: failed to recover contract %v: %v", rc.id, err)
+			failed++
+			continue
+		}
+		c.mu.Lock()
+		c.contractIDToPubKey[contract.ID] = contract.HostPublicKey
+		c.pubKeysToContractID[string(contract.HostPublicKey.Key)] = contract.ID
+		c.hostSettings[contract.ID] = rc.host.HostExternalSettings
+		c.mu.Unlock()
+		c.log.Println("INFO: recovered contract", contract.ID)
+		c.managedNotifySubscribers(ContractEvent{
+			Type:          ContractRecovered,
+			ID:            contract.ID,
+			HostPublicKey: contract.HostPublicKey,
+		})
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to recover %v of %v contracts", failed, len(latest))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package contractor
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+	"gitlab.com/NebulousLabs/fastrand"
+)
+
+// TestContractScanner tests that the contractScanner finds the contracts that
+// were signed with keys derived from its seed.
+func TestContractScanner(t *testing.T) {
+	var seed modules.Seed
+	fastrand.Read(seed[:])
+	_, hostPK := crypto.GenerateKeyPair()
+	host := modules.HostDBEntry{PublicKey: types.Ed25519PublicKey(hostPK)}
+	host.UnlockHash = types.UnlockHash{1}
+
+	// Contract keys are deterministic.
+	if contractKey(seed, host.PublicKey, 100) != contractKey(seed, host.PublicKey, 100) {
+		t.Fatal("contract key is not deterministic")
+	} else if contractKey(seed, host.PublicKey, 100) == contractKey(seed, host.PublicKey, 101) {
+		t.Fatal("contract key doesn't depend on the end height")
+	}
+
+	// Create a contract signed with a key derived from the seed and one
+	// signed with a random key.
+	newContract := func(sk crypto.SecretKey) types.FileContract {
+		uc := types.UnlockConditions{
+			PublicKeys: []types.SiaPublicKey{
+				types.Ed25519PublicKey(sk.PublicKey()),
+				host.PublicKey,
+			},
+			SignaturesRequired: 2,
+		}
+		return types.FileContract{
+			WindowStart: 100,
+			UnlockHash:  uc.UnlockHash(),
+			ValidProofOutputs: []types.SiacoinOutput{
+				{}, {UnlockHash: host.UnlockHash},
+			},
+		}
+	}
+	randomSK, _ := crypto.GenerateKeyPair()
+	ours := newContract(contractKey(seed, host.PublicKey, 100))
+	theirs := newContract(randomSK)
+
+	s := newContractScanner(seed, []modules.HostDBEntry{host})
+	s.ProcessConsensusChange(modules.ConsensusChange{
+		AppliedBlocks: []types.Block{{}},
+		FileContractDiffs: []modules.FileContractDiff{
+			{Direction: modules.DiffApply, ID: types.FileContractID{1}, FileContract: ours},
+			{Direction: modules.DiffApply, ID: types.FileContractID{2}, FileContract: theirs},
+		},
+	})
+	if len(s.contracts) != 1 {
+		t.Fatal("expected 1 contract, got", len(s.contracts))
+	}
+	rc, exists := s.contracts[types.FileContractID{1}]
+	if !exists || rc.startHeight != 1 || rc.endHeight != 100 || rc.secretKey != contractKey(seed, host.PublicKey, 100) {
+		t.Fatal("contract was not found correctly:", rc)
+	}
+
+	// A revision keeps the start height of the contract.
+	s.ProcessConsensusChange(modules.ConsensusChange{
+		AppliedBlocks: []types.Block{{}},
+		FileContractDiffs: []modules.FileContractDiff{
+			{Direction: modules.DiffRevert, ID: types.FileContractID{1}, FileContract: ours},
+			{Direction: modules.DiffApply, ID: types.FileContractID{1}, FileContract: ours},
+		},
+	})
+	if rc := s.contracts[types.FileContractID{1}]; rc.startHeight != 1 {
+		t.Fatal("revision changed the start height to", rc.startHeight)
+	}
+
+	// A contract that is reverted is removed.
+	s.ProcessConsensusChange(modules.ConsensusChange{
+		AppliedBlocks: []types.Block{{}},
+		FileContractDiffs: []modules.FileContractDiff{
+			{Direction: modules.DiffRevert, ID: types.FileContractID{1}, FileContract: ours},
+		},
+	})
+	if len(s.contracts) != 0 {
+		t.Fatal("expected no contracts, got", len(s.contracts))
+	}
+}
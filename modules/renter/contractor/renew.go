@@ -0,0 +1,85 @@
+package contractor
+
+import (
+	"errors"
+
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errContractRenewing is returned if a contract is renewed while it is
+	// already being renewed.
+	errContractRenewing = errors.New("contract is already being renewed")
+
+	// errContractRevising is returned by RenewContract if the contract is
+	// currently being revised by an editor or downloader.
+	errContractRevising = errors.New("contract is currently being revised")
+
+	// errNoAllowance is returned by RenewContract if no allowance is set.
+	errNoAllowance = errors.New("cannot renew contracts without an allowance")
+
+	// errRenewInsufficientFunds is returned by RenewContract if the remaining
+	// allowance can't cover the renewal.
+	errRenewInsufficientFunds = errors.New("not enough allowance funds remaining to renew the contract")
+)
+
+// RenewContract renews the contract with the given id right away instead of
+// waiting for its renew window. The renewal is funded and scheduled using the
+// current allowance, just like the renewals of contract maintenance. The id of
+// the new contract is returned.
+func (c *Contractor) RenewContract(id types.FileContractID) (types.FileContractID, error) {
+	if err := c.tg.Add(); err != nil {
+		return types.FileContractID{}, err
+	}
+	defer c.tg.Done()
+
+	contract, exists := c.staticContracts.View(id)
+	if !exists {
+		return types.FileContractID{}, errors.New("no record of that contract")
+	}
+
+	c.mu.RLock()
+	renewing, revising := c.renewing[id], c.revising[id]
+	allowance := c.allowance
+	blockHeight := c.blockHeight
+	currentPeriod := c.currentPeriod
+	endHeight := c.contractEndHeight()
+	c.mu.RUnlock()
+	if renewing {
+		return types.FileContractID{}, errContractRenewing
+	} else if revising {
+		return types.FileContractID{}, errContractRevising
+	} else if allowance.Hosts == 0 {
+		return types.FileContractID{}, errNoAllowance
+	}
+
+	// Determine the funding the same way contract maintenance does and make
+	// sure that the allowance can cover it.
+	amount, err := c.managedEstimateRenewFundingRequirements(contract, blockHeight, allowance)
+	if err != nil {
+		return types.FileContractID{}, err
+	}
+	spending := c.PeriodSpending()
+	var fundsRemaining types.Currency
+	if spending.TotalAllocated.Cmp(allowance.Funds) < 0 {
+		fundsRemaining = allowance.Funds.Sub(spending.TotalAllocated)
+	}
+	if amount.Cmp(fundsRemaining) > 0 {
+		return types.FileContractID{}, errRenewInsufficientFunds
+	}
+
+	renewal := fileContractRenewal{
+		id:     id,
+		amount: amount,
+	}
+	if _, err := c.managedRenewContract(renewal, currentPeriod, allowance, blockHeight, endHeight); err != nil {
+		c.managedRecordMaintenance(MaintenanceRenewFailed, id, contract.HostPublicKey, err.Error())
+		return types.FileContractID{}, err
+	}
+	c.managedRecordMaintenance(MaintenanceRenewed, id, contract.HostPublicKey, "renewal was requested")
+
+	c.mu.RLock()
+	newID := c.renewedTo[id]
+	c.mu.RUnlock()
+	return newID, nil
+}
@@ -0,0 +1,74 @@
+package contractor
+
+import (
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// A ContractScheduleEntry describes when a contract ends and when the
+// contractor will attempt to renew it.
+type ContractScheduleEntry struct {
+	ID            types.FileContractID
+	HostPublicKey types.SiaPublicKey
+
+	// WindowStart and WindowEnd delimit the proof window of the contract.
+	// The host must submit its storage proof between these heights.
+	WindowStart types.BlockHeight
+	WindowEnd   types.BlockHeight
+
+	// RenewHeight is the height at which the contractor will attempt to
+	// renew the contract, based on the current renew window. It is only
+	// meaningful if GoodForRenew is true.
+	RenewHeight  types.BlockHeight
+	GoodForRenew bool
+}
+
+// newContractScheduleEntry returns the schedule of contract under a renew
+// window of renewWindow.
+func newContractScheduleEntry(contract modules.RenterContract, renewWindow types.BlockHeight) ContractScheduleEntry {
+	e := ContractScheduleEntry{
+		ID:            contract.ID,
+		HostPublicKey: contract.HostPublicKey,
+		WindowStart:   contract.EndHeight,
+		WindowEnd:     contract.EndHeight,
+		GoodForRenew:  contract.Utility.GoodForRenew,
+	}
+	if revs := contract.Transaction.FileContractRevisions; len(revs) > 0 {
+		e.WindowEnd = revs[0].NewWindowEnd
+	}
+	// Contract maintenance renews a contract once blockHeight+RenewWindow
+	// reaches the end height.
+	if contract.EndHeight > renewWindow {
+		e.RenewHeight = contract.EndHeight - renewWindow
+	}
+	return e
+}
+
+// ContractSchedule returns the end heights and projected renewal heights of
+// the contractor's contracts. Contracts that were renewed are only
+// represented by their most recent renewal.
+func (c *Contractor) ContractSchedule() []ContractScheduleEntry {
+	contracts := c.staticContracts.ViewAll()
+
+	c.mu.RLock()
+	renewWindow := c.allowance.RenewWindow
+	ids := make([]types.FileContractID, len(contracts))
+	for i, contract := range contracts {
+		ids[i] = c.resolveID(contract.ID)
+	}
+	c.mu.RUnlock()
+
+	seen := make(map[types.FileContractID]struct{})
+	schedule := make([]ContractScheduleEntry, 0, len(contracts))
+	for i, contract := range contracts {
+		if _, ok := seen[ids[i]]; ok {
+			continue
+		}
+		seen[ids[i]] = struct{}{}
+		if renewed, ok := c.staticContracts.View(ids[i]); ok && ids[i] != contract.ID {
+			contract = renewed
+		}
+		schedule = append(schedule, newContractScheduleEntry(contract, renewWindow))
+	}
+	return schedule
+}
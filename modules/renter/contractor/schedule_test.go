@@ -0,0 +1,50 @@
+package contractor
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestNewContractScheduleEntry tests the newContractScheduleEntry function.
+func TestNewContractScheduleEntry(t *testing.T) {
+	contract := modules.RenterContract{
+		ID:        types.FileContractID{1},
+		EndHeight: 100,
+		Transaction: types.Transaction{
+			FileContractRevisions: []types.FileContractRevision{{
+				NewWindowStart: 100,
+				NewWindowEnd:   244,
+			}},
+		},
+		Utility: modules.ContractUtility{GoodForRenew: true},
+	}
+	e := newContractScheduleEntry(contract, 30)
+	if e.ID != contract.ID || !e.GoodForRenew {
+		t.Fatal("wrong entry:", e)
+	} else if e.WindowStart != 100 || e.WindowEnd != 244 {
+		t.Fatal("wrong window:", e.WindowStart, e.WindowEnd)
+	} else if e.RenewHeight != 70 {
+		t.Fatal("expected renew height 70, got", e.RenewHeight)
+	}
+
+	// A renew window that exceeds the end height results in a renew height
+	// of zero.
+	if e := newContractScheduleEntry(contract, 200); e.RenewHeight != 0 {
+		t.Fatal("expected renew height 0, got", e.RenewHeight)
+	}
+}
+
+// TestResolveID tests the resolveID method.
+func TestResolveID(t *testing.T) {
+	a, b, c := types.FileContractID{1}, types.FileContractID{2}, types.FileContractID{3}
+	con := &Contractor{
+		renewedTo: map[types.FileContractID]types.FileContractID{a: b, b: c},
+	}
+	if id := con.resolveID(a); id != c {
+		t.Fatal("expected", c, "got", id)
+	} else if id := con.resolveID(c); id != c {
+		t.Fatal("expected", c, "got", id)
+	}
+}
@@ -0,0 +1,96 @@
+package contractor
+
+import (
+	"errors"
+	"math/big"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+)
+
+// errInvalidSpendingAlertThreshold is returned by SetSpendingAlertThreshold if
+// the fraction is not between 0 and 1.
+var errInvalidSpendingAlertThreshold = errors.New("spending alert threshold must be between 0 and 1")
+
+// spentFraction returns the fraction of the allowance funds that has been
+// spent in the current period, based on the unspent funds reported by
+// PeriodSpending. If the allowance has no funds, zero is returned.
+func spentFraction(allowance modules.Allowance, spending modules.ContractorSpending) float64 {
+	if allowance.Funds.IsZero() {
+		return 0
+	}
+	spent := allowance.Funds.Sub(spending.Unspent)
+	fraction, _ := big.NewRat(0, 1).SetFrac(spent.Big(), allowance.Funds.Big()).Float64()
+	return fraction
+}
+
+// SpendingAlert returns the spending alert threshold of the contractor, the
+// fraction of the allowance that has been spent in the current period and
+// whether the alert has been triggered.
+func (c *Contractor) SpendingAlert() modules.SpendingAlert {
+	spending := c.PeriodSpending()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return modules.SpendingAlert{
+		Threshold:     c.spendingAlertThreshold,
+		SpentFraction: spentFraction(c.allowance, spending),
+		Triggered:     c.spendingAlerted,
+	}
+}
+
+// SpendingAlertThreshold returns the fraction of the allowance that can be
+// spent in a period before the contractor warns about it. A value of zero
+// disables the alert.
+func (c *Contractor) SpendingAlertThreshold() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.spendingAlertThreshold
+}
+
+// SetSpendingAlertThreshold sets the fraction of the allowance that can be
+// spent in a period before the contractor warns about it. The alert is reset
+// and the current spending is checked against the new threshold immediately.
+func (c *Contractor) SetSpendingAlertThreshold(fraction float64) error {
+	if fraction < 0 || fraction > 1 {
+		return errInvalidSpendingAlertThreshold
+	}
+	c.mu.Lock()
+	c.spendingAlertThreshold = fraction
+	c.spendingAlerted = false
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.log.Printf("INFO: set spending alert threshold to %v", fraction)
+	c.managedCheckSpendingAlert()
+	return nil
+}
+
+// managedCheckSpendingAlert logs a warning and notifies the subscribers with a
+// SpendingAlertTriggered event when the unspent allowance drops below the
+// spending alert threshold. The alert is only sent once; it is rearmed when
+// the spending falls below the threshold again, e.g. because a new period
+// started or the allowance was increased.
+func (c *Contractor) managedCheckSpendingAlert() {
+	spending := c.PeriodSpending()
+	c.mu.Lock()
+	threshold := c.spendingAlertThreshold
+	funds := c.allowance.Funds
+	fraction := spentFraction(c.allowance, spending)
+	if threshold == 0 || funds.IsZero() || fraction < threshold {
+		c.spendingAlerted = false
+		c.mu.Unlock()
+		return
+	}
+	alerted := c.spendingAlerted
+	c.spendingAlerted = true
+	c.mu.Unlock()
+	if alerted {
+		return
+	}
+
+	c.log.Printf("WARN: %.2f%% of the allowance has been spent in the current period, %v of %v are left", fraction*100, spending.Unspent.HumanString(), funds.HumanString())
+	c.managedNotifySubscribers(ContractEvent{
+		Type: SpendingAlertTriggered,
+	})
+}
@@ -21,6 +21,9 @@ func (c *Contractor) managedArchiveContracts() {
 			id := contract.ID
 			c.mu.Lock()
 			c.oldContracts[id] = contract
+			c.oldContractReasons[id] = expiredReason(contract.Utility)
+			delete(c.pinnedContracts, id)
+			delete(c.canceledContracts, id)
 			c.mu.Unlock()
 			expired = append(expired, id)
 			c.log.Println("INFO: archived expired contract", id)
@@ -71,6 +74,9 @@ func (c *Contractor) ProcessConsensusChange(cc modules.ConsensusChange) {
 	}
 	c.mu.Unlock()
 
+	// Warn if the spending of the current period reached the alert threshold.
+	c.managedCheckSpendingAlert()
+
 	// Perform contract maintenance if our blockchain is synced. Use a separate
 	// goroutine so that the rest of the contractor is not blocked during
 	// maintenance.
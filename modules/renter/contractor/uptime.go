@@ -1,6 +1,7 @@
 package contractor
 
 import (
+	"errors"
 	"time"
 
 	"gitlab.com/NebulousLabs/Sia/build"
@@ -25,6 +26,16 @@ var uptimeWindow = func() time.Duration {
 	panic("undefined uptimeWindow")
 }()
 
+var (
+	// errHostUptimeTooLow is returned when the contractor is asked to form or
+	// renew a contract with a host whose recent uptime is below the minimum.
+	errHostUptimeTooLow = errors.New("host uptime is below the minimum")
+
+	// errInvalidMinHostUptime is returned by SetMinHostUptime if the fraction
+	// is not between 0 and 1.
+	errInvalidMinHostUptime = errors.New("minimum host uptime must be between 0 and 1")
+)
+
 // IsOffline indicates whether a contract's host should be considered offline,
 // based on its scan metrics.
 func (c *Contractor) IsOffline(pk types.SiaPublicKey) bool {
@@ -55,3 +66,85 @@ func isOffline(host modules.HostDBEntry) bool {
 	success2 := host.ScanHistory[len(host.ScanHistory)-2].Success
 	return !(success1 || success2)
 }
+
+// recentUptime returns the fraction of the uptimeWindow before now during
+// which the host was online, judging by its scans. The time between two scans
+// is attributed to the result of the earlier scan. If the host was scanned
+// fewer than uptimeMinScans times within the window, its uptime can't be
+// judged and false is returned.
+func recentUptime(host modules.HostDBEntry, now time.Time) (float64, bool) {
+	start := now.Add(-uptimeWindow)
+	var scans []modules.HostDBScan
+	for _, scan := range host.ScanHistory {
+		if scan.Timestamp.After(start) && !scan.Timestamp.After(now) {
+			scans = append(scans, scan)
+		}
+	}
+	if len(scans) < uptimeMinScans {
+		return 0, false
+	}
+
+	var uptime, total time.Duration
+	for i, scan := range scans {
+		end := now
+		if i+1 < len(scans) {
+			end = scans[i+1].Timestamp
+		}
+		if scan.Success {
+			uptime += end.Sub(scan.Timestamp)
+		}
+		total += end.Sub(scan.Timestamp)
+	}
+	if total <= 0 {
+		return 0, false
+	}
+	return float64(uptime) / float64(total), true
+}
+
+// MinHostUptime returns the minimum fraction of the recent past that a host
+// needs to have been online for the contractor to form or renew contracts with
+// it. A value of zero disables the check.
+func (c *Contractor) MinHostUptime() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.minHostUptime
+}
+
+// SetMinHostUptime sets the minimum fraction of the recent past that a host
+// needs to have been online for the contractor to form or renew contracts with
+// it. Existing contracts with hosts below the minimum are marked as
+// !GoodForRenew during the next round of contract maintenance, which is
+// triggered immediately.
+func (c *Contractor) SetMinHostUptime(fraction float64) error {
+	if fraction < 0 || fraction > 1 {
+		return errInvalidMinHostUptime
+	}
+	c.mu.Lock()
+	c.minHostUptime = fraction
+	err := c.saveSync()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.log.Printf("INFO: set minimum host uptime to %v", fraction)
+
+	// Interrupt any existing maintenance and launch a new round of
+	// maintenance so that the minimum is applied to existing contracts.
+	c.managedInterruptContractMaintenance()
+	go c.threadedContractMaintenance()
+	return nil
+}
+
+// managedHasLowUptime returns true if the recent uptime of the host is below
+// the contractor's minimum host uptime. Hosts without enough recent scans are
+// given the benefit of the doubt.
+func (c *Contractor) managedHasLowUptime(host modules.HostDBEntry) bool {
+	c.mu.RLock()
+	minUptime := c.minHostUptime
+	c.mu.RUnlock()
+	if minUptime == 0 {
+		return false
+	}
+	uptime, ok := recentUptime(host, time.Now())
+	return ok && uptime < minUptime
+}
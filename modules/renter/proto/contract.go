@@ -36,6 +36,22 @@ type v132UpdateSetHeader struct {
 	Header v132ContractHeader
 }
 
+// v133UpdateSetHeader was introduced due to backwards compatibility reasons
+// after adding the Reason field to the contract utility. It contains the legacy
+// v133ContractHeader.
+type v133UpdateSetHeader struct {
+	ID     types.FileContractID
+	Header v133ContractHeader
+}
+
+// v134UpdateSetHeader was introduced due to backwards compatibility reasons
+// after adding the byte counters to the contractHeader. It contains the legacy
+// v134ContractHeader.
+type v134UpdateSetHeader struct {
+	ID     types.FileContractID
+	Header v134ContractHeader
+}
+
 type updateSetRoot struct {
 	ID    types.FileContractID
 	Root  crypto.Hash
@@ -61,6 +77,24 @@ type contractHeader struct {
 	TxnFee           types.Currency
 	SiafundFee       types.Currency
 	Utility          modules.ContractUtility
+	DownloadedBytes  uint64
+	UploadedBytes    uint64
+}
+
+// v134ContractHeader is a contractHeader without the DownloadedBytes and
+// UploadedBytes fields.
+type v134ContractHeader struct {
+	Transaction      types.Transaction
+	SecretKey        crypto.SecretKey
+	StartHeight      types.BlockHeight
+	DownloadSpending types.Currency
+	StorageSpending  types.Currency
+	UploadSpending   types.Currency
+	TotalCost        types.Currency
+	ContractFee      types.Currency
+	TxnFee           types.Currency
+	SiafundFee       types.Currency
+	Utility          modules.ContractUtility
 }
 
 // v132ContractHeader is a contractHeader without the Utility field. This field
@@ -85,6 +119,29 @@ type v132ContractHeader struct {
 	SiafundFee       types.Currency
 }
 
+// v133ContractHeader is a contractHeader with a v133ContractUtility instead of
+// a modules.ContractUtility.
+type v133ContractHeader struct {
+	Transaction      types.Transaction
+	SecretKey        crypto.SecretKey
+	StartHeight      types.BlockHeight
+	DownloadSpending types.Currency
+	StorageSpending  types.Currency
+	UploadSpending   types.Currency
+	TotalCost        types.Currency
+	ContractFee      types.Currency
+	TxnFee           types.Currency
+	SiafundFee       types.Currency
+	Utility          v133ContractUtility
+}
+
+// v133ContractUtility is a modules.ContractUtility without the Reason field.
+type v133ContractUtility struct {
+	GoodForUpload bool
+	GoodForRenew  bool
+	Locked        bool
+}
+
 // validate returns an error if the contractHeader is invalid.
 func (h *contractHeader) validate() error {
 	if len(h.Transaction.FileContractRevisions) > 0 &&
@@ -158,6 +215,8 @@ func (c *SafeContract) Metadata() modules.RenterContract {
 		TxnFee:           h.TxnFee,
 		SiafundFee:       h.SiafundFee,
 		Utility:          h.Utility,
+		DownloadedBytes:  h.DownloadedBytes,
+		UploadedBytes:    h.UploadedBytes,
 	}
 }
 
@@ -247,7 +306,7 @@ func (c *SafeContract) applySetRoot(root crypto.Hash, index int) error {
 	return c.merkleRoots.insert(index, root)
 }
 
-func (c *SafeContract) recordUploadIntent(rev types.FileContractRevision, root crypto.Hash, storageCost, bandwidthCost types.Currency) (*writeaheadlog.Transaction, error) {
+func (c *SafeContract) recordUploadIntent(rev types.FileContractRevision, root crypto.Hash, storageCost, bandwidthCost types.Currency, uploaded uint64) (*writeaheadlog.Transaction, error) {
 	// construct new header
 	// NOTE: this header will not include the host signature
 	c.headerMu.Lock()
@@ -256,6 +315,7 @@ func (c *SafeContract) recordUploadIntent(rev types.FileContractRevision, root c
 	newHeader.Transaction.FileContractRevisions = []types.FileContractRevision{rev}
 	newHeader.StorageSpending = newHeader.StorageSpending.Add(storageCost)
 	newHeader.UploadSpending = newHeader.UploadSpending.Add(bandwidthCost)
+	newHeader.UploadedBytes += uploaded
 
 	t, err := c.wal.NewTransaction([]writeaheadlog.Update{
 		c.makeUpdateSetHeader(newHeader),
@@ -271,7 +331,7 @@ func (c *SafeContract) recordUploadIntent(rev types.FileContractRevision, root c
 	return t, nil
 }
 
-func (c *SafeContract) commitUpload(t *writeaheadlog.Transaction, signedTxn types.Transaction, root crypto.Hash, storageCost, bandwidthCost types.Currency) error {
+func (c *SafeContract) commitUpload(t *writeaheadlog.Transaction, signedTxn types.Transaction, root crypto.Hash, storageCost, bandwidthCost types.Currency, uploaded uint64) error {
 	// construct new header
 	c.headerMu.Lock()
 	newHeader := c.header
@@ -279,6 +339,7 @@ func (c *SafeContract) commitUpload(t *writeaheadlog.Transaction, signedTxn type
 	newHeader.Transaction = signedTxn
 	newHeader.StorageSpending = newHeader.StorageSpending.Add(storageCost)
 	newHeader.UploadSpending = newHeader.UploadSpending.Add(bandwidthCost)
+	newHeader.UploadedBytes += uploaded
 
 	if err := c.applySetHeader(newHeader); err != nil {
 		return err
@@ -296,7 +357,7 @@ func (c *SafeContract) commitUpload(t *writeaheadlog.Transaction, signedTxn type
 	return nil
 }
 
-func (c *SafeContract) recordDownloadIntent(rev types.FileContractRevision, bandwidthCost types.Currency) (*writeaheadlog.Transaction, error) {
+func (c *SafeContract) recordDownloadIntent(rev types.FileContractRevision, bandwidthCost types.Currency, downloaded uint64) (*writeaheadlog.Transaction, error) {
 	// construct new header
 	// NOTE: this header will not include the host signature
 	c.headerMu.Lock()
@@ -304,6 +365,7 @@ func (c *SafeContract) recordDownloadIntent(rev types.FileContractRevision, band
 	c.headerMu.Unlock()
 	newHeader.Transaction.FileContractRevisions = []types.FileContractRevision{rev}
 	newHeader.DownloadSpending = newHeader.DownloadSpending.Add(bandwidthCost)
+	newHeader.DownloadedBytes += downloaded
 
 	t, err := c.wal.NewTransaction([]writeaheadlog.Update{
 		c.makeUpdateSetHeader(newHeader),
@@ -318,13 +380,14 @@ func (c *SafeContract) recordDownloadIntent(rev types.FileContractRevision, band
 	return t, nil
 }
 
-func (c *SafeContract) commitDownload(t *writeaheadlog.Transaction, signedTxn types.Transaction, bandwidthCost types.Currency) error {
+func (c *SafeContract) commitDownload(t *writeaheadlog.Transaction, signedTxn types.Transaction, bandwidthCost types.Currency, downloaded uint64) error {
 	// construct new header
 	c.headerMu.Lock()
 	newHeader := c.header
 	c.headerMu.Unlock()
 	newHeader.Transaction = signedTxn
 	newHeader.DownloadSpending = newHeader.DownloadSpending.Add(bandwidthCost)
+	newHeader.DownloadedBytes += downloaded
 
 	if err := c.applySetHeader(newHeader); err != nil {
 		return err
@@ -392,41 +455,50 @@ func (c *SafeContract) unappliedHeader() (h contractHeader) {
 }
 
 func (cs *ContractSet) managedInsertContract(h contractHeader, roots []crypto.Hash) (modules.RenterContract, error) {
-	if err := h.validate(); err != nil {
+	sc, err := cs.newSafeContract(h, roots)
+	if err != nil {
 		return modules.RenterContract{}, err
 	}
+	cs.mu.Lock()
+	cs.contracts[sc.header.ID()] = sc
+	cs.pubKeys[string(h.HostPublicKey().Key)] = sc.header.ID()
+	cs.mu.Unlock()
+	return sc.Metadata(), nil
+}
+
+// newSafeContract creates the contract file for a contract with header h and
+// the given roots. The contract is not added to the set.
+func (cs *ContractSet) newSafeContract(h contractHeader, roots []crypto.Hash) (*SafeContract, error) {
+	if err := h.validate(); err != nil {
+		return nil, err
+	}
 	f, err := os.Create(filepath.Join(cs.dir, h.ID().String()+contractExtension))
 	if err != nil {
-		return modules.RenterContract{}, err
+		return nil, err
 	}
 	// create fileSections
 	headerSection := newFileSection(f, 0, contractHeaderSize)
 	rootsSection := newFileSection(f, contractHeaderSize, -1)
 	// write header
 	if _, err := headerSection.WriteAt(encoding.Marshal(h), 0); err != nil {
-		return modules.RenterContract{}, err
+		return nil, err
 	}
 	// write roots
 	merkleRoots := newMerkleRoots(rootsSection)
 	for _, root := range roots {
 		if err := merkleRoots.push(root); err != nil {
-			return modules.RenterContract{}, err
+			return nil, err
 		}
 	}
 	if err := f.Sync(); err != nil {
-		return modules.RenterContract{}, err
+		return nil, err
 	}
-	sc := &SafeContract{
+	return &SafeContract{
 		header:      h,
 		merkleRoots: merkleRoots,
 		headerFile:  headerSection,
 		wal:         cs.wal,
-	}
-	cs.mu.Lock()
-	cs.contracts[sc.header.ID()] = sc
-	cs.pubKeys[string(h.HostPublicKey().Key)] = sc.header.ID()
-	cs.mu.Unlock()
-	return sc.Metadata(), nil
+	}, nil
 }
 
 // loadSafeContract loads a contract from disk and adds it to the contractset
@@ -518,9 +590,9 @@ func (cs *ContractSet) ConvertV130Contract(c V130Contract, cr V130CachedRevision
 		defer cs.Return(sc)
 		if len(cr.MerkleRoots) == sc.merkleRoots.len()+1 {
 			root := cr.MerkleRoots[len(cr.MerkleRoots)-1]
-			_, err = sc.recordUploadIntent(cr.Revision, root, types.ZeroCurrency, types.ZeroCurrency)
+			_, err = sc.recordUploadIntent(cr.Revision, root, types.ZeroCurrency, types.ZeroCurrency, 0)
 		} else {
-			_, err = sc.recordDownloadIntent(cr.Revision, types.ZeroCurrency)
+			_, err = sc.recordDownloadIntent(cr.Revision, types.ZeroCurrency, 0)
 		}
 		if err != nil {
 			return err
@@ -610,6 +682,48 @@ func (mrs *MerkleRootSet) UnmarshalJSON(b []byte) error {
 func unmarshalHeader(b []byte, u *updateSetHeader) error {
 	// Try unmarshaling the header.
 	if err := encoding.Unmarshal(b, u); err != nil {
+		// COMPATv134 try unmarshaling the header without the byte counters.
+		var v134Header v134UpdateSetHeader
+		if err2 := encoding.Unmarshal(b, &v134Header); err2 == nil {
+			u.ID = v134Header.ID
+			u.Header = contractHeader{
+				Transaction:      v134Header.Header.Transaction,
+				SecretKey:        v134Header.Header.SecretKey,
+				StartHeight:      v134Header.Header.StartHeight,
+				DownloadSpending: v134Header.Header.DownloadSpending,
+				StorageSpending:  v134Header.Header.StorageSpending,
+				UploadSpending:   v134Header.Header.UploadSpending,
+				TotalCost:        v134Header.Header.TotalCost,
+				ContractFee:      v134Header.Header.ContractFee,
+				TxnFee:           v134Header.Header.TxnFee,
+				SiafundFee:       v134Header.Header.SiafundFee,
+				Utility:          v134Header.Header.Utility,
+			}
+			return nil
+		}
+		// COMPATv133 try unmarshaling the header without the utility reason.
+		var v133Header v133UpdateSetHeader
+		if err2 := encoding.Unmarshal(b, &v133Header); err2 == nil {
+			u.ID = v133Header.ID
+			u.Header = contractHeader{
+				Transaction:      v133Header.Header.Transaction,
+				SecretKey:        v133Header.Header.SecretKey,
+				StartHeight:      v133Header.Header.StartHeight,
+				DownloadSpending: v133Header.Header.DownloadSpending,
+				StorageSpending:  v133Header.Header.StorageSpending,
+				UploadSpending:   v133Header.Header.UploadSpending,
+				TotalCost:        v133Header.Header.TotalCost,
+				ContractFee:      v133Header.Header.ContractFee,
+				TxnFee:           v133Header.Header.TxnFee,
+				SiafundFee:       v133Header.Header.SiafundFee,
+				Utility: modules.ContractUtility{
+					GoodForUpload: v133Header.Header.Utility.GoodForUpload,
+					GoodForRenew:  v133Header.Header.Utility.GoodForRenew,
+					Locked:        v133Header.Header.Utility.Locked,
+				},
+			}
+			return nil
+		}
 		// COMPATv132 try unmarshaling the header the old way.
 		var oldHeader v132UpdateSetHeader
 		if err2 := encoding.Unmarshal(b, &oldHeader); err2 != nil {
@@ -56,13 +56,14 @@ func TestContractUncommittedTxn(t *testing.T) {
 		},
 		StorageSpending: types.NewCurrency64(7),
 		UploadSpending:  types.NewCurrency64(17),
+		UploadedBytes:   modules.SectorSize,
 	}
 	revisedRoots := []crypto.Hash{{1}, {2}}
 	fcr := revisedHeader.Transaction.FileContractRevisions[0]
 	newRoot := revisedRoots[1]
 	storageCost := revisedHeader.StorageSpending.Sub(initialHeader.StorageSpending)
 	bandwidthCost := revisedHeader.UploadSpending.Sub(initialHeader.UploadSpending)
-	walTxn, err := sc.recordUploadIntent(fcr, newRoot, storageCost, bandwidthCost)
+	walTxn, err := sc.recordUploadIntent(fcr, newRoot, storageCost, bandwidthCost, modules.SectorSize)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -124,3 +125,84 @@ func TestContractUncommittedTxn(t *testing.T) {
 		t.Fatal("Merkle roots should match revised Merkle roots")
 	}
 }
+
+// TestUnmarshalHeaderCompat tests that headers which were written before the
+// utility reason or the byte counters were added can still be unmarshaled.
+func TestUnmarshalHeaderCompat(t *testing.T) {
+	id := types.FileContractID{1, 2, 3}
+
+	// Unmarshal a v133 header.
+	v133 := v133UpdateSetHeader{
+		ID: id,
+		Header: v133ContractHeader{
+			StartHeight: 5,
+			Utility: v133ContractUtility{
+				GoodForUpload: true,
+				Locked:        true,
+			},
+		},
+	}
+	var u updateSetHeader
+	if err := unmarshalHeader(encoding.Marshal(v133), &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != id || u.Header.StartHeight != 5 {
+		t.Fatal("v133 header was not unmarshaled correctly")
+	}
+	if !u.Header.Utility.GoodForUpload || u.Header.Utility.GoodForRenew || !u.Header.Utility.Locked {
+		t.Fatal("v133 utility was not unmarshaled correctly", u.Header.Utility)
+	}
+
+	// Unmarshal a v134 header.
+	v134 := v134UpdateSetHeader{
+		ID: id,
+		Header: v134ContractHeader{
+			StartHeight:    7,
+			UploadSpending: types.NewCurrency64(9),
+			Utility: modules.ContractUtility{
+				GoodForUpload: true,
+				Reason:        "bar",
+			},
+		},
+	}
+	u = updateSetHeader{}
+	if err := unmarshalHeader(encoding.Marshal(v134), &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != id || u.Header.StartHeight != 7 || !u.Header.UploadSpending.Equals64(9) {
+		t.Fatal("v134 header was not unmarshaled correctly")
+	}
+	if !reflect.DeepEqual(u.Header.Utility, v134.Header.Utility) {
+		t.Fatal("v134 utility was not unmarshaled correctly", u.Header.Utility)
+	}
+	if u.Header.UploadedBytes != 0 || u.Header.DownloadedBytes != 0 {
+		t.Fatal("v134 header should not have transferred any bytes")
+	}
+
+	// Unmarshal a current header.
+	current := updateSetHeader{
+		ID: id,
+		Header: contractHeader{
+			StartHeight: 6,
+			Utility: modules.ContractUtility{
+				GoodForRenew: true,
+				Reason:       "foo",
+			},
+			DownloadedBytes: 10,
+			UploadedBytes:   20,
+		},
+	}
+	u = updateSetHeader{}
+	if err := unmarshalHeader(encoding.Marshal(current), &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != id || u.Header.StartHeight != 6 {
+		t.Fatal("header was not unmarshaled correctly")
+	}
+	if !reflect.DeepEqual(u.Header.Utility, current.Header.Utility) {
+		t.Fatal("utility was not unmarshaled correctly", u.Header.Utility)
+	}
+	if u.Header.DownloadedBytes != 10 || u.Header.UploadedBytes != 20 {
+		t.Fatal("byte counters were not unmarshaled correctly")
+	}
+}
@@ -1,11 +1,14 @@
 package proto
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
 
 	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/encoding"
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/types"
 	"gitlab.com/NebulousLabs/ratelimit"
@@ -14,6 +17,16 @@ import (
 	"gitlab.com/NebulousLabs/writeaheadlog"
 )
 
+var (
+	// errImportNonEmpty is returned when Import is called on a contract set
+	// that already contains contracts.
+	errImportNonEmpty = errors.New("cannot import into a non-empty contract set")
+
+	// exportSpecifier is the specifier written at the start of a contract set
+	// export.
+	exportSpecifier = types.Specifier{'C', 'o', 'n', 't', 'r', 'a', 'c', 't', 'S', 'e', 't', 'E', 'x', 'p'}
+)
+
 // A ContractSet provides safe concurrent access to a set of contracts. Its
 // purpose is to serialize modifications to individual contracts, as well as
 // to provide operations on the set as a whole.
@@ -224,3 +237,201 @@ func v131RC2RenameWAL(dir string) error {
 	}
 	return nil
 }
+
+// Export writes the full contract set to w. For each contract, the header,
+// the Merkle roots and any unapplied WAL transactions are written, which is
+// enough to resume revising the contract with its host after the set has been
+// imported elsewhere.
+func (cs *ContractSet) Export(w io.Writer) error {
+	ids := cs.IDs()
+	enc := encoding.NewEncoder(w)
+	if err := enc.EncodeAll(exportSpecifier, uint64(len(ids))); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		sc, ok := cs.Acquire(id)
+		if !ok {
+			return errors.New("contract set is missing contract that was just listed")
+		}
+		err := sc.export(enc)
+		cs.Return(sc)
+		if err != nil {
+			return errors.AddContext(err, "failed to export contract "+id.String())
+		}
+	}
+	return nil
+}
+
+// Import reads a contract set previously written by Export from r and adds
+// its contracts to the set. Import may only be called on an empty set. The
+// whole export is read and validated before any contract is added, and the
+// set stays locked while the contracts are added, so that no other contract
+// can be inserted in the meantime.
+func (cs *ContractSet) Import(r io.Reader) error {
+	dec := encoding.NewDecoder(r)
+	var specifier types.Specifier
+	var numContracts uint64
+	if err := dec.DecodeAll(&specifier, &numContracts); err != nil {
+		return err
+	} else if specifier != exportSpecifier {
+		return errors.New("not a contract set export")
+	}
+	var contracts []exportedContract
+	for i := uint64(0); i < numContracts; i++ {
+		ec, err := readExportedContract(dec)
+		if err != nil {
+			return errors.AddContext(err, "failed to read contract")
+		}
+		contracts = append(contracts, ec)
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if len(cs.contracts) != 0 {
+		return errImportNonEmpty
+	}
+	for _, ec := range contracts {
+		if err := cs.importContract(ec); err != nil {
+			return errors.AddContext(err, "failed to import contract")
+		}
+	}
+	return nil
+}
+
+// export writes the contract's header, Merkle roots and unapplied WAL
+// transactions to enc. The contract must be acquired.
+func (c *SafeContract) export(enc *encoding.Encoder) error {
+	c.headerMu.Lock()
+	header := c.header
+	c.headerMu.Unlock()
+	roots, err := c.merkleRoots.merkleRoots()
+	if err != nil {
+		return err
+	}
+	// Roots are encoded one by one since the full slice may exceed
+	// encoding.MaxSliceSize.
+	if err := enc.EncodeAll(header, uint64(len(roots))); err != nil {
+		return err
+	}
+	for _, root := range roots {
+		if err := enc.Encode(root); err != nil {
+			return err
+		}
+	}
+	if err := enc.Encode(uint64(len(c.unappliedTxns))); err != nil {
+		return err
+	}
+	for _, t := range c.unappliedTxns {
+		if err := enc.Encode(t.Updates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportedContract is a contract read from a contract set export.
+type exportedContract struct {
+	header     contractHeader
+	roots      []crypto.Hash
+	txnUpdates [][]writeaheadlog.Update
+}
+
+// readExportedContract reads a single contract written by export from dec
+// and validates it.
+func readExportedContract(dec *encoding.Decoder) (exportedContract, error) {
+	var ec exportedContract
+	var numRoots uint64
+	if err := dec.DecodeAll(&ec.header, &numRoots); err != nil {
+		return exportedContract{}, err
+	}
+	if err := ec.header.validate(); err != nil {
+		return exportedContract{}, err
+	}
+	for i := uint64(0); i < numRoots; i++ {
+		var root crypto.Hash
+		if err := dec.Decode(&root); err != nil {
+			return exportedContract{}, err
+		}
+		ec.roots = append(ec.roots, root)
+	}
+	var numTxns uint64
+	if err := dec.Decode(&numTxns); err != nil {
+		return exportedContract{}, err
+	}
+	for i := uint64(0); i < numTxns; i++ {
+		var updates []writeaheadlog.Update
+		if err := dec.Decode(&updates); err != nil {
+			return exportedContract{}, err
+		}
+		if err := validateExportedUpdates(ec.header, len(ec.roots), updates); err != nil {
+			return exportedContract{}, err
+		}
+		ec.txnUpdates = append(ec.txnUpdates, updates)
+	}
+	return ec, nil
+}
+
+// validateExportedUpdates checks that updates form a valid unapplied
+// transaction of the contract with header h and numRoots sector roots.
+func validateExportedUpdates(h contractHeader, numRoots int, updates []writeaheadlog.Update) error {
+	if len(updates) == 0 {
+		return errors.New("empty transaction")
+	}
+	for _, update := range updates {
+		switch update.Name {
+		case updateNameSetHeader:
+			var u updateSetHeader
+			if err := unmarshalHeader(update.Instructions, &u); err != nil {
+				return err
+			}
+			if err := u.Header.validate(); err != nil {
+				return err
+			}
+			if u.ID != h.ID() || u.Header.ID() != h.ID() {
+				return errors.New("transaction updates a different contract")
+			}
+		case updateNameSetRoot:
+			var u updateSetRoot
+			if err := encoding.Unmarshal(update.Instructions, &u); err != nil {
+				return err
+			}
+			if u.ID != h.ID() {
+				return errors.New("transaction updates a different contract")
+			}
+			if u.Index < 0 || u.Index > numRoots {
+				return errors.New("transaction sets a root out of bounds")
+			}
+		default:
+			return errors.New("unknown update " + update.Name)
+		}
+	}
+	return nil
+}
+
+// importContract creates the contract file of ec, writes its unapplied
+// transactions to the set's WAL and adds it to the set, so that the
+// transactions can be applied or discarded as usual. The caller must hold the
+// set's lock.
+func (cs *ContractSet) importContract(ec exportedContract) error {
+	id := ec.header.ID()
+	if _, ok := cs.contracts[id]; ok {
+		return errors.New("contract set already contains contract " + id.String())
+	}
+	sc, err := cs.newSafeContract(ec.header, ec.roots)
+	if err != nil {
+		return err
+	}
+	for _, updates := range ec.txnUpdates {
+		t, err := cs.wal.NewTransaction(updates)
+		if err != nil {
+			return err
+		}
+		if err := <-t.SignalSetupComplete(); err != nil {
+			return err
+		}
+		sc.unappliedTxns = append(sc.unappliedTxns, t)
+	}
+	cs.contracts[id] = sc
+	cs.pubKeys[string(ec.header.HostPublicKey().Key)] = id
+	return nil
+}
@@ -1,16 +1,21 @@
 package proto
 
 import (
+	"bytes"
+	"path/filepath"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
 
 	"gitlab.com/NebulousLabs/Sia/build"
 	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/encoding"
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/types"
 
 	"gitlab.com/NebulousLabs/fastrand"
+	"gitlab.com/NebulousLabs/writeaheadlog"
 )
 
 // mustAcquire is a convenience function for acquiring contracts that are
@@ -149,3 +154,144 @@ func TestContractSet(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+// TestContractSetExportImport tests that a contract set can be exported and
+// imported into a new, empty set.
+func TestContractSetExportImport(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	// create contract set with a contract
+	testDir := build.TempDir(t.Name())
+	cs, err := NewContractSet(filepath.Join(testDir, "old"), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := contractHeader{Transaction: types.Transaction{
+		FileContractRevisions: []types.FileContractRevision{{
+			ParentID:             types.FileContractID{1},
+			NewValidProofOutputs: []types.SiacoinOutput{{}, {}},
+			UnlockConditions: types.UnlockConditions{
+				PublicKeys: []types.SiaPublicKey{{}, {}},
+			},
+		}},
+	}}
+	roots := []crypto.Hash{{1}, {2}, {3}}
+	if _, err := cs.managedInsertContract(header, roots); err != nil {
+		t.Fatal(err)
+	}
+	// record an intent so that the contract has an unapplied transaction
+	sc := cs.mustAcquire(t, header.ID())
+	rev := header.LastRevision()
+	rev.NewRevisionNumber++
+	if _, err := sc.recordDownloadIntent(rev, types.ZeroCurrency, 0); err != nil {
+		t.Fatal(err)
+	}
+	cs.Return(sc)
+
+	// export the set
+	var buf bytes.Buffer
+	if err := cs.Export(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// importing into a non-empty set should fail
+	if err := cs.Import(bytes.NewReader(buf.Bytes())); err != errImportNonEmpty {
+		t.Fatal("expected errImportNonEmpty, got", err)
+	}
+
+	// import into a new set
+	cs2, err := NewContractSet(filepath.Join(testDir, "new"), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs2.Import(&buf); err != nil {
+		t.Fatal(err)
+	}
+	sc = cs2.mustAcquire(t, header.ID())
+	defer cs2.Return(sc)
+	importedRoots, err := sc.merkleRoots.merkleRoots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(importedRoots, roots) {
+		t.Fatal("imported roots do not match exported roots")
+	}
+	if len(sc.unappliedTxns) != 1 {
+		t.Fatal("expected 1 unapplied transaction, got", len(sc.unappliedTxns))
+	}
+}
+
+// TestContractSetImportInvalid tests that Import rejects invalid contracts
+// and WAL transactions without adding anything to the set.
+func TestContractSetImportInvalid(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	cs, err := NewContractSet(build.TempDir(t.Name()), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	header := contractHeader{Transaction: types.Transaction{
+		FileContractRevisions: []types.FileContractRevision{{
+			ParentID:             types.FileContractID{1},
+			NewValidProofOutputs: []types.SiacoinOutput{{}, {}},
+			UnlockConditions: types.UnlockConditions{
+				PublicKeys: []types.SiaPublicKey{{}, {}},
+			},
+		}},
+	}}
+	// export encodes a set with a single contract without roots.
+	export := func(h contractHeader, txnUpdates ...[]writeaheadlog.Update) *bytes.Buffer {
+		var buf bytes.Buffer
+		enc := encoding.NewEncoder(&buf)
+		if err := enc.EncodeAll(exportSpecifier, uint64(1), h, uint64(0), uint64(len(txnUpdates))); err != nil {
+			t.Fatal(err)
+		}
+		for _, updates := range txnUpdates {
+			if err := enc.Encode(updates); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return &buf
+	}
+	setRoot := func(id types.FileContractID, index int) writeaheadlog.Update {
+		return writeaheadlog.Update{
+			Name:         updateNameSetRoot,
+			Instructions: encoding.Marshal(updateSetRoot{ID: id, Index: index}),
+		}
+	}
+
+	tests := []struct {
+		name string
+		r    *bytes.Buffer
+	}{
+		{"no revision", export(contractHeader{})},
+		{"empty transaction", export(header, []writeaheadlog.Update{})},
+		{"unknown update", export(header, []writeaheadlog.Update{{Name: "foo"}})},
+		{"invalid header", export(header, []writeaheadlog.Update{{
+			Name:         updateNameSetHeader,
+			Instructions: encoding.Marshal(updateSetHeader{ID: header.ID()}),
+		}})},
+		{"other contract", export(header, []writeaheadlog.Update{setRoot(types.FileContractID{2}, 0)})},
+		{"root out of bounds", export(header, []writeaheadlog.Update{setRoot(header.ID(), 1)})},
+	}
+	for _, test := range tests {
+		if err := cs.Import(test.r); err == nil {
+			t.Errorf("%v: expected import to fail", test.name)
+		}
+		if cs.Len() != 0 {
+			t.Fatalf("%v: contract was imported", test.name)
+		}
+	}
+
+	// A valid intent is imported.
+	if err := cs.Import(export(header, []writeaheadlog.Update{setRoot(header.ID(), 0)})); err != nil {
+		t.Fatal(err)
+	}
+	if cs.Len() != 1 {
+		t.Fatal("expected 1 contract, got", cs.Len())
+	}
+}
@@ -63,7 +63,7 @@ func (hd *Downloader) Sector(root crypto.Hash) (_ modules.RenterContract, _ []by
 	// record the change we are about to make to the contract. If we lose power
 	// mid-revision, this allows us to restore either the pre-revision or
 	// post-revision contract.
-	walTxn, err := sc.recordDownloadIntent(rev, sectorPrice)
+	walTxn, err := sc.recordDownloadIntent(rev, sectorPrice, modules.SectorSize)
 	if err != nil {
 		return modules.RenterContract{}, nil, err
 	}
@@ -129,7 +129,7 @@ func (hd *Downloader) Sector(root crypto.Hash) (_ modules.RenterContract, _ []by
 	}
 
 	// update contract and metrics
-	if err := sc.commitDownload(walTxn, signedTxn, sectorPrice); err != nil {
+	if err := sc.commitDownload(walTxn, signedTxn, sectorPrice, modules.SectorSize); err != nil {
 		return modules.RenterContract{}, nil, err
 	}
 
@@ -139,10 +139,7 @@ func (hd *Downloader) Sector(root crypto.Hash) (_ modules.RenterContract, _ []by
 // shutdown terminates the revision loop and signals the goroutine spawned in
 // NewDownloader to return.
 func (hd *Downloader) shutdown() {
-	extendDeadline(hd.conn, modules.NegotiateSettingsTime)
-	// don't care about these errors
-	_, _ = verifySettings(hd.conn, hd.host)
-	_ = modules.WriteNegotiationStop(hd.conn)
+	endRevisionLoop(hd.conn, hd.host)
 	close(hd.closeChan)
 }
 
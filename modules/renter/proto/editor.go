@@ -42,10 +42,7 @@ type Editor struct {
 // shutdown terminates the revision loop and signals the goroutine spawned in
 // NewEditor to return.
 func (he *Editor) shutdown() {
-	extendDeadline(he.conn, modules.NegotiateSettingsTime)
-	// don't care about these errors
-	_, _ = verifySettings(he.conn, he.host)
-	_ = modules.WriteNegotiationStop(he.conn)
+	endRevisionLoop(he.conn, he.host)
 	close(he.closeChan)
 }
 
@@ -126,7 +123,7 @@ func (he *Editor) Upload(data []byte) (_ modules.RenterContract, _ crypto.Hash,
 	// record the change we are about to make to the contract. If we lose power
 	// mid-revision, this allows us to restore either the pre-revision or
 	// post-revision contract.
-	walTxn, err := sc.recordUploadIntent(rev, sectorRoot, sectorStoragePrice, sectorBandwidthPrice)
+	walTxn, err := sc.recordUploadIntent(rev, sectorRoot, sectorStoragePrice, sectorBandwidthPrice, modules.SectorSize)
 	if err != nil {
 		return modules.RenterContract{}, crypto.Hash{}, err
 	}
@@ -161,7 +158,7 @@ func (he *Editor) Upload(data []byte) (_ modules.RenterContract, _ crypto.Hash,
 	}
 
 	// update contract
-	err = sc.commitUpload(walTxn, signedTxn, sectorRoot, sectorStoragePrice, sectorBandwidthPrice)
+	err = sc.commitUpload(walTxn, signedTxn, sectorRoot, sectorStoragePrice, sectorBandwidthPrice, modules.SectorSize)
 	if err != nil {
 		return modules.RenterContract{}, crypto.Hash{}, err
 	}
@@ -227,6 +224,22 @@ func (cs *ContractSet) NewEditor(host modules.HostDBEntry, id types.FileContract
 // initiateRevisionLoop initiates either the editor or downloader loop with
 // host, depending on which rpc was passed.
 func initiateRevisionLoop(host modules.HostDBEntry, contract contractHeader, rpc types.Specifier, cancel <-chan struct{}, rl *ratelimit.RateLimit) (net.Conn, chan struct{}, error) {
+	conn, closeChan, err := initiateRPC(host, rpc, cancel, rl)
+	if err != nil {
+		return nil, closeChan, err
+	}
+	defer extendDeadline(conn, time.Hour)
+	if err := verifyRecentRevision(conn, contract, host.Version); err != nil {
+		conn.Close() // TODO: close gracefully if host has entered revision loop
+		close(closeChan)
+		return nil, closeChan, err
+	}
+	return conn, closeChan, nil
+}
+
+// initiateRPC dials host and sends it the specified rpc. The returned channel
+// must be closed once the connection is no longer needed.
+func initiateRPC(host modules.HostDBEntry, rpc types.Specifier, cancel <-chan struct{}, rl *ratelimit.RateLimit) (net.Conn, chan struct{}, error) {
 	c, err := (&net.Dialer{
 		Cancel:  cancel,
 		Timeout: 45 * time.Second, // TODO: Constant
@@ -247,16 +260,10 @@ func initiateRevisionLoop(host modules.HostDBEntry, contract contractHeader, rpc
 
 	// allot 2 minutes for RPC request + revision exchange
 	extendDeadline(conn, modules.NegotiateRecentRevisionTime)
-	defer extendDeadline(conn, time.Hour)
 	if err := encoding.WriteObject(conn, rpc); err != nil {
 		conn.Close()
 		close(closeChan)
 		return nil, closeChan, errors.New("couldn't initiate RPC: " + err.Error())
 	}
-	if err := verifyRecentRevision(conn, contract, host.Version); err != nil {
-		conn.Close() // TODO: close gracefully if host has entered revision loop
-		close(closeChan)
-		return nil, closeChan, err
-	}
 	return conn, closeChan, nil
 }
@@ -18,8 +18,11 @@ func (cs *ContractSet) FormContract(params ContractParams, txnBuilder transactio
 	// Extract vars from params, for convenience.
 	host, funding, startHeight, endHeight, refundAddress := params.Host, params.Funding, params.StartHeight, params.EndHeight, params.RefundAddress
 
-	// Create our key.
-	ourSK, ourPK := crypto.GenerateKeyPair()
+	// Create our key, unless one was supplied.
+	ourSK, ourPK := params.SecretKey, params.SecretKey.PublicKey()
+	if ourSK == (crypto.SecretKey{}) {
+		ourSK, ourPK = crypto.GenerateKeyPair()
+	}
 	// Create unlock conditions.
 	uc := types.UnlockConditions{
 		PublicKeys: []types.SiaPublicKey{
@@ -288,6 +288,27 @@ func (mr *merkleRoots) push(root crypto.Hash) error {
 	return nil
 }
 
+// replace replaces all the merkle roots of the contract with roots. The file is
+// truncated if it contained more roots than that.
+func (mr *merkleRoots) replace(roots []crypto.Hash) error {
+	data := make([]byte, 0, len(roots)*crypto.HashSize)
+	for _, root := range roots {
+		data = append(data, root[:]...)
+	}
+	if _, err := mr.rootsFile.WriteAt(data, 0); err != nil {
+		return errors.AddContext(err, "failed to write roots to disk")
+	}
+	if err := mr.rootsFile.Truncate(fileOffsetFromRootIndex(len(roots))); err != nil {
+		return errors.AddContext(err, "failed to truncate file")
+	}
+	// Rebuild the in-memory structure.
+	mr.cachedSubTrees = nil
+	mr.uncachedRoots = nil
+	mr.appendRootMemory(roots...)
+	mr.numMerkleRoots = len(roots)
+	return nil
+}
+
 // root returns the root of the merkle roots.
 func (mr *merkleRoots) root() crypto.Hash {
 	tree := crypto.NewTree()
@@ -192,6 +192,59 @@ func TestInsertMerkleRoot(t *testing.T) {
 	}
 }
 
+// TestReplaceMerkleRoots tests the replace method.
+func TestReplaceMerkleRoots(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	dir := build.TempDir(t.Name())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	filePath := path.Join(dir, "file.dat")
+	file, err := os.Create(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create sector roots.
+	rootSection := newFileSection(file, 0, -1)
+	merkleRoots := newMerkleRoots(rootSection)
+	for i := 0; i < 300; i++ {
+		hash := crypto.Hash{}
+		copy(hash[:], fastrand.Bytes(crypto.HashSize)[:])
+		merkleRoots.push(hash)
+	}
+
+	// Replace them with fewer roots. The file should be truncated.
+	newRoots := make([]crypto.Hash, 200)
+	for i := range newRoots {
+		copy(newRoots[i][:], fastrand.Bytes(crypto.HashSize)[:])
+	}
+	if err := merkleRoots.replace(newRoots); err != nil {
+		t.Fatal("failed to replace roots", err)
+	}
+	roots, err := merkleRoots.merkleRoots()
+	if err != nil {
+		t.Fatal("failed to get roots from disk", err)
+	}
+	if !reflect.DeepEqual(roots, newRoots) {
+		t.Fatal("roots weren't replaced correctly on disk")
+	}
+	if merkleRoots.root() != cachedMerkleRoot(newRoots) {
+		t.Fatal("in-memory roots don't match the new roots")
+	}
+	// Reload the roots. The in-memory structure and the roots on disk should
+	// still be consistent.
+	loadedRoots, err := loadExistingMerkleRoots(merkleRoots.rootsFile)
+	if err != nil {
+		t.Fatal("failed to load existing roots", err)
+	}
+	if err := cmpRoots(merkleRoots, loadedRoots); err != nil {
+		t.Fatal("loaded roots are inconsistent", err)
+	}
+}
+
 // TestDeleteLastRoot tests the deleteLastRoot method.
 func TestDeleteLastRoot(t *testing.T) {
 	if testing.Short() {
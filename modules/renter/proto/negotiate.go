@@ -69,14 +69,35 @@ func verifySettings(conn net.Conn, host modules.HostDBEntry) (modules.HostDBEntr
 // verifyRecentRevision confirms that the host and contractor agree upon the current
 // state of the contract being revised.
 func verifyRecentRevision(conn net.Conn, contract contractHeader, hostVersion string) error {
+	lastRevision, hostSignatures, err := readRecentRevision(conn, contract, hostVersion)
+	if err != nil {
+		return err
+	}
+	// Check that the unlock hashes match; if they do not, something is
+	// seriously wrong. Otherwise, check that the revision numbers match.
+	ourRev := contract.LastRevision()
+	if lastRevision.UnlockConditions.UnlockHash() != ourRev.UnlockConditions.UnlockHash() {
+		return errors.New("unlock conditions do not match")
+	} else if lastRevision.NewRevisionNumber != ourRev.NewRevisionNumber {
+		return &recentRevisionError{ourRev.NewRevisionNumber, lastRevision.NewRevisionNumber}
+	}
+	// NOTE: we can fake the blockheight here because it doesn't affect
+	// verification; it just needs to be above the fork height and below the
+	// contract expiration (which was checked earlier).
+	return modules.VerifyFileContractRevisionTransactionSignatures(lastRevision, hostSignatures, contract.EndHeight()-1)
+}
+
+// readRecentRevision proves ownership of the contract to the host and reads
+// the host's most recent revision of the contract and its signatures.
+func readRecentRevision(conn net.Conn, contract contractHeader, hostVersion string) (types.FileContractRevision, []types.TransactionSignature, error) {
 	// send contract ID
 	if err := encoding.WriteObject(conn, contract.ID()); err != nil {
-		return errors.New("couldn't send contract ID: " + err.Error())
+		return types.FileContractRevision{}, nil, errors.New("couldn't send contract ID: " + err.Error())
 	}
 	// read challenge
 	var challenge crypto.Hash
 	if err := encoding.ReadObject(conn, &challenge, 32); err != nil {
-		return errors.New("couldn't read challenge: " + err.Error())
+		return types.FileContractRevision{}, nil, errors.New("couldn't read challenge: " + err.Error())
 	}
 	if build.VersionCmp(hostVersion, "1.3.0") >= 0 {
 		crypto.SecureWipe(challenge[:16])
@@ -84,33 +105,22 @@ func verifyRecentRevision(conn net.Conn, contract contractHeader, hostVersion st
 	// sign and return
 	sig := crypto.SignHash(challenge, contract.SecretKey)
 	if err := encoding.WriteObject(conn, sig); err != nil {
-		return errors.New("couldn't send challenge response: " + err.Error())
+		return types.FileContractRevision{}, nil, errors.New("couldn't send challenge response: " + err.Error())
 	}
 	// read acceptance
 	if err := modules.ReadNegotiationAcceptance(conn); err != nil {
-		return errors.New("host did not accept revision request: " + err.Error())
+		return types.FileContractRevision{}, nil, errors.New("host did not accept revision request: " + err.Error())
 	}
 	// read last revision and signatures
 	var lastRevision types.FileContractRevision
 	var hostSignatures []types.TransactionSignature
 	if err := encoding.ReadObject(conn, &lastRevision, 2048); err != nil {
-		return errors.New("couldn't read last revision: " + err.Error())
+		return types.FileContractRevision{}, nil, errors.New("couldn't read last revision: " + err.Error())
 	}
 	if err := encoding.ReadObject(conn, &hostSignatures, 2048); err != nil {
-		return errors.New("couldn't read host signatures: " + err.Error())
-	}
-	// Check that the unlock hashes match; if they do not, something is
-	// seriously wrong. Otherwise, check that the revision numbers match.
-	ourRev := contract.LastRevision()
-	if lastRevision.UnlockConditions.UnlockHash() != ourRev.UnlockConditions.UnlockHash() {
-		return errors.New("unlock conditions do not match")
-	} else if lastRevision.NewRevisionNumber != ourRev.NewRevisionNumber {
-		return &recentRevisionError{ourRev.NewRevisionNumber, lastRevision.NewRevisionNumber}
+		return types.FileContractRevision{}, nil, errors.New("couldn't read host signatures: " + err.Error())
 	}
-	// NOTE: we can fake the blockheight here because it doesn't affect
-	// verification; it just needs to be above the fork height and below the
-	// contract expiration (which was checked earlier).
-	return modules.VerifyFileContractRevisionTransactionSignatures(lastRevision, hostSignatures, contract.EndHeight()-1)
+	return lastRevision, hostSignatures, nil
 }
 
 // negotiateRevision sends a revision and actions to the host for approval,
@@ -42,7 +42,11 @@ type ContractParams struct {
 	StartHeight   types.BlockHeight
 	EndHeight     types.BlockHeight
 	RefundAddress types.UnlockHash
-	// TODO: add optional keypair
+
+	// SecretKey is the key that the renter uses to sign the contract. If it
+	// is empty, FormContract generates a random key and Renew keeps the key
+	// of the old contract.
+	SecretKey crypto.SecretKey
 }
 
 // A revisionSaver is called just before we send our revision signature to the host; this
@@ -0,0 +1,374 @@
+package proto
+
+import (
+	"bytes"
+	"net"
+
+	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/encoding"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/ratelimit"
+	"gitlab.com/NebulousLabs/writeaheadlog"
+)
+
+// sectorRootsMinVersion is the first host version that supports
+// RPCSectorRoots.
+const sectorRootsMinVersion = "1.3.3"
+
+var (
+	// errSectorRootsUnsupported is returned when the sector roots of a
+	// contract are requested from a host that doesn't support RPCSectorRoots.
+	// The roots can't be rebuilt from such a host; the contract should be
+	// replaced by a new contract instead.
+	errSectorRootsUnsupported = errors.New("host does not support RPCSectorRoots; version " + sectorRootsMinVersion + " or later is required")
+
+	// errHostRevisionOutdated is returned by RecoverContract if the host
+	// reports an older revision than the one we have stored. Adopting such a
+	// revision would discard a revision that the host already signed.
+	errHostRevisionOutdated = errors.New("host's revision is older than ours")
+
+	// errHostRevisionChanged is returned by RecoverContract if the host's
+	// revision changed while the sector roots were downloaded.
+	errHostRevisionChanged = errors.New("host's revision changed during recovery")
+
+	// errRevisionMismatch is returned by SyncMerkleRoots if the host's
+	// revision differs from ours. The roots the host sends would then belong
+	// to a different revision.
+	errRevisionMismatch = errors.New("host's revision doesn't match ours; recover the contract first")
+
+	// ErrSectorRootsInvalid is returned by SyncMerkleRoots if the sector roots
+	// sent by the host don't match the revision's Merkle root.
+	ErrSectorRootsInvalid = errors.New("sector roots sent by host don't match the revision's Merkle root")
+)
+
+// fetchRecentRevision retrieves the host's most recent revision of contract
+// and the signatures covering it. The revision loop is terminated before
+// returning.
+func fetchRecentRevision(host modules.HostDBEntry, contract contractHeader, cancel <-chan struct{}, rl *ratelimit.RateLimit) (types.FileContractRevision, []types.TransactionSignature, error) {
+	conn, closeChan, err := initiateRPC(host, modules.RPCDownload, cancel, rl)
+	if err != nil {
+		return types.FileContractRevision{}, nil, err
+	}
+	defer close(closeChan)
+	defer conn.Close()
+
+	rev, sigs, err := readRecentRevision(conn, contract, host.Version)
+	if err != nil {
+		return types.FileContractRevision{}, nil, err
+	}
+	endRevisionLoop(conn, host)
+	return rev, sigs, nil
+}
+
+// endRevisionLoop gracefully terminates a revision loop that the host has
+// entered.
+func endRevisionLoop(conn net.Conn, host modules.HostDBEntry) {
+	extendDeadline(conn, modules.NegotiateSettingsTime)
+	// don't care about these errors
+	_, _ = verifySettings(conn, host)
+	_ = modules.WriteNegotiationStop(conn)
+}
+
+// RecoverContract resynchronizes the contract with the given id with the
+// host's most recent revision. It is meant to be called after the host
+// reported a revision number that doesn't match ours. The host's revision is
+// only adopted if it is properly signed and newer than ours. If it covers
+// sector roots that are neither stored in the contract nor recorded in the
+// WAL, the roots are downloaded from the host and verified against the
+// revision.
+func (cs *ContractSet) RecoverContract(host modules.HostDBEntry, id types.FileContractID, cancel <-chan struct{}) (modules.RenterContract, error) {
+	sc, ok := cs.Acquire(id)
+	if !ok {
+		return modules.RenterContract{}, errors.New("invalid contract")
+	}
+	defer cs.Return(sc)
+	contract := sc.header
+
+	rev, sigs, err := fetchRecentRevision(host, contract, cancel, cs.rl)
+	if err != nil {
+		return modules.RenterContract{}, err
+	}
+	ourRev := contract.LastRevision()
+	if rev.UnlockConditions.UnlockHash() != ourRev.UnlockConditions.UnlockHash() {
+		return modules.RenterContract{}, errors.New("unlock conditions do not match")
+	}
+	// NOTE: we can fake the blockheight here because it doesn't affect
+	// verification; it just needs to be above the fork height and below the
+	// contract expiration.
+	if err := modules.VerifyFileContractRevisionTransactionSignatures(rev, sigs, contract.EndHeight()-1); err != nil {
+		return modules.RenterContract{}, errors.New("host's revision is not properly signed: " + err.Error())
+	}
+
+	switch {
+	case rev.NewRevisionNumber == ourRev.NewRevisionNumber:
+		// We are in sync with the host; any unapplied updates are stale.
+		if err := sc.discardTxns(); err != nil {
+			return modules.RenterContract{}, err
+		}
+		return sc.Metadata(), nil
+	case rev.NewRevisionNumber < ourRev.NewRevisionNumber:
+		return modules.RenterContract{}, errHostRevisionOutdated
+	}
+
+	// If the WAL contains the host's revision, apply it. This also restores
+	// any sector roots that were added by the revision.
+	if len(sc.unappliedTxns) > 0 {
+		unappliedHeader := sc.unappliedHeader()
+		unappliedRev := unappliedHeader.LastRevision()
+		if unappliedRev.NewRevisionNumber == rev.NewRevisionNumber && unappliedRev.NewFileMerkleRoot == rev.NewFileMerkleRoot {
+			if err := sc.commitTxns(); err != nil {
+				return modules.RenterContract{}, err
+			}
+			// The unapplied header does not include the host's signature, so
+			// it must be replaced with the host's signed revision.
+			if err := sc.recoverRevision(rev, sigs, types.ZeroCurrency, types.ZeroCurrency); err != nil {
+				return modules.RenterContract{}, err
+			}
+			return sc.Metadata(), nil
+		}
+	}
+
+	// Otherwise, the host's revision is adopted directly. If it changed the
+	// sectors stored by the host, our roots no longer match and are replaced
+	// by the roots of the host.
+	ourFunds, theirFunds := ourRev.NewValidProofOutputs[0].Value, rev.NewValidProofOutputs[0].Value
+	if theirFunds.Cmp(ourFunds) > 0 {
+		return modules.RenterContract{}, errors.New("host's revision increases the renter's funds")
+	}
+	rootsChanged := rev.NewFileMerkleRoot != sc.merkleRoots.root() || rev.NewFileSize != ourRev.NewFileSize
+	var roots []crypto.Hash
+	if rootsChanged {
+		roots, err = fetchRevisionRoots(host, contract, rev, cancel, cs.rl)
+		if err != nil {
+			return modules.RenterContract{}, err
+		}
+	}
+	if err := sc.discardTxns(); err != nil {
+		return modules.RenterContract{}, err
+	}
+	// The money spent by a revision that changed the roots is counted as
+	// upload spending; otherwise the revision paid for a download.
+	cost := ourFunds.Sub(theirFunds)
+	downloadCost, uploadCost := cost, types.ZeroCurrency
+	if rootsChanged {
+		if err := sc.replaceRoots(roots); err != nil {
+			return modules.RenterContract{}, err
+		}
+		downloadCost, uploadCost = types.ZeroCurrency, cost
+	}
+	if err := sc.recoverRevision(rev, sigs, downloadCost, uploadCost); err != nil {
+		return modules.RenterContract{}, err
+	}
+	return sc.Metadata(), nil
+}
+
+// fetchSectorRoots retrieves the host's most recent revision of contract, the
+// signatures covering it, and the sector roots of the contract.
+func fetchSectorRoots(host modules.HostDBEntry, contract contractHeader, cancel <-chan struct{}, rl *ratelimit.RateLimit) (types.FileContractRevision, []types.TransactionSignature, []crypto.Hash, error) {
+	if build.VersionCmp(host.Version, sectorRootsMinVersion) < 0 {
+		return types.FileContractRevision{}, nil, nil, errSectorRootsUnsupported
+	}
+	conn, closeChan, err := initiateRPC(host, modules.RPCSectorRoots, cancel, rl)
+	if err != nil {
+		return types.FileContractRevision{}, nil, nil, err
+	}
+	defer close(closeChan)
+	defer conn.Close()
+
+	rev, sigs, err := readRecentRevision(conn, contract, host.Version)
+	if err != nil {
+		return types.FileContractRevision{}, nil, nil, err
+	}
+	// The number of roots is bounded by the size of the revision's file.
+	extendDeadline(conn, modules.NegotiateSectorRootsTime)
+	maxLen := 8 + (rev.NewFileSize/modules.SectorSize+1)*crypto.HashSize
+	var roots []crypto.Hash
+	if err := encoding.ReadObject(conn, &roots, maxLen); err != nil {
+		return types.FileContractRevision{}, nil, nil, errors.AddContext(err, "couldn't read sector roots")
+	}
+	return rev, sigs, roots, nil
+}
+
+// fetchRevisionRoots retrieves the sector roots of contract from the host and
+// verifies them against rev, the host's most recent revision.
+func fetchRevisionRoots(host modules.HostDBEntry, contract contractHeader, rev types.FileContractRevision, cancel <-chan struct{}, rl *ratelimit.RateLimit) ([]crypto.Hash, error) {
+	hostRev, _, roots, err := fetchSectorRoots(host, contract, cancel, rl)
+	if err != nil {
+		return nil, err
+	}
+	if hostRev.NewRevisionNumber != rev.NewRevisionNumber || hostRev.NewFileMerkleRoot != rev.NewFileMerkleRoot {
+		return nil, errHostRevisionChanged
+	}
+	if !validRoots(roots, rev) {
+		return nil, ErrSectorRootsInvalid
+	}
+	return roots, nil
+}
+
+// validRoots returns true if roots are the sector roots covered by rev.
+func validRoots(roots []crypto.Hash, rev types.FileContractRevision) bool {
+	return uint64(len(roots))*modules.SectorSize == rev.NewFileSize && cachedMerkleRoot(roots) == rev.NewFileMerkleRoot
+}
+
+// SyncMerkleRoots replaces the sector roots stored for the contract with the
+// given id by the roots that the host stores for it. It is meant to be used
+// if the local roots were lost or corrupted. The roots are only accepted if
+// the host's revision matches ours and the roots match the revision's Merkle
+// root.
+func (cs *ContractSet) SyncMerkleRoots(host modules.HostDBEntry, id types.FileContractID, cancel <-chan struct{}) error {
+	sc, ok := cs.Acquire(id)
+	if !ok {
+		return errors.New("invalid contract")
+	}
+	defer cs.Return(sc)
+	contract := sc.header
+
+	rev, sigs, roots, err := fetchSectorRoots(host, contract, cancel, cs.rl)
+	if err != nil {
+		return err
+	}
+	ourRev := contract.LastRevision()
+	if rev.UnlockConditions.UnlockHash() != ourRev.UnlockConditions.UnlockHash() {
+		return errors.New("unlock conditions do not match")
+	}
+	// NOTE: we can fake the blockheight here because it doesn't affect
+	// verification; it just needs to be above the fork height and below the
+	// contract expiration.
+	if err := modules.VerifyFileContractRevisionTransactionSignatures(rev, sigs, contract.EndHeight()-1); err != nil {
+		return errors.New("host's revision is not properly signed: " + err.Error())
+	}
+	if rev.NewRevisionNumber != ourRev.NewRevisionNumber || rev.NewFileMerkleRoot != ourRev.NewFileMerkleRoot {
+		return errRevisionMismatch
+	}
+
+	// Verify the roots before accepting them.
+	if !validRoots(roots, ourRev) {
+		return ErrSectorRootsInvalid
+	}
+	return sc.replaceRoots(roots)
+}
+
+// replaceRoots replaces all sector roots of the contract with roots.
+func (c *SafeContract) replaceRoots(roots []crypto.Hash) error {
+	// Record the roots in the WAL, so that they are written again if the
+	// replacement is interrupted.
+	updates := make([]writeaheadlog.Update, 0, len(roots))
+	for i, root := range roots {
+		updates = append(updates, c.makeUpdateSetRoot(root, i))
+	}
+	t, err := c.wal.NewTransaction(updates)
+	if err != nil {
+		return err
+	}
+	if err := <-t.SignalSetupComplete(); err != nil {
+		return err
+	}
+	if err := c.merkleRoots.replace(roots); err != nil {
+		return err
+	}
+	if err := c.headerFile.Sync(); err != nil {
+		return err
+	}
+	return t.SignalUpdatesApplied()
+}
+
+// discardTxns marks the unapplied transactions of the contract as applied
+// without applying them.
+func (c *SafeContract) discardTxns() error {
+	for _, t := range c.unappliedTxns {
+		if err := t.SignalUpdatesApplied(); err != nil {
+			return err
+		}
+	}
+	c.unappliedTxns = nil
+	return nil
+}
+
+// recoverRevision replaces the revision of the contract with a revision signed
+// by both parties. downloadCost and uploadCost are the amounts of money that
+// were spent by the revision on downloads and uploads.
+func (c *SafeContract) recoverRevision(rev types.FileContractRevision, sigs []types.TransactionSignature, downloadCost, uploadCost types.Currency) error {
+	// construct new header
+	c.headerMu.Lock()
+	newHeader := c.header
+	c.headerMu.Unlock()
+	newHeader.Transaction = types.Transaction{
+		FileContractRevisions: []types.FileContractRevision{rev},
+		TransactionSignatures: sigs,
+	}
+	newHeader.DownloadSpending = newHeader.DownloadSpending.Add(downloadCost)
+	newHeader.UploadSpending = newHeader.UploadSpending.Add(uploadCost)
+
+	t, err := c.wal.NewTransaction([]writeaheadlog.Update{
+		c.makeUpdateSetHeader(newHeader),
+	})
+	if err != nil {
+		return err
+	}
+	if err := <-t.SignalSetupComplete(); err != nil {
+		return err
+	}
+	if err := c.applySetHeader(newHeader); err != nil {
+		return err
+	}
+	if err := c.headerFile.Sync(); err != nil {
+		return err
+	}
+	return t.SignalUpdatesApplied()
+}
+
+// RecoverContractFromHost adds the contract with the given id to the set by
+// retrieving its most recent revision and its sector roots from the host. It
+// is meant to be used if the renter lost its record of the contract but still
+// knows the key that was used to sign it. Since the original cost of the
+// contract is unknown, its total cost is set to the renter's remaining funds.
+func (cs *ContractSet) RecoverContractFromHost(host modules.HostDBEntry, id types.FileContractID, sk crypto.SecretKey, startHeight types.BlockHeight, cancel <-chan struct{}) (modules.RenterContract, error) {
+	if _, exists := cs.View(id); exists {
+		return modules.RenterContract{}, errors.New("contract is already in the set")
+	}
+	// Proving ownership of the contract only requires its id and our key.
+	header := contractHeader{
+		Transaction: types.Transaction{
+			FileContractRevisions: []types.FileContractRevision{{ParentID: id}},
+		},
+		SecretKey:   sk,
+		StartHeight: startHeight,
+	}
+	rev, sigs, roots, err := fetchSectorRoots(host, header, cancel, cs.rl)
+	if err != nil {
+		return modules.RenterContract{}, err
+	}
+	uc := rev.UnlockConditions
+	if rev.ParentID != id || len(uc.PublicKeys) != 2 || !equalPublicKeys(uc.PublicKeys[0], types.Ed25519PublicKey(sk.PublicKey())) || !equalPublicKeys(uc.PublicKeys[1], host.PublicKey) {
+		return modules.RenterContract{}, errors.New("unlock conditions do not match")
+	}
+	// NOTE: we can fake the blockheight here because it doesn't affect
+	// verification; it just needs to be above the fork height and below the
+	// contract expiration.
+	if err := modules.VerifyFileContractRevisionTransactionSignatures(rev, sigs, rev.NewWindowStart-1); err != nil {
+		return modules.RenterContract{}, errors.New("host's revision is not properly signed: " + err.Error())
+	}
+	if !validRoots(roots, rev) {
+		return modules.RenterContract{}, ErrSectorRootsInvalid
+	}
+
+	header.Transaction = types.Transaction{
+		FileContractRevisions: []types.FileContractRevision{rev},
+		TransactionSignatures: sigs,
+	}
+	header.TotalCost = rev.NewValidProofOutputs[0].Value
+	header.Utility = modules.ContractUtility{
+		GoodForUpload: true,
+		GoodForRenew:  true,
+	}
+	return cs.managedInsertContract(header, roots)
+}
+
+// equalPublicKeys returns true if a and b are the same public key.
+func equalPublicKeys(a, b types.SiaPublicKey) bool {
+	return a.Algorithm == b.Algorithm && bytes.Equal(a.Key, b.Key)
+}
@@ -0,0 +1,416 @@
+package proto
+
+import (
+	"net"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/encoding"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestContractRecoverRevision tests that a contract can adopt a revision
+// reported by the host after discarding its unapplied transactions.
+func TestContractRecoverRevision(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	// create contract set with one contract
+	dir := build.TempDir(filepath.Join("proto", t.Name()))
+	cs, err := NewContractSet(dir, modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialHeader := contractHeader{
+		Transaction: types.Transaction{
+			FileContractRevisions: []types.FileContractRevision{{
+				NewRevisionNumber:    1,
+				NewValidProofOutputs: []types.SiacoinOutput{{Value: types.NewCurrency64(100)}, {}},
+				UnlockConditions: types.UnlockConditions{
+					PublicKeys: []types.SiaPublicKey{{}, {}},
+				},
+			}},
+		},
+	}
+	c, err := cs.managedInsertContract(initialHeader, []crypto.Hash{{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// record a download that is never committed
+	sc := cs.mustAcquire(t, c.ID)
+	rev := initialHeader.LastRevision()
+	rev.NewRevisionNumber = 2
+	rev.NewValidProofOutputs = []types.SiacoinOutput{{Value: types.NewCurrency64(90)}, {}}
+	if _, err := sc.recordDownloadIntent(rev, types.NewCurrency64(10), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// discard the transaction and adopt a later revision instead
+	if err := sc.discardTxns(); err != nil {
+		t.Fatal(err)
+	} else if len(sc.unappliedTxns) != 0 {
+		t.Fatal("expected 0 unappliedTxns, got", len(sc.unappliedTxns))
+	}
+	rev.NewRevisionNumber = 3
+	rev.NewValidProofOutputs = []types.SiacoinOutput{{Value: types.NewCurrency64(80)}, {}}
+	sigs := []types.TransactionSignature{{}, {}}
+	if err := sc.recoverRevision(rev, sigs, types.NewCurrency64(20), types.ZeroCurrency); err != nil {
+		t.Fatal(err)
+	}
+	cs.Return(sc)
+
+	// the recovered revision should persist across restarts
+	cs.Close()
+	cs, err = NewContractSet(dir, modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc = cs.mustAcquire(t, c.ID)
+	defer cs.Return(sc)
+	if len(sc.unappliedTxns) != 0 {
+		t.Fatal("expected 0 unappliedTxns, got", len(sc.unappliedTxns))
+	}
+	if sc.header.LastRevision().NewRevisionNumber != 3 {
+		t.Fatal("wrong revision number:", sc.header.LastRevision().NewRevisionNumber)
+	} else if len(sc.header.Transaction.TransactionSignatures) != 2 {
+		t.Fatal("revision should include both signatures")
+	} else if !sc.header.RenterFunds().Equals64(80) {
+		t.Fatal("wrong renter funds:", sc.header.RenterFunds())
+	} else if !sc.header.DownloadSpending.Equals64(20) {
+		t.Fatal("wrong download spending:", sc.header.DownloadSpending)
+	}
+}
+
+// recoveryTestHost is a minimal host that answers the recent revision exchange
+// of RPCDownload and RPCSectorRoots with a fixed revision, which it signs on
+// behalf of both parties.
+type recoveryTestHost struct {
+	listener net.Listener
+	renterSK crypto.SecretKey
+	sk       crypto.SecretKey
+	pk       crypto.PublicKey
+
+	mu    sync.Mutex
+	rev   types.FileContractRevision
+	roots []crypto.Hash
+}
+
+// newRecoveryTestHost creates a recoveryTestHost listening on localhost.
+func newRecoveryTestHost(t *testing.T) *recoveryTestHost {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &recoveryTestHost{listener: l}
+	h.renterSK, _ = crypto.GenerateKeyPair()
+	h.sk, h.pk = crypto.GenerateKeyPair()
+	go h.threadedServe()
+	return h
+}
+
+// entry returns the host's hostdb entry.
+func (h *recoveryTestHost) entry() modules.HostDBEntry {
+	var e modules.HostDBEntry
+	e.NetAddress = modules.NetAddress(h.listener.Addr().String())
+	e.PublicKey = types.Ed25519PublicKey(h.pk)
+	e.Version = build.Version
+	return e
+}
+
+// revision returns the initial revision of a contract with the host that
+// covers roots.
+func (h *recoveryTestHost) revision(id types.FileContractID, roots []crypto.Hash) types.FileContractRevision {
+	outputs := []types.SiacoinOutput{{Value: types.NewCurrency64(100)}, {Value: types.NewCurrency64(100)}}
+	return types.FileContractRevision{
+		ParentID: id,
+		UnlockConditions: types.UnlockConditions{
+			PublicKeys: []types.SiaPublicKey{
+				types.Ed25519PublicKey(h.renterSK.PublicKey()),
+				types.Ed25519PublicKey(h.pk),
+			},
+			SignaturesRequired: 2,
+		},
+		NewRevisionNumber:     1,
+		NewFileSize:           uint64(len(roots)) * modules.SectorSize,
+		NewFileMerkleRoot:     cachedMerkleRoot(roots),
+		NewWindowStart:        100,
+		NewWindowEnd:          200,
+		NewValidProofOutputs:  outputs,
+		NewMissedProofOutputs: outputs,
+	}
+}
+
+// signRevision signs rev with the keys of both parties.
+func (h *recoveryTestHost) signRevision(rev types.FileContractRevision) []types.TransactionSignature {
+	txn := types.Transaction{
+		FileContractRevisions: []types.FileContractRevision{rev},
+	}
+	for i, sk := range []crypto.SecretKey{h.renterSK, h.sk} {
+		txn.TransactionSignatures = append(txn.TransactionSignatures, types.TransactionSignature{
+			ParentID:       crypto.Hash(rev.ParentID),
+			CoveredFields:  types.CoveredFields{FileContractRevisions: []uint64{0}},
+			PublicKeyIndex: uint64(i),
+		})
+		sig := crypto.SignHash(txn.SigHash(i), sk)
+		txn.TransactionSignatures[i].Signature = sig[:]
+	}
+	return txn.TransactionSignatures
+}
+
+// setRevision sets the revision and the sector roots that the host reports.
+func (h *recoveryTestHost) setRevision(rev types.FileContractRevision, roots []crypto.Hash) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rev = rev
+	h.roots = roots
+}
+
+// insertContract inserts a contract with the host that covers roots into cs.
+func (h *recoveryTestHost) insertContract(t *testing.T, cs *ContractSet, id types.FileContractID, roots []crypto.Hash) types.FileContractRevision {
+	t.Helper()
+	rev := h.revision(id, roots)
+	header := contractHeader{
+		Transaction: types.Transaction{
+			FileContractRevisions: []types.FileContractRevision{rev},
+			TransactionSignatures: h.signRevision(rev),
+		},
+		SecretKey: h.renterSK,
+	}
+	if _, err := cs.managedInsertContract(header, roots); err != nil {
+		t.Fatal(err)
+	}
+	h.setRevision(rev, roots)
+	return rev
+}
+
+// threadedServe handles incoming connections until the listener is closed.
+func (h *recoveryTestHost) threadedServe() {
+	for {
+		conn, err := h.listener.Accept()
+		if err != nil {
+			return
+		}
+		go h.handleConn(conn)
+	}
+}
+
+// handleConn answers the recent revision exchange. For RPCSectorRoots, the
+// roots are sent afterwards; otherwise the revision loop is ended.
+func (h *recoveryTestHost) handleConn(conn net.Conn) {
+	defer conn.Close()
+	var rpc types.Specifier
+	if err := encoding.ReadObject(conn, &rpc, 16); err != nil {
+		return
+	}
+	var id types.FileContractID
+	if err := encoding.ReadObject(conn, &id, 32); err != nil {
+		return
+	}
+	if err := encoding.WriteObject(conn, crypto.Hash{}); err != nil {
+		return
+	}
+	var sig crypto.Signature
+	if err := encoding.ReadObject(conn, &sig, 64); err != nil {
+		return
+	}
+	if err := modules.WriteNegotiationAcceptance(conn); err != nil {
+		return
+	}
+	h.mu.Lock()
+	rev, roots := h.rev, h.roots
+	h.mu.Unlock()
+	if err := encoding.WriteObject(conn, rev); err != nil {
+		return
+	}
+	if err := encoding.WriteObject(conn, h.signRevision(rev)); err != nil {
+		return
+	}
+	if rpc == modules.RPCSectorRoots {
+		encoding.WriteObject(conn, roots)
+		return
+	}
+	// The renter reads the settings before ending the revision loop.
+	settings := modules.HostExternalSettings{NetAddress: modules.NetAddress(h.listener.Addr().String())}
+	crypto.WriteSignedObject(conn, settings, h.sk)
+	modules.ReadNegotiationAcceptance(conn)
+}
+
+// TestContractSetRecoverContract tests that RecoverContract only adopts the
+// host's revision if it is newer than ours, and that the sector roots are
+// downloaded from the host if they don't match ours or the roots recorded in
+// the WAL.
+func TestContractSetRecoverContract(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	dir := build.TempDir(filepath.Join("proto", t.Name()))
+	cs, err := NewContractSet(dir, modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	h := newRecoveryTestHost(t)
+	defer h.listener.Close()
+	roots := []crypto.Hash{{1}}
+
+	// checkRevision checks the revision number of the contract and that the
+	// unapplied transactions were resolved.
+	checkRevision := func(id types.FileContractID, revNum uint64) *SafeContract {
+		t.Helper()
+		sc := cs.mustAcquire(t, id)
+		defer cs.Return(sc)
+		if n := sc.header.LastRevision().NewRevisionNumber; n != revNum {
+			t.Fatalf("expected revision %v, got %v", revNum, n)
+		}
+		return sc
+	}
+
+	// If the host is in sync, the unapplied transactions are discarded.
+	id := types.FileContractID{1}
+	rev := h.insertContract(t, cs, id, roots)
+	sc := cs.mustAcquire(t, id)
+	stale := rev
+	stale.NewRevisionNumber++
+	if _, err := sc.recordDownloadIntent(stale, types.NewCurrency64(10), 0); err != nil {
+		t.Fatal(err)
+	}
+	cs.Return(sc)
+	if _, err := cs.RecoverContract(h.entry(), id, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sc := checkRevision(id, 1); len(sc.unappliedTxns) != 0 {
+		t.Fatal("stale transactions weren't discarded")
+	}
+
+	// An outdated revision of the host is rejected.
+	id = types.FileContractID{2}
+	rev = h.insertContract(t, cs, id, roots)
+	outdated := rev
+	outdated.NewRevisionNumber--
+	h.setRevision(outdated, roots)
+	if _, err := cs.RecoverContract(h.entry(), id, nil); err != errHostRevisionOutdated {
+		t.Fatal("expected errHostRevisionOutdated, got", err)
+	}
+	checkRevision(id, 1)
+
+	// An upload that the host accepted is committed from the WAL.
+	id = types.FileContractID{3}
+	rev = h.insertContract(t, cs, id, roots)
+	newRoots := []crypto.Hash{{1}, {2}}
+	uploaded := rev
+	uploaded.NewRevisionNumber++
+	uploaded.NewFileSize += modules.SectorSize
+	uploaded.NewFileMerkleRoot = cachedMerkleRoot(newRoots)
+	sc = cs.mustAcquire(t, id)
+	if _, err := sc.recordUploadIntent(uploaded, newRoots[1], types.ZeroCurrency, types.ZeroCurrency, modules.SectorSize); err != nil {
+		t.Fatal(err)
+	}
+	cs.Return(sc)
+	h.setRevision(uploaded, newRoots)
+	if _, err := cs.RecoverContract(h.entry(), id, nil); err != nil {
+		t.Fatal(err)
+	}
+	sc = checkRevision(id, 2)
+	if len(sc.unappliedTxns) != 0 {
+		t.Fatal("WAL transaction wasn't committed")
+	} else if len(sc.header.Transaction.TransactionSignatures) != 2 {
+		t.Fatal("recovered revision should include both signatures")
+	}
+	if stored, err := sc.merkleRoots.merkleRoots(); err != nil || !reflect.DeepEqual(stored, newRoots) {
+		t.Fatal("uploaded root wasn't recovered:", stored, err)
+	}
+
+	// If the host's revision covers different roots than the WAL, the roots
+	// are downloaded from the host.
+	id = types.FileContractID{4}
+	rev = h.insertContract(t, cs, id, roots)
+	sc = cs.mustAcquire(t, id)
+	uploaded = rev
+	uploaded.NewRevisionNumber++
+	uploaded.NewFileSize += modules.SectorSize
+	uploaded.NewFileMerkleRoot = cachedMerkleRoot(newRoots)
+	if _, err := sc.recordUploadIntent(uploaded, newRoots[1], types.ZeroCurrency, types.ZeroCurrency, modules.SectorSize); err != nil {
+		t.Fatal(err)
+	}
+	cs.Return(sc)
+	otherRoots := []crypto.Hash{{1}, {3}}
+	diverged := uploaded
+	diverged.NewFileMerkleRoot = cachedMerkleRoot(otherRoots)
+	h.setRevision(diverged, otherRoots)
+	if _, err := cs.RecoverContract(h.entry(), id, nil); err != nil {
+		t.Fatal(err)
+	}
+	sc = checkRevision(id, 2)
+	if len(sc.unappliedTxns) != 0 {
+		t.Fatal("WAL transaction wasn't discarded")
+	}
+	if stored, err := sc.merkleRoots.merkleRoots(); err != nil || !reflect.DeepEqual(stored, otherRoots) {
+		t.Fatal("host's roots weren't downloaded:", stored, err)
+	}
+
+	// Without a WAL transaction, the roots of a revision that changed them
+	// are downloaded as well. Hosts that don't support RPCSectorRoots and
+	// roots that don't match the revision are rejected.
+	id = types.FileContractID{5}
+	rev = h.insertContract(t, cs, id, roots)
+	diverged = rev
+	diverged.NewRevisionNumber++
+	diverged.NewFileSize += modules.SectorSize
+	diverged.NewFileMerkleRoot = cachedMerkleRoot(otherRoots)
+	diverged.NewValidProofOutputs = []types.SiacoinOutput{{Value: types.NewCurrency64(95)}, {Value: types.NewCurrency64(105)}}
+	h.setRevision(diverged, otherRoots)
+	oldHost := h.entry()
+	oldHost.Version = "1.3.2"
+	if _, err := cs.RecoverContract(oldHost, id, nil); err != errSectorRootsUnsupported {
+		t.Fatal("expected errSectorRootsUnsupported, got", err)
+	}
+	checkRevision(id, 1)
+	h.setRevision(diverged, newRoots)
+	if _, err := cs.RecoverContract(h.entry(), id, nil); err != ErrSectorRootsInvalid {
+		t.Fatal("expected ErrSectorRootsInvalid, got", err)
+	}
+	checkRevision(id, 1)
+	h.setRevision(diverged, otherRoots)
+	if _, err := cs.RecoverContract(h.entry(), id, nil); err != nil {
+		t.Fatal(err)
+	}
+	sc = checkRevision(id, 2)
+	if stored, err := sc.merkleRoots.merkleRoots(); err != nil || !reflect.DeepEqual(stored, otherRoots) {
+		t.Fatal("host's roots weren't downloaded:", stored, err)
+	} else if !sc.header.UploadSpending.Equals64(5) || !sc.header.DownloadSpending.IsZero() {
+		t.Fatal("wrong spending:", sc.header.UploadSpending, sc.header.DownloadSpending)
+	}
+
+	// A revision that only paid for a download is adopted, unless it returns
+	// money to the renter.
+	id = types.FileContractID{6}
+	rev = h.insertContract(t, cs, id, roots)
+	refund := rev
+	refund.NewRevisionNumber++
+	refund.NewValidProofOutputs = []types.SiacoinOutput{{Value: types.NewCurrency64(110)}, {Value: types.NewCurrency64(90)}}
+	h.setRevision(refund, roots)
+	if _, err := cs.RecoverContract(h.entry(), id, nil); err == nil {
+		t.Fatal("revision increasing the renter's funds was adopted")
+	}
+	checkRevision(id, 1)
+	download := rev
+	download.NewRevisionNumber++
+	download.NewValidProofOutputs = []types.SiacoinOutput{{Value: types.NewCurrency64(90)}, {Value: types.NewCurrency64(110)}}
+	h.setRevision(download, roots)
+	if _, err := cs.RecoverContract(h.entry(), id, nil); err != nil {
+		t.Fatal(err)
+	}
+	sc = checkRevision(id, 2)
+	if !sc.header.RenterFunds().Equals64(90) {
+		t.Fatal("wrong renter funds:", sc.header.RenterFunds())
+	} else if !sc.header.DownloadSpending.Equals64(10) {
+		t.Fatal("wrong download spending:", sc.header.DownloadSpending)
+	}
+}
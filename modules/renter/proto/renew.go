@@ -23,6 +23,19 @@ func (cs *ContractSet) Renew(oldContract *SafeContract, params ContractParams, t
 	ourSK := contract.SecretKey
 	lastRev := contract.LastRevision()
 
+	// If a new key was supplied, the renewed contract is signed with it.
+	uc := lastRev.UnlockConditions
+	if params.SecretKey != (crypto.SecretKey{}) {
+		ourSK = params.SecretKey
+		uc = types.UnlockConditions{
+			PublicKeys: []types.SiaPublicKey{
+				types.Ed25519PublicKey(ourSK.PublicKey()),
+				host.PublicKey,
+			},
+			SignaturesRequired: 2,
+		}
+	}
+
 	// Calculate additional basePrice and baseCollateral. If the contract height
 	// did not increase, basePrice and baseCollateral are zero.
 	var basePrice, baseCollateral types.Currency
@@ -71,7 +84,7 @@ func (cs *ContractSet) Renew(oldContract *SafeContract, params ContractParams, t
 		WindowStart:    endHeight,
 		WindowEnd:      endHeight + host.WindowSize,
 		Payout:         totalPayout,
-		UnlockHash:     lastRev.NewUnlockHash,
+		UnlockHash:     uc.UnlockHash(),
 		RevisionNumber: 0,
 		ValidProofOutputs: []types.SiacoinOutput{
 			// renter
@@ -206,7 +219,7 @@ func (cs *ContractSet) Renew(oldContract *SafeContract, params ContractParams, t
 	// create initial (no-op) revision, transaction, and signature
 	initRevision := types.FileContractRevision{
 		ParentID:          signedTxnSet[len(signedTxnSet)-1].FileContractID(0),
-		UnlockConditions:  lastRev.UnlockConditions,
+		UnlockConditions:  uc,
 		NewRevisionNumber: 1,
 
 		NewFileSize:           fc.FileSize,
@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"gitlab.com/NebulousLabs/Sia/build"
 	"gitlab.com/NebulousLabs/Sia/modules"
@@ -107,15 +108,34 @@ type hostContractor interface {
 	// Allowance returns the current allowance
 	Allowance() modules.Allowance
 
+	// CancelContract marks the contract with the given id as !GoodForUpload
+	// and !GoodForRenew.
+	CancelContract(types.FileContractID) error
+
 	// Close closes the hostContractor.
 	Close() error
 
+	// AllContracts returns the staticContracts of the renter's
+	// hostContractor along with the online status of their hosts.
+	AllContracts() []modules.RenterContractStatus
+
 	// Contracts returns the staticContracts of the renter's hostContractor.
 	Contracts() []modules.RenterContract
 
+	// ContractRedundancy reports whether enough contracts are good for
+	// upload.
+	ContractRedundancy() modules.ContractRedundancy
+
 	// OldContracts returns the oldContracts of the renter's hostContractor.
 	OldContracts() []modules.RenterContract
 
+	// OldContractReasons returns why each of the oldContracts was retired.
+	OldContractReasons() map[types.FileContractID]string
+
+	// ContractHealth returns a score between 0 and 1 that summarizes the
+	// health of a contract.
+	ContractHealth(types.FileContractID) (float64, bool)
+
 	// ContractByPublicKey returns the contract associated with the host key.
 	ContractByPublicKey(types.SiaPublicKey) (modules.RenterContract, bool)
 
@@ -127,6 +147,10 @@ type hostContractor interface {
 	// began.
 	CurrentPeriod() types.BlockHeight
 
+	// CurrentPeriodEnd returns the height at which the current allowance
+	// period ends.
+	CurrentPeriodEnd() types.BlockHeight
+
 	// PeriodSpending returns the amount spent on contracts during the current
 	// billing period.
 	PeriodSpending() modules.ContractorSpending
@@ -135,6 +159,21 @@ type hostContractor interface {
 	// insertion, deletion, and modification of sectors.
 	Editor(types.SiaPublicKey, <-chan struct{}) (contractor.Editor, error)
 
+	// AutoTopUp returns the settings of the automatic allowance top-up.
+	AutoTopUp() modules.AutoTopUp
+
+	// FormationCandidates returns the hosts that were considered in the most
+	// recent contract formation pass.
+	FormationCandidates() []modules.HostCandidate
+
+	// FormationWorkers returns the maximum number of hosts that the
+	// contractor negotiates new contracts with at the same time.
+	FormationWorkers() uint64
+
+	// HostBlacklist returns the hosts that the contractor will not form or
+	// renew contracts with.
+	HostBlacklist() []types.SiaPublicKey
+
 	// IsOffline reports whether the specified host is considered offline.
 	IsOffline(types.SiaPublicKey) bool
 
@@ -142,6 +181,26 @@ type hostContractor interface {
 	// allowing the retrieval of sectors.
 	Downloader(types.SiaPublicKey, <-chan struct{}) (contractor.Downloader, error)
 
+	// MinContracts returns the number of contracts that need to be good for
+	// upload for the contracts to be sufficiently redundant.
+	MinContracts() uint64
+
+	// PeriodAlignment returns the settings that align the allowance periods
+	// to a fixed epoch.
+	PeriodAlignment() modules.PeriodAlignment
+
+	// PersistInterval returns the interval at which the contractor flushes
+	// changes to disk.
+	PersistInterval() time.Duration
+
+	// PriceCeilings returns the maximum prices of hosts that the contractor
+	// forms and renews contracts with.
+	PriceCeilings() modules.PriceCeilings
+
+	// RecoverContracts scans the blockchain for contracts that were signed
+	// with keys derived from the seed and adds them to the contractor.
+	RecoverContracts(modules.Seed) error
+
 	// ResolveIDToPubKey returns the public key of a host given a contract id.
 	ResolveIDToPubKey(types.FileContractID) types.SiaPublicKey
 
@@ -149,9 +208,44 @@ type hostContractor interface {
 	// contractor and its submodules.
 	RateLimits() (readBPS int64, writeBPS int64, packetSize uint64)
 
+	// SetAutoTopUp sets the settings of the automatic allowance top-up.
+	SetAutoTopUp(modules.AutoTopUp) error
+
+	// SetFormationWorkers sets the maximum number of hosts that the
+	// contractor negotiates new contracts with at the same time.
+	SetFormationWorkers(uint64) error
+
+	// SetHostBlacklist sets the hosts that the contractor will not form or
+	// renew contracts with.
+	SetHostBlacklist([]types.SiaPublicKey) error
+
+	// SetMinContracts sets the number of contracts that need to be good for
+	// upload for the contracts to be sufficiently redundant.
+	SetMinContracts(uint64) error
+
+	// SetPeriodAlignment sets the settings that align the allowance periods
+	// to a fixed epoch.
+	SetPeriodAlignment(modules.PeriodAlignment) error
+
+	// SetPersistInterval sets the interval at which the contractor flushes
+	// changes to disk.
+	SetPersistInterval(time.Duration) error
+
+	// SetPriceCeilings sets the maximum prices of hosts that the contractor
+	// forms and renews contracts with.
+	SetPriceCeilings(modules.PriceCeilings) error
+
 	// SetRateLimits sets the bandwidth limits for connections created by the
 	// contractor and its submodules.
 	SetRateLimits(int64, int64, uint64)
+
+	// SetSpendingAlertThreshold sets the fraction of the allowance that can
+	// be spent in a period before the contractor warns about it.
+	SetSpendingAlertThreshold(float64) error
+
+	// SpendingAlert returns the spending alert threshold and the fraction of
+	// the allowance that has been spent in the current period.
+	SpendingAlert() modules.SpendingAlert
 }
 
 // A trackedFile contains metadata about files being tracked by the Renter.
@@ -388,6 +482,12 @@ func (r *Renter) EstimateHostScore(e modules.HostDBEntry) modules.HostScoreBreak
 	return r.hostDB.EstimateHostScore(e)
 }
 
+// AllContracts returns an array of host contractor's staticContracts along
+// with the online status of their hosts
+func (r *Renter) AllContracts() []modules.RenterContractStatus {
+	return r.hostContractor.AllContracts()
+}
+
 // Contracts returns an array of host contractor's staticContracts
 func (r *Renter) Contracts() []modules.RenterContract { return r.hostContractor.Contracts() }
 
@@ -396,15 +496,135 @@ func (r *Renter) OldContracts() []modules.RenterContract {
 	return r.hostContractor.OldContracts()
 }
 
+// OldContractReasons returns the reasons why the host contractor's
+// oldContracts were retired, keyed by contract id.
+func (r *Renter) OldContractReasons() map[types.FileContractID]string {
+	return r.hostContractor.OldContractReasons()
+}
+
+// ContractHealth returns a score between 0 and 1 that summarizes the health of
+// the contract with the given id.
+func (r *Renter) ContractHealth(id types.FileContractID) (float64, bool) {
+	return r.hostContractor.ContractHealth(id)
+}
+
+// CancelContract cancels a renter contract by marking it !GoodForUpload and
+// !GoodForRenew
+func (r *Renter) CancelContract(id types.FileContractID) error {
+	return r.hostContractor.CancelContract(id)
+}
+
+// RecoverContracts recovers the contracts of the host contractor that were
+// signed with keys derived from seed.
+func (r *Renter) RecoverContracts(seed modules.Seed) error {
+	return r.hostContractor.RecoverContracts(seed)
+}
+
 // CurrentPeriod returns the host contractor's current period
 func (r *Renter) CurrentPeriod() types.BlockHeight { return r.hostContractor.CurrentPeriod() }
 
+// CurrentPeriodEnd returns the height at which the host contractor's current
+// period ends
+func (r *Renter) CurrentPeriodEnd() types.BlockHeight { return r.hostContractor.CurrentPeriodEnd() }
+
 // ContractUtility returns the utility field for a given contract, along
 // with a bool indicating if it exists.
 func (r *Renter) ContractUtility(pk types.SiaPublicKey) (modules.ContractUtility, bool) {
 	return r.hostContractor.ContractUtility(pk)
 }
 
+// AutoTopUp returns the settings of the host contractor's automatic allowance
+// top-up.
+func (r *Renter) AutoTopUp() modules.AutoTopUp { return r.hostContractor.AutoTopUp() }
+
+// SetAutoTopUp sets the settings of the host contractor's automatic allowance
+// top-up.
+func (r *Renter) SetAutoTopUp(atu modules.AutoTopUp) error {
+	return r.hostContractor.SetAutoTopUp(atu)
+}
+
+// SpendingAlert returns the host contractor's spending alert threshold and the
+// fraction of the allowance that has been spent in the current period.
+func (r *Renter) SpendingAlert() modules.SpendingAlert { return r.hostContractor.SpendingAlert() }
+
+// SetSpendingAlertThreshold sets the fraction of the allowance that can be
+// spent in a period before the host contractor warns about it.
+func (r *Renter) SetSpendingAlertThreshold(fraction float64) error {
+	return r.hostContractor.SetSpendingAlertThreshold(fraction)
+}
+
+// FormationCandidates returns the hosts that the host contractor considered in
+// its most recent contract formation pass.
+func (r *Renter) FormationCandidates() []modules.HostCandidate {
+	return r.hostContractor.FormationCandidates()
+}
+
+// FormationWorkers returns the maximum number of hosts that the host
+// contractor negotiates new contracts with at the same time.
+func (r *Renter) FormationWorkers() uint64 { return r.hostContractor.FormationWorkers() }
+
+// SetFormationWorkers sets the maximum number of hosts that the host
+// contractor negotiates new contracts with at the same time.
+func (r *Renter) SetFormationWorkers(n uint64) error {
+	return r.hostContractor.SetFormationWorkers(n)
+}
+
+// HostBlacklist returns the hosts that the host contractor will not form or
+// renew contracts with
+func (r *Renter) HostBlacklist() []types.SiaPublicKey { return r.hostContractor.HostBlacklist() }
+
+// SetHostBlacklist sets the hosts that the host contractor will not form or
+// renew contracts with
+func (r *Renter) SetHostBlacklist(hosts []types.SiaPublicKey) error {
+	return r.hostContractor.SetHostBlacklist(hosts)
+}
+
+// PeriodAlignment returns the settings that align the host contractor's
+// allowance periods to a fixed epoch.
+func (r *Renter) PeriodAlignment() modules.PeriodAlignment {
+	return r.hostContractor.PeriodAlignment()
+}
+
+// SetPeriodAlignment sets the settings that align the host contractor's
+// allowance periods to a fixed epoch.
+func (r *Renter) SetPeriodAlignment(pa modules.PeriodAlignment) error {
+	return r.hostContractor.SetPeriodAlignment(pa)
+}
+
+// PersistInterval returns the interval at which the host contractor flushes
+// changes to disk.
+func (r *Renter) PersistInterval() time.Duration { return r.hostContractor.PersistInterval() }
+
+// SetPersistInterval sets the interval at which the host contractor flushes
+// changes to disk.
+func (r *Renter) SetPersistInterval(interval time.Duration) error {
+	return r.hostContractor.SetPersistInterval(interval)
+}
+
+// PriceCeilings returns the maximum prices of hosts that the host contractor
+// forms and renews contracts with.
+func (r *Renter) PriceCeilings() modules.PriceCeilings { return r.hostContractor.PriceCeilings() }
+
+// SetPriceCeilings sets the maximum prices of hosts that the host contractor
+// forms and renews contracts with.
+func (r *Renter) SetPriceCeilings(pc modules.PriceCeilings) error {
+	return r.hostContractor.SetPriceCeilings(pc)
+}
+
+// ContractRedundancy reports whether enough of the host contractor's contracts
+// are good for upload.
+func (r *Renter) ContractRedundancy() modules.ContractRedundancy {
+	return r.hostContractor.ContractRedundancy()
+}
+
+// MinContracts returns the number of contracts that need to be good for upload
+// for the host contractor's contracts to be sufficiently redundant.
+func (r *Renter) MinContracts() uint64 { return r.hostContractor.MinContracts() }
+
+// SetMinContracts sets the number of contracts that need to be good for upload
+// for the host contractor's contracts to be sufficiently redundant.
+func (r *Renter) SetMinContracts(n uint64) error { return r.hostContractor.SetMinContracts(n) }
+
 // PeriodSpending returns the host contractor's period spending
 func (r *Renter) PeriodSpending() modules.ContractorSpending { return r.hostContractor.PeriodSpending() }
 
@@ -9,6 +9,7 @@ import (
 	"gitlab.com/NebulousLabs/Sia/build"
 	"gitlab.com/NebulousLabs/Sia/crypto"
 	"gitlab.com/NebulousLabs/Sia/persist"
+	"gitlab.com/NebulousLabs/Sia/types"
 	"gitlab.com/NebulousLabs/fastrand"
 )
 
@@ -225,3 +226,33 @@ func BenchmarkSliceCryptoHashSave(b *testing.B) {
 		}
 	}
 }
+
+// TestRenterContractRemainingFunds probes the RemainingFunds method of the
+// RenterContract type.
+func TestRenterContractRemainingFunds(t *testing.T) {
+	rc := RenterContract{
+		TotalCost:        types.NewCurrency64(100),
+		ContractFee:      types.NewCurrency64(5),
+		TxnFee:           types.NewCurrency64(3),
+		SiafundFee:       types.NewCurrency64(2),
+		StorageSpending:  types.NewCurrency64(20),
+		UploadSpending:   types.NewCurrency64(10),
+		DownloadSpending: types.NewCurrency64(10),
+		RenterFunds:      types.NewCurrency64(50),
+	}
+	if !rc.RemainingFunds().Equals64(50) {
+		t.Fatal("wrong remaining funds:", rc.RemainingFunds())
+	}
+
+	// The remaining funds should never exceed the renter's payout.
+	rc.RenterFunds = types.NewCurrency64(40)
+	if !rc.RemainingFunds().Equals64(40) {
+		t.Fatal("remaining funds should be capped at the renter funds:", rc.RemainingFunds())
+	}
+
+	// Spending more than the total cost should not underflow.
+	rc.DownloadSpending = types.NewCurrency64(100)
+	if !rc.RemainingFunds().IsZero() {
+		t.Fatal("expected no remaining funds, got", rc.RemainingFunds())
+	}
+}
@@ -71,6 +71,25 @@ type (
 		RevertedTransactions []TransactionSetID
 	}
 
+	// TransactionPoolStats describes the congestion of the transaction pool.
+	TransactionPoolStats struct {
+		TransactionCount int    `json:"transactioncount"`
+		Size             uint64 `json:"size"`
+
+		// FeeHistogram groups the transaction sets in the pool by their fee
+		// per byte, in ascending order. Empty buckets are omitted.
+		FeeHistogram []TransactionPoolFeeBucket `json:"feehistogram"`
+	}
+
+	// A TransactionPoolFeeBucket contains the transactions of the pool whose
+	// set pays a fee per byte of at least MinFee and less than MaxFee.
+	TransactionPoolFeeBucket struct {
+		MinFee           types.Currency `json:"minfee"`
+		MaxFee           types.Currency `json:"maxfee"`
+		TransactionCount int            `json:"transactioncount"`
+		Size             uint64         `json:"size"`
+	}
+
 	// UnconfirmedTransactionSet defines a new unconfirmed transaction that has
 	// been added to the transaction pool. ID is the ID of the set, IDs contains
 	// an ID for each transaction, eliminating the need to recompute it (because
@@ -124,6 +143,20 @@ type (
 		// that make this condition necessary.
 		PurgeTransactionPool()
 
+		// ConflictingTransactions returns the IDs of the transactions that
+		// were rejected because they spend the given siacoin output, which is
+		// already spent by a transaction in the pool.
+		ConflictingTransactions(id types.SiacoinOutputID) []types.TransactionID
+
+		// ReplaceTransactionSet replaces the transaction set containing the
+		// transaction with the given id with a new set, which may spend the
+		// same outputs. The original set is kept if the new set is rejected.
+		ReplaceTransactionSet(id types.TransactionID, ts []types.Transaction) error
+
+		// Stats returns the number and total size of the transactions in
+		// the pool, along with a histogram of their fees per byte.
+		Stats() TransactionPoolStats
+
 		// Transaction returns the transaction and unconfirmed parents
 		// corresponding to the provided transaction id.
 		Transaction(id types.TransactionID) (txn types.Transaction, unconfirmedParents []types.Transaction, exists bool)
@@ -21,6 +21,7 @@ var (
 	errFullTransactionPool = errors.New("transaction pool cannot accept more transactions")
 	errLowMinerFees        = errors.New("transaction set needs more miner fees to be accepted")
 	errObjectConflict      = errors.New("transaction set conflicts with an existing transaction set")
+	errTransactionNotFound = errors.New("transaction is not in the transaction pool")
 )
 
 // relatedObjectIDs determines all of the object ids related to a transaction.
@@ -284,7 +285,12 @@ func (tp *TransactionPool) acceptTransactionSet(ts []types.Transaction, txnFn fu
 		}
 	}
 	if len(conflicts) > 0 {
-		return tp.handleConflicts(ts, conflicts, txnFn)
+		// Remember the transactions that are rejected as double spends.
+		err := tp.handleConflicts(ts, conflicts, txnFn)
+		if _, ok := err.(modules.ConsensusConflict); ok {
+			tp.recordConflicts(ts, txnFn)
+		}
+		return err
 	}
 	cc, err := txnFn(ts)
 	if err != nil {
@@ -348,6 +354,71 @@ func (tp *TransactionPool) AcceptTransactionSet(ts []types.Transaction) error {
 	})
 }
 
+// ReplaceTransactionSet replaces the unconfirmed transaction set containing
+// the transaction with the given id with ts. ts may spend the same outputs as
+// the set it replaces. If ts is rejected, the original set is kept. Peers that
+// have already seen the original set will reject ts as a double spend, so the
+// replacement only propagates to peers that don't know the original yet.
+func (tp *TransactionPool) ReplaceTransactionSet(id types.TransactionID, ts []types.Transaction) error {
+	// assert on consensus set to get special method
+	cs, ok := tp.consensusSet.(interface {
+		LockedTryTransactionSet(fn func(func(txns []types.Transaction) (modules.ConsensusChange, error)) error) error
+	})
+	if !ok {
+		return errors.New("consensus set does not support LockedTryTransactionSet method")
+	}
+
+	return cs.LockedTryTransactionSet(func(txnFn func(txns []types.Transaction) (modules.ConsensusChange, error)) error {
+		tp.mu.Lock()
+		defer tp.mu.Unlock()
+
+		// Find the set containing the transaction.
+		var setID TransactionSetID
+		var oldSet []types.Transaction
+		for sid, set := range tp.transactionSets {
+			for _, txn := range set {
+				if txn.ID() == id {
+					setID, oldSet = sid, set
+				}
+			}
+		}
+		if oldSet == nil {
+			return errTransactionNotFound
+		}
+
+		// Remove the set from the pool, remembering its state so that it can
+		// be restored if the replacement is rejected.
+		oldDiff := tp.transactionSetDiffs[setID]
+		var oldObjects []ObjectID
+		for oid, sid := range tp.knownObjects {
+			if sid == setID {
+				oldObjects = append(oldObjects, oid)
+				delete(tp.knownObjects, oid)
+			}
+		}
+		delete(tp.transactionSets, setID)
+		delete(tp.transactionSetDiffs, setID)
+		oldSize := len(encoding.Marshal(oldSet))
+		tp.transactionListSize -= oldSize
+
+		err := tp.acceptTransactionSet(ts, txnFn)
+		if err != nil {
+			tp.transactionSets[setID] = oldSet
+			tp.transactionSetDiffs[setID] = oldDiff
+			for _, oid := range oldObjects {
+				tp.knownObjects[oid] = setID
+			}
+			tp.transactionListSize += oldSize
+			tp.log.Debugln("Replacement transaction set was rejected:", err)
+			return err
+		}
+		go tp.gateway.Broadcast("RelayTransactionSet", ts, tp.gateway.Peers())
+		// Notify subscribers of the removed and accepted transaction sets
+		tp.updateSubscribersTransactions()
+		return nil
+	})
+}
+
 // relayTransactionSet is an RPC that accepts a transaction set from a peer. If
 // the accept is successful, the transaction will be relayed to the gateway's
 // other peers.
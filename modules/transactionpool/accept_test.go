@@ -108,6 +108,49 @@ func TestConflictingTransactionSets(t *testing.T) {
 		t.Error("transaction should not have passed inspection")
 	}
 
+	// The rejected transaction should be reported as a conflict of the
+	// output it tried to spend, but the shared parent shouldn't.
+	doubleSpendID := txnSetDoubleSpend[txnIndex].ID()
+	conflicts := tpt.tpool.ConflictingTransactions(txnSet[txnIndex].SiacoinInputs[0].ParentID)
+	if len(conflicts) != 1 || conflicts[0] != doubleSpendID {
+		t.Fatal("expected the double spend to be reported as a conflict, got", conflicts)
+	}
+	for _, sci := range txnSet[0].SiacoinInputs {
+		if len(tpt.tpool.ConflictingTransactions(sci.ParentID)) != 0 {
+			t.Fatal("the shared parent transaction was reported as a conflict")
+		}
+	}
+
+	// doubleSpend returns a copy of txnSetDoubleSpend that sends the output
+	// to a different address.
+	doubleSpend := func(addr byte) []types.Transaction {
+		set := make([]types.Transaction, len(txnSetDoubleSpend))
+		copy(set, txnSetDoubleSpend)
+		set[txnIndex].SiacoinOutputs = []types.SiacoinOutput{{Value: fund, UnlockHash: types.UnlockHash{addr}}}
+		return set
+	}
+
+	// A double spend with an invalid signature isn't recorded.
+	invalidSet := doubleSpend(0)
+	invalidSet[txnIndex].TransactionSignatures = append([]types.TransactionSignature(nil), invalidSet[txnIndex].TransactionSignatures...)
+	invalidSet[txnIndex].TransactionSignatures[0].Signature = make([]byte, len(invalidSet[txnIndex].TransactionSignatures[0].Signature))
+	if err := tpt.tpool.AcceptTransactionSet(invalidSet); err == nil {
+		t.Fatal("transaction with an invalid signature should not have passed inspection")
+	}
+	if conflicts := tpt.tpool.ConflictingTransactions(txnSet[txnIndex].SiacoinInputs[0].ParentID); len(conflicts) != 1 {
+		t.Fatal("expected the invalid double spend to be ignored, got", conflicts)
+	}
+
+	// The number of recorded double spends per output is limited.
+	for i := 1; i <= maxConflictsPerOutput; i++ {
+		if err := tpt.tpool.AcceptTransactionSet(doubleSpend(byte(i))); err == nil {
+			t.Fatal("transaction should not have passed inspection")
+		}
+	}
+	if conflicts := tpt.tpool.ConflictingTransactions(txnSet[txnIndex].SiacoinInputs[0].ParentID); len(conflicts) != maxConflictsPerOutput {
+		t.Fatalf("expected %v conflicts, got %v", maxConflictsPerOutput, len(conflicts))
+	}
+
 	// Purge and try the sets in the reverse order.
 	tpt.tpool.PurgeTransactionPool()
 	err = tpt.tpool.AcceptTransactionSet(txnSetDoubleSpend)
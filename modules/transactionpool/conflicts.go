@@ -0,0 +1,94 @@
+package transactionpool
+
+import (
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+const (
+	// maxRecentConflicts is the maximum number of outputs for which the
+	// transaction pool remembers rejected double spends. The oldest outputs
+	// are forgotten first.
+	maxRecentConflicts = 1000
+
+	// maxConflictsPerOutput is the maximum number of rejected double spends
+	// that the transaction pool remembers for a single output. Later double
+	// spends of the output are not recorded.
+	maxConflictsPerOutput = 10
+)
+
+// recordConflicts remembers the transactions of ts that spend siacoin outputs
+// which are already spent by other transactions in the pool. It is called
+// after ts was rejected as a double spend. Since ts is relayed by peers, it is
+// only recorded if it is valid on its own, and the number of transactions
+// recorded per output is limited. The caller must hold the lock.
+func (tp *TransactionPool) recordConflicts(ts []types.Transaction, txnFn func([]types.Transaction) (modules.ConsensusChange, error)) {
+	if _, err := txnFn(ts); err != nil {
+		return
+	}
+	for _, txn := range ts {
+		txid := txn.ID()
+		for _, sci := range txn.SiacoinInputs {
+			oid := ObjectID(sci.ParentID)
+			setID, exists := tp.knownObjects[oid]
+			if !exists || setContains(tp.transactionSets[setID], txid) || !setSpends(tp.transactionSets[setID], sci.ParentID) {
+				continue
+			}
+			conflicts, exists := tp.recentConflicts[oid]
+			if len(conflicts) >= maxConflictsPerOutput || containsTransactionID(conflicts, txid) {
+				continue
+			}
+			if !exists {
+				tp.conflictOrder = append(tp.conflictOrder, oid)
+			}
+			tp.recentConflicts[oid] = append(conflicts, txid)
+		}
+	}
+	for len(tp.conflictOrder) > maxRecentConflicts {
+		delete(tp.recentConflicts, tp.conflictOrder[0])
+		tp.conflictOrder = tp.conflictOrder[1:]
+	}
+}
+
+// ConflictingTransactions returns the IDs of the transactions that tried to
+// spend the given siacoin output while it was already spent by a transaction
+// in the pool, and that were rejected as double spends.
+func (tp *TransactionPool) ConflictingTransactions(id types.SiacoinOutputID) []types.TransactionID {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return append([]types.TransactionID(nil), tp.recentConflicts[ObjectID(id)]...)
+}
+
+// setContains returns true if the transaction set contains the transaction
+// with the given id.
+func setContains(set []types.Transaction, id types.TransactionID) bool {
+	for _, txn := range set {
+		if txn.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// setSpends returns true if a transaction of the set spends the siacoin output
+// with the given id.
+func setSpends(set []types.Transaction, id types.SiacoinOutputID) bool {
+	for _, txn := range set {
+		for _, sci := range txn.SiacoinInputs {
+			if sci.ParentID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsTransactionID returns true if ids contains id.
+func containsTransactionID(ids []types.TransactionID, id types.TransactionID) bool {
+	for _, txid := range ids {
+		if txid == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package transactionpool
+
+import (
+	"math/big"
+	"sort"
+
+	"gitlab.com/NebulousLabs/Sia/encoding"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// feeBucket returns the bounds of the histogram bucket that fee falls into.
+// Every bucket above zero spans a power of two, so the histogram covers any
+// range of fees with few buckets. Sets without fees fall into [0, 1).
+func feeBucket(fee types.Currency) (min, max types.Currency) {
+	if fee.IsZero() {
+		return types.ZeroCurrency, types.NewCurrency64(1)
+	}
+	k := uint(fee.Big().BitLen() - 1)
+	min = types.NewCurrency(new(big.Int).Lsh(big.NewInt(1), k))
+	max = types.NewCurrency(new(big.Int).Lsh(big.NewInt(1), k+1))
+	return min, max
+}
+
+// Stats returns the number and total size of the transactions in the pool,
+// along with a histogram of the fees per byte that their sets pay.
+func (tp *TransactionPool) Stats() (stats modules.TransactionPoolStats) {
+	if err := tp.tg.Add(); err != nil {
+		return
+	}
+	defer tp.tg.Done()
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	buckets := make(map[string]*modules.TransactionPoolFeeBucket)
+	for _, tSet := range tp.transactionSets {
+		stats.TransactionCount += len(tSet)
+
+		var fees types.Currency
+		for _, txn := range tSet {
+			for _, fee := range txn.MinerFees {
+				fees = fees.Add(fee)
+			}
+		}
+		size := uint64(len(encoding.Marshal(tSet)))
+		stats.Size += size
+		min, max := feeBucket(fees.Div64(size))
+		b, exists := buckets[min.String()]
+		if !exists {
+			b = &modules.TransactionPoolFeeBucket{MinFee: min, MaxFee: max}
+			buckets[min.String()] = b
+		}
+		b.TransactionCount += len(tSet)
+		b.Size += size
+	}
+	for _, b := range buckets {
+		stats.FeeHistogram = append(stats.FeeHistogram, *b)
+	}
+	sort.Slice(stats.FeeHistogram, func(i, j int) bool {
+		return stats.FeeHistogram[i].MinFee.Cmp(stats.FeeHistogram[j].MinFee) < 0
+	})
+	return stats
+}
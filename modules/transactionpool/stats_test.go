@@ -0,0 +1,72 @@
+package transactionpool
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestFeeBucket tests the feeBucket function.
+func TestFeeBucket(t *testing.T) {
+	tests := []struct {
+		fee, min, max uint64
+	}{
+		{0, 0, 1},
+		{1, 1, 2},
+		{5, 4, 8},
+		{8, 8, 16},
+		{1023, 512, 1024},
+	}
+	for _, test := range tests {
+		min, max := feeBucket(types.NewCurrency64(test.fee))
+		if !min.Equals64(test.min) || !max.Equals64(test.max) {
+			t.Errorf("fee %v: expected bucket [%v, %v), got [%v, %v)", test.fee, test.min, test.max, min, max)
+		}
+	}
+}
+
+// TestStats checks that Stats reports the transactions in the pool.
+func TestStats(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	tpt, err := createTpoolTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tpt.Close()
+
+	// The pool is empty.
+	stats := tpt.tpool.Stats()
+	if stats.TransactionCount != 0 || stats.Size != 0 || len(stats.FeeHistogram) != 0 {
+		t.Fatal("expected empty stats, got", stats)
+	}
+
+	// Send coins and check that the transactions are reported.
+	txns, err := tpt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats = tpt.tpool.Stats()
+	if stats.TransactionCount != len(txns) {
+		t.Fatalf("expected %v transactions, got %v", len(txns), stats.TransactionCount)
+	}
+	if stats.Size == 0 {
+		t.Fatal("expected a non-zero size")
+	}
+	var count int
+	var size uint64
+	for _, b := range stats.FeeHistogram {
+		if b.MinFee.Cmp(b.MaxFee) >= 0 {
+			t.Fatal("invalid bucket bounds", b.MinFee, b.MaxFee)
+		}
+		count += b.TransactionCount
+		size += b.Size
+	}
+	if count != stats.TransactionCount {
+		t.Fatalf("histogram contains %v transactions, expected %v", count, stats.TransactionCount)
+	}
+	if size != stats.Size {
+		t.Fatalf("histogram contains %v bytes, expected %v", size, stats.Size)
+	}
+}
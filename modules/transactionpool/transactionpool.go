@@ -52,6 +52,13 @@ type (
 		transactionSetDiffs map[TransactionSetID]*modules.ConsensusChange
 		transactionListSize int
 
+		// recentConflicts maps siacoin outputs spent by the pool to the
+		// transactions that were rejected for spending them as well.
+		// conflictOrder holds the outputs in the order in which they were
+		// added, so that the oldest can be forgotten first.
+		recentConflicts map[ObjectID][]types.TransactionID
+		conflictOrder   []ObjectID
+
 		// Variables related to the blockchain.
 		blockHeight     types.BlockHeight
 		recentMedians   []types.Currency
@@ -93,6 +100,7 @@ func New(cs modules.ConsensusSet, g modules.Gateway, persistDir string) (*Transa
 		transactionHeights:  make(map[types.TransactionID]types.BlockHeight),
 		transactionSets:     make(map[TransactionSetID][]types.Transaction),
 		transactionSetDiffs: make(map[TransactionSetID]*modules.ConsensusChange),
+		recentConflicts:     make(map[ObjectID][]types.TransactionID),
 
 		persistDir: persistDir,
 	}
@@ -3,6 +3,8 @@ package modules
 import (
 	"bytes"
 	"errors"
+	"io"
+	"time"
 
 	"gitlab.com/NebulousLabs/entropy-mnemonics"
 
@@ -42,9 +44,32 @@ var (
 	// complete the desired action.
 	ErrLowBalance = errors.New("insufficient balance")
 
+	// ErrPublicKeyMismatch is returned by VerifyMessageSignature if the
+	// public key doesn't belong to the address.
+	ErrPublicKeyMismatch = errors.New("public key does not match address")
+
 	// ErrWalletShutdown is returned when a method can't continue execution due
 	// to the wallet shutting down.
 	ErrWalletShutdown = errors.New("wallet is shutting down")
+
+	// messageSpecifier is prefixed to hashes signed by Wallet.SignMessage.
+	messageSpecifier = types.Specifier{'s', 'i', 'g', 'n', 'e', 'd', ' ', 'm', 'e', 's', 's', 'a', 'g', 'e'}
+
+	// WalletUnlockStatusUnencrypted is the unlock status of a wallet that has
+	// never been encrypted.
+	WalletUnlockStatusUnencrypted = WalletUnlockStatus("unencrypted")
+
+	// WalletUnlockStatusLocked is the unlock status of an encrypted wallet
+	// that is waiting for its password.
+	WalletUnlockStatusLocked = WalletUnlockStatus("locked")
+
+	// WalletUnlockStatusRescanning is the unlock status of a wallet that is
+	// rescanning the blockchain, e.g. while it is being unlocked.
+	WalletUnlockStatusRescanning = WalletUnlockStatus("rescanning")
+
+	// WalletUnlockStatusUnlocked is the unlock status of a wallet that is
+	// unlocked and not rescanning.
+	WalletUnlockStatusUnlocked = WalletUnlockStatus("unlocked")
 )
 
 type (
@@ -55,6 +80,10 @@ type (
 	// WalletTransactionID is a unique identifier for a wallet transaction.
 	WalletTransactionID crypto.Hash
 
+	// WalletUnlockStatus reports the lock state of a wallet. Can be one of
+	// "unencrypted", "locked", "rescanning", or "unlocked".
+	WalletUnlockStatus string
+
 	// A ProcessedInput represents funding to a transaction. The input is
 	// coming from an address and going to the outputs. The fund types are
 	// 'SiacoinInput', 'SiafundInput'.
@@ -87,6 +116,42 @@ type (
 		Value          types.Currency    `json:"value"`
 	}
 
+	// A CoinSelection describes how the wallet would select the outputs
+	// that fund sending Amount plus Fee. If the selection fails, Error
+	// explains why, and Skipped and Immature show which funds couldn't be
+	// used. Change is only set if the selection succeeds; ChangeToFee is true
+	// if the change is below the change threshold and would be added to the
+	// miner fee instead of being refunded.
+	CoinSelection struct {
+		Amount      types.Currency        `json:"amount"`
+		Fee         types.Currency        `json:"fee"`
+		Target      types.Currency        `json:"target"`
+		Selected    []CoinSelectionOutput `json:"selected"`
+		Gathered    types.Currency        `json:"gathered"`
+		Change      types.Currency        `json:"change"`
+		ChangeToFee bool                  `json:"changetofee"`
+		Skipped     []CoinSelectionOutput `json:"skipped"`
+		Immature    types.Currency        `json:"immature"`
+		Error       string                `json:"error"`
+	}
+
+	// A CoinSelectionOutput is a siacoin output of the wallet that was
+	// considered by coin selection. Reason is set for skipped outputs and is
+	// one of "dust", "locked", "reserved", "timelocked" or "excluded".
+	CoinSelectionOutput struct {
+		ID     types.SiacoinOutputID `json:"id"`
+		Value  types.Currency        `json:"value"`
+		Reason string                `json:"reason,omitempty"`
+	}
+
+	// An IncomingOutput is a siacoin output that is created by a transaction
+	// in the transaction pool, and therefore awaits confirmation.
+	IncomingOutput struct {
+		ID            types.SiacoinOutputID `json:"id"`
+		TransactionID types.TransactionID   `json:"transactionid"`
+		Value         types.Currency        `json:"value"`
+	}
+
 	// A ProcessedTransaction is a transaction that has been processed into
 	// explicit inputs and outputs and tagged with some header data such as
 	// confirmation height + timestamp.
@@ -104,6 +169,39 @@ type (
 		Outputs []ProcessedOutput `json:"outputs"`
 	}
 
+	// A TransactionGraph is the subgraph of the wallet's transactions that
+	// is connected to a root transaction. Edges point from the transaction
+	// that created an output to the transaction that spends it.
+	TransactionGraph struct {
+		Root         types.TransactionID    `json:"root"`
+		Transactions []TransactionGraphNode `json:"transactions"`
+		Edges        []TransactionGraphEdge `json:"edges"`
+
+		// Truncated is true if the traversal stopped at the maximum depth
+		// even though the wallet knows further transactions.
+		Truncated bool `json:"truncated"`
+	}
+
+	// A TransactionGraphNode is a transaction in a TransactionGraph. Depth
+	// is the distance to the root transaction, it is negative for the
+	// transactions that funded the root and positive for the transactions
+	// that spend its outputs. Truncated is true if the transaction has
+	// parents or children that were not included because of the depth
+	// limit.
+	TransactionGraphNode struct {
+		Transaction ProcessedTransaction `json:"transaction"`
+		Depth       int                  `json:"depth"`
+		Truncated   bool                 `json:"truncated"`
+	}
+
+	// A TransactionGraphEdge connects the transaction that created an output
+	// to the transaction that spends it.
+	TransactionGraphEdge struct {
+		OutputID types.OutputID      `json:"outputid"`
+		Parent   types.TransactionID `json:"parent"`
+		Child    types.TransactionID `json:"child"`
+	}
+
 	// TransactionBuilder is used to construct custom transactions. A transaction
 	// builder is initialized via 'RegisterTransaction' and then can be modified by
 	// adding funds or other fields. The transaction is completed by calling
@@ -282,12 +380,22 @@ type (
 		// byte-order.
 		AllAddresses() ([]types.UnlockHash, error)
 
+		// UsedAddresses returns the addresses of the wallet that received
+		// funds in a confirmed transaction, along with the total they
+		// received. Addresses are returned sorted in byte-order.
+		UsedAddresses() ([]WalletUsedAddress, error)
+
 		// AllSeeds returns all of the seeds that are being tracked by the
 		// wallet, including the primary seed. Only the primary seed is used to
 		// generate new addresses, but the wallet can spend funds sent to
 		// public keys generated by any of the seeds returned.
 		AllSeeds() ([]Seed, error)
 
+		// SeedAddresses returns the addresses that the wallet derived from
+		// the given seed and is tracking. The seed must be the primary seed
+		// or a seed that was loaded into the wallet.
+		SeedAddresses(seed Seed) ([]types.UnlockHash, error)
+
 		// CreateBackup will create a backup of the wallet at the provided
 		// filepath. The backup will have all seeds and keys.
 		CreateBackup(string) error
@@ -312,25 +420,70 @@ type (
 		// become spendable.
 		LoadSiagKeys(crypto.TwofishKey, []string) error
 
+		// LoadSiagKeyData is like LoadSiagKeys, but takes the contents of the
+		// siag keyfiles instead of their filepaths.
+		LoadSiagKeyData(crypto.TwofishKey, [][]byte) error
+
+		// MultisigAddress returns the unlock conditions of an address that
+		// requires sigLimit signatures of the given public keys. If
+		// includeWalletKey is set, a new key of the wallet is added to the set.
+		MultisigAddress(pubkeys []types.SiaPublicKey, sigLimit uint64, includeWalletKey bool) (types.UnlockConditions, error)
+
 		// NextAddress returns a new coin addresses generated from the
 		// primary seed.
 		NextAddress() (types.UnlockConditions, error)
 
+		// FreshAddress returns a new address generated from the primary
+		// seed that was never returned by the wallet before and that has
+		// never received funds.
+		FreshAddress() (types.UnlockConditions, error)
+
 		// NextAddresses returns n new coin addresses generated from the primary
 		// seed.
 		NextAddresses(uint64) ([]types.UnlockConditions, error)
 
+		// PrimarySeedAddress returns the address that the primary seed
+		// produces at the given index without advancing the seed progress.
+		PrimarySeedAddress(index uint64) (types.UnlockConditions, error)
+
+		// VanityAddress derives addresses from the primary seed until one of
+		// them starts with the hex prefix, trying at most maxAttempts
+		// addresses. The address is returned together with its index and
+		// remains recoverable from the seed.
+		VanityAddress(prefix string, maxAttempts uint64) (types.UnlockConditions, uint64, error)
+
 		// PrimarySeed returns the unencrypted primary seed of the wallet,
 		// along with a uint64 indicating how many addresses may be safely
 		// generated from the seed.
 		PrimarySeed() (Seed, uint64, error)
 
+		// ExportPrimarySeed returns the primary seed encrypted with key,
+		// which is independent of the wallet's master key. The wallet must
+		// be unlocked.
+		ExportPrimarySeed(key crypto.TwofishKey) ([]byte, error)
+
+		// PrimarySeedProgress returns the number of addresses generated from
+		// the primary seed and the highest index of a primary seed address
+		// that has received funds. The bool is false if no generated address
+		// has received funds.
+		PrimarySeedProgress() (progress, highestFunded uint64, funded bool, err error)
+
 		// SweepSeed scans the blockchain for outputs generated from seed and
 		// creates a transaction that transfers them to the wallet. Note that
 		// this incurs a transaction fee. It returns the total value of the
 		// outputs, minus the fee. If only siafunds were found, the fee is
 		// deducted from the wallet.
 		SweepSeed(seed Seed) (coins, funds types.Currency, err error)
+
+		// SignMessage signs hash with the key of an address that the wallet
+		// is able to spend from. The signature covers MessageSigHash(hash),
+		// so that it can't be used to sign transactions. The public key of
+		// the address is returned along with the signature.
+		SignMessage(addr types.UnlockHash, hash crypto.Hash) (types.SiaPublicKey, crypto.Signature, error)
+
+		// UnlockConditions returns the unlock conditions of an address that
+		// the wallet is able to spend from. The wallet must be unlocked.
+		UnlockConditions(addr types.UnlockHash) (types.UnlockConditions, error)
 	}
 
 	// Wallet stores and manages siacoins and siafunds. The wallet file is
@@ -348,6 +501,20 @@ type (
 		// refund transactions.
 		ConfirmedBalance() (siacoinBalance types.Currency, siafundBalance types.Currency, siacoinClaimBalance types.Currency, err error)
 
+		// ConfirmedSiacoinBalanceMinConf returns the confirmed siacoin
+		// balance of the wallet, only counting outputs that have at least
+		// minConf confirmations. The value of the remaining outputs is
+		// returned as the pending balance.
+		ConfirmedSiacoinBalanceMinConf(minConf types.BlockHeight) (confirmed types.Currency, pending types.Currency, err error)
+
+		// ConfirmedBalanceDelta returns how the confirmed balance of the
+		// wallet changed between sinceHeight and the current height.
+		ConfirmedBalanceDelta(sinceHeight types.BlockHeight) (WalletBalanceDelta, error)
+
+		// ConfirmedBalanceAt returns the confirmed siacoin and siafund
+		// balance that the wallet had at the given height.
+		ConfirmedBalanceAt(height types.BlockHeight) (siacoins, siafunds types.Currency, err error)
+
 		// UnconfirmedBalance returns the unconfirmed balance of the wallet.
 		// Outgoing funds and incoming funds are reported separately. Refund
 		// outputs are included, meaning that sending a single coin to
@@ -358,6 +525,11 @@ type (
 		// Height returns the wallet's internal processed consensus height
 		Height() (types.BlockHeight, error)
 
+		// ScanHeight returns the height up to which the wallet has processed
+		// consensus changes, including changes that haven't been synced to
+		// disk yet.
+		ScanHeight() (types.BlockHeight, error)
+
 		// AddressTransactions returns all of the transactions that are related
 		// to a given address.
 		AddressTransactions(types.UnlockHash) ([]ProcessedTransaction, error)
@@ -366,20 +538,72 @@ type (
 		// transactions related to a given address.
 		AddressUnconfirmedTransactions(types.UnlockHash) ([]ProcessedTransaction, error)
 
+		// IncomingOutputs returns the siacoin outputs to an address that are
+		// created by unconfirmed transactions. The address doesn't need to
+		// belong to the wallet.
+		IncomingOutputs(types.UnlockHash) ([]IncomingOutput, error)
+
 		// Transaction returns the transaction with the given id. The bool
 		// indicates whether the transaction is in the wallet database. The
 		// wallet only stores transactions that are related to the wallet.
 		Transaction(types.TransactionID) (ProcessedTransaction, bool, error)
 
+		// TransactionGraph returns the transactions that funded the
+		// transaction with the given id and the transactions that spend its
+		// outputs, following the outputs up to depth transactions away. A
+		// depth of zero uses the wallet's default depth. Only transactions
+		// that are related to the wallet are included.
+		TransactionGraph(txid types.TransactionID, depth int) (TransactionGraph, error)
+
 		// Transactions returns all of the transactions that were confirmed at
 		// heights [startHeight, endHeight]. Unconfirmed transactions are not
 		// included.
 		Transactions(startHeight types.BlockHeight, endHeight types.BlockHeight) ([]ProcessedTransaction, error)
 
+		// ExportTransactions writes all of the transactions that were
+		// confirmed at heights [startHeight, endHeight] to w as
+		// newline-delimited JSON, ordered by confirmation height.
+		ExportTransactions(w io.Writer, startHeight types.BlockHeight, endHeight types.BlockHeight) error
+
 		// UnconfirmedTransactions returns all unconfirmed transactions
 		// relative to the wallet.
 		UnconfirmedTransactions() ([]ProcessedTransaction, error)
 
+		// ConfirmingTransactions returns the transactions that are in a
+		// block but don't have the wallet's confirmation depth yet.
+		ConfirmingTransactions() ([]ProcessedTransaction, error)
+
+		// ConfirmationDepth returns the number of blocks that need to be
+		// built on top of a transaction's block before the wallet treats the
+		// transaction as confirmed.
+		ConfirmationDepth() (types.BlockHeight, error)
+
+		// SetConfirmationDepth sets the number of blocks that need to be
+		// built on top of a transaction's block before the wallet treats the
+		// transaction as confirmed.
+		SetConfirmationDepth(types.BlockHeight) error
+
+		// Conflicts returns the inputs of the wallet's unconfirmed
+		// transactions that other transactions seen by the transaction pool
+		// tried to double spend.
+		Conflicts() ([]WalletConflict, error)
+
+		// ReservedOutputs returns the ids of the wallet's siacoin outputs
+		// that are spent by unconfirmed transactions.
+		ReservedOutputs() ([]types.SiacoinOutputID, error)
+
+		// LockOutput prevents a siacoin output of the wallet from being used
+		// to fund transactions until it is unlocked with UnlockOutput.
+		LockOutput(id types.SiacoinOutputID) error
+
+		// UnlockOutput allows a locked siacoin output to be used to fund
+		// transactions again.
+		UnlockOutput(id types.SiacoinOutputID) error
+
+		// LockedOutputs returns the ids of the siacoin outputs that are
+		// locked.
+		LockedOutputs() ([]types.SiacoinOutputID, error)
+
 		// RegisterTransaction takes a transaction and its parents and returns
 		// a TransactionBuilder which can be used to expand the transaction.
 		RegisterTransaction(t types.Transaction, parents []types.Transaction) (TransactionBuilder, error)
@@ -388,12 +612,48 @@ type (
 		// blockchain.
 		Rescanning() (bool, error)
 
+		// Rescan rebuilds the wallet's outputs and transactions by rescanning
+		// the blockchain from the beginning. Seeds and keys are kept.
+		Rescan() error
+
 		// Settings returns the Wallet's current settings.
 		Settings() (WalletSettings, error)
 
 		// SetSettings sets the Wallet's settings.
 		SetSettings(WalletSettings) error
 
+		// DefragSettings returns the settings that control the wallet's
+		// background defragmentation.
+		DefragSettings() (WalletDefragSettings, error)
+
+		// SetDefragSettings sets the settings that control the wallet's
+		// background defragmentation.
+		SetDefragSettings(WalletDefragSettings) error
+
+		// ChangeThreshold returns the value below which the change of a
+		// transaction is added to its miner fees instead of being refunded.
+		ChangeThreshold() (types.Currency, error)
+
+		// SetChangeThreshold sets the value below which the change of a
+		// transaction is added to its miner fees instead of being refunded.
+		SetChangeThreshold(types.Currency) error
+
+		// RescanThrottle returns the pause between two consensus changes
+		// that the wallet processes while rescanning the blockchain.
+		RescanThrottle() (time.Duration, error)
+
+		// SetRescanThrottle sets the pause between two consensus changes
+		// that the wallet processes while rescanning the blockchain.
+		SetRescanThrottle(time.Duration) error
+
+		// FeeMultiplier returns the factor that the wallet applies to the
+		// fee estimated by the transaction pool when sending siacoins.
+		FeeMultiplier() (float64, error)
+
+		// SetFeeMultiplier sets the factor that the wallet applies to the
+		// fee estimated by the transaction pool when sending siacoins.
+		SetFeeMultiplier(float64) error
+
 		// StartTransaction is a convenience method that calls
 		// RegisterTransaction(types.Transaction{}, nil)
 		StartTransaction() (TransactionBuilder, error)
@@ -404,32 +664,180 @@ type (
 		// are also returned to the caller.
 		SendSiacoins(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error)
 
+		// BumpTransactionFee replaces the unconfirmed transaction set
+		// containing the given transaction by a set that spends the same
+		// inputs but pays a total miner fee of fee. A zero fee is estimated
+		// from the transaction pool. The original set must not be confirmed.
+		BumpTransactionFee(txid types.TransactionID, fee types.Currency) ([]types.Transaction, error)
+
+		// PreviewSendSiacoins returns the signed transaction set that
+		// SendSiacoins would create, without submitting it to the transaction
+		// pool.
+		PreviewSendSiacoins(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error)
+
+		// SendSiacoinsArbitraryData sends siacoins to an address like
+		// SendSiacoins, attaching data to the arbitrary data of the
+		// transaction.
+		SendSiacoinsArbitraryData(amount types.Currency, dest types.UnlockHash, data []byte) ([]types.Transaction, error)
+
+		// SendSiacoinsExcluding sends siacoins to an address like
+		// SendSiacoins, without using the given outputs to fund the
+		// transaction.
+		SendSiacoinsExcluding(amount types.Currency, dest types.UnlockHash, exclude []types.SiacoinOutputID) ([]types.Transaction, error)
+
+		// TopUpAddress sends the siacoins needed to bring the confirmed
+		// balance of an address of the wallet up to target. The amount that
+		// was sent is returned.
+		TopUpAddress(dest types.UnlockHash, target types.Currency) (types.Currency, []types.Transaction, error)
+
 		// SendSiacoinsMulti sends coins to multiple addresses.
 		SendSiacoinsMulti(outputs []types.SiacoinOutput) ([]types.Transaction, error)
 
+		// SplitOutputs creates count outputs of the given value that are
+		// sent back to the wallet, so that they can be spent in parallel.
+		SplitOutputs(count uint64, value types.Currency) ([]types.Transaction, error)
+
+		// EstimateSiacoinsMultiFee estimates the size of the transaction set
+		// and the fee that SendSiacoinsMulti would produce for numOutputs
+		// outputs of the given value. No transaction is created.
+		EstimateSiacoinsMultiFee(numOutputs uint64, value types.Currency) (size uint64, fee types.Currency, err error)
+
+		// SendAllSiacoins sends all spendable siacoins of the wallet to dest,
+		// minus the transaction fee. The amount that was sent is returned.
+		SendAllSiacoins(dest types.UnlockHash) (types.Currency, []types.Transaction, error)
+
+		// CanSendSiacoins reports whether the wallet can currently fund
+		// sending amount in total to numOutputs outputs, including the
+		// estimated fee. If it can't, the missing amount is returned. No
+		// inputs are reserved and no transaction is created.
+		CanSendSiacoins(amount types.Currency, numOutputs uint64) (canSend bool, shortfall, fee types.Currency, err error)
+
+		// SimulateCoinSelection reports which outputs SendSiacoins would
+		// select to fund sending amount, and why it would fail if the
+		// outputs don't suffice. Nothing is reserved or spent.
+		SimulateCoinSelection(amount types.Currency) (CoinSelection, error)
+
+		// SendSiacoinsTimelocked sends siacoins to the address of the given
+		// unlock conditions. The coins can't be spent before the Timelock of
+		// the unlock conditions, which must be in the future.
+		SendSiacoinsTimelocked(amount types.Currency, uc types.UnlockConditions) ([]types.Transaction, error)
+
 		// SendSiafunds is a tool for sending siafunds from the wallet to an
 		// address. Sending money usually results in multiple transactions. The
 		// transactions are automatically given to the transaction pool, and
 		// are also returned to the caller.
 		SendSiafunds(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error)
 
+		// SendSiafundsMulti sends siafunds to multiple addresses.
+		SendSiafundsMulti(outputs []types.SiafundOutput) ([]types.Transaction, error)
+
 		// DustThreshold returns the quantity per byte below which a Currency is
 		// considered to be Dust.
 		DustThreshold() (types.Currency, error)
 	}
 
+	// WalletBalanceDelta describes how the confirmed balance of the wallet
+	// changed since a given height. Incoming and outgoing funds are reported
+	// separately, the net change is the difference of the two.
+	WalletBalanceDelta struct {
+		Height           types.BlockHeight `json:"height"`
+		SiacoinsIncoming types.Currency    `json:"siacoinsincoming"`
+		SiacoinsOutgoing types.Currency    `json:"siacoinsoutgoing"`
+		SiafundsIncoming types.Currency    `json:"siafundsincoming"`
+		SiafundsOutgoing types.Currency    `json:"siafundsoutgoing"`
+	}
+
+	// WalletConflict describes an unconfirmed transaction of the wallet that
+	// spends an output which other transactions tried to spend as well. The
+	// conflicting transactions were rejected by the transaction pool, but
+	// may still be accepted by other nodes or miners.
+	WalletConflict struct {
+		TransactionID             types.TransactionID   `json:"transactionid"`
+		OutputID                  types.SiacoinOutputID `json:"outputid"`
+		ConflictingTransactionIDs []types.TransactionID `json:"conflictingtransactionids"`
+	}
+
+	// WalletUsedAddress describes an address of the wallet that received
+	// funds in a confirmed transaction, along with the total it received.
+	WalletUsedAddress struct {
+		Address          types.UnlockHash `json:"address"`
+		SiacoinsReceived types.Currency   `json:"siacoinsreceived"`
+		SiafundsReceived types.Currency   `json:"siafundsreceived"`
+	}
+
 	// WalletSettings control the behavior of the Wallet.
 	WalletSettings struct {
 		NoDefrag bool `json:"noDefrag"`
 	}
+
+	// WalletDefragSettings control the wallet's background defragmentation.
+	// When enabled, the wallet merges its smaller outputs whenever it is
+	// unlocked and idle and has more than Threshold spendable outputs.
+	WalletDefragSettings struct {
+		Enabled bool `json:"enabled"`
+
+		// Threshold is the number of spendable outputs the wallet may have
+		// before it is defragmented.
+		Threshold uint64 `json:"threshold"`
+
+		// TargetOutputSize is the value below which an output is considered
+		// small enough to be merged. Larger outputs are left untouched.
+		TargetOutputSize types.Currency `json:"targetoutputsize"`
+
+		// BatchSize is the maximum number of outputs that are merged by a
+		// single defrag transaction.
+		BatchSize uint64 `json:"batchsize"`
+	}
 )
 
+// NewWalletUnlockStatus returns the unlock status of a wallet with the given
+// state. A rescan takes precedence over the lock state, since the wallet is
+// rescanning while it is being unlocked.
+func NewWalletUnlockStatus(encrypted, unlocked, rescanning bool) WalletUnlockStatus {
+	switch {
+	case !encrypted:
+		return WalletUnlockStatusUnencrypted
+	case rescanning:
+		return WalletUnlockStatusRescanning
+	case unlocked:
+		return WalletUnlockStatusUnlocked
+	default:
+		return WalletUnlockStatusLocked
+	}
+}
+
 // CalculateWalletTransactionID is a helper function for determining the id of
 // a wallet transaction.
 func CalculateWalletTransactionID(tid types.TransactionID, oid types.OutputID) WalletTransactionID {
 	return WalletTransactionID(crypto.HashAll(tid, oid))
 }
 
+// MessageSigHash returns the hash that is signed by Wallet.SignMessage for
+// hash. The hash is prefixed with a specifier, so that a signed message can't
+// be mistaken for a signed transaction.
+func MessageSigHash(hash crypto.Hash) crypto.Hash {
+	return crypto.HashAll(messageSpecifier, hash)
+}
+
+// VerifyMessageSignature checks that sig is a valid signature of hash by the
+// owner of addr, which must be a standard address with the single public key
+// pk.
+func VerifyMessageSignature(addr types.UnlockHash, pk types.SiaPublicKey, hash crypto.Hash, sig crypto.Signature) error {
+	uc := types.UnlockConditions{
+		PublicKeys:         []types.SiaPublicKey{pk},
+		SignaturesRequired: 1,
+	}
+	if uc.UnlockHash() != addr {
+		return ErrPublicKeyMismatch
+	}
+	if pk.Algorithm != types.SignatureEd25519 || len(pk.Key) != crypto.PublicKeySize {
+		return errors.New("only ed25519 public keys are supported")
+	}
+	var edPK crypto.PublicKey
+	copy(edPK[:], pk.Key)
+	return crypto.VerifyHash(MessageSigHash(hash), edPK, sig)
+}
+
 // SeedToString converts a wallet seed to a human friendly string.
 func SeedToString(seed Seed, did mnemonics.DictionaryID) (string, error) {
 	fullChecksum := crypto.HashObject(seed)
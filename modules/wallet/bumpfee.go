@@ -0,0 +1,226 @@
+package wallet
+
+import (
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/encoding"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+	"gitlab.com/NebulousLabs/errors"
+)
+
+var (
+	// errTransactionConfirmed is returned by BumpTransactionFee if the
+	// transaction has already been confirmed.
+	errTransactionConfirmed = errors.New("transaction has already been confirmed")
+
+	// errUnconfirmedTransactionNotFound is returned by BumpTransactionFee if
+	// the transaction is not in the transaction pool.
+	errUnconfirmedTransactionNotFound = errors.New("transaction is not in the transaction pool")
+
+	// errFeeNotIncreased is returned by BumpTransactionFee if the new fee
+	// doesn't exceed the fee that is currently paid by the transaction set.
+	errFeeNotIncreased = errors.New("new fee must be higher than the current fee of the transaction set")
+
+	// errCannotBumpFee is returned by BumpTransactionFee if the transaction
+	// set can't be rebuilt by the wallet alone.
+	errCannotBumpFee = errors.New("fee of transaction can't be bumped")
+)
+
+// BumpTransactionFee replaces the unconfirmed transaction set containing the
+// transaction with the given id by a set that spends the same inputs but pays
+// a total miner fee of fee. The additional fee is taken from a wallet output
+// of the set, typically the refund output. If fee is zero, the fee is
+// estimated from the transaction pool, paying at least twice the current fee.
+//
+// Sia has no notion of replacing transactions, so this only works as long as
+// the original set hasn't been mined. Peers that have already seen the
+// original set will reject the replacement as a double spend until the
+// original set is dropped from their transaction pools.
+func (w *Wallet) BumpTransactionFee(txid types.TransactionID, fee types.Currency) ([]types.Transaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.RLock()
+	unlocked := w.unlocked
+	_, confirmedErr := dbGetTransactionIndex(w.dbTx, txid)
+	w.mu.RUnlock()
+	if !unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+	if confirmedErr == nil {
+		return nil, errTransactionConfirmed
+	}
+
+	// Get the unconfirmed set containing the transaction.
+	txn, _, exists := w.tpool.Transaction(txid)
+	if !exists {
+		return nil, errUnconfirmedTransactionNotFound
+	}
+	var oid crypto.Hash
+	if len(txn.SiacoinInputs) > 0 {
+		oid = crypto.Hash(txn.SiacoinInputs[0].ParentID)
+	} else if len(txn.SiafundInputs) > 0 {
+		oid = crypto.Hash(txn.SiafundInputs[0].ParentID)
+	} else {
+		return nil, errors.AddContext(errCannotBumpFee, "transaction has no inputs")
+	}
+	oldSet := w.tpool.TransactionSet(oid)
+	if len(oldSet) == 0 {
+		return nil, errUnconfirmedTransactionNotFound
+	}
+
+	// Determine the new fee.
+	var oldFee types.Currency
+	for _, t := range oldSet {
+		for _, f := range t.MinerFees {
+			oldFee = oldFee.Add(f)
+		}
+	}
+	if fee.IsZero() {
+		_, maxFee := w.tpool.FeeEstimation()
+		fee = maxFee.Mul64(uint64(len(encoding.Marshal(oldSet))))
+		if fee.Cmp(oldFee.Mul64(2)) < 0 {
+			fee = oldFee.Mul64(2)
+		}
+	}
+	if fee.Cmp(oldFee) <= 0 {
+		return nil, errFeeNotIncreased
+	}
+
+	w.mu.Lock()
+	newSet, replacedOutputs, err := w.bumpTransactionSetFee(oldSet, fee.Sub(oldFee))
+	w.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace the original set. The lock can't be held while doing so, since
+	// the transaction pool will notify the wallet about the replacement.
+	err = w.tpool.ReplaceTransactionSet(txid, newSet)
+	if err != nil {
+		return nil, errors.AddContext(err, "transaction pool rejected the replacement")
+	}
+
+	// The ids of the outputs created within the set changed. Move the spent
+	// marks of the wallet's reserved outputs to the new ids.
+	w.mu.Lock()
+	for oldID, newID := range replacedOutputs {
+		height, err := dbGetSpentOutput(w.dbTx, oldID)
+		if err != nil {
+			continue
+		}
+		err = errors.Compose(dbPutSpentOutput(w.dbTx, newID, height), dbDeleteSpentOutput(w.dbTx, oldID))
+		if err != nil {
+			w.mu.Unlock()
+			return nil, err
+		}
+	}
+	w.mu.Unlock()
+
+	w.log.Println("Bumped the fee of transaction", txid, "from", oldFee.HumanString(), "to", fee.HumanString(), "new IDs:")
+	for _, t := range newSet {
+		w.log.Println("\t", t.ID())
+	}
+	return newSet, nil
+}
+
+// bumpTransactionSetFee returns a copy of set which pays an additional fee of
+// extraFee. The fee is taken from the first wallet output of the set that
+// isn't spent within the set. Every transaction that changes, including the
+// children of changed transactions, is signed again. The returned map contains
+// the new ids of all outputs whose id changed. The caller must hold the wallet
+// lock.
+func (w *Wallet) bumpTransactionSetFee(set []types.Transaction, extraFee types.Currency) ([]types.Transaction, map[types.OutputID]types.OutputID, error) {
+	// Find the outputs that are spent within the set.
+	spentInSet := make(map[types.OutputID]struct{})
+	for _, txn := range set {
+		for _, sci := range txn.SiacoinInputs {
+			spentInSet[types.OutputID(sci.ParentID)] = struct{}{}
+		}
+	}
+
+	newSet := make([]types.Transaction, len(set))
+	replaced := make(map[types.OutputID]types.OutputID)
+	feeAdded := false
+	for i, oldTxn := range set {
+		// Copy the transaction so that the original set isn't modified.
+		var txn types.Transaction
+		if err := encoding.Unmarshal(encoding.Marshal(oldTxn), &txn); err != nil {
+			return nil, nil, err
+		}
+
+		// Spend the new ids of the outputs that were replaced.
+		changed := false
+		for j, sci := range txn.SiacoinInputs {
+			if newID, ok := replaced[types.OutputID(sci.ParentID)]; ok {
+				txn.SiacoinInputs[j].ParentID = types.SiacoinOutputID(newID)
+				changed = true
+			}
+		}
+		for j, sfi := range txn.SiafundInputs {
+			if newID, ok := replaced[types.OutputID(sfi.ParentID)]; ok {
+				txn.SiafundInputs[j].ParentID = types.SiafundOutputID(newID)
+				changed = true
+			}
+		}
+
+		// Take the additional fee from the first suitable wallet output.
+		if !feeAdded {
+			for j, sco := range txn.SiacoinOutputs {
+				if _, spent := spentInSet[types.OutputID(oldTxn.SiacoinOutputID(uint64(j)))]; spent {
+					continue
+				}
+				if _, owned := w.keys[sco.UnlockHash]; !owned || sco.Value.Cmp(extraFee) <= 0 {
+					continue
+				}
+				txn.SiacoinOutputs[j].Value = sco.Value.Sub(extraFee)
+				if len(txn.MinerFees) == 0 {
+					txn.MinerFees = append(txn.MinerFees, extraFee)
+				} else {
+					txn.MinerFees[0] = txn.MinerFees[0].Add(extraFee)
+				}
+				feeAdded = true
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			newSet[i] = txn
+			continue
+		}
+
+		// The transaction changed and has to be signed again, which is only
+		// possible if all of its inputs belong to the wallet.
+		if len(txn.FileContracts) > 0 || len(txn.FileContractRevisions) > 0 || len(txn.StorageProofs) > 0 {
+			return nil, nil, errors.AddContext(errCannotBumpFee, "transaction set contains file contracts")
+		}
+		txn.TransactionSignatures = nil
+		for _, sci := range txn.SiacoinInputs {
+			key, exists := w.keys[sci.UnlockConditions.UnlockHash()]
+			if !exists {
+				return nil, nil, errors.AddContext(errCannotBumpFee, "transaction set spends outputs that don't belong to the wallet")
+			}
+			addSignatures(&txn, types.FullCoveredFields, sci.UnlockConditions, crypto.Hash(sci.ParentID), key)
+		}
+		for _, sfi := range txn.SiafundInputs {
+			key, exists := w.keys[sfi.UnlockConditions.UnlockHash()]
+			if !exists {
+				return nil, nil, errors.AddContext(errCannotBumpFee, "transaction set spends outputs that don't belong to the wallet")
+			}
+			addSignatures(&txn, types.FullCoveredFields, sfi.UnlockConditions, crypto.Hash(sfi.ParentID), key)
+		}
+		for j := range txn.SiacoinOutputs {
+			replaced[types.OutputID(oldTxn.SiacoinOutputID(uint64(j)))] = types.OutputID(txn.SiacoinOutputID(uint64(j)))
+		}
+		for j := range txn.SiafundOutputs {
+			replaced[types.OutputID(oldTxn.SiafundOutputID(uint64(j)))] = types.OutputID(txn.SiafundOutputID(uint64(j)))
+		}
+		newSet[i] = txn
+	}
+	if !feeAdded {
+		return nil, nil, errors.AddContext(errCannotBumpFee, "transaction set has no wallet output that can pay the additional fee")
+	}
+	return newSet, replaced, nil
+}
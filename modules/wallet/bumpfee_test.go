@@ -0,0 +1,96 @@
+package wallet
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+	"gitlab.com/NebulousLabs/errors"
+)
+
+// TestBumpTransactionFee probes the BumpTransactionFee method of the wallet.
+func TestBumpTransactionFee(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// setFee returns the total miner fee of a transaction set.
+	setFee := func(set []types.Transaction) (fee types.Currency) {
+		for _, txn := range set {
+			for _, f := range txn.MinerFees {
+				fee = fee.Add(f)
+			}
+		}
+		return
+	}
+
+	// Send some coins to create an unconfirmed transaction set.
+	oldSet, err := wt.wallet.SendSiacoins(types.SiacoinPrecision.Mul64(100), types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txid := oldSet[len(oldSet)-1].ID()
+	oldFee := setFee(oldSet)
+
+	// A fee that isn't higher than the current fee should be rejected.
+	if _, err := wt.wallet.BumpTransactionFee(txid, oldFee); !errors.Contains(err, errFeeNotIncreased) {
+		t.Fatal("expected errFeeNotIncreased, got", err)
+	}
+
+	// Bump the fee of the set.
+	newFee := oldFee.Add(types.SiacoinPrecision)
+	newSet, err := wt.wallet.BumpTransactionFee(txid, newFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if setFee(newSet).Cmp(newFee) != 0 {
+		t.Fatalf("expected fee %v, got %v", newFee, setFee(newSet))
+	}
+	for _, txn := range newSet {
+		if err := txn.StandaloneValid(wt.cs.Height()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The transaction pool should only contain the new set.
+	inPool := make(map[types.TransactionID]struct{})
+	for _, txn := range wt.tpool.TransactionList() {
+		inPool[txn.ID()] = struct{}{}
+	}
+	for _, txn := range oldSet {
+		if _, exists := inPool[txn.ID()]; exists {
+			t.Fatal("original transaction is still in the transaction pool")
+		}
+	}
+	for _, txn := range newSet {
+		if _, exists := inPool[txn.ID()]; !exists {
+			t.Fatal("replacement transaction is not in the transaction pool")
+		}
+	}
+
+	// The replaced transaction is no longer in the pool.
+	if _, err := wt.wallet.BumpTransactionFee(txid, newFee.Mul64(2)); !errors.Contains(err, errUnconfirmedTransactionNotFound) {
+		t.Fatal("expected errUnconfirmedTransactionNotFound, got", err)
+	}
+
+	// Once the new set is confirmed, its fee can't be bumped anymore.
+	wt.addBlockNoPayout()
+	newTxid := newSet[len(newSet)-1].ID()
+	if _, found, err := wt.wallet.Transaction(newTxid); err != nil || !found {
+		t.Fatal("replacement transaction was not confirmed", err)
+	}
+	if _, err := wt.wallet.BumpTransactionFee(newTxid, newFee.Mul64(2)); !errors.Contains(err, errTransactionConfirmed) {
+		t.Fatal("expected errTransactionConfirmed, got", err)
+	}
+
+	// The wallet should still be able to send coins.
+	if _, err := wt.wallet.SendSiacoins(types.SiacoinPrecision.Mul64(100), types.UnlockHash{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,193 @@
+package wallet
+
+import (
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// The reasons why coin selection skips an output of the wallet.
+const (
+	skipReasonDust       = "dust"
+	skipReasonExcluded   = "excluded"
+	skipReasonLocked     = "locked"
+	skipReasonReserved   = "reserved"
+	skipReasonTimelocked = "timelocked"
+)
+
+// coinSelection is the set of outputs that were selected to fund a
+// transaction, together with the outputs that were skipped on the way.
+type coinSelection struct {
+	ids     []types.SiacoinOutputID
+	outputs []types.SiacoinOutput
+	fund    types.Currency
+	skipped []modules.CoinSelectionOutput
+}
+
+// skipReason returns the reason that is reported for an output that
+// checkOutput rejected with err.
+func skipReason(err error) string {
+	switch err {
+	case errDustOutput:
+		return skipReasonDust
+	case errOutputLocked:
+		return skipReasonLocked
+	case errSpendHeightTooHigh:
+		return skipReasonReserved
+	case errOutputTimelock:
+		return skipReasonTimelocked
+	default:
+		return err.Error()
+	}
+}
+
+// selectSiacoinOutputs selects outputs from so, which is sorted from the
+// largest to the smallest output, until their value reaches amount. Outputs
+// that can't be spent are skipped. The selection is returned even if it
+// doesn't reach amount, together with an error that explains why. The caller
+// must hold the wallet lock.
+func (w *Wallet) selectSiacoinOutputs(so sortedOutputs, amount types.Currency, height types.BlockHeight, dustThreshold types.Currency, excludedAddresses map[types.UnlockHash]struct{}, excludedOutputs map[types.SiacoinOutputID]struct{}) (coinSelection, error) {
+	var selection coinSelection
+	// potentialFund tracks the balance of the wallet including outputs that
+	// have been spent in other unconfirmed transactions recently. This is to
+	// provide the user with a more useful error message in the event that they
+	// are overspending.
+	var potentialFund types.Currency
+	// excludedFund tracks the value of the spendable outputs that were
+	// skipped because they were excluded explicitly.
+	var excludedFund types.Currency
+	for i := range so.ids {
+		scoid := so.ids[i]
+		sco := so.outputs[i]
+		if _, excluded := excludedAddresses[sco.UnlockHash]; excluded {
+			continue
+		}
+		// Check that the output can be spent.
+		if err := w.checkOutput(w.dbTx, height, scoid, sco, dustThreshold); err != nil {
+			if err == errSpendHeightTooHigh {
+				potentialFund = potentialFund.Add(sco.Value)
+			}
+			selection.skipped = append(selection.skipped, modules.CoinSelectionOutput{
+				ID:     scoid,
+				Value:  sco.Value,
+				Reason: skipReason(err),
+			})
+			continue
+		}
+		if _, excluded := excludedOutputs[scoid]; excluded {
+			excludedFund = excludedFund.Add(sco.Value)
+			selection.skipped = append(selection.skipped, modules.CoinSelectionOutput{
+				ID:     scoid,
+				Value:  sco.Value,
+				Reason: skipReasonExcluded,
+			})
+			continue
+		}
+
+		// Add the output to the total fund
+		selection.ids = append(selection.ids, scoid)
+		selection.outputs = append(selection.outputs, sco)
+		selection.fund = selection.fund.Add(sco.Value)
+		potentialFund = potentialFund.Add(sco.Value)
+		if selection.fund.Cmp(amount) >= 0 {
+			break
+		}
+	}
+	if potentialFund.Cmp(amount) >= 0 && selection.fund.Cmp(amount) < 0 {
+		return selection, modules.ErrIncompleteTransactions
+	}
+	if selection.fund.Add(excludedFund).Cmp(amount) >= 0 && selection.fund.Cmp(amount) < 0 {
+		return selection, errInsufficientFundsAfterExclusions
+	}
+	if selection.fund.Cmp(amount) < 0 {
+		return selection, modules.ErrLowBalance
+	}
+	return selection, nil
+}
+
+// SimulateCoinSelection reports how SendSiacoins would select the outputs
+// that fund a transaction sending amount, without creating the transaction.
+// Nothing is reserved, spent or written to the database, and no addresses are
+// generated. If the selection doesn't reach the amount plus the fee, the error
+// that SendSiacoins would return is reported in the result instead of being
+// returned.
+func (w *Wallet) SimulateCoinSelection(amount types.Currency) (modules.CoinSelection, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.CoinSelection{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	dustThreshold, err := w.DustThreshold()
+	if err != nil {
+		return modules.CoinSelection{}, err
+	}
+	fee := w.managedSendFee(0)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return modules.CoinSelection{}, modules.ErrLockedWallet
+	}
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return modules.CoinSelection{}, err
+	}
+	changeThreshold, err := dbGetChangeThreshold(w.dbTx)
+	if err != nil {
+		return modules.CoinSelection{}, err
+	}
+	so, err := w.sortedSiacoinOutputs()
+	if err != nil {
+		return modules.CoinSelection{}, err
+	}
+
+	result := modules.CoinSelection{
+		Amount:   amount,
+		Fee:      fee,
+		Target:   amount.Add(fee),
+		Immature: w.immatureSiacoins(height),
+	}
+	var selection coinSelection
+	if w.rescanning {
+		err = errRescanning
+	} else {
+		selection, err = w.selectSiacoinOutputs(so, result.Target, height, dustThreshold, nil, nil)
+	}
+	for i, id := range selection.ids {
+		result.Selected = append(result.Selected, modules.CoinSelectionOutput{
+			ID:    id,
+			Value: selection.outputs[i].Value,
+		})
+	}
+	result.Skipped = selection.skipped
+	result.Gathered = selection.fund
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Change = selection.fund.Sub(result.Target)
+	result.ChangeToFee = !result.Change.IsZero() && result.Change.Cmp(changeThreshold) < 0
+	return result, nil
+}
+
+// immatureSiacoins returns the value of the wallet's miner payouts and siafund
+// claims that haven't matured at the given height. They are part of the
+// wallet's history but can't be selected to fund transactions yet. The caller
+// must hold the wallet lock.
+func (w *Wallet) immatureSiacoins(height types.BlockHeight) (immature types.Currency) {
+	_ = dbForEachProcessedTransactionReverse(w.dbTx, func(pt modules.ProcessedTransaction) bool {
+		// Outputs mature at most MaturityDelay blocks after they were
+		// confirmed.
+		if pt.ConfirmationHeight+types.MaturityDelay < height {
+			return false
+		}
+		for _, output := range pt.Outputs {
+			if !output.WalletAddress || output.MaturityHeight <= height {
+				continue
+			}
+			if output.FundType == types.SpecifierMinerPayout || output.FundType == types.SpecifierClaimOutput {
+				immature = immature.Add(output.Value)
+			}
+		}
+		return true
+	})
+	return
+}
@@ -0,0 +1,82 @@
+package wallet
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestSimulateCoinSelection checks that simulating coin selection reports the
+// selected and skipped outputs without reserving anything.
+func TestSimulateCoinSelection(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	wt.wallet.mu.Lock()
+	progress, err := dbGetPrimarySeedProgress(wt.wallet.dbTx)
+	wt.wallet.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A small amount is funded by the largest output.
+	sel, err := wt.wallet.SimulateCoinSelection(types.SiacoinPrecision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sel.Error != "" || len(sel.Selected) != 1 || len(sel.Skipped) != 0 {
+		t.Fatal("expected a single selected output, got", sel)
+	}
+	if !sel.Target.Equals(sel.Amount.Add(sel.Fee)) || !sel.Gathered.Equals(sel.Target.Add(sel.Change)) {
+		t.Fatal("selection doesn't add up:", sel)
+	}
+	id := sel.Selected[0].ID
+
+	// Nothing was reserved and no address was generated.
+	wt.wallet.mu.Lock()
+	_, err = dbGetSpentOutput(wt.wallet.dbTx, types.OutputID(id))
+	newProgress, _ := dbGetPrimarySeedProgress(wt.wallet.dbTx)
+	wt.wallet.mu.Unlock()
+	if err == nil {
+		t.Fatal("simulation marked the output as spent")
+	}
+	if newProgress != progress {
+		t.Fatal("simulation generated addresses")
+	}
+
+	// A locked output is skipped.
+	if err := wt.wallet.LockOutput(id); err != nil {
+		t.Fatal(err)
+	}
+	sel, err = wt.wallet.SimulateCoinSelection(types.SiacoinPrecision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sel.Error != "" || len(sel.Skipped) != 1 || sel.Skipped[0].ID != id || sel.Skipped[0].Reason != skipReasonLocked {
+		t.Fatal("expected the locked output to be skipped, got", sel)
+	}
+	if sel.Selected[0].ID == id {
+		t.Fatal("locked output was selected")
+	}
+
+	// An amount above the balance can't be funded.
+	balance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sel, err = wt.wallet.SimulateCoinSelection(balance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sel.Error != modules.ErrLowBalance.Error() || !sel.Change.IsZero() {
+		t.Fatal("expected the selection to fail with ErrLowBalance, got", sel)
+	}
+}
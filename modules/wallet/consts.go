@@ -1,7 +1,11 @@
 package wallet
 
 import (
+	"time"
+
 	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
 )
 
 const (
@@ -15,9 +19,61 @@ const (
 	// defragThreshold is the number of outputs a wallet is allowed before it is
 	// defragmented.
 	defragThreshold = 50
+
+	// maxArbitraryDataSize is the largest payload that can be attached to a
+	// transaction by SendSiacoinsArbitraryData. It is kept well below the
+	// transaction size limit so that the transaction is always accepted.
+	maxArbitraryDataSize = 1024
+
+	// maxFreshAddressAttempts is the number of addresses that FreshAddress
+	// skips before giving up. Every skipped address has received funds or
+	// was handed out already, so hitting this limit indicates that the seed
+	// is being used heavily outside of the wallet.
+	maxFreshAddressAttempts = 1000
+
+	// defaultVanityAddressAttempts is the number of addresses that
+	// VanityAddress tries if the caller doesn't specify a limit.
+	defaultVanityAddressAttempts = 10e3
+
+	// maxVanityAddressAttempts is the largest number of addresses that
+	// VanityAddress can be asked to try. Every address that is tried is
+	// tracked by the wallet afterwards, and the addresses have to stay within
+	// the range that the seed scanner covers when the wallet is restored.
+	maxVanityAddressAttempts = 100e3
+
+	// defaultTransactionGraphDepth is the depth of the transaction graph
+	// returned by TransactionGraph if the caller doesn't specify one.
+	defaultTransactionGraphDepth = 10
+
+	// maxTransactionGraphDepth is the largest depth that can be passed to
+	// TransactionGraph. It bounds the work done for a single request, since
+	// every step of the traversal may double the size of the graph.
+	maxTransactionGraphDepth = 100
+
+	// maxDefragBatchSize is the largest batch size that can be set in the
+	// wallet's defrag settings. Larger batches would result in defrag
+	// transactions that are too large to be accepted by the transaction pool.
+	maxDefragBatchSize = 100
 )
 
 var (
+	// autoDefragInterval is the interval at which the wallet checks whether
+	// it should defragment its outputs in the background.
+	autoDefragInterval = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: 10 * time.Minute,
+		Testing:  time.Second,
+	}).(time.Duration)
+
+	// defaultDefragSettings are the defrag settings of a wallet that hasn't
+	// configured background defragmentation yet.
+	defaultDefragSettings = modules.WalletDefragSettings{
+		Enabled:          false,
+		Threshold:        defragThreshold,
+		TargetOutputSize: types.SiacoinPrecision.Mul64(1000),
+		BatchSize:        defragBatchSize,
+	}
+
 	// lookaheadBuffer together with lookaheadRescanThreshold defines the constant part
 	// of the maxLookahead
 	lookaheadBuffer = build.Select(build.Var{
@@ -3,6 +3,7 @@ package wallet
 import (
 	"encoding/binary"
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 
@@ -39,6 +40,14 @@ var (
 	// these outputs so that it can reuse them if they are not confirmed on
 	// the blockchain.
 	bucketSpentOutputs = []byte("bucketSpentOutputs")
+	// bucketIssuedAddresses contains the UnlockHashes of the addresses that
+	// the wallet has handed out from its primary seed. It is used to
+	// guarantee that fresh addresses were never returned before.
+	bucketIssuedAddresses = []byte("bucketIssuedAddresses")
+	// bucketLockedOutputs contains the SiacoinOutputIDs that the user locked
+	// explicitly. The wallet does not use these outputs to fund transactions
+	// until they are unlocked again.
+	bucketLockedOutputs = []byte("bucketLockedOutputs")
 	// bucketWallet contains various fields needed by the wallet, such as its
 	// UID, EncryptionVerification, and PrimarySeedFile.
 	bucketWallet = []byte("bucketWallet")
@@ -50,6 +59,8 @@ var (
 		bucketSiacoinOutputs,
 		bucketSiafundOutputs,
 		bucketSpentOutputs,
+		bucketIssuedAddresses,
+		bucketLockedOutputs,
 		bucketWallet,
 	}
 
@@ -57,11 +68,17 @@ var (
 
 	// these keys are used in bucketWallet
 	keyAuxiliarySeedFiles     = []byte("keyAuxiliarySeedFiles")
+	keyChangeThreshold        = []byte("keyChangeThreshold")
+	keyConfirmationDepth      = []byte("keyConfirmationDepth")
 	keyConsensusChange        = []byte("keyConsensusChange")
 	keyConsensusHeight        = []byte("keyConsensusHeight")
+	keyDefragSettings         = []byte("keyDefragSettings")
 	keyEncryptionVerification = []byte("keyEncryptionVerification")
+	keyFeeMultiplier          = []byte("keyFeeMultiplier")
+	keyHistoryStartHeight     = []byte("keyHistoryStartHeight")
 	keyPrimarySeedFile        = []byte("keyPrimarySeedFile")
 	keyPrimarySeedProgress    = []byte("keyPrimarySeedProgress")
+	keyRescanThrottle         = []byte("keyRescanThrottle")
 	keySiafundPool            = []byte("keySiafundPool")
 	keySpendableKeyFiles      = []byte("keySpendableKeyFiles")
 	keyUID                    = []byte("keyUID")
@@ -232,6 +249,26 @@ func dbDeleteSpentOutput(tx *bolt.Tx, id types.OutputID) error {
 	return dbDelete(tx.Bucket(bucketSpentOutputs), id)
 }
 
+func dbPutIssuedAddress(tx *bolt.Tx, addr types.UnlockHash) error {
+	return dbPut(tx.Bucket(bucketIssuedAddresses), addr, true)
+}
+func dbGetIssuedAddress(tx *bolt.Tx, addr types.UnlockHash) bool {
+	return tx.Bucket(bucketIssuedAddresses).Get(encoding.Marshal(addr)) != nil
+}
+
+func dbPutLockedOutput(tx *bolt.Tx, id types.SiacoinOutputID) error {
+	return dbPut(tx.Bucket(bucketLockedOutputs), id, true)
+}
+func dbGetLockedOutput(tx *bolt.Tx, id types.SiacoinOutputID) bool {
+	return tx.Bucket(bucketLockedOutputs).Get(encoding.Marshal(id)) != nil
+}
+func dbDeleteLockedOutput(tx *bolt.Tx, id types.SiacoinOutputID) error {
+	return dbDelete(tx.Bucket(bucketLockedOutputs), id)
+}
+func dbForEachLockedOutput(tx *bolt.Tx, fn func(types.SiacoinOutputID, bool)) error {
+	return dbForEach(tx.Bucket(bucketLockedOutputs), fn)
+}
+
 func dbPutAddrTransactions(tx *bolt.Tx, addr types.UnlockHash, txns []uint64) error {
 	return dbPut(tx.Bucket(bucketAddrTransactions), addr, txns)
 }
@@ -426,6 +463,23 @@ func dbProcessedTransactionsIterator(tx *bolt.Tx) *processedTransactionsIter {
 	}
 }
 
+// dbForEachProcessedTransactionReverse calls fn for each ProcessedTransaction,
+// starting with the most recent one. The iteration stops early if fn returns
+// false.
+func dbForEachProcessedTransactionReverse(tx *bolt.Tx, fn func(modules.ProcessedTransaction) bool) error {
+	c := tx.Bucket(bucketProcessedTransactions).Cursor()
+	for seqBytes, ptBytes := c.Last(); seqBytes != nil; seqBytes, ptBytes = c.Prev() {
+		var pt modules.ProcessedTransaction
+		if err := decodeProcessedTransaction(ptBytes, &pt); err != nil {
+			return err
+		}
+		if !fn(pt) {
+			break
+		}
+	}
+	return nil
+}
+
 // dbGetWalletUID returns the UID assigned to the wallet's primary seed.
 func dbGetWalletUID(tx *bolt.Tx) (uid uniqueID) {
 	copy(uid[:], tx.Bucket(bucketWallet).Get(keyUID))
@@ -466,6 +520,31 @@ func dbPutConsensusHeight(tx *bolt.Tx, height types.BlockHeight) error {
 	return tx.Bucket(bucketWallet).Put(keyConsensusHeight, encoding.Marshal(height))
 }
 
+// dbGetHistoryStartHeight returns the height of the first block in the
+// wallet's transaction history. errNoKey is returned if no block has been
+// processed since the history was last reset.
+func dbGetHistoryStartHeight(tx *bolt.Tx) (height types.BlockHeight, err error) {
+	b := tx.Bucket(bucketWallet).Get(keyHistoryStartHeight)
+	if b == nil {
+		return 0, errNoKey
+	}
+	err = encoding.Unmarshal(b, &height)
+	return
+}
+
+// dbPutHistoryStartHeight stores the height of the first block in the
+// wallet's transaction history.
+func dbPutHistoryStartHeight(tx *bolt.Tx, height types.BlockHeight) error {
+	return tx.Bucket(bucketWallet).Put(keyHistoryStartHeight, encoding.Marshal(height))
+}
+
+// dbDeleteHistoryStartHeight clears the history start height. It must be
+// called whenever the wallet's transaction history is reset, so that the
+// height is recorded again by the next scan.
+func dbDeleteHistoryStartHeight(tx *bolt.Tx) error {
+	return tx.Bucket(bucketWallet).Delete(keyHistoryStartHeight)
+}
+
 // dbGetSiafundPool returns the value of the siafund pool.
 func dbGetSiafundPool(tx *bolt.Tx) (pool types.Currency, err error) {
 	err = encoding.Unmarshal(tx.Bucket(bucketWallet).Get(keySiafundPool), &pool)
@@ -477,6 +556,71 @@ func dbPutSiafundPool(tx *bolt.Tx, pool types.Currency) error {
 	return tx.Bucket(bucketWallet).Put(keySiafundPool, encoding.Marshal(pool))
 }
 
+// dbGetDefragSettings returns the wallet's background defrag settings.
+func dbGetDefragSettings(tx *bolt.Tx) (settings modules.WalletDefragSettings, err error) {
+	err = encoding.Unmarshal(tx.Bucket(bucketWallet).Get(keyDefragSettings), &settings)
+	return
+}
+
+// dbPutDefragSettings stores the wallet's background defrag settings.
+func dbPutDefragSettings(tx *bolt.Tx, settings modules.WalletDefragSettings) error {
+	return tx.Bucket(bucketWallet).Put(keyDefragSettings, encoding.Marshal(settings))
+}
+
+// dbGetChangeThreshold returns the value below which change is added to the
+// miner fees instead of being refunded.
+func dbGetChangeThreshold(tx *bolt.Tx) (threshold types.Currency, err error) {
+	err = encoding.Unmarshal(tx.Bucket(bucketWallet).Get(keyChangeThreshold), &threshold)
+	return
+}
+
+// dbPutChangeThreshold stores the value below which change is added to the
+// miner fees instead of being refunded.
+func dbPutChangeThreshold(tx *bolt.Tx, threshold types.Currency) error {
+	return tx.Bucket(bucketWallet).Put(keyChangeThreshold, encoding.Marshal(threshold))
+}
+
+// dbGetConfirmationDepth returns the number of blocks that need to be built on
+// top of a transaction's block before the wallet treats it as confirmed.
+func dbGetConfirmationDepth(tx *bolt.Tx) (depth types.BlockHeight, err error) {
+	err = encoding.Unmarshal(tx.Bucket(bucketWallet).Get(keyConfirmationDepth), &depth)
+	return
+}
+
+// dbPutConfirmationDepth stores the number of blocks that need to be built on
+// top of a transaction's block before the wallet treats it as confirmed.
+func dbPutConfirmationDepth(tx *bolt.Tx, depth types.BlockHeight) error {
+	return tx.Bucket(bucketWallet).Put(keyConfirmationDepth, encoding.Marshal(depth))
+}
+
+// dbGetRescanThrottle returns the pause between two consensus changes during a
+// rescan.
+func dbGetRescanThrottle(tx *bolt.Tx) (throttle time.Duration, err error) {
+	err = encoding.Unmarshal(tx.Bucket(bucketWallet).Get(keyRescanThrottle), &throttle)
+	return
+}
+
+// dbPutRescanThrottle stores the pause between two consensus changes during a
+// rescan.
+func dbPutRescanThrottle(tx *bolt.Tx, throttle time.Duration) error {
+	return tx.Bucket(bucketWallet).Put(keyRescanThrottle, encoding.Marshal(throttle))
+}
+
+// dbGetFeeMultiplier returns the factor applied to the fee estimate when
+// sending siacoins. The encoding package doesn't support floats, so the
+// multiplier is stored as its IEEE 754 bits.
+func dbGetFeeMultiplier(tx *bolt.Tx) (multiplier float64, err error) {
+	var bits uint64
+	err = encoding.Unmarshal(tx.Bucket(bucketWallet).Get(keyFeeMultiplier), &bits)
+	return math.Float64frombits(bits), err
+}
+
+// dbPutFeeMultiplier stores the factor applied to the fee estimate when
+// sending siacoins.
+func dbPutFeeMultiplier(tx *bolt.Tx, multiplier float64) error {
+	return tx.Bucket(bucketWallet).Put(keyFeeMultiplier, encoding.Marshal(math.Float64bits(multiplier)))
+}
+
 // COMPATv121: these types were stored in the db in v1.2.2 and earlier.
 type (
 	v121ProcessedInput struct {
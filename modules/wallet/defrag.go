@@ -2,16 +2,60 @@ package wallet
 
 import (
 	"errors"
+	"fmt"
 	"sort"
+	"time"
 
 	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/types"
 )
 
 var (
 	errDefragNotNeeded = errors.New("defragging not needed, wallet is already sufficiently defragged")
+
+	// errInvalidDefragBatchSize is returned by SetDefragSettings if the batch
+	// size is out of range.
+	errInvalidDefragBatchSize = fmt.Errorf("defrag batch size must be between 2 and %v", maxDefragBatchSize)
+
+	// errZeroDefragTargetOutputSize is returned by SetDefragSettings if the
+	// target output size is zero, which would never select any outputs.
+	errZeroDefragTargetOutputSize = errors.New("defrag target output size must be greater than zero")
 )
 
+// DefragSettings returns the settings that control the wallet's background
+// defragmentation.
+func (w *Wallet) DefragSettings() (modules.WalletDefragSettings, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.WalletDefragSettings{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbGetDefragSettings(w.dbTx)
+}
+
+// SetDefragSettings sets the settings that control the wallet's background
+// defragmentation. The settings are persisted in the wallet's database.
+func (w *Wallet) SetDefragSettings(s modules.WalletDefragSettings) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	if s.BatchSize < 2 || s.BatchSize > maxDefragBatchSize {
+		return errInvalidDefragBatchSize
+	}
+	if s.TargetOutputSize.IsZero() {
+		return errZeroDefragTargetOutputSize
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbPutDefragSettings(w.dbTx, s)
+}
+
 // managedCreateDefragTransaction creates a transaction that spends multiple existing
 // wallet outputs into a single new address.
 func (w *Wallet) managedCreateDefragTransaction() ([]types.Transaction, error) {
@@ -50,10 +94,87 @@ func (w *Wallet) managedCreateDefragTransaction() ([]types.Transaction, error) {
 
 	// Skip over the 'defragStartIndex' largest outputs, so that the user can
 	// still reasonably use their wallet while the defrag is happening.
+	so.ids = so.ids[defragStartIndex : defragStartIndex+defragBatchSize]
+	so.outputs = so.outputs[defragStartIndex : defragStartIndex+defragBatchSize]
+	return w.createDefragTransaction(so, minFee, consensusHeight)
+}
+
+// managedCreateAutoDefragTransaction creates a transaction that merges the
+// wallet's outputs according to its defrag settings. Only spendable outputs
+// below the target output size are merged, and outputs that are spent by
+// unconfirmed transactions are never touched.
+func (w *Wallet) managedCreateAutoDefragTransaction() ([]types.Transaction, error) {
+	// dustThreshold and minFee have to be obtained separate from the lock
+	dustThreshold, err := w.DustThreshold()
+	if err != nil {
+		return nil, err
+	}
+	minFee, _ := w.tpool.FeeEstimation()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	settings, err := dbGetDefragSettings(w.dbTx)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled || !w.unlocked || w.rescanning {
+		return nil, errDefragNotNeeded
+	}
+	consensusHeight, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the outputs that are spent by pending transactions.
+	pending := make(map[types.SiacoinOutputID]struct{})
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, sci := range upt.Transaction.SiacoinInputs {
+			pending[sci.ParentID] = struct{}{}
+		}
+	}
+
+	// Collect a value-sorted set of the outputs that can be merged, while
+	// counting all spendable outputs.
+	var so sortedOutputs
+	var spendable uint64
+	err = dbForEachSiacoinOutput(w.dbTx, func(scoid types.SiacoinOutputID, sco types.SiacoinOutput) {
+		if _, exists := pending[scoid]; exists {
+			return
+		}
+		if w.checkOutput(w.dbTx, consensusHeight, scoid, sco, dustThreshold) != nil {
+			return
+		}
+		spendable++
+		if sco.Value.Cmp(settings.TargetOutputSize) < 0 {
+			so.ids = append(so.ids, scoid)
+			so.outputs = append(so.outputs, sco)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if spendable <= settings.Threshold || len(so.ids) < 2 {
+		return nil, errDefragNotNeeded
+	}
+	sort.Sort(sort.Reverse(so))
+
+	// Merge the largest of the small outputs first, so that the merged output
+	// gets as close to the target output size as possible.
+	if uint64(len(so.ids)) > settings.BatchSize {
+		so.ids = so.ids[:settings.BatchSize]
+		so.outputs = so.outputs[:settings.BatchSize]
+	}
+	return w.createDefragTransaction(so, minFee, consensusHeight)
+}
+
+// createDefragTransaction creates a transaction that spends the outputs in so
+// into a single new address. The caller must hold the wallet lock.
+func (w *Wallet) createDefragTransaction(so sortedOutputs, minFee types.Currency, consensusHeight types.BlockHeight) ([]types.Transaction, error) {
 	var amount types.Currency
 	var parentTxn types.Transaction
 	var spentScoids []types.SiacoinOutputID
-	for i := defragStartIndex; i < defragStartIndex+defragBatchSize; i++ {
+	for i := range so.ids {
 		scoid := so.ids[i]
 		sco := so.outputs[i]
 
@@ -70,6 +191,13 @@ func (w *Wallet) managedCreateDefragTransaction() ([]types.Transaction, error) {
 		amount = amount.Add(sco.Value)
 	}
 
+	// compute the transaction fee.
+	sizeAvgOutput := uint64(250)
+	fee := minFee.Mul64(sizeAvgOutput * uint64(len(so.ids)))
+	if amount.Cmp(fee) <= 0 {
+		return nil, errDefragNotNeeded
+	}
+
 	// Create and add the output that will be used to fund the defrag
 	// transaction.
 	parentUnlockConditions, err := w.nextPrimarySeedAddress(w.dbTx)
@@ -93,10 +221,6 @@ func (w *Wallet) managedCreateDefragTransaction() ([]types.Transaction, error) {
 		return nil, err
 	}
 
-	// compute the transaction fee.
-	sizeAvgOutput := uint64(250)
-	fee := minFee.Mul64(sizeAvgOutput * defragBatchSize)
-
 	txn := types.Transaction{
 		SiacoinInputs: []types.SiacoinInput{{
 			ParentID:         parentTxn.SiacoinOutputID(0),
@@ -151,8 +275,14 @@ func (w *Wallet) threadedDefragWallet() {
 		return
 	}
 
-	// Create the defrag transaction.
-	txnSet, err := w.managedCreateDefragTransaction()
+	w.managedDefrag(w.managedCreateDefragTransaction)
+}
+
+// managedDefrag creates a defrag transaction set using create and submits it
+// to the transaction pool. If the transaction set can't be submitted, its
+// outputs are no longer marked as spent.
+func (w *Wallet) managedDefrag(create func() ([]types.Transaction, error)) {
+	txnSet, err := create()
 	defer func() {
 		if err == nil {
 			return
@@ -188,3 +318,25 @@ func (w *Wallet) threadedDefragWallet() {
 		w.log.Println("Wallet defrag: \t", txn.ID())
 	}
 }
+
+// threadedAutoDefrag periodically defragments the wallet according to its
+// defrag settings until the wallet is shut down. A defrag is skipped while the
+// wallet is scanning the blockchain.
+func (w *Wallet) threadedAutoDefrag() {
+	for {
+		select {
+		case <-w.tg.StopChan():
+			return
+		case <-time.After(autoDefragInterval):
+		}
+
+		if err := w.tg.Add(); err != nil {
+			return
+		}
+		if w.scanLock.TryLock() {
+			w.managedDefrag(w.managedCreateAutoDefragTransaction)
+			w.scanLock.Unlock()
+		}
+		w.tg.Done()
+	}
+}
@@ -55,6 +55,92 @@ func TestDefragWallet(t *testing.T) {
 	}
 }
 
+// defragSettingsEqual returns true if a and b are the same settings.
+func defragSettingsEqual(a, b modules.WalletDefragSettings) bool {
+	return a.Enabled == b.Enabled && a.Threshold == b.Threshold &&
+		a.TargetOutputSize.Equals(b.TargetOutputSize) && a.BatchSize == b.BatchSize
+}
+
+// TestAutoDefragWallet checks that the wallet merges its outputs in the
+// background according to its defrag settings.
+func TestAutoDefragWallet(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Background defragmentation should be disabled by default.
+	settings, err := wt.wallet.DefragSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !defragSettingsEqual(settings, defaultDefragSettings) {
+		t.Fatalf("expected default settings %v, got %v", defaultDefragSettings, settings)
+	}
+
+	// Invalid settings should be rejected.
+	invalid := defaultDefragSettings
+	invalid.BatchSize = 1
+	if err := wt.wallet.SetDefragSettings(invalid); err != errInvalidDefragBatchSize {
+		t.Fatal("expected errInvalidDefragBatchSize, got", err)
+	}
+	invalid = defaultDefragSettings
+	invalid.TargetOutputSize = types.ZeroCurrency
+	if err := wt.wallet.SetDefragSettings(invalid); err != errZeroDefragTargetOutputSize {
+		t.Fatal("expected errZeroDefragTargetOutputSize, got", err)
+	}
+
+	// Mine enough blocks to exceed a small threshold, staying below the
+	// threshold of the consensus-triggered defrag.
+	for i := 0; i < 20; i++ {
+		if _, err := wt.miner.AddBlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wt.wallet.mu.Lock()
+	wt.wallet.syncDB()
+	before := wt.wallet.dbTx.Bucket(bucketSiacoinOutputs).Stats().KeyN
+	wt.wallet.mu.Unlock()
+
+	// Enable background defragmentation. The block rewards are all below the
+	// target output size.
+	settings = modules.WalletDefragSettings{
+		Enabled:          true,
+		Threshold:        10,
+		TargetOutputSize: types.SiacoinPrecision.Mul64(1e9),
+		BatchSize:        5,
+	}
+	if err := wt.wallet.SetDefragSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := wt.wallet.DefragSettings(); err != nil {
+		t.Fatal(err)
+	} else if !defragSettingsEqual(s, settings) {
+		t.Fatalf("expected settings %v, got %v", settings, s)
+	}
+
+	// Allow some time for the defrag transactions to be created, then mine
+	// them.
+	time.Sleep(autoDefragInterval * 3)
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	wt.wallet.mu.Lock()
+	wt.wallet.syncDB()
+	after := wt.wallet.dbTx.Bucket(bucketSiacoinOutputs).Stats().KeyN
+	wt.wallet.mu.Unlock()
+	// The new block adds one output, every defrag removes at least one.
+	if after >= before+1 {
+		t.Fatalf("expected fewer than %v outputs after defragging, got %v", before+1, after)
+	}
+}
+
 // TestDefragWalletDust verifies that dust outputs do not trigger the defrag
 // operation.
 func TestDefragWalletDust(t *testing.T) {
@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// exportBatchSize is the number of transactions that ExportTransactions reads
+// from the database before writing them out.
+const exportBatchSize = 1000
+
+// ExportTransactions writes all transactions relevant to the wallet that were
+// confirmed in the range [startHeight, endHeight] to dst as newline-delimited
+// JSON, ordered by confirmation height. The transactions are read in batches
+// and the wallet is not locked while writing, so the export neither holds the
+// whole history in memory nor blocks the wallet on a slow writer.
+func (w *Wallet) ExportTransactions(dst io.Writer, startHeight, endHeight types.BlockHeight) error {
+	if err := w.tg.Add(); err != nil {
+		return err
+	}
+	defer w.tg.Done()
+
+	// Check the bounds and find the first transaction to export.
+	w.mu.Lock()
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		w.mu.Unlock()
+		return err
+	} else if startHeight > height || startHeight > endHeight {
+		w.mu.Unlock()
+		return errOutOfBounds
+	}
+	index, err := searchProcessedTransactions(w.dbTx.Bucket(bucketProcessedTransactions), startHeight)
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(dst)
+	for {
+		pts, next, done, err := w.managedExportBatch(index, endHeight)
+		if err != nil {
+			return err
+		}
+		for _, pt := range pts {
+			if err := enc.Encode(pt); err != nil {
+				return err
+			}
+		}
+		if done {
+			return nil
+		}
+		index = next
+	}
+}
+
+// managedExportBatch reads up to exportBatchSize processed transactions
+// confirmed no later than endHeight, starting at index. It returns the index
+// of the next transaction to read and whether the export is complete.
+func (w *Wallet) managedExportBatch(index uint64, endHeight types.BlockHeight) (pts []modules.ProcessedTransaction, next uint64, done bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	keyBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(keyBytes, index)
+	cursor := w.dbTx.Bucket(bucketProcessedTransactions).Cursor()
+	for key, ptBytes := cursor.Seek(keyBytes); key != nil; key, ptBytes = cursor.Next() {
+		if len(pts) == exportBatchSize {
+			return pts, binary.BigEndian.Uint64(key), false, nil
+		}
+		var pt modules.ProcessedTransaction
+		if err := decodeProcessedTransaction(ptBytes, &pt); err != nil {
+			return nil, 0, false, err
+		}
+		if pt.ConfirmationHeight > endHeight {
+			break
+		}
+		pts = append(pts, pt)
+	}
+	return pts, 0, true, nil
+}
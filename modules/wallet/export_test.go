@@ -0,0 +1,70 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestExportTransactions checks that ExportTransactions writes the same
+// transactions as Transactions returns.
+func TestExportTransactions(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Confirm a few more transactions.
+	if _, err := wt.wallet.SendSiacoins(types.NewCurrency64(5000), types.UnlockHash{}); err != nil {
+		t.Fatal(err)
+	}
+	wt.addBlockNoPayout()
+
+	// export decodes the exported transactions.
+	export := func(start, end types.BlockHeight) []modules.ProcessedTransaction {
+		var buf bytes.Buffer
+		if err := wt.wallet.ExportTransactions(&buf, start, end); err != nil {
+			t.Fatal(err)
+		}
+		var pts []modules.ProcessedTransaction
+		dec := json.NewDecoder(&buf)
+		for dec.More() {
+			var pt modules.ProcessedTransaction
+			if err := dec.Decode(&pt); err != nil {
+				t.Fatal(err)
+			}
+			pts = append(pts, pt)
+		}
+		return pts
+	}
+
+	height := wt.cs.Height()
+	for _, bounds := range [][2]types.BlockHeight{{0, height}, {0, 100}, {types.MaturityDelay + 2, height}, {3, 3}} {
+		txns, err := wt.wallet.Transactions(bounds[0], bounds[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		pts := export(bounds[0], bounds[1])
+		if len(pts) != len(txns) {
+			t.Fatalf("%v: expected %v transactions, got %v", bounds, len(txns), len(pts))
+		}
+		for i := range pts {
+			if pts[i].TransactionID != txns[i].TransactionID || pts[i].ConfirmationHeight != txns[i].ConfirmationHeight {
+				t.Fatalf("%v: transaction %v doesn't match", bounds, i)
+			}
+		}
+	}
+
+	// Invalid bounds should be rejected.
+	if err := wt.wallet.ExportTransactions(new(bytes.Buffer), height+1, height+1); err != errOutOfBounds {
+		t.Fatal("expected errOutOfBounds, got", err)
+	}
+}
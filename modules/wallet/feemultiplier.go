@@ -0,0 +1,67 @@
+package wallet
+
+import (
+	"fmt"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+const (
+	// minFeeMultiplier and maxFeeMultiplier limit the fee multiplier to a
+	// range that protects against accidentally overpaying by orders of
+	// magnitude or underpaying so much that transactions never confirm.
+	minFeeMultiplier = 0.5
+	maxFeeMultiplier = 10
+)
+
+var (
+	// errInvalidFeeMultiplier is returned by SetFeeMultiplier if the
+	// multiplier is outside of [minFeeMultiplier, maxFeeMultiplier].
+	errInvalidFeeMultiplier = fmt.Errorf("fee multiplier must be between %v and %v", minFeeMultiplier, maxFeeMultiplier)
+)
+
+// FeeMultiplier returns the factor that the wallet applies to the fee
+// estimated by the transaction pool when sending siacoins.
+func (w *Wallet) FeeMultiplier() (float64, error) {
+	if err := w.tg.Add(); err != nil {
+		return 0, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbGetFeeMultiplier(w.dbTx)
+}
+
+// SetFeeMultiplier sets the factor that the wallet applies to the fee
+// estimated by the transaction pool when sending siacoins. A multiplier above
+// 1 pays more than the estimate for faster confirmation. The multiplier is
+// persisted in the wallet's database.
+func (w *Wallet) SetFeeMultiplier(multiplier float64) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	if !(multiplier >= minFeeMultiplier && multiplier <= maxFeeMultiplier) {
+		return errInvalidFeeMultiplier
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbPutFeeMultiplier(w.dbTx, multiplier)
+}
+
+// managedSendFeePerByte returns the fee per byte that the wallet pays when
+// sending siacoins, which is the transaction pool's estimate scaled by the
+// fee multiplier.
+func (w *Wallet) managedSendFeePerByte() types.Currency {
+	_, tpoolFee := w.tpool.FeeEstimation()
+	w.mu.Lock()
+	multiplier, err := dbGetFeeMultiplier(w.dbTx)
+	w.mu.Unlock()
+	if err != nil || multiplier == 1 {
+		return tpoolFee
+	}
+	return tpoolFee.MulFloat(multiplier)
+}
@@ -0,0 +1,54 @@
+package wallet
+
+import (
+	"math"
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestFeeMultiplier checks that the fee multiplier is validated and applied
+// to the fees of sent transactions.
+func TestFeeMultiplier(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// By default, the fee estimate is paid as is.
+	if multiplier, err := wt.wallet.FeeMultiplier(); err != nil || multiplier != 1 {
+		t.Fatal("expected a fee multiplier of 1, got", multiplier, err)
+	}
+	fee := wt.wallet.managedMultiSendFee(1)
+
+	for _, multiplier := range []float64{0, minFeeMultiplier / 2, maxFeeMultiplier * 2, math.NaN()} {
+		if err := wt.wallet.SetFeeMultiplier(multiplier); err != errInvalidFeeMultiplier {
+			t.Fatalf("expected errInvalidFeeMultiplier for %v, got %v", multiplier, err)
+		}
+	}
+	if err := wt.wallet.SetFeeMultiplier(2); err != nil {
+		t.Fatal(err)
+	}
+	if multiplier, err := wt.wallet.FeeMultiplier(); err != nil || multiplier != 2 {
+		t.Fatal("expected a fee multiplier of 2, got", multiplier, err)
+	}
+	if newFee := wt.wallet.managedMultiSendFee(1); !newFee.Equals(fee.Mul64(2)) {
+		t.Fatalf("expected fee %v, got %v", fee.Mul64(2), newFee)
+	}
+
+	// The multiplier is applied when sending siacoins.
+	_, tpoolFee := wt.tpool.FeeEstimation()
+	txns, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	minerFees := txns[len(txns)-1].MinerFees
+	if len(minerFees) != 1 || !minerFees[0].Equals(tpoolFee.Mul64(2*750)) {
+		t.Fatalf("expected miner fee %v, got %v", tpoolFee.Mul64(2*750), minerFees)
+	}
+}
@@ -0,0 +1,89 @@
+package wallet
+
+import (
+	"errors"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errOutputLocked indicates an output is not spendable because the user
+	// locked it.
+	errOutputLocked = errors.New("output has been locked by the user")
+
+	// errOutputNotLocked is returned by UnlockOutput if the output is not
+	// locked.
+	errOutputNotLocked = errors.New("output is not locked")
+
+	// errUnknownOutput is returned by LockOutput if the output does not
+	// belong to the wallet.
+	errUnknownOutput = errors.New("output does not belong to the wallet")
+)
+
+// LockOutput locks a siacoin output of the wallet, so that it won't be used to
+// fund transactions until it is unlocked again with UnlockOutput. The output
+// may be confirmed or created by an unconfirmed transaction. Locked outputs
+// are persisted in the wallet's database.
+func (w *Wallet) LockOutput(id types.SiacoinOutputID) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := dbGetSiacoinOutput(w.dbTx, id); err == errNoKey && !w.isUnconfirmedOutput(id) {
+		return errUnknownOutput
+	} else if err != nil && err != errNoKey {
+		return err
+	}
+	return dbPutLockedOutput(w.dbTx, id)
+}
+
+// UnlockOutput unlocks a siacoin output that was locked with LockOutput,
+// allowing it to be used to fund transactions again.
+func (w *Wallet) UnlockOutput(id types.SiacoinOutputID) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !dbGetLockedOutput(w.dbTx, id) {
+		return errOutputNotLocked
+	}
+	return dbDeleteLockedOutput(w.dbTx, id)
+}
+
+// LockedOutputs returns the ids of the siacoin outputs that were locked with
+// LockOutput.
+func (w *Wallet) LockedOutputs() ([]types.SiacoinOutputID, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var ids []types.SiacoinOutputID
+	err := dbForEachLockedOutput(w.dbTx, func(id types.SiacoinOutputID, _ bool) {
+		ids = append(ids, id)
+	})
+	return ids, err
+}
+
+// isUnconfirmedOutput returns true if id is a siacoin output of the wallet
+// that is created by an unconfirmed transaction. The caller must hold the
+// wallet lock.
+func (w *Wallet) isUnconfirmedOutput(id types.SiacoinOutputID) bool {
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, output := range upt.Outputs {
+			if output.FundType == types.SpecifierSiacoinOutput && output.WalletAddress && output.ID == types.OutputID(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package wallet
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestLockOutput checks that locked outputs are not used to fund transactions
+// and that they remain locked after the wallet is reloaded.
+func TestLockOutput(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Unknown outputs can't be locked and unlocked.
+	if err := wt.wallet.LockOutput(types.SiacoinOutputID{}); err != errUnknownOutput {
+		t.Fatal("expected errUnknownOutput, got", err)
+	}
+	if err := wt.wallet.UnlockOutput(types.SiacoinOutputID{}); err != errOutputNotLocked {
+		t.Fatal("expected errOutputNotLocked, got", err)
+	}
+
+	// Lock one of the wallet's outputs.
+	var id types.SiacoinOutputID
+	var output types.SiacoinOutput
+	wt.wallet.mu.Lock()
+	dbForEachSiacoinOutput(wt.wallet.dbTx, func(scoid types.SiacoinOutputID, sco types.SiacoinOutput) {
+		id, output = scoid, sco
+	})
+	wt.wallet.mu.Unlock()
+	if err := wt.wallet.LockOutput(id); err != nil {
+		t.Fatal(err)
+	}
+	if ids, err := wt.wallet.LockedOutputs(); err != nil || len(ids) != 1 || ids[0] != id {
+		t.Fatal("expected the output to be locked, got", ids, err)
+	}
+	wt.wallet.mu.Lock()
+	err = wt.wallet.checkOutput(wt.wallet.dbTx, wt.cs.Height(), id, output, types.ZeroCurrency)
+	wt.wallet.mu.Unlock()
+	if err != errOutputLocked {
+		t.Fatal("expected errOutputLocked, got", err)
+	}
+
+	// The output is still locked after reloading the wallet.
+	if err := wt.wallet.Close(); err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(wt.cs, wt.tpool, filepath.Join(wt.persistDir, modules.WalletDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt.wallet = w
+	if err := wt.wallet.Unlock(wt.walletMasterKey); err != nil {
+		t.Fatal(err)
+	}
+	if ids, err := wt.wallet.LockedOutputs(); err != nil || len(ids) != 1 || ids[0] != id {
+		t.Fatal("expected the output to be locked after reloading, got", ids, err)
+	}
+
+	// Unlock the output.
+	if err := wt.wallet.UnlockOutput(id); err != nil {
+		t.Fatal(err)
+	}
+	if ids, err := wt.wallet.LockedOutputs(); err != nil || len(ids) != 0 {
+		t.Fatal("expected no locked outputs, got", ids, err)
+	}
+	wt.wallet.mu.Lock()
+	err = wt.wallet.checkOutput(wt.wallet.dbTx, wt.cs.Height(), id, output, types.ZeroCurrency)
+	wt.wallet.mu.Unlock()
+	if err == errOutputLocked {
+		t.Fatal("output is still locked")
+	}
+}
@@ -2,12 +2,50 @@ package wallet
 
 import (
 	"errors"
+	"fmt"
 
 	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/encoding"
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/types"
 )
 
+var (
+	// errSinceHeightTooHigh is returned by ConfirmedBalanceDelta if the
+	// provided height is above the wallet's current height.
+	errSinceHeightTooHigh = errors.New("height is above the wallet's current height")
+
+	// errHeightTooHigh is returned by ConfirmedBalanceAt if the provided
+	// height is above the wallet's current height.
+	errHeightTooHigh = errors.New("height is above the wallet's current height")
+
+	// errHeightBeforeHistory is returned by ConfirmedBalanceAt if the
+	// provided height predates the wallet's transaction history, e.g. because
+	// the history is still being rebuilt after keys were imported.
+	errHeightBeforeHistory = errors.New("height predates the wallet's transaction history")
+
+	// errInconsistentBalance is returned by ConfirmedBalanceAt if the
+	// processed transactions spend more than the wallet received.
+	errInconsistentBalance = errors.New("processed transactions spend more than the wallet received")
+
+	// errArbitraryDataTooLarge is returned by SendSiacoinsArbitraryData if the
+	// data exceeds maxArbitraryDataSize.
+	errArbitraryDataTooLarge = fmt.Errorf("arbitrary data must not be larger than %v bytes", maxArbitraryDataSize)
+
+	// errTargetBalanceReached is returned by TopUpAddress if the balance of
+	// the address already meets the target.
+	errTargetBalanceReached = errors.New("address balance already meets the target")
+
+	// errNothingToSend is returned by SendAllSiacoins if the spendable
+	// outputs of the wallet can't cover the fee of sending them.
+	errNothingToSend = errors.New("spendable balance does not cover the transaction fee")
+
+	// errTooManyOutputsToSend is returned by SendAllSiacoins if the wallet's
+	// spendable outputs don't fit into a single transaction.
+	errTooManyOutputsToSend = errors.New("wallet has too many outputs to send them in a single transaction, defrag it first")
+)
+
 // sortedOutputs is a struct containing a slice of siacoin outputs and their
 // corresponding ids. sortedOutputs can be sorted using the sort package.
 type sortedOutputs struct {
@@ -27,6 +65,35 @@ func (w *Wallet) DustThreshold() (types.Currency, error) {
 	return minFee.Mul64(3), nil
 }
 
+// ChangeThreshold returns the value below which the change of a transaction is
+// added to its miner fees instead of being refunded to the wallet. A zero
+// threshold means that all change is refunded.
+func (w *Wallet) ChangeThreshold() (types.Currency, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.Currency{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbGetChangeThreshold(w.dbTx)
+}
+
+// SetChangeThreshold sets the value below which the change of a transaction
+// is added to its miner fees instead of being refunded to the wallet. This
+// avoids creating outputs that cost more to spend than they are worth. The
+// threshold is persisted in the wallet's database.
+func (w *Wallet) SetChangeThreshold(threshold types.Currency) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbPutChangeThreshold(w.dbTx, threshold)
+}
+
 // ConfirmedBalance returns the balance of the wallet according to all of the
 // confirmed transactions.
 func (w *Wallet) ConfirmedBalance() (siacoinBalance types.Currency, siafundBalance types.Currency, siafundClaimBalance types.Currency, err error) {
@@ -72,6 +139,191 @@ func (w *Wallet) ConfirmedBalance() (siacoinBalance types.Currency, siafundBalan
 	return
 }
 
+// ConfirmedSiacoinBalanceMinConf returns the siacoin balance of the wallet,
+// counting only outputs that have at least minConf confirmations towards the
+// confirmed balance. The value of outputs with fewer confirmations is returned
+// as the pending balance. An output that was created in the most recent block
+// has one confirmation.
+func (w *Wallet) ConfirmedSiacoinBalanceMinConf(minConf types.BlockHeight) (confirmed types.Currency, pending types.Currency, err error) {
+	if err := w.tg.Add(); err != nil {
+		return types.ZeroCurrency, types.ZeroCurrency, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	// dustThreshold has to be obtained separate from the lock
+	dustThreshold, err := w.DustThreshold()
+	if err != nil {
+		return types.ZeroCurrency, types.ZeroCurrency, modules.ErrWalletShutdown
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	consensusHeight, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return
+	}
+
+	// Collect the wallet's outputs that have fewer than minConf
+	// confirmations. The transactions are visited from the most recent one,
+	// so only the transactions of the last minConf blocks are read.
+	recentOutputs := make(map[types.OutputID]struct{})
+	err = dbForEachProcessedTransactionReverse(w.dbTx, func(pt modules.ProcessedTransaction) bool {
+		// Compare the number of confirmations instead of adding minConf
+		// to the confirmation height, which could overflow.
+		if pt.ConfirmationHeight <= consensusHeight && consensusHeight+1-pt.ConfirmationHeight >= minConf {
+			return false
+		}
+		for _, output := range pt.Outputs {
+			if output.WalletAddress {
+				recentOutputs[output.ID] = struct{}{}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return types.ZeroCurrency, types.ZeroCurrency, err
+	}
+
+	dbForEachSiacoinOutput(w.dbTx, func(scoid types.SiacoinOutputID, sco types.SiacoinOutput) {
+		if sco.Value.Cmp(dustThreshold) <= 0 {
+			return
+		}
+		if _, recent := recentOutputs[types.OutputID(scoid)]; recent {
+			pending = pending.Add(sco.Value)
+		} else {
+			confirmed = confirmed.Add(sco.Value)
+		}
+	})
+	return
+}
+
+// ConfirmedBalanceDelta returns how the confirmed balance of the wallet
+// changed between sinceHeight and the current height. Outputs count towards
+// the height at which they matured, so miner payouts and siafund claims are
+// only included once they can be spent. Only the wallet's processed
+// transactions of the affected heights are read.
+func (w *Wallet) ConfirmedBalanceDelta(sinceHeight types.BlockHeight) (delta modules.WalletBalanceDelta, err error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.WalletBalanceDelta{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	consensusHeight, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return modules.WalletBalanceDelta{}, err
+	}
+	if sinceHeight > consensusHeight {
+		return modules.WalletBalanceDelta{}, errSinceHeightTooHigh
+	}
+	delta.Height = consensusHeight
+
+	// Delayed outputs mature MaturityDelay blocks after they were confirmed,
+	// so transactions confirmed up to MaturityDelay blocks before sinceHeight
+	// need to be considered as well.
+	var oldestHeight types.BlockHeight
+	if sinceHeight > types.MaturityDelay {
+		oldestHeight = sinceHeight - types.MaturityDelay
+	}
+	err = dbForEachProcessedTransactionReverse(w.dbTx, func(pt modules.ProcessedTransaction) bool {
+		if pt.ConfirmationHeight <= oldestHeight {
+			return false
+		}
+		if pt.ConfirmationHeight > sinceHeight {
+			for _, input := range pt.Inputs {
+				if !input.WalletAddress {
+					continue
+				}
+				if input.FundType == types.SpecifierSiacoinInput {
+					delta.SiacoinsOutgoing = delta.SiacoinsOutgoing.Add(input.Value)
+				} else if input.FundType == types.SpecifierSiafundInput {
+					delta.SiafundsOutgoing = delta.SiafundsOutgoing.Add(input.Value)
+				}
+			}
+		}
+		for _, output := range pt.Outputs {
+			if !output.WalletAddress || output.MaturityHeight <= sinceHeight || output.MaturityHeight > consensusHeight {
+				continue
+			}
+			switch output.FundType {
+			case types.SpecifierSiacoinOutput, types.SpecifierMinerPayout, types.SpecifierClaimOutput:
+				delta.SiacoinsIncoming = delta.SiacoinsIncoming.Add(output.Value)
+			case types.SpecifierSiafundOutput:
+				delta.SiafundsIncoming = delta.SiafundsIncoming.Add(output.Value)
+			}
+		}
+		return true
+	})
+	return delta, err
+}
+
+// ConfirmedBalanceAt reconstructs the confirmed balance of the wallet as of
+// height from its processed transactions. Like ConfirmedBalanceDelta, outputs
+// count towards the height at which they matured. Only heights between the
+// start of the wallet's transaction history and the wallet's current height
+// can be queried.
+func (w *Wallet) ConfirmedBalanceAt(height types.BlockHeight) (siacoinBalance types.Currency, siafundBalance types.Currency, err error) {
+	if err := w.tg.Add(); err != nil {
+		return types.ZeroCurrency, types.ZeroCurrency, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	consensusHeight, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return types.ZeroCurrency, types.ZeroCurrency, err
+	}
+	if height > consensusHeight {
+		return types.ZeroCurrency, types.ZeroCurrency, errHeightTooHigh
+	}
+	startHeight, err := dbGetHistoryStartHeight(w.dbTx)
+	if err == errNoKey || (err == nil && height < startHeight) {
+		return types.ZeroCurrency, types.ZeroCurrency, errHeightBeforeHistory
+	} else if err != nil {
+		return types.ZeroCurrency, types.ZeroCurrency, err
+	}
+
+	var siacoinsOutgoing, siafundsOutgoing types.Currency
+	err = dbForEachProcessedTransactionReverse(w.dbTx, func(pt modules.ProcessedTransaction) bool {
+		if pt.ConfirmationHeight <= height {
+			for _, input := range pt.Inputs {
+				if !input.WalletAddress {
+					continue
+				}
+				if input.FundType == types.SpecifierSiacoinInput {
+					siacoinsOutgoing = siacoinsOutgoing.Add(input.Value)
+				} else if input.FundType == types.SpecifierSiafundInput {
+					siafundsOutgoing = siafundsOutgoing.Add(input.Value)
+				}
+			}
+		}
+		for _, output := range pt.Outputs {
+			if !output.WalletAddress || output.MaturityHeight > height {
+				continue
+			}
+			switch output.FundType {
+			case types.SpecifierSiacoinOutput, types.SpecifierMinerPayout, types.SpecifierClaimOutput:
+				siacoinBalance = siacoinBalance.Add(output.Value)
+			case types.SpecifierSiafundOutput:
+				siafundBalance = siafundBalance.Add(output.Value)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return types.ZeroCurrency, types.ZeroCurrency, err
+	}
+	if siacoinBalance.Cmp(siacoinsOutgoing) < 0 || siafundBalance.Cmp(siafundsOutgoing) < 0 {
+		return types.ZeroCurrency, types.ZeroCurrency, errInconsistentBalance
+	}
+	return siacoinBalance.Sub(siacoinsOutgoing), siafundBalance.Sub(siafundsOutgoing), nil
+}
+
 // UnconfirmedBalance returns the number of outgoing and incoming siacoins in
 // the unconfirmed transaction set. Refund outputs are included in this
 // reporting.
@@ -113,7 +365,225 @@ func (w *Wallet) SendSiacoins(amount types.Currency, dest types.UnlockHash) (txn
 		return nil, err
 	}
 	defer w.tg.Done()
+	return w.managedSendSiacoins(amount, dest, nil, false, false, nil)
+}
+
+// PreviewSendSiacoins builds and signs the transaction set that SendSiacoins
+// would create for sending 'amount' to 'dest', but doesn't submit it to the
+// transaction pool. The outputs selected to fund the transaction are released
+// again, so they can be spent by other transactions.
+func (w *Wallet) PreviewSendSiacoins(amount types.Currency, dest types.UnlockHash) (txns []types.Transaction, err error) {
+	if err := w.tg.Add(); err != nil {
+		err = modules.ErrWalletShutdown
+		return nil, err
+	}
+	defer w.tg.Done()
+	return w.managedSendSiacoins(amount, dest, nil, true, false, nil)
+}
+
+// SendSiacoinsArbitraryData creates a transaction sending 'amount' to 'dest'
+// that carries 'data' in its arbitrary data. The data is prefixed with
+// modules.PrefixNonSia so that the transaction is relayed by the network. The
+// transaction is submitted to the transaction pool and is also returned.
+func (w *Wallet) SendSiacoinsArbitraryData(amount types.Currency, dest types.UnlockHash, data []byte) (txns []types.Transaction, err error) {
+	if err := w.tg.Add(); err != nil {
+		err = modules.ErrWalletShutdown
+		return nil, err
+	}
+	defer w.tg.Done()
+	if len(data) > maxArbitraryDataSize {
+		return nil, errArbitraryDataTooLarge
+	}
+	return w.managedSendSiacoins(amount, dest, append(modules.PrefixNonSia[:], data...), false, false, nil)
+}
+
+// SendSiacoinsExcluding creates a transaction sending 'amount' to 'dest' like
+// SendSiacoins, but never uses the outputs in 'exclude' to fund it. This allows
+// earmarking outputs for later use without moving them.
+func (w *Wallet) SendSiacoinsExcluding(amount types.Currency, dest types.UnlockHash, exclude []types.SiacoinOutputID) (txns []types.Transaction, err error) {
+	if err := w.tg.Add(); err != nil {
+		err = modules.ErrWalletShutdown
+		return nil, err
+	}
+	defer w.tg.Done()
+	return w.managedSendSiacoins(amount, dest, nil, false, false, exclude)
+}
 
+// TopUpAddress sends the siacoins that are needed to bring the confirmed
+// balance of 'dest' up to 'target'. 'dest' must be an address of the wallet.
+// The outputs of 'dest' are not used to fund the transaction, so the fees are
+// paid by the rest of the wallet. The amount that was sent is returned
+// together with the transaction set.
+func (w *Wallet) TopUpAddress(dest types.UnlockHash, target types.Currency) (amount types.Currency, txns []types.Transaction, err error) {
+	if err := w.tg.Add(); err != nil {
+		return types.ZeroCurrency, nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	unlocked := w.unlocked
+	_, tracked := w.keys[dest]
+	var balance types.Currency
+	err = dbForEachSiacoinOutput(w.dbTx, func(_ types.SiacoinOutputID, sco types.SiacoinOutput) {
+		if sco.UnlockHash == dest {
+			balance = balance.Add(sco.Value)
+		}
+	})
+	w.mu.Unlock()
+	if !unlocked {
+		return types.ZeroCurrency, nil, modules.ErrLockedWallet
+	} else if !tracked {
+		return types.ZeroCurrency, nil, errUnknownAddress
+	} else if err != nil {
+		return types.ZeroCurrency, nil, err
+	} else if balance.Cmp(target) >= 0 {
+		return types.ZeroCurrency, nil, errTargetBalanceReached
+	}
+
+	amount = target.Sub(balance)
+	txns, err = w.managedSendSiacoins(amount, dest, nil, false, true, nil)
+	if err != nil {
+		return types.ZeroCurrency, nil, err
+	}
+	return amount, txns, nil
+}
+
+// SendAllSiacoins sends every spendable output of the wallet to dest in a
+// single transaction. The fee is deducted from the sent amount, so no change
+// is left behind. Outputs that are still timelocked, dust, or spent by
+// unconfirmed transactions are not used. The amount that was sent is returned
+// together with the transaction set.
+func (w *Wallet) SendAllSiacoins(dest types.UnlockHash) (amount types.Currency, txns []types.Transaction, err error) {
+	if err := w.tg.Add(); err != nil {
+		return types.ZeroCurrency, nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	// dustThreshold and tpoolFee have to be obtained separate from the lock
+	dustThreshold, err := w.DustThreshold()
+	if err != nil {
+		return types.ZeroCurrency, nil, err
+	}
+	tpoolFee := w.managedSendFeePerByte()
+
+	w.mu.Lock()
+	txn, amount, err := w.createSendAllTransaction(dest, dustThreshold, tpoolFee)
+	w.mu.Unlock()
+	if err != nil {
+		w.log.Println("Attempt to send all coins has failed:", err)
+		return types.ZeroCurrency, nil, err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for _, sci := range txn.SiacoinInputs {
+			dbDeleteSpentOutput(w.dbTx, types.OutputID(sci.ParentID))
+		}
+	}()
+
+	txns = []types.Transaction{txn}
+	if err = w.tpool.AcceptTransactionSet(txns); err != nil {
+		w.log.Println("Attempt to send all coins has failed - transaction pool rejected transaction:", err)
+		return types.ZeroCurrency, nil, build.ExtendErr("unable to get transaction accepted", err)
+	}
+	w.log.Printf("Sent all %v to %v, txid: %v", amount.HumanString(), dest, txn.ID())
+	return amount, txns, nil
+}
+
+// createSendAllTransaction creates a signed transaction that spends all of the
+// spendable outputs of the wallet to dest, minus the fee, and marks the outputs
+// as spent. The caller must hold the wallet lock.
+func (w *Wallet) createSendAllTransaction(dest types.UnlockHash, dustThreshold, tpoolFee types.Currency) (types.Transaction, types.Currency, error) {
+	if !w.unlocked {
+		return types.Transaction{}, types.ZeroCurrency, modules.ErrLockedWallet
+	}
+	if w.rescanning {
+		return types.Transaction{}, types.ZeroCurrency, errRescanning
+	}
+	consensusHeight, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return types.Transaction{}, types.ZeroCurrency, err
+	}
+
+	// Collect the outputs that are spent by pending transactions.
+	pending := make(map[types.SiacoinOutputID]struct{})
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, sci := range upt.Transaction.SiacoinInputs {
+			pending[sci.ParentID] = struct{}{}
+		}
+	}
+
+	var txn types.Transaction
+	var fund types.Currency
+	err = dbForEachSiacoinOutput(w.dbTx, func(scoid types.SiacoinOutputID, sco types.SiacoinOutput) {
+		if _, exists := pending[scoid]; exists {
+			return
+		}
+		if w.checkOutput(w.dbTx, consensusHeight, scoid, sco, dustThreshold) != nil {
+			return
+		}
+		txn.SiacoinInputs = append(txn.SiacoinInputs, types.SiacoinInput{
+			ParentID:         scoid,
+			UnlockConditions: w.keys[sco.UnlockHash].UnlockConditions,
+		})
+		fund = fund.Add(sco.Value)
+	})
+	if err != nil {
+		return types.Transaction{}, types.ZeroCurrency, err
+	}
+
+	if len(txn.SiacoinInputs) == 0 {
+		return types.Transaction{}, types.ZeroCurrency, errNothingToSend
+	}
+
+	// Estimate the size of the signed transaction using placeholder
+	// signatures, and compute the fee from it.
+	txn.SiacoinOutputs = []types.SiacoinOutput{{Value: fund, UnlockHash: dest}}
+	txn.MinerFees = []types.Currency{fund}
+	estimate := txn
+	for _, sci := range estimate.SiacoinInputs {
+		estimate.TransactionSignatures = append(estimate.TransactionSignatures, types.TransactionSignature{
+			ParentID:      crypto.Hash(sci.ParentID),
+			CoveredFields: types.FullCoveredFields,
+			Signature:     make([]byte, crypto.SignatureSize),
+		})
+	}
+	size := uint64(len(encoding.Marshal(estimate)))
+	if size > modules.TransactionSizeLimit {
+		return types.Transaction{}, types.ZeroCurrency, errTooManyOutputsToSend
+	}
+	fee := tpoolFee.Mul64(size)
+	if fund.Cmp(fee) <= 0 {
+		return types.Transaction{}, types.ZeroCurrency, errNothingToSend
+	}
+	amount := fund.Sub(fee)
+	txn.SiacoinOutputs[0].Value = amount
+	txn.MinerFees[0] = fee
+
+	// Sign all of the inputs.
+	for _, sci := range txn.SiacoinInputs {
+		addSignatures(&txn, types.FullCoveredFields, sci.UnlockConditions, crypto.Hash(sci.ParentID), w.keys[sci.UnlockConditions.UnlockHash()])
+	}
+
+	// Mark all outputs that were spent as spent.
+	for _, sci := range txn.SiacoinInputs {
+		if err := dbPutSpentOutput(w.dbTx, types.OutputID(sci.ParentID), consensusHeight); err != nil {
+			return types.Transaction{}, types.ZeroCurrency, err
+		}
+	}
+	return txn, amount, nil
+}
+
+// managedSendSiacoins creates a transaction sending 'amount' to 'dest' and
+// submits it to the transaction pool. If arbData is not nil, it is added to
+// the arbitrary data of the transaction. If preview is set, the transaction is
+// not submitted and its inputs are released. If excludeDest is set, outputs
+// that already belong to dest are not used to fund the transaction, and neither
+// are the outputs in excludeOutputs.
+func (w *Wallet) managedSendSiacoins(amount types.Currency, dest types.UnlockHash, arbData []byte, preview, excludeDest bool, excludeOutputs []types.SiacoinOutputID) (txns []types.Transaction, err error) {
 	w.mu.RLock()
 	unlocked := w.unlocked
 	w.mu.RUnlock()
@@ -122,16 +592,23 @@ func (w *Wallet) SendSiacoins(amount types.Currency, dest types.UnlockHash) (txn
 		return nil, modules.ErrLockedWallet
 	}
 
-	_, tpoolFee := w.tpool.FeeEstimation()
-	tpoolFee = tpoolFee.Mul64(750) // Estimated transaction size in bytes
+	tpoolFee := w.managedSendFee(uint64(len(arbData)))
 	output := types.SiacoinOutput{
 		Value:      amount,
 		UnlockHash: dest,
 	}
 
-	txnBuilder, err := w.StartTransaction()
-	if err != nil {
-		return nil, err
+	w.mu.Lock()
+	txnBuilder := w.registerTransaction(types.Transaction{}, nil)
+	w.mu.Unlock()
+	if excludeDest {
+		txnBuilder.excludedAddresses = map[types.UnlockHash]struct{}{dest: {}}
+	}
+	if len(excludeOutputs) > 0 {
+		txnBuilder.excludedOutputs = make(map[types.SiacoinOutputID]struct{}, len(excludeOutputs))
+		for _, id := range excludeOutputs {
+			txnBuilder.excludedOutputs[id] = struct{}{}
+		}
 	}
 	defer func() {
 		if err != nil {
@@ -145,11 +622,19 @@ func (w *Wallet) SendSiacoins(amount types.Currency, dest types.UnlockHash) (txn
 	}
 	txnBuilder.AddMinerFee(tpoolFee)
 	txnBuilder.AddSiacoinOutput(output)
+	if arbData != nil {
+		txnBuilder.AddArbitraryData(arbData)
+	}
 	txnSet, err := txnBuilder.Sign(true)
 	if err != nil {
 		w.log.Println("Attempt to send coins has failed - failed to sign transaction:", err)
 		return nil, build.ExtendErr("unable to sign transaction", err)
 	}
+	if preview {
+		// Nothing is broadcast, so the inputs can be spent again right away.
+		txnBuilder.Drop()
+		return txnSet, nil
+	}
 	if w.deps.Disrupt("SendSiacoinsInterrupted") {
 		return nil, errors.New("failed to accept transaction set (SendSiacoinsInterrupted)")
 	}
@@ -165,6 +650,189 @@ func (w *Wallet) SendSiacoins(amount types.Currency, dest types.UnlockHash) (txn
 	return txnSet, nil
 }
 
+// SendSiacoinsTimelocked creates a transaction sending 'amount' to the address
+// of 'uc'. The coins can't be spent before the block height given by the
+// Timelock of 'uc', which must be in the future. The transaction is submitted
+// to the transaction pool and is also returned.
+func (w *Wallet) SendSiacoinsTimelocked(amount types.Currency, uc types.UnlockConditions) (txns []types.Transaction, err error) {
+	if err := w.tg.Add(); err != nil {
+		err = modules.ErrWalletShutdown
+		return nil, err
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	consensusHeight, err := dbGetConsensusHeight(w.dbTx)
+	w.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if uc.Timelock <= consensusHeight {
+		return nil, fmt.Errorf("timelock %v must be greater than the current block height %v", uc.Timelock, consensusHeight)
+	}
+	return w.SendSiacoins(amount, uc.UnlockHash())
+}
+
+// managedSendFee returns the miner fee that SendSiacoins adds to a transaction
+// carrying dataLen bytes of arbitrary data.
+func (w *Wallet) managedSendFee(dataLen uint64) types.Currency {
+	tpoolFee := w.managedSendFeePerByte()
+	return tpoolFee.Mul64(750 + dataLen) // Estimated transaction size in bytes
+}
+
+// managedMultiSendFee returns the miner fee that SendSiacoinsMulti adds to a
+// transaction with numOutputs outputs.
+func (w *Wallet) managedMultiSendFee(numOutputs uint64) types.Currency {
+	tpoolFee := w.managedSendFeePerByte()
+	tpoolFee = tpoolFee.Mul64(2)                // We don't want send-to-many transactions to fail.
+	return tpoolFee.Mul64(1000 + 60*numOutputs) // Estimated transaction size in bytes
+}
+
+// EstimateSiacoinsMultiFee estimates the fee and the size of the transaction
+// set that SendSiacoinsMulti would create for numOutputs outputs of the given
+// value. The wallet's outputs are selected the same way as when sending, but
+// no transaction is created and no outputs are marked as spent.
+func (w *Wallet) EstimateSiacoinsMultiFee(numOutputs uint64, value types.Currency) (size uint64, fee types.Currency, err error) {
+	if err := w.tg.Add(); err != nil {
+		return 0, types.ZeroCurrency, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	if numOutputs == 0 {
+		return 0, types.ZeroCurrency, errors.New("no outputs were supplied")
+	}
+
+	// dustThreshold has to be obtained separate from the lock
+	dustThreshold, err := w.DustThreshold()
+	if err != nil {
+		return 0, types.ZeroCurrency, err
+	}
+	fee = w.managedMultiSendFee(numOutputs)
+	totalCost := value.Mul64(numOutputs).Add(fee)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return 0, types.ZeroCurrency, modules.ErrLockedWallet
+	}
+	if w.rescanning {
+		return 0, types.ZeroCurrency, errRescanning
+	}
+	consensusHeight, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return 0, types.ZeroCurrency, err
+	}
+	so, err := w.sortedSiacoinOutputs()
+	if err != nil {
+		return 0, types.ZeroCurrency, err
+	}
+
+	// Select outputs like FundSiacoins does and add them to a parent
+	// transaction that mirrors the one created by FundSiacoins.
+	var fund, potentialFund types.Currency
+	var parentTxn types.Transaction
+	for i := range so.ids {
+		if err := w.checkOutput(w.dbTx, consensusHeight, so.ids[i], so.outputs[i], dustThreshold); err != nil {
+			if err == errSpendHeightTooHigh {
+				potentialFund = potentialFund.Add(so.outputs[i].Value)
+			}
+			continue
+		}
+		parentTxn.SiacoinInputs = append(parentTxn.SiacoinInputs, types.SiacoinInput{
+			ParentID:         so.ids[i],
+			UnlockConditions: w.keys[so.outputs[i].UnlockHash].UnlockConditions,
+		})
+		fund = fund.Add(so.outputs[i].Value)
+		potentialFund = potentialFund.Add(so.outputs[i].Value)
+		if fund.Cmp(totalCost) >= 0 {
+			break
+		}
+	}
+	if potentialFund.Cmp(totalCost) >= 0 && fund.Cmp(totalCost) < 0 {
+		return 0, types.ZeroCurrency, modules.ErrIncompleteTransactions
+	}
+	if fund.Cmp(totalCost) < 0 {
+		return 0, types.ZeroCurrency, modules.ErrLowBalance
+	}
+	parentTxn.SiacoinOutputs = append(parentTxn.SiacoinOutputs, types.SiacoinOutput{Value: totalCost})
+	if !fund.Equals(totalCost) {
+		parentTxn.SiacoinOutputs = append(parentTxn.SiacoinOutputs, types.SiacoinOutput{Value: fund.Sub(totalCost)})
+	}
+
+	// The transaction itself spends the exact output of the parent.
+	txn := types.Transaction{
+		SiacoinInputs: []types.SiacoinInput{parentTxn.SiacoinInputs[0]},
+		MinerFees:     []types.Currency{fee},
+	}
+	for i := uint64(0); i < numOutputs; i++ {
+		txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{Value: value})
+	}
+
+	// Add a placeholder signature for every input.
+	for _, t := range []*types.Transaction{&parentTxn, &txn} {
+		for _, sci := range t.SiacoinInputs {
+			t.TransactionSignatures = append(t.TransactionSignatures, types.TransactionSignature{
+				ParentID:      crypto.Hash(sci.ParentID),
+				CoveredFields: types.FullCoveredFields,
+				Signature:     make([]byte, crypto.SignatureSize),
+			})
+		}
+	}
+	size = uint64(len(encoding.Marshal(parentTxn)) + len(encoding.Marshal(txn)))
+	return size, fee, nil
+}
+
+// CanSendSiacoins reports whether the wallet's spendable outputs can currently
+// fund sending amount in total to numOutputs outputs, including the fee that
+// SendSiacoinsMulti would add. If they can't, the missing amount is returned
+// as well. No inputs are reserved and no transaction is built.
+func (w *Wallet) CanSendSiacoins(amount types.Currency, numOutputs uint64) (canSend bool, shortfall, fee types.Currency, err error) {
+	if err := w.tg.Add(); err != nil {
+		return false, types.ZeroCurrency, types.ZeroCurrency, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	if numOutputs == 0 {
+		return false, types.ZeroCurrency, types.ZeroCurrency, errors.New("no outputs were supplied")
+	}
+
+	// dustThreshold has to be obtained separate from the lock
+	dustThreshold, err := w.DustThreshold()
+	if err != nil {
+		return false, types.ZeroCurrency, types.ZeroCurrency, err
+	}
+	fee = w.managedMultiSendFee(numOutputs)
+	totalCost := amount.Add(fee)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.unlocked {
+		return false, types.ZeroCurrency, types.ZeroCurrency, modules.ErrLockedWallet
+	}
+	if w.rescanning {
+		return false, types.ZeroCurrency, types.ZeroCurrency, errRescanning
+	}
+	consensusHeight, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return false, types.ZeroCurrency, types.ZeroCurrency, err
+	}
+	so, err := w.sortedSiacoinOutputs()
+	if err != nil {
+		return false, types.ZeroCurrency, types.ZeroCurrency, err
+	}
+
+	// Sum up the outputs that FundSiacoins would be allowed to spend.
+	var fund types.Currency
+	for i := range so.ids {
+		if err := w.checkOutput(w.dbTx, consensusHeight, so.ids[i], so.outputs[i], dustThreshold); err != nil {
+			continue
+		}
+		fund = fund.Add(so.outputs[i].Value)
+		if fund.Cmp(totalCost) >= 0 {
+			return true, types.ZeroCurrency, fee, nil
+		}
+	}
+	return false, totalCost.Sub(fund), fee, nil
+}
+
 // SendSiacoinsMulti creates a transaction that includes the specified
 // outputs. The transaction is submitted to the transaction pool and is also
 // returned.
@@ -194,9 +862,7 @@ func (w *Wallet) SendSiacoinsMulti(outputs []types.SiacoinOutput) (txns []types.
 	}()
 
 	// Add estimated transaction fee.
-	_, tpoolFee := w.tpool.FeeEstimation()
-	tpoolFee = tpoolFee.Mul64(2)                              // We don't want send-to-many transactions to fail.
-	tpoolFee = tpoolFee.Mul64(1000 + 60*uint64(len(outputs))) // Estimated transaction size in bytes
+	tpoolFee := w.managedMultiSendFee(uint64(len(outputs)))
 	txnBuilder.AddMinerFee(tpoolFee)
 
 	// Calculate total cost to wallet.
@@ -244,6 +910,17 @@ func (w *Wallet) SendSiacoinsMulti(outputs []types.SiacoinOutput) (txns []types.
 // SendSiafunds creates a transaction sending 'amount' to 'dest'. The transaction
 // is submitted to the transaction pool and is also returned.
 func (w *Wallet) SendSiafunds(amount types.Currency, dest types.UnlockHash) (txns []types.Transaction, err error) {
+	return w.SendSiafundsMulti([]types.SiafundOutput{{
+		Value:      amount,
+		UnlockHash: dest,
+	}})
+}
+
+// SendSiafundsMulti creates a transaction that includes the specified
+// outputs. The siacoin claims of the spent siafunds are sent back to the
+// wallet. The transaction is submitted to the transaction pool and is also
+// returned.
+func (w *Wallet) SendSiafundsMulti(outputs []types.SiafundOutput) (txns []types.Transaction, err error) {
 	if err := w.tg.Add(); err != nil {
 		err = modules.ErrWalletShutdown
 		return nil, err
@@ -255,13 +932,18 @@ func (w *Wallet) SendSiafunds(amount types.Currency, dest types.UnlockHash) (txn
 	if !unlocked {
 		return nil, modules.ErrLockedWallet
 	}
+	if len(outputs) == 0 {
+		return nil, errors.New("no outputs were supplied")
+	}
 
 	_, tpoolFee := w.tpool.FeeEstimation()
-	tpoolFee = tpoolFee.Mul64(750) // Estimated transaction size in bytes
-	tpoolFee = tpoolFee.Mul64(5)   // use large fee to ensure siafund transactions are selected by miners
-	output := types.SiafundOutput{
-		Value:      amount,
-		UnlockHash: dest,
+	tpoolFee = tpoolFee.Mul64(750 + 60*uint64(len(outputs)-1)) // Estimated transaction size in bytes; each extra output adds ~60 bytes
+	tpoolFee = tpoolFee.Mul64(5)                               // use large fee to ensure siafund transactions are selected by miners
+
+	// Calculate the total amount of siafunds sent.
+	var amount types.Currency
+	for _, sfo := range outputs {
+		amount = amount.Add(sfo.Value)
 	}
 
 	txnBuilder, err := w.StartTransaction()
@@ -282,7 +964,9 @@ func (w *Wallet) SendSiafunds(amount types.Currency, dest types.UnlockHash) (txn
 		return nil, err
 	}
 	txnBuilder.AddMinerFee(tpoolFee)
-	txnBuilder.AddSiafundOutput(output)
+	for _, sfo := range outputs {
+		txnBuilder.AddSiafundOutput(sfo)
+	}
 	txnSet, err := txnBuilder.Sign(true)
 	if err != nil {
 		return nil, err
@@ -291,7 +975,7 @@ func (w *Wallet) SendSiafunds(amount types.Currency, dest types.UnlockHash) (txn
 	if err != nil {
 		return nil, err
 	}
-	w.log.Println("Submitted a siafund transfer transaction set for value", amount.HumanString(), "with fees", tpoolFee.HumanString(), "IDs:")
+	w.log.Println("Submitted a siafund transfer transaction set for value", amount.HumanString(), "to", len(outputs), "outputs with fees", tpoolFee.HumanString(), "IDs:")
 	for _, txn := range txnSet {
 		w.log.Println("\t", txn.ID())
 	}
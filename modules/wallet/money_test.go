@@ -1,9 +1,12 @@
 package wallet
 
 import (
+	"bytes"
 	"sort"
+	"strings"
 	"testing"
 
+	"gitlab.com/NebulousLabs/Sia/crypto"
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/types"
 )
@@ -89,6 +92,659 @@ func TestSendSiacoins(t *testing.T) {
 	}
 }
 
+// TestConfirmedSiacoinBalanceMinConf checks that outputs with fewer than
+// minConf confirmations are reported as pending.
+func TestConfirmedSiacoinBalanceMinConf(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	balance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a minimum, all outputs are confirmed.
+	confirmed, pending, err := wt.wallet.ConfirmedSiacoinBalanceMinConf(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !confirmed.Equals(balance) || !pending.IsZero() {
+		t.Fatalf("expected %v confirmed and 0 pending, got %v and %v", balance, confirmed, pending)
+	}
+
+	// No output is buried deep enough for an excessive minimum, even if the
+	// minimum would overflow the confirmation height.
+	for _, minConf := range []types.BlockHeight{1e6, ^types.BlockHeight(0)} {
+		confirmed, pending, err = wt.wallet.ConfirmedSiacoinBalanceMinConf(minConf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !confirmed.IsZero() || !pending.Equals(balance) {
+			t.Fatalf("minConf %v: expected 0 confirmed and %v pending, got %v and %v", minConf, balance, confirmed, pending)
+		}
+	}
+
+	// Send coins to the wallet and confirm them. The new outputs have a
+	// single confirmation.
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, uc.UnlockHash()); err != nil {
+		t.Fatal(err)
+	}
+	wt.addBlockNoPayout()
+	balance, _, _, err = wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirmed, pending, err = wt.wallet.ConfirmedSiacoinBalanceMinConf(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !confirmed.Equals(balance) || !pending.IsZero() {
+		t.Fatalf("expected %v confirmed and 0 pending, got %v and %v", balance, confirmed, pending)
+	}
+	confirmed, pending, err = wt.wallet.ConfirmedSiacoinBalanceMinConf(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Cmp(types.SiacoinPrecision) < 0 {
+		t.Fatalf("expected at least %v pending, got %v", types.SiacoinPrecision, pending)
+	}
+	if !confirmed.Add(pending).Equals(balance) {
+		t.Fatalf("confirmed %v and pending %v don't add up to %v", confirmed, pending, balance)
+	}
+}
+
+// TestConfirmedBalanceDelta probes the ConfirmedBalanceDelta method of the
+// wallet.
+func TestConfirmedBalanceDelta(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	startHeight, err := wt.wallet.Height()
+	if err != nil {
+		t.Fatal(err)
+	}
+	startBalance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing changed since the current height.
+	delta, err := wt.wallet.ConfirmedBalanceDelta(startHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta.Height != startHeight || !delta.SiacoinsIncoming.IsZero() || !delta.SiacoinsOutgoing.IsZero() {
+		t.Fatal("expected an empty delta, got", delta)
+	}
+	if _, err := wt.wallet.ConfirmedBalanceDelta(startHeight + 1); err != errSinceHeightTooHigh {
+		t.Fatal("expected errSinceHeightTooHigh, got", err)
+	}
+
+	// Send coins away and confirm the transaction. The delta should match
+	// the change of the confirmed balance.
+	if _, err := wt.wallet.SendSiacoins(types.SiacoinPrecision.Mul64(100), types.UnlockHash{}); err != nil {
+		t.Fatal(err)
+	}
+	wt.addBlockNoPayout()
+	balance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	delta, err = wt.wallet.ConfirmedBalanceDelta(startHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta.Height != startHeight+1 {
+		t.Fatalf("expected height %v, got %v", startHeight+1, delta.Height)
+	}
+	if delta.SiacoinsOutgoing.Cmp(types.SiacoinPrecision.Mul64(100)) < 0 {
+		t.Fatal("outgoing siacoins are too low:", delta.SiacoinsOutgoing)
+	}
+	if !startBalance.Add(delta.SiacoinsIncoming).Equals(balance.Add(delta.SiacoinsOutgoing)) {
+		t.Fatalf("delta %v doesn't match the balance change from %v to %v", delta, startBalance, balance)
+	}
+
+	// The transaction is not part of the delta since the new height.
+	delta, err = wt.wallet.ConfirmedBalanceDelta(startHeight + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !delta.SiacoinsIncoming.IsZero() || !delta.SiacoinsOutgoing.IsZero() {
+		t.Fatal("expected an empty delta, got", delta)
+	}
+}
+
+// TestConfirmedBalanceAt checks that the balance at past heights is
+// reconstructed from the processed transactions.
+func TestConfirmedBalanceAt(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	startHeight, err := wt.wallet.Height()
+	if err != nil {
+		t.Fatal(err)
+	}
+	startBalance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The balance at the current height is the confirmed balance.
+	balance, _, err := wt.wallet.ConfirmedBalanceAt(startHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.Equals(startBalance) {
+		t.Fatalf("expected balance %v, got %v", startBalance, balance)
+	}
+	if _, _, err := wt.wallet.ConfirmedBalanceAt(startHeight + 1); err != errHeightTooHigh {
+		t.Fatal("expected errHeightTooHigh, got", err)
+	}
+
+	// Nothing was received at the genesis block.
+	balance, _, err = wt.wallet.ConfirmedBalanceAt(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.IsZero() {
+		t.Fatal("expected an empty balance at height 0, got", balance)
+	}
+
+	// Send coins away and confirm the transaction. The balance at the old
+	// height must not change.
+	if _, err := wt.wallet.SendSiacoins(types.SiacoinPrecision.Mul64(100), types.UnlockHash{}); err != nil {
+		t.Fatal(err)
+	}
+	wt.addBlockNoPayout()
+	newBalance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, _, err = wt.wallet.ConfirmedBalanceAt(startHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.Equals(startBalance) {
+		t.Fatalf("expected balance %v, got %v", startBalance, balance)
+	}
+	balance, _, err = wt.wallet.ConfirmedBalanceAt(startHeight + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.Equals(newBalance) {
+		t.Fatalf("expected balance %v, got %v", newBalance, balance)
+	}
+
+	// The wallet scanned the blockchain from the genesis block.
+	wt.wallet.mu.Lock()
+	historyStart, err := dbGetHistoryStartHeight(wt.wallet.dbTx)
+	wt.wallet.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if historyStart != 0 {
+		t.Fatal("expected the history to start at the genesis block, got", historyStart)
+	}
+
+	// Heights before the start of the history can't be queried.
+	wt.wallet.mu.Lock()
+	err = dbPutHistoryStartHeight(wt.wallet.dbTx, startHeight)
+	wt.wallet.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := wt.wallet.ConfirmedBalanceAt(startHeight - 1); err != errHeightBeforeHistory {
+		t.Fatal("expected errHeightBeforeHistory, got", err)
+	}
+	if _, _, err := wt.wallet.ConfirmedBalanceAt(startHeight); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a history, no height can be queried.
+	wt.wallet.mu.Lock()
+	err = dbDeleteHistoryStartHeight(wt.wallet.dbTx)
+	wt.wallet.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := wt.wallet.ConfirmedBalanceAt(startHeight); err != errHeightBeforeHistory {
+		t.Fatal("expected errHeightBeforeHistory, got", err)
+	}
+}
+
+// TestSendSiacoinsArbitraryData checks that SendSiacoinsArbitraryData attaches
+// the prefixed data to the transaction and rejects oversized payloads.
+func TestSendSiacoinsArbitraryData(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	data := []byte("invoice 1234")
+	txns, err := wt.wallet.SendSiacoinsArbitraryData(types.SiacoinPrecision, types.UnlockHash{}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn := txns[len(txns)-1]
+	if len(txn.ArbitraryData) != 1 {
+		t.Fatal("expected one arbitrary data entry, got", len(txn.ArbitraryData))
+	}
+	expected := append(modules.PrefixNonSia[:], data...)
+	if !bytes.Equal(txn.ArbitraryData[0], expected) {
+		t.Fatalf("expected arbitrary data %v, got %v", expected, txn.ArbitraryData[0])
+	}
+
+	// The transaction should be confirmed like any other transaction.
+	wt.addBlockNoPayout()
+	if _, found, err := wt.wallet.Transaction(txn.ID()); err != nil || !found {
+		t.Fatal("transaction was not confirmed", err)
+	}
+
+	// Oversized payloads are rejected.
+	_, err = wt.wallet.SendSiacoinsArbitraryData(types.SiacoinPrecision, types.UnlockHash{}, make([]byte, maxArbitraryDataSize+1))
+	if err != errArbitraryDataTooLarge {
+		t.Fatal("expected errArbitraryDataTooLarge, got", err)
+	}
+}
+
+// TestPreviewSendSiacoins checks that PreviewSendSiacoins returns a signed
+// transaction set without submitting it or reserving its inputs.
+func TestPreviewSendSiacoins(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	amount := types.SiacoinPrecision.Mul64(100)
+	txns, err := wt.wallet.PreviewSendSiacoins(amount, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) == 0 {
+		t.Fatal("expected a transaction set")
+	}
+	for _, txn := range txns {
+		if err := txn.StandaloneValid(wt.cs.Height()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	txn := txns[len(txns)-1]
+	if len(txn.MinerFees) == 0 {
+		t.Fatal("preview doesn't pay a miner fee")
+	}
+	found := false
+	for _, sco := range txn.SiacoinOutputs {
+		if sco.UnlockHash == (types.UnlockHash{}) && sco.Value.Cmp(amount) == 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("preview doesn't send the coins to the destination")
+	}
+
+	// Nothing should have been submitted.
+	if len(wt.tpool.TransactionList()) != 0 {
+		t.Fatal("preview was submitted to the transaction pool")
+	}
+
+	// The inputs of the preview must not be reserved.
+	wt.wallet.mu.Lock()
+	for _, sci := range txns[0].SiacoinInputs {
+		if _, err := dbGetSpentOutput(wt.wallet.dbTx, types.OutputID(sci.ParentID)); err == nil {
+			wt.wallet.mu.Unlock()
+			t.Fatal("input of the preview is still reserved")
+		}
+	}
+	wt.wallet.mu.Unlock()
+
+	// The previewed transaction set is valid and can be broadcast.
+	if err := wt.tpool.AcceptTransactionSet(txns); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestTopUpAddress probes the TopUpAddress method of the wallet.
+func TestTopUpAddress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := uc.UnlockHash()
+
+	// balance returns the confirmed balance of addr.
+	balance := func() (b types.Currency) {
+		wt.wallet.mu.Lock()
+		defer wt.wallet.mu.Unlock()
+		dbForEachSiacoinOutput(wt.wallet.dbTx, func(_ types.SiacoinOutputID, sco types.SiacoinOutput) {
+			if sco.UnlockHash == addr {
+				b = b.Add(sco.Value)
+			}
+		})
+		return
+	}
+
+	// Addresses that don't belong to the wallet are rejected.
+	target := types.SiacoinPrecision.Mul64(100)
+	if _, _, err := wt.wallet.TopUpAddress(types.UnlockHash{}, target); err != errUnknownAddress {
+		t.Fatal("expected errUnknownAddress, got", err)
+	}
+
+	// Top up the empty address.
+	amount, _, err := wt.wallet.TopUpAddress(addr, target)
+	if err != nil {
+		t.Fatal(err)
+	} else if !amount.Equals(target) {
+		t.Fatalf("expected to send %v, sent %v", target, amount)
+	}
+	wt.addBlockNoPayout()
+	if !balance().Equals(target) {
+		t.Fatalf("expected balance %v, got %v", target, balance())
+	}
+
+	// The target is already met.
+	if _, _, err := wt.wallet.TopUpAddress(addr, target); err != errTargetBalanceReached {
+		t.Fatal("expected errTargetBalanceReached, got", err)
+	}
+
+	// Raise the target. Only the difference should be sent and the outputs of
+	// the address must not be spent.
+	target = types.SiacoinPrecision.Mul64(150)
+	amount, _, err = wt.wallet.TopUpAddress(addr, target)
+	if err != nil {
+		t.Fatal(err)
+	} else if !amount.Equals(types.SiacoinPrecision.Mul64(50)) {
+		t.Fatalf("expected to send %v, sent %v", types.SiacoinPrecision.Mul64(50), amount)
+	}
+	wt.addBlockNoPayout()
+	if !balance().Equals(target) {
+		t.Fatalf("expected balance %v, got %v", target, balance())
+	}
+}
+
+// TestSendSiacoinsExcluding checks that SendSiacoinsExcluding doesn't spend
+// the excluded outputs and fails if they are needed to fund the transaction.
+func TestSendSiacoinsExcluding(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Collect the outputs of the wallet.
+	var ids []types.SiacoinOutputID
+	var total types.Currency
+	wt.wallet.mu.Lock()
+	dbForEachSiacoinOutput(wt.wallet.dbTx, func(id types.SiacoinOutputID, sco types.SiacoinOutput) {
+		ids = append(ids, id)
+		total = total.Add(sco.Value)
+	})
+	wt.wallet.mu.Unlock()
+	if len(ids) < 2 {
+		t.Fatal("expected the wallet to have multiple outputs")
+	}
+
+	// Excluding every output leaves nothing to spend.
+	_, err = wt.wallet.SendSiacoinsExcluding(types.SiacoinPrecision, types.UnlockHash{}, ids)
+	if err == nil || !strings.Contains(err.Error(), errInsufficientFundsAfterExclusions.Error()) {
+		t.Fatal("expected errInsufficientFundsAfterExclusions, got", err)
+	}
+
+	// Send some coins without spending the first output.
+	txns, err := wt.wallet.SendSiacoinsExcluding(types.SiacoinPrecision, types.UnlockHash{}, ids[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, txn := range txns {
+		for _, sci := range txn.SiacoinInputs {
+			if sci.ParentID == ids[0] {
+				t.Fatal("excluded output was spent")
+			}
+		}
+	}
+
+	// Exclusions don't turn a low balance into an exclusion error.
+	_, err = wt.wallet.SendSiacoinsExcluding(total.Mul64(2), types.UnlockHash{}, ids[:1])
+	if err == nil || !strings.Contains(err.Error(), modules.ErrLowBalance.Error()) {
+		t.Fatal("expected ErrLowBalance, got", err)
+	}
+}
+
+// TestChangeThreshold checks that change below the change threshold is added
+// to the miner fees instead of being refunded.
+func TestChangeThreshold(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// By default, the change is refunded.
+	threshold, err := wt.wallet.ChangeThreshold()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !threshold.IsZero() {
+		t.Fatal("expected a zero change threshold by default, got", threshold)
+	}
+	txns, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns[0].SiacoinOutputs) != 2 || len(txns[0].MinerFees) != 0 {
+		t.Fatal("expected the parent transaction to refund the change")
+	}
+
+	// With a threshold above the change, the change goes to the miners.
+	balance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.wallet.SetChangeThreshold(balance); err != nil {
+		t.Fatal(err)
+	}
+	if threshold, err := wt.wallet.ChangeThreshold(); err != nil || !threshold.Equals(balance) {
+		t.Fatal("change threshold was not stored:", threshold, err)
+	}
+	txns, err = wt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns[0].SiacoinOutputs) != 1 || len(txns[0].MinerFees) != 1 {
+		t.Fatal("expected the change to be added to the miner fees")
+	}
+}
+
+// TestSendSiacoinsTimelocked checks that SendSiacoinsTimelocked creates an
+// output for the address of the supplied unlock conditions and rejects
+// timelocks that are not in the future.
+func TestSendSiacoinsTimelocked(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	height, err := wt.wallet.Height()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, pk := crypto.GenerateKeyPair()
+	uc := types.UnlockConditions{
+		Timelock:           height,
+		PublicKeys:         []types.SiaPublicKey{types.Ed25519PublicKey(pk)},
+		SignaturesRequired: 1,
+	}
+
+	// A timelock at the current height is rejected.
+	if _, err := wt.wallet.SendSiacoinsTimelocked(types.SiacoinPrecision, uc); err == nil {
+		t.Fatal("expected timelock at the current height to be rejected")
+	}
+
+	// A timelock in the future is accepted.
+	uc.Timelock = height + 10
+	txns, err := wt.wallet.SendSiacoinsTimelocked(types.SiacoinPrecision, uc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, sco := range txns[len(txns)-1].SiacoinOutputs {
+		if sco.UnlockHash == uc.UnlockHash() && sco.Value.Equals(types.SiacoinPrecision) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("transaction doesn't contain the time-locked output")
+	}
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestEstimateSiacoinsMultiFee probes the EstimateSiacoinsMultiFee method of
+// the wallet.
+func TestEstimateSiacoinsMultiFee(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	size, fee, err := wt.wallet.EstimateSiacoinsMultiFee(1, types.SiacoinPrecision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fee.Equals(wt.wallet.managedMultiSendFee(1)) {
+		t.Fatalf("expected fee %v, got %v", wt.wallet.managedMultiSendFee(1), fee)
+	}
+	size2, fee2, err := wt.wallet.EstimateSiacoinsMultiFee(10, types.SiacoinPrecision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size2 <= size || fee2.Cmp(fee) <= 0 {
+		t.Fatal("size and fee should grow with the number of outputs")
+	}
+
+	// Estimating shouldn't spend any outputs, so the full balance can still
+	// be sent.
+	balance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := balance.Sub(wt.wallet.managedMultiSendFee(2)).Div64(2)
+	if _, _, err := wt.wallet.EstimateSiacoinsMultiFee(2, value); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := wt.wallet.EstimateSiacoinsMultiFee(3, value); err != modules.ErrLowBalance {
+		t.Fatal("expected ErrLowBalance, got", err)
+	}
+	outputs := []types.SiacoinOutput{{Value: value}, {Value: value}}
+	if _, err := wt.wallet.SendSiacoinsMulti(outputs); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := wt.wallet.EstimateSiacoinsMultiFee(0, value); err == nil {
+		t.Fatal("expected an error for zero outputs")
+	}
+}
+
+// TestCanSendSiacoins checks that CanSendSiacoins reports the shortfall of a
+// send that exceeds the balance and doesn't reserve any outputs.
+func TestCanSendSiacoins(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	balance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee := wt.wallet.managedMultiSendFee(3)
+	amount := balance.Sub(fee)
+	canSend, shortfall, estimatedFee, err := wt.wallet.CanSendSiacoins(amount, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !canSend || !shortfall.IsZero() || !estimatedFee.Equals(fee) {
+		t.Fatal("expected the full balance to be sendable:", canSend, shortfall, estimatedFee)
+	}
+
+	// One more hasting can't be funded.
+	canSend, shortfall, _, err = wt.wallet.CanSendSiacoins(amount.Add(types.NewCurrency64(1)), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canSend || !shortfall.Equals64(1) {
+		t.Fatal("expected a shortfall of 1 hasting, got", canSend, shortfall)
+	}
+
+	// The check shouldn't reserve any outputs.
+	value := amount.Div64(3)
+	outputs := []types.SiacoinOutput{{Value: value}, {Value: value}, {Value: value}}
+	if _, err := wt.wallet.SendSiacoinsMulti(outputs); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := wt.wallet.CanSendSiacoins(amount, 0); err == nil {
+		t.Fatal("expected an error for zero outputs")
+	}
+}
+
 // TestIntegrationSendOverUnder sends too many siacoins, resulting in an error,
 // followed by sending few enough siacoins that the send should complete.
 //
@@ -275,3 +931,39 @@ func TestSendSiacoinsAcceptTxnSetFailed(t *testing.T) {
 		t.Fatalf("SendSiacoins failed: %v", err)
 	}
 }
+
+// TestSendAllSiacoins checks that SendAllSiacoins sends the whole spendable
+// balance minus the fee and leaves nothing else to send.
+func TestSendAllSiacoins(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	balance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount, txns, err := wt.wallet.SendAllSiacoins(types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || len(txns[0].SiacoinOutputs) != 1 || len(txns[0].MinerFees) != 1 {
+		t.Fatal("expected a single transaction with a single output")
+	}
+	if !txns[0].SiacoinOutputs[0].Value.Equals(amount) {
+		t.Fatal("reported amount doesn't match the sent amount")
+	}
+	if !amount.Add(txns[0].MinerFees[0]).Equals(balance) {
+		t.Fatalf("expected to send %v, sent %v plus a fee of %v", balance, amount, txns[0].MinerFees[0])
+	}
+
+	// All outputs are spent, so there is nothing left to send.
+	if _, _, err := wt.wallet.SendAllSiacoins(types.UnlockHash{}); err != errNothingToSend {
+		t.Fatal("expected errNothingToSend, got", err)
+	}
+}
@@ -0,0 +1,74 @@
+package wallet
+
+import (
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+	"gitlab.com/NebulousLabs/errors"
+)
+
+var (
+	// errNoMultisigKeys is returned by MultisigAddress if no public keys were
+	// provided.
+	errNoMultisigKeys = errors.New("multisig address requires at least one public key")
+
+	// errInvalidSigLimit is returned by MultisigAddress if the number of
+	// required signatures is zero or exceeds the number of public keys.
+	errInvalidSigLimit = errors.New("signature limit must be between 1 and the number of public keys")
+
+	// errInvalidMultisigKey is returned by MultisigAddress if one of the
+	// public keys isn't a valid ed25519 key.
+	errInvalidMultisigKey = errors.New("multisig public keys must be ed25519 keys")
+
+	// errDuplicateMultisigKey is returned by MultisigAddress if the same
+	// public key is provided more than once.
+	errDuplicateMultisigKey = errors.New("multisig public keys must be unique")
+)
+
+// MultisigAddress returns the unlock conditions of an address that requires
+// sigLimit signatures of the given public keys to be spent. If
+// includeWalletKey is set, a new key of the wallet's primary seed is added to
+// the set, so that the wallet can contribute a signature later on.
+func (w *Wallet) MultisigAddress(pubkeys []types.SiaPublicKey, sigLimit uint64, includeWalletKey bool) (types.UnlockConditions, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.UnlockConditions{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	for i, pk := range pubkeys {
+		if pk.Algorithm != types.SignatureEd25519 || len(pk.Key) != crypto.PublicKeySize {
+			return types.UnlockConditions{}, errInvalidMultisigKey
+		}
+		for _, prev := range pubkeys[:i] {
+			if prev.String() == pk.String() {
+				return types.UnlockConditions{}, errDuplicateMultisigKey
+			}
+		}
+	}
+	numKeys := uint64(len(pubkeys))
+	if includeWalletKey {
+		numKeys++
+	}
+	if numKeys == 0 {
+		return types.UnlockConditions{}, errNoMultisigKeys
+	}
+	if sigLimit == 0 || sigLimit > numKeys {
+		return types.UnlockConditions{}, errInvalidSigLimit
+	}
+
+	uc := types.UnlockConditions{
+		PublicKeys:         append([]types.SiaPublicKey(nil), pubkeys...),
+		SignaturesRequired: sigLimit,
+	}
+	if includeWalletKey {
+		w.mu.Lock()
+		walletUC, err := w.nextPrimarySeedAddress(w.dbTx)
+		err = errors.Compose(err, w.syncDB())
+		w.mu.Unlock()
+		if err != nil {
+			return types.UnlockConditions{}, err
+		}
+		uc.PublicKeys = append(uc.PublicKeys, walletUC.PublicKeys[0])
+	}
+	return uc, nil
+}
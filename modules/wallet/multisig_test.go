@@ -0,0 +1,84 @@
+package wallet
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+	"gitlab.com/NebulousLabs/errors"
+)
+
+// TestMultisigAddress probes the MultisigAddress method of the wallet.
+func TestMultisigAddress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	_, pk1 := crypto.GenerateKeyPair()
+	_, pk2 := crypto.GenerateKeyPair()
+	pubkeys := []types.SiaPublicKey{types.Ed25519PublicKey(pk1), types.Ed25519PublicKey(pk2)}
+
+	// Create a 2-of-2 address from external keys.
+	uc, err := wt.wallet.MultisigAddress(pubkeys, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uc.PublicKeys) != 2 || uc.SignaturesRequired != 2 {
+		t.Fatal("unexpected unlock conditions:", uc)
+	}
+
+	// Create a 2-of-3 address that includes a key of the wallet. The wallet
+	// should be able to sign for its key.
+	uc, err = wt.wallet.MultisigAddress(pubkeys, 2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uc.PublicKeys) != 3 || uc.SignaturesRequired != 2 {
+		t.Fatal("unexpected unlock conditions:", uc)
+	}
+	walletKey := uc.PublicKeys[2]
+	found := false
+	wt.wallet.mu.RLock()
+	for _, sk := range wt.wallet.keys {
+		if sk.UnlockConditions.PublicKeys[0].String() == walletKey.String() {
+			found = true
+		}
+	}
+	wt.wallet.mu.RUnlock()
+	if !found {
+		t.Fatal("wallet key of multisig address is not known to the wallet")
+	}
+
+	// Invalid parameters should be rejected.
+	if _, err := wt.wallet.MultisigAddress(pubkeys, 3, false); err != errInvalidSigLimit {
+		t.Fatal("expected errInvalidSigLimit, got", err)
+	}
+	if _, err := wt.wallet.MultisigAddress(pubkeys, 0, false); err != errInvalidSigLimit {
+		t.Fatal("expected errInvalidSigLimit, got", err)
+	}
+	if _, err := wt.wallet.MultisigAddress(nil, 1, false); err != errNoMultisigKeys {
+		t.Fatal("expected errNoMultisigKeys, got", err)
+	}
+	if _, err := wt.wallet.MultisigAddress([]types.SiaPublicKey{pubkeys[0], pubkeys[0]}, 1, false); err != errDuplicateMultisigKey {
+		t.Fatal("expected errDuplicateMultisigKey, got", err)
+	}
+	invalid := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{1, 2, 3}}
+	if _, err := wt.wallet.MultisigAddress([]types.SiaPublicKey{invalid}, 1, false); err != errInvalidMultisigKey {
+		t.Fatal("expected errInvalidMultisigKey, got", err)
+	}
+
+	// A locked wallet can't add its own key.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.wallet.MultisigAddress(pubkeys, 2, true); !errors.Contains(err, modules.ErrLockedWallet) {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+}
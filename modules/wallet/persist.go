@@ -3,8 +3,10 @@ package wallet
 import (
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gitlab.com/NebulousLabs/Sia/crypto"
 	"gitlab.com/NebulousLabs/Sia/encoding"
@@ -74,6 +76,26 @@ func (w *Wallet) openDB(filename string) (err error) {
 		if wb.Get(keySiafundPool) == nil {
 			wb.Put(keySiafundPool, encoding.Marshal(types.ZeroCurrency))
 		}
+		if wb.Get(keyDefragSettings) == nil {
+			wb.Put(keyDefragSettings, encoding.Marshal(defaultDefragSettings))
+		}
+		if wb.Get(keyChangeThreshold) == nil {
+			wb.Put(keyChangeThreshold, encoding.Marshal(types.ZeroCurrency))
+		}
+		if wb.Get(keyConfirmationDepth) == nil {
+			wb.Put(keyConfirmationDepth, encoding.Marshal(types.BlockHeight(0)))
+		}
+		if wb.Get(keyRescanThrottle) == nil {
+			wb.Put(keyRescanThrottle, encoding.Marshal(time.Duration(0)))
+		}
+		if wb.Get(keyFeeMultiplier) == nil {
+			wb.Put(keyFeeMultiplier, encoding.Marshal(math.Float64bits(1)))
+		}
+		// wallets that processed blocks before the history start height was
+		// tracked scanned the blockchain from the genesis block
+		if wb.Get(keyHistoryStartHeight) == nil && dbGetConsensusChangeID(tx) != modules.ConsensusChangeBeginning {
+			wb.Put(keyHistoryStartHeight, encoding.Marshal(types.BlockHeight(0)))
+		}
 
 		// build the bucketAddrTransactions bucket if necessary
 		if buildAddrTxns {
@@ -0,0 +1,77 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+)
+
+// maxRescanThrottle is the longest pause between two consensus changes during
+// a rescan. The consensus set is read-locked while it sends a batch of changes
+// to the wallet, so long pauses would delay the processing of new blocks.
+const maxRescanThrottle = 50 * time.Millisecond
+
+var (
+	// errRescanThrottleTooHigh is returned by SetRescanThrottle if the pause
+	// exceeds maxRescanThrottle.
+	errRescanThrottleTooHigh = fmt.Errorf("rescan throttle cannot exceed %v", maxRescanThrottle)
+
+	// errNegativeRescanThrottle is returned by SetRescanThrottle if the pause
+	// is negative.
+	errNegativeRescanThrottle = errors.New("rescan throttle cannot be negative")
+)
+
+// RescanThrottle returns the pause between two consensus changes that the
+// wallet processes while it is rescanning the blockchain. A zero throttle
+// rescans at full speed.
+func (w *Wallet) RescanThrottle() (time.Duration, error) {
+	if err := w.tg.Add(); err != nil {
+		return 0, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbGetRescanThrottle(w.dbTx)
+}
+
+// SetRescanThrottle sets the pause between two consensus changes that the
+// wallet processes while it is rescanning the blockchain. Throttling a rescan
+// keeps the node responsive on slow hardware, at the cost of a longer rescan.
+// The throttle is persisted in the wallet's database.
+func (w *Wallet) SetRescanThrottle(throttle time.Duration) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	if throttle < 0 {
+		return errNegativeRescanThrottle
+	} else if throttle > maxRescanThrottle {
+		return errRescanThrottleTooHigh
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbPutRescanThrottle(w.dbTx, throttle)
+}
+
+// managedThrottleRescan pauses for the wallet's rescan throttle if the wallet
+// is currently rescanning the blockchain.
+func (w *Wallet) managedThrottleRescan() {
+	w.mu.Lock()
+	throttle, err := dbGetRescanThrottle(w.dbTx)
+	w.mu.Unlock()
+	if err != nil || throttle == 0 {
+		return
+	}
+	if !w.scanLock.TryLock() {
+		select {
+		case <-time.After(throttle):
+		case <-w.tg.StopChan():
+		}
+		return
+	}
+	w.scanLock.Unlock()
+}
@@ -0,0 +1,43 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+)
+
+// TestRescanThrottle checks that the rescan throttle is validated and
+// persisted.
+func TestRescanThrottle(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// By default, rescans run at full speed.
+	if throttle, err := wt.wallet.RescanThrottle(); err != nil || throttle != 0 {
+		t.Fatal("expected no rescan throttle, got", throttle, err)
+	}
+	if err := wt.wallet.SetRescanThrottle(-time.Millisecond); err != errNegativeRescanThrottle {
+		t.Fatal("expected errNegativeRescanThrottle, got", err)
+	}
+	if err := wt.wallet.SetRescanThrottle(maxRescanThrottle + 1); err != errRescanThrottleTooHigh {
+		t.Fatal("expected errRescanThrottleTooHigh, got", err)
+	}
+	if err := wt.wallet.SetRescanThrottle(5 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if throttle, err := wt.wallet.RescanThrottle(); err != nil || throttle != 5*time.Millisecond {
+		t.Fatal("expected a rescan throttle of 5ms, got", throttle, err)
+	}
+
+	// A throttled rescan still completes.
+	if err := wt.wallet.Rescan(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -14,7 +14,9 @@ import (
 )
 
 var (
-	errKnownSeed = errors.New("seed is already known")
+	errKnownSeed      = errors.New("seed is already known")
+	errNoFreshAddress = errors.New("unable to find an address that hasn't been used before")
+	errUnknownSeed    = errors.New("seed is not loaded into the wallet")
 )
 
 type (
@@ -133,8 +135,12 @@ func (w *Wallet) nextPrimarySeedAddresses(tx *bolt.Tx, n uint64) ([]types.Unlock
 	spendableKeys := generateKeys(w.primarySeed, progress, n)
 	ucs := make([]types.UnlockConditions, 0, len(spendableKeys))
 	for _, spendableKey := range spendableKeys {
-		w.keys[spendableKey.UnlockConditions.UnlockHash()] = spendableKey
-		delete(w.lookahead, spendableKey.UnlockConditions.UnlockHash())
+		uh := spendableKey.UnlockConditions.UnlockHash()
+		if err := dbPutIssuedAddress(tx, uh); err != nil {
+			return []types.UnlockConditions{}, err
+		}
+		w.keys[uh] = spendableKey
+		delete(w.lookahead, uh)
 		ucs = append(ucs, spendableKey.UnlockConditions)
 	}
 	w.regenerateLookahead(progress + n)
@@ -161,6 +167,33 @@ func (w *Wallet) AllSeeds() ([]modules.Seed, error) {
 	return append([]modules.Seed{w.primarySeed}, w.seeds...), nil
 }
 
+// ExportPrimarySeed returns the primary seed of the wallet encrypted with key.
+// The key is independent of the wallet's master key, so that the export can
+// be stored or transported separately from the wallet. Every export uses a
+// fresh salt. DecryptSeedExport recovers the seed from the export.
+func (w *Wallet) ExportPrimarySeed(key crypto.TwofishKey) ([]byte, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer w.tg.Done()
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+	return encoding.Marshal(createSeedFile(key, w.primarySeed)), nil
+}
+
+// DecryptSeedExport decrypts a seed that was exported by ExportPrimarySeed
+// using the key that it was exported with.
+func DecryptSeedExport(export []byte, key crypto.TwofishKey) (modules.Seed, error) {
+	var sf seedFile
+	if err := encoding.Unmarshal(export, &sf); err != nil {
+		return modules.Seed{}, errors.AddContext(err, "unable to decode seed export")
+	}
+	return decryptSeedFile(key, sf)
+}
+
 // PrimarySeed returns the decrypted primary seed of the wallet, as well as
 // the number of addresses that the seed can be safely used to generate.
 func (w *Wallet) PrimarySeed() (modules.Seed, uint64, error) {
@@ -184,6 +217,56 @@ func (w *Wallet) PrimarySeed() (modules.Seed, uint64, error) {
 	return w.primarySeed, remaining, nil
 }
 
+// PrimarySeedProgress returns the number of addresses that have been
+// generated from the primary seed, as well as the highest index of a primary
+// seed address that has received siacoins or siafunds. funded is false if
+// none of the generated addresses have received any funds.
+func (w *Wallet) PrimarySeedProgress() (progress, highestFunded uint64, funded bool, err error) {
+	if err := w.tg.Add(); err != nil {
+		return 0, 0, false, err
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return 0, 0, false, modules.ErrLockedWallet
+	}
+	progress, err = dbGetPrimarySeedProgress(w.dbTx)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	// Map the generated addresses to their index so that the outputs of the
+	// wallet's transactions can be traced back to the seed.
+	indices := make(map[types.UnlockHash]uint64, progress)
+	for i, key := range generateKeys(w.primarySeed, 0, progress) {
+		indices[key.UnlockConditions.UnlockHash()] = uint64(i)
+	}
+	checkOutputs := func(pt modules.ProcessedTransaction) {
+		for _, output := range pt.Outputs {
+			if output.FundType != types.SpecifierSiacoinOutput && output.FundType != types.SpecifierSiafundOutput {
+				continue
+			}
+			index, exists := indices[output.RelatedAddress]
+			if !exists {
+				continue
+			}
+			if !funded || index > highestFunded {
+				highestFunded = index
+				funded = true
+			}
+		}
+	}
+	it := dbProcessedTransactionsIterator(w.dbTx)
+	for it.next() {
+		checkOutputs(it.value())
+	}
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		checkOutputs(upt)
+	}
+	return progress, highestFunded, funded, nil
+}
+
 // NextAddresses returns n unlock hashes that are ready to receive siacoins or
 // siafunds. The addresses are generated using the primary address seed.
 //
@@ -219,6 +302,112 @@ func (w *Wallet) NextAddress() (types.UnlockConditions, error) {
 	return ucs[0], nil
 }
 
+// FreshAddress returns an address of the primary seed that the wallet has never
+// handed out before and that has never received funds. Addresses that don't
+// qualify, e.g. because external tooling derived them from the same seed and
+// received funds on them, are skipped.
+func (w *Wallet) FreshAddress() (types.UnlockConditions, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.UnlockConditions{}, err
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return types.UnlockConditions{}, modules.ErrLockedWallet
+	}
+
+	for i := 0; i < maxFreshAddressAttempts; i++ {
+		progress, err := dbGetPrimarySeedProgress(w.dbTx)
+		if err != nil {
+			return types.UnlockConditions{}, err
+		}
+		uh := generateSpendableKey(w.primarySeed, progress).UnlockConditions.UnlockHash()
+		fresh := !dbGetIssuedAddress(w.dbTx, uh) && !w.addressSeenFunds(uh)
+
+		// The address is consumed either way, so that it is not considered
+		// again.
+		uc, err := w.nextPrimarySeedAddress(w.dbTx)
+		if err != nil {
+			return types.UnlockConditions{}, err
+		}
+		if fresh {
+			return uc, w.syncDB()
+		}
+	}
+	return types.UnlockConditions{}, errors.Compose(errNoFreshAddress, w.syncDB())
+}
+
+// addressSeenFunds returns true if the address appears in a confirmed or
+// unconfirmed transaction of the wallet. The caller must hold the wallet lock.
+func (w *Wallet) addressSeenFunds(uh types.UnlockHash) bool {
+	if _, err := dbGetAddrTransactions(w.dbTx, uh); err == nil {
+		return true
+	}
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, output := range upt.Outputs {
+			if output.RelatedAddress == uh {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PrimarySeedAddress returns the unlock conditions of the address that the
+// primary seed produces at the given index. Unlike NextAddress, the address is
+// not tracked by the wallet and the seed progress is not advanced, so it can be
+// used to verify the derivation against other implementations.
+func (w *Wallet) PrimarySeedAddress(index uint64) (types.UnlockConditions, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.UnlockConditions{}, err
+	}
+	defer w.tg.Done()
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.unlocked {
+		return types.UnlockConditions{}, modules.ErrLockedWallet
+	}
+	return generateSpendableKey(w.primarySeed, index).UnlockConditions, nil
+}
+
+// SeedAddresses returns the addresses that the wallet derived from seed and is
+// tracking, in the order of their index. seed has to be the primary seed or
+// one of the auxiliary seeds loaded into the wallet.
+func (w *Wallet) SeedAddresses(seed modules.Seed) ([]types.UnlockHash, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer w.tg.Done()
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+	known := false
+	for _, wSeed := range append([]modules.Seed{w.primarySeed}, w.seeds...) {
+		if seed == wSeed {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, errUnknownSeed
+	}
+
+	// The keys of a seed are always generated starting at index 0, so the
+	// tracked addresses end at the first index that isn't tracked.
+	var addrs []types.UnlockHash
+	for index := uint64(0); ; index++ {
+		addr := generateSpendableKey(seed, index).UnlockConditions.UnlockHash()
+		if _, exists := w.keys[addr]; !exists {
+			break
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
 // LoadSeed will track all of the addresses generated by the input seed,
 // reclaiming any funds that were lost due to a deleted file or lost encryption
 // key. An error will be returned if the seed has already been integrated with
@@ -301,6 +490,9 @@ func (w *Wallet) LoadSeed(masterKey crypto.TwofishKey, seed modules.Seed) error
 			return err
 		}
 		w.unconfirmedProcessedTransactions = nil
+		if err = dbDeleteHistoryStartHeight(w.dbTx); err != nil {
+			return err
+		}
 
 		// reset the consensus change ID and height in preparation for rescan
 		err = dbPutConsensusChangeID(w.dbTx, modules.ConsensusChangeBeginning)
@@ -10,6 +10,7 @@ import (
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/modules/miner"
 	"gitlab.com/NebulousLabs/Sia/types"
+	"gitlab.com/NebulousLabs/fastrand"
 )
 
 // TestPrimarySeed checks that the correct seed is returned when calling
@@ -84,6 +85,249 @@ func TestPrimarySeed(t *testing.T) {
 	}
 }
 
+// TestPrimarySeedProgress checks that PrimarySeedProgress reports the number
+// of generated addresses and the highest index of a funded address.
+func TestPrimarySeedProgress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// The wallet tester has mined blocks, so some address must be funded.
+	progress, highestFunded, funded, err := wt.wallet.PrimarySeedProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !funded {
+		t.Fatal("expected a funded address")
+	}
+	if highestFunded >= progress {
+		t.Fatalf("highest funded index %v should be lower than progress %v", highestFunded, progress)
+	}
+
+	// Generating an address should advance the progress without changing
+	// the highest funded index.
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress2, highestFunded2, _, err := wt.wallet.PrimarySeedProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress2 != progress+1 {
+		t.Fatalf("expected progress %v, got %v", progress+1, progress2)
+	}
+	if highestFunded2 != highestFunded {
+		t.Fatalf("expected highest funded index %v, got %v", highestFunded, highestFunded2)
+	}
+
+	// Send money to the new address. Its index should now be funded.
+	_, err = wt.wallet.SendSiacoins(types.SiacoinPrecision, uc.UnlockHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, highestFunded3, _, err := wt.wallet.PrimarySeedProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if highestFunded3 < progress {
+		t.Fatalf("expected highest funded index of at least %v, got %v", progress, highestFunded3)
+	}
+
+	// The progress is unavailable while the wallet is locked.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := wt.wallet.PrimarySeedProgress(); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+}
+
+// TestPrimarySeedAddress checks that the address derived at an index matches
+// the address generated by NextAddress, and that deriving it doesn't advance
+// the seed progress.
+func TestPrimarySeedAddress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	progress, _, _, err := wt.wallet.PrimarySeedProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	derived, err := wt.wallet.PrimarySeedAddress(progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress2, _, _, err := wt.wallet.PrimarySeedProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress2 != progress {
+		t.Fatalf("deriving an address advanced the progress from %v to %v", progress, progress2)
+	}
+
+	// The next address generated by the wallet should be the derived one.
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uc.UnlockHash() != derived.UnlockHash() {
+		t.Fatal("derived address doesn't match the generated address")
+	}
+
+	// Addresses can't be derived while the wallet is locked.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.wallet.PrimarySeedAddress(0); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+}
+
+// TestFreshAddress checks that FreshAddress skips addresses that have been
+// handed out or have received funds.
+func TestFreshAddress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Addresses returned by NextAddress are recorded as issued.
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt.wallet.mu.Lock()
+	issued := dbGetIssuedAddress(wt.wallet.dbTx, uc.UnlockHash())
+	wt.wallet.mu.Unlock()
+	if !issued {
+		t.Fatal("address returned by NextAddress was not recorded")
+	}
+
+	// Mark the next two addresses as issued and as having received funds,
+	// as if they had been handed out by external tooling.
+	progress, _, _, err := wt.wallet.PrimarySeedProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuedUC, err := wt.wallet.PrimarySeedAddress(progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fundedUC, err := wt.wallet.PrimarySeedAddress(progress + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt.wallet.mu.Lock()
+	err = dbPutIssuedAddress(wt.wallet.dbTx, issuedUC.UnlockHash())
+	if err == nil {
+		err = dbAddAddrTransaction(wt.wallet.dbTx, fundedUC.UnlockHash(), 0)
+	}
+	wt.wallet.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both addresses should be skipped.
+	fresh, err := wt.wallet.FreshAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := wt.wallet.PrimarySeedAddress(progress + 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh.UnlockHash() != expected.UnlockHash() {
+		t.Fatal("FreshAddress didn't skip the used addresses")
+	}
+
+	// Fresh addresses are never returned twice.
+	fresh2, err := wt.wallet.FreshAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh2.UnlockHash() == fresh.UnlockHash() {
+		t.Fatal("FreshAddress returned the same address twice")
+	}
+}
+
+// TestExportPrimarySeed checks that an exported seed can only be decrypted
+// with the passphrase it was exported with.
+func TestExportPrimarySeed(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	key := crypto.TwofishKey(crypto.HashObject("passphrase"))
+	export, err := wt.wallet.ExportPrimarySeed(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed, _, err := wt.wallet.PrimarySeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(export, seed[:]) {
+		t.Fatal("export contains the plaintext seed")
+	}
+	decrypted, err := DecryptSeedExport(export, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted != seed {
+		t.Fatal("decrypted seed doesn't match the primary seed")
+	}
+
+	// Every export uses a fresh salt.
+	export2, err := wt.wallet.ExportPrimarySeed(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(export, export2) {
+		t.Fatal("exports of the same seed are identical")
+	}
+
+	// The wallet's master key and other passphrases don't decrypt the export.
+	if _, err := DecryptSeedExport(export, wt.walletMasterKey); err != modules.ErrBadEncryptionKey {
+		t.Fatal("expected ErrBadEncryptionKey, got", err)
+	}
+	if _, err := DecryptSeedExport(export, crypto.TwofishKey(crypto.HashObject("wrong"))); err != modules.ErrBadEncryptionKey {
+		t.Fatal("expected ErrBadEncryptionKey, got", err)
+	}
+
+	// The seed can't be exported from a locked wallet.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.wallet.ExportPrimarySeed(key); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+}
+
 // TestLoadSeed checks that a seed can be successfully recovered from a wallet,
 // and then remain available on subsequent loads of the wallet.
 func TestLoadSeed(t *testing.T) {
@@ -172,6 +416,69 @@ func TestLoadSeed(t *testing.T) {
 	}
 }
 
+// TestSeedAddresses probes the SeedAddresses method of the wallet.
+func TestSeedAddresses(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// The addresses of the primary seed match the generated keys.
+	seed, _, err := wt.wallet.PrimarySeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress, _, _, err := wt.wallet.PrimarySeedProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := wt.wallet.SeedAddresses(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint64(len(addrs)) != progress {
+		t.Fatalf("expected %v addresses, got %v", progress, len(addrs))
+	}
+	for i, key := range generateKeys(seed, 0, progress) {
+		if addrs[i] != key.UnlockConditions.UnlockHash() {
+			t.Fatal("address mismatch at index", i)
+		}
+	}
+
+	// A new address is included.
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs, err = wt.wallet.SeedAddresses(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint64(len(addrs)) != progress+1 || addrs[progress] != uc.UnlockHash() {
+		t.Fatal("new address is missing")
+	}
+
+	// Seeds that weren't loaded are rejected.
+	var unknownSeed modules.Seed
+	fastrand.Read(unknownSeed[:])
+	if _, err := wt.wallet.SeedAddresses(unknownSeed); err != errUnknownSeed {
+		t.Fatal("expected errUnknownSeed, got", err)
+	}
+
+	// The wallet has to be unlocked.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.wallet.SeedAddresses(seed); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+}
+
 // TestSweepSeedCoins tests that sweeping a seed results in the transfer of
 // its siacoin outputs to the wallet.
 func TestSweepSeedCoins(t *testing.T) {
@@ -0,0 +1,175 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"gitlab.com/NebulousLabs/Sia/build"
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+const (
+	// splitOutputsPerTransaction is the number of outputs that a single
+	// transaction of a split creates. At roughly 60 bytes per output, this
+	// keeps the transactions well below modules.TransactionSizeLimit.
+	splitOutputsPerTransaction = 400
+
+	// maxSplitOutputs is the maximum number of outputs that a single split can
+	// create. All transactions of a split form one transaction set, which has
+	// to fit into modules.TransactionSetSizeLimit.
+	maxSplitOutputs = 2000
+)
+
+var (
+	// errZeroSplitCount is returned by SplitOutputs if no outputs are
+	// requested.
+	errZeroSplitCount = errors.New("split count must be greater than zero")
+
+	// errZeroSplitValue is returned by SplitOutputs if the value of the
+	// outputs is zero.
+	errZeroSplitValue = errors.New("split value must be greater than zero")
+
+	// errTooManySplitOutputs is returned by SplitOutputs if more outputs are
+	// requested than fit into a single transaction set.
+	errTooManySplitOutputs = fmt.Errorf("cannot split into more than %v outputs at once", maxSplitOutputs)
+)
+
+// SplitOutputs creates count outputs of the given value that are sent back to
+// the wallet, so that they can be spent in parallel later on. The outputs are
+// spread across a chain of transactions that each spend a carry output of
+// the previous one, which allows the whole split to be funded by a single
+// large output. The transaction set is submitted to the transaction pool and
+// is also returned.
+func (w *Wallet) SplitOutputs(count uint64, value types.Currency) (txns []types.Transaction, err error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	if count == 0 {
+		return nil, errZeroSplitCount
+	} else if value.IsZero() {
+		return nil, errZeroSplitValue
+	} else if count > maxSplitOutputs {
+		return nil, errTooManySplitOutputs
+	}
+	w.mu.RLock()
+	unlocked := w.unlocked
+	w.mu.RUnlock()
+	if !unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+
+	// Determine the outputs and fee of every transaction in the chain. All
+	// but the last transaction have an additional carry output.
+	numTxns := (count + splitOutputsPerTransaction - 1) / splitOutputsPerTransaction
+	outputCounts := make([]uint64, numTxns)
+	fees := make([]types.Currency, numTxns)
+	totalCost := types.ZeroCurrency
+	for i := range outputCounts {
+		outputCounts[i] = splitOutputsPerTransaction
+		if i == len(outputCounts)-1 {
+			outputCounts[i] = count - uint64(i)*splitOutputsPerTransaction
+		}
+		fees[i] = w.managedMultiSendFee(outputCounts[i] + 1)
+		totalCost = totalCost.Add(fees[i]).Add(value.Mul64(outputCounts[i]))
+	}
+
+	txnBuilder, err := w.StartTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			txnBuilder.Drop()
+		}
+	}()
+	txnBuilder.AddMinerFee(fees[0])
+	if err = txnBuilder.FundSiacoins(totalCost); err != nil {
+		return nil, build.ExtendErr("unable to fund split", err)
+	}
+
+	// Generate the addresses of the outputs and the carry outputs.
+	w.mu.Lock()
+	ucs, err := w.nextPrimarySeedAddresses(w.dbTx, count+numTxns-1)
+	if err == nil {
+		err = w.syncDB()
+	}
+	w.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	nextAddress := func() types.UnlockConditions {
+		uc := ucs[0]
+		ucs = ucs[1:]
+		return uc
+	}
+
+	// The first transaction is funded by the transaction builder.
+	remaining := totalCost.Sub(fees[0])
+	for j := uint64(0); j < outputCounts[0]; j++ {
+		txnBuilder.AddSiacoinOutput(types.SiacoinOutput{
+			Value:      value,
+			UnlockHash: nextAddress().UnlockHash(),
+		})
+	}
+	remaining = remaining.Sub(value.Mul64(outputCounts[0]))
+	var carryUC types.UnlockConditions
+	var carryIndex uint64
+	if numTxns > 1 {
+		carryUC = nextAddress()
+		carryIndex = txnBuilder.AddSiacoinOutput(types.SiacoinOutput{
+			Value:      remaining,
+			UnlockHash: carryUC.UnlockHash(),
+		})
+	}
+	txnSet, err := txnBuilder.Sign(true)
+	if err != nil {
+		return nil, build.ExtendErr("unable to sign split", err)
+	}
+
+	// Every following transaction spends the carry output of the previous
+	// one.
+	prev := txnSet[len(txnSet)-1]
+	for i := uint64(1); i < numTxns; i++ {
+		parentID := prev.SiacoinOutputID(carryIndex)
+		parentUC := carryUC
+		txn := types.Transaction{
+			SiacoinInputs: []types.SiacoinInput{{
+				ParentID:         parentID,
+				UnlockConditions: parentUC,
+			}},
+			MinerFees: []types.Currency{fees[i]},
+		}
+		remaining = remaining.Sub(fees[i])
+		for j := uint64(0); j < outputCounts[i]; j++ {
+			txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+				Value:      value,
+				UnlockHash: nextAddress().UnlockHash(),
+			})
+		}
+		remaining = remaining.Sub(value.Mul64(outputCounts[i]))
+		if i < numTxns-1 {
+			carryUC = nextAddress()
+			carryIndex = uint64(len(txn.SiacoinOutputs))
+			txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+				Value:      remaining,
+				UnlockHash: carryUC.UnlockHash(),
+			})
+		}
+		w.mu.RLock()
+		addSignatures(&txn, types.FullCoveredFields, parentUC, crypto.Hash(parentID), w.keys[parentUC.UnlockHash()])
+		w.mu.RUnlock()
+		txnSet = append(txnSet, txn)
+		prev = txn
+	}
+
+	if err = w.tpool.AcceptTransactionSet(txnSet); err != nil {
+		w.log.Println("Attempt to split outputs has failed - transaction pool rejected transaction:", err)
+		return nil, build.ExtendErr("unable to get transaction accepted", err)
+	}
+	w.log.Printf("Successfully split %v into %v outputs of %v", totalCost.HumanString(), count, value.HumanString())
+	return txnSet, nil
+}
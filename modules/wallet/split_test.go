@@ -0,0 +1,72 @@
+package wallet
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// TestSplitOutputs checks that SplitOutputs creates the requested number of
+// outputs, spreads them across a chain of transactions and that the chain is
+// accepted into the blockchain.
+func TestSplitOutputs(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Invalid splits should be rejected.
+	if _, err := wt.wallet.SplitOutputs(0, types.SiacoinPrecision); err != errZeroSplitCount {
+		t.Fatal("expected errZeroSplitCount, got", err)
+	}
+	if _, err := wt.wallet.SplitOutputs(10, types.ZeroCurrency); err != errZeroSplitValue {
+		t.Fatal("expected errZeroSplitValue, got", err)
+	}
+	if _, err := wt.wallet.SplitOutputs(maxSplitOutputs+1, types.SiacoinPrecision); err != errTooManySplitOutputs {
+		t.Fatal("expected errTooManySplitOutputs, got", err)
+	}
+
+	// Split into more outputs than fit into a single transaction.
+	count := uint64(splitOutputsPerTransaction + 50)
+	txns, err := wt.wallet.SplitOutputs(count, types.SiacoinPrecision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outputs uint64
+	for _, txn := range txns {
+		for _, sco := range txn.SiacoinOutputs {
+			if sco.Value.Equals(types.SiacoinPrecision) {
+				outputs++
+			}
+		}
+	}
+	if outputs != count {
+		t.Fatalf("expected %v outputs, got %v", count, outputs)
+	}
+	last := txns[len(txns)-1]
+	if len(last.SiacoinOutputs) != 50 || len(last.SiacoinInputs) != 1 {
+		t.Fatal("expected the last transaction to spend the carry output of the previous one")
+	}
+	if last.SiacoinInputs[0].ParentID != txns[len(txns)-2].SiacoinOutputID(splitOutputsPerTransaction) {
+		t.Fatal("last transaction does not spend the carry output")
+	}
+
+	// Mine a block and check that the split was confirmed.
+	if err := wt.addBlockNoPayout(); err != nil {
+		t.Fatal(err)
+	}
+	for _, txn := range txns {
+		pt, found, err := wt.wallet.Transaction(txn.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || pt.ConfirmationHeight == types.BlockHeight(0) {
+			t.Fatal("split transaction was not confirmed")
+		}
+	}
+}
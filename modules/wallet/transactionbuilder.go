@@ -22,9 +22,18 @@ var (
 	// errDustOutput indicates an output is not spendable because it is dust.
 	errDustOutput = errors.New("output is too small")
 
+	// errInsufficientFundsAfterExclusions indicates that the wallet would
+	// have enough spendable outputs to fund a transaction, but not without
+	// the outputs that were excluded explicitly.
+	errInsufficientFundsAfterExclusions = errors.New("insufficient spendable funds after exclusions")
+
 	// errOutputTimelock indicates an output's timelock is still active.
 	errOutputTimelock = errors.New("wallet consensus set height is lower than the output timelock")
 
+	// errRescanning indicates that the wallet is rebuilding its set of
+	// outputs and cannot spend any of them until the rescan has completed.
+	errRescanning = errors.New("cannot spend outputs while the wallet is rescanning")
+
 	// errSpendHeightTooHigh indicates an output's spend height is greater than
 	// the allowed height.
 	errSpendHeightTooHigh = errors.New("output spend height exceeds the allowed height")
@@ -44,6 +53,13 @@ type transactionBuilder struct {
 	siafundInputs         []int
 	transactionSignatures []int
 
+	// excludedAddresses are addresses whose outputs are not used to fund
+	// the transaction.
+	excludedAddresses map[types.UnlockHash]struct{}
+
+	// excludedOutputs are outputs that are not used to fund the transaction.
+	excludedOutputs map[types.SiacoinOutputID]struct{}
+
 	wallet *Wallet
 }
 
@@ -97,6 +113,10 @@ func (w *Wallet) checkOutput(tx *bolt.Tx, currentHeight types.BlockHeight, id ty
 	if output.Value.Cmp(dustThreshold) < 0 {
 		return errDustOutput
 	}
+	// Check that the output has not been locked by the user.
+	if dbGetLockedOutput(tx, id) {
+		return errOutputLocked
+	}
 	// Check that this output has not recently been spent by the wallet.
 	spendHeight, err := dbGetSpentOutput(tx, types.OutputID(id))
 	if err == nil {
@@ -112,6 +132,34 @@ func (w *Wallet) checkOutput(tx *bolt.Tx, currentHeight types.BlockHeight, id ty
 	return nil
 }
 
+// sortedSiacoinOutputs returns the confirmed and unconfirmed siacoin outputs
+// of the wallet, sorted from the largest to the smallest value. The caller
+// must hold the wallet lock.
+func (w *Wallet) sortedSiacoinOutputs() (sortedOutputs, error) {
+	var so sortedOutputs
+	err := dbForEachSiacoinOutput(w.dbTx, func(scoid types.SiacoinOutputID, sco types.SiacoinOutput) {
+		so.ids = append(so.ids, scoid)
+		so.outputs = append(so.outputs, sco)
+	})
+	if err != nil {
+		return sortedOutputs{}, err
+	}
+	// Add all of the unconfirmed outputs as well.
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for i, sco := range upt.Transaction.SiacoinOutputs {
+			// Determine if the output belongs to the wallet.
+			_, exists := w.keys[sco.UnlockHash]
+			if !exists {
+				continue
+			}
+			so.ids = append(so.ids, upt.Transaction.SiacoinOutputID(uint64(i)))
+			so.outputs = append(so.outputs, sco)
+		}
+	}
+	sort.Sort(sort.Reverse(so))
+	return so, nil
+}
+
 // FundSiacoins will add a siacoin input of exactly 'amount' to the
 // transaction. A parent transaction may be needed to achieve an input with the
 // correct value. The siacoin input will not be signed until 'Sign' is called
@@ -125,6 +173,9 @@ func (tb *transactionBuilder) FundSiacoins(amount types.Currency) error {
 
 	tb.wallet.mu.Lock()
 	defer tb.wallet.mu.Unlock()
+	if tb.wallet.rescanning {
+		return errRescanning
+	}
 
 	consensusHeight, err := dbGetConsensusHeight(tb.wallet.dbTx)
 	if err != nil {
@@ -132,69 +183,25 @@ func (tb *transactionBuilder) FundSiacoins(amount types.Currency) error {
 	}
 
 	// Collect a value-sorted set of siacoin outputs.
-	var so sortedOutputs
-	err = dbForEachSiacoinOutput(tb.wallet.dbTx, func(scoid types.SiacoinOutputID, sco types.SiacoinOutput) {
-		so.ids = append(so.ids, scoid)
-		so.outputs = append(so.outputs, sco)
-	})
+	so, err := tb.wallet.sortedSiacoinOutputs()
 	if err != nil {
 		return err
 	}
-	// Add all of the unconfirmed outputs as well.
-	for _, upt := range tb.wallet.unconfirmedProcessedTransactions {
-		for i, sco := range upt.Transaction.SiacoinOutputs {
-			// Determine if the output belongs to the wallet.
-			_, exists := tb.wallet.keys[sco.UnlockHash]
-			if !exists {
-				continue
-			}
-			so.ids = append(so.ids, upt.Transaction.SiacoinOutputID(uint64(i)))
-			so.outputs = append(so.outputs, sco)
-		}
-	}
-	sort.Sort(sort.Reverse(so))
 
-	// Create and fund a parent transaction that will add the correct amount of
-	// siacoins to the transaction.
-	var fund types.Currency
-	// potentialFund tracks the balance of the wallet including outputs that
-	// have been spent in other unconfirmed transactions recently. This is to
-	// provide the user with a more useful error message in the event that they
-	// are overspending.
-	var potentialFund types.Currency
+	// Select the outputs that fund a parent transaction that will add the
+	// correct amount of siacoins to the transaction.
+	selection, err := tb.wallet.selectSiacoinOutputs(so, amount, consensusHeight, dustThreshold, tb.excludedAddresses, tb.excludedOutputs)
+	if err != nil {
+		return err
+	}
+	fund := selection.fund
 	parentTxn := types.Transaction{}
-	var spentScoids []types.SiacoinOutputID
-	for i := range so.ids {
-		scoid := so.ids[i]
-		sco := so.outputs[i]
-		// Check that the output can be spent.
-		if err := tb.wallet.checkOutput(tb.wallet.dbTx, consensusHeight, scoid, sco, dustThreshold); err != nil {
-			if err == errSpendHeightTooHigh {
-				potentialFund = potentialFund.Add(sco.Value)
-			}
-			continue
-		}
-
-		// Add a siacoin input for this output.
-		sci := types.SiacoinInput{
+	spentScoids := selection.ids
+	for i, scoid := range selection.ids {
+		parentTxn.SiacoinInputs = append(parentTxn.SiacoinInputs, types.SiacoinInput{
 			ParentID:         scoid,
-			UnlockConditions: tb.wallet.keys[sco.UnlockHash].UnlockConditions,
-		}
-		parentTxn.SiacoinInputs = append(parentTxn.SiacoinInputs, sci)
-		spentScoids = append(spentScoids, scoid)
-
-		// Add the output to the total fund
-		fund = fund.Add(sco.Value)
-		potentialFund = potentialFund.Add(sco.Value)
-		if fund.Cmp(amount) >= 0 {
-			break
-		}
-	}
-	if potentialFund.Cmp(amount) >= 0 && fund.Cmp(amount) < 0 {
-		return modules.ErrIncompleteTransactions
-	}
-	if fund.Cmp(amount) < 0 {
-		return modules.ErrLowBalance
+			UnlockConditions: tb.wallet.keys[selection.outputs[i].UnlockHash].UnlockConditions,
+		})
 	}
 
 	// Create and add the output that will be used to fund the standard
@@ -210,14 +217,22 @@ func (tb *transactionBuilder) FundSiacoins(amount types.Currency) error {
 	}
 	parentTxn.SiacoinOutputs = append(parentTxn.SiacoinOutputs, exactOutput)
 
-	// Create a refund output if needed.
-	if !amount.Equals(fund) {
+	// Create a refund output if needed. Change below the change threshold is
+	// not worth an output of its own and goes to the miners instead.
+	changeThreshold, err := dbGetChangeThreshold(tb.wallet.dbTx)
+	if err != nil {
+		return err
+	}
+	change := fund.Sub(amount)
+	if !change.IsZero() && change.Cmp(changeThreshold) < 0 {
+		parentTxn.MinerFees = append(parentTxn.MinerFees, change)
+	} else if !change.IsZero() {
 		refundUnlockConditions, err := tb.wallet.nextPrimarySeedAddress(tb.wallet.dbTx)
 		if err != nil {
 			return err
 		}
 		refundOutput := types.SiacoinOutput{
-			Value:      fund.Sub(amount),
+			Value:      change,
 			UnlockHash: refundUnlockConditions.UnlockHash(),
 		}
 		parentTxn.SiacoinOutputs = append(parentTxn.SiacoinOutputs, refundOutput)
@@ -261,6 +276,9 @@ func (tb *transactionBuilder) FundSiacoins(amount types.Currency) error {
 func (tb *transactionBuilder) FundSiafunds(amount types.Currency) error {
 	tb.wallet.mu.Lock()
 	defer tb.wallet.mu.Unlock()
+	if tb.wallet.rescanning {
+		return errRescanning
+	}
 
 	consensusHeight, err := dbGetConsensusHeight(tb.wallet.dbTx)
 	if err != nil {
@@ -0,0 +1,153 @@
+package wallet
+
+import (
+	"errors"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errInvalidTransactionGraphDepth is returned by TransactionGraph if the
+	// requested depth is negative or exceeds maxTransactionGraphDepth.
+	errInvalidTransactionGraphDepth = errors.New("transaction graph depth must be between 0 and 100")
+
+	// errUnknownTransaction is returned by TransactionGraph if the root
+	// transaction is not known to the wallet.
+	errUnknownTransaction = errors.New("transaction is not known to the wallet")
+)
+
+// transactionIndex maps the outputs of the wallet's transactions to the
+// transactions that create and spend them.
+type transactionIndex struct {
+	txns     map[types.TransactionID]modules.ProcessedTransaction
+	creators map[types.OutputID]types.TransactionID
+	spenders map[types.OutputID]types.TransactionID
+}
+
+// add adds a processed transaction to the index.
+func (ti *transactionIndex) add(pt modules.ProcessedTransaction) {
+	ti.txns[pt.TransactionID] = pt
+	for _, pi := range pt.Inputs {
+		ti.spenders[pi.ParentID] = pt.TransactionID
+	}
+	for _, po := range pt.Outputs {
+		ti.creators[po.ID] = pt.TransactionID
+	}
+}
+
+// parents returns the edges to the transactions that created the outputs
+// spent by pt.
+func (ti *transactionIndex) parents(pt modules.ProcessedTransaction) (edges []modules.TransactionGraphEdge) {
+	for _, pi := range pt.Inputs {
+		if parent, ok := ti.creators[pi.ParentID]; ok {
+			edges = append(edges, modules.TransactionGraphEdge{
+				OutputID: pi.ParentID,
+				Parent:   parent,
+				Child:    pt.TransactionID,
+			})
+		}
+	}
+	return
+}
+
+// children returns the edges to the transactions that spend the outputs
+// created by pt.
+func (ti *transactionIndex) children(pt modules.ProcessedTransaction) (edges []modules.TransactionGraphEdge) {
+	for _, po := range pt.Outputs {
+		if child, ok := ti.spenders[po.ID]; ok {
+			edges = append(edges, modules.TransactionGraphEdge{
+				OutputID: po.ID,
+				Parent:   pt.TransactionID,
+				Child:    child,
+			})
+		}
+	}
+	return
+}
+
+// TransactionGraph returns the subgraph of the wallet's transactions that
+// funded the transaction with the given id, directly or indirectly, and the
+// transactions that spend its outputs. The traversal stops depth transactions
+// away from the root; transactions whose parents or children were cut off are
+// marked as truncated. Since the wallet only tracks transactions that are
+// related to it, the graph ends wherever funds enter or leave the wallet.
+func (w *Wallet) TransactionGraph(txid types.TransactionID, depth int) (modules.TransactionGraph, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.TransactionGraph{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	if depth == 0 {
+		depth = defaultTransactionGraphDepth
+	} else if depth < 0 || depth > maxTransactionGraphDepth {
+		return modules.TransactionGraph{}, errInvalidTransactionGraphDepth
+	}
+
+	// Index the confirmed and unconfirmed transactions of the wallet.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.syncDB(); err != nil {
+		return modules.TransactionGraph{}, err
+	}
+	ti := transactionIndex{
+		txns:     make(map[types.TransactionID]modules.ProcessedTransaction),
+		creators: make(map[types.OutputID]types.TransactionID),
+		spenders: make(map[types.OutputID]types.TransactionID),
+	}
+	it := dbProcessedTransactionsIterator(w.dbTx)
+	for it.next() {
+		ti.add(it.value())
+	}
+	for _, pt := range w.unconfirmedProcessedTransactions {
+		ti.add(pt)
+	}
+	root, ok := ti.txns[txid]
+	if !ok {
+		return modules.TransactionGraph{}, errUnknownTransaction
+	}
+
+	graph := modules.TransactionGraph{
+		Root: txid,
+		Transactions: []modules.TransactionGraphNode{{
+			Transaction: root,
+		}},
+	}
+	visited := map[types.TransactionID]struct{}{txid: {}}
+
+	// Walk the parents and the children of the root separately, so that the
+	// graph only contains the ancestors and descendants of the root and not
+	// e.g. other transactions that were funded by the same parent.
+	walk := func(next func(modules.ProcessedTransaction) []modules.TransactionGraphEdge, neighbor func(modules.TransactionGraphEdge) types.TransactionID, sign int) {
+		frontier := []int{0} // indices into graph.Transactions
+		for d := 1; len(frontier) > 0; d++ {
+			var nextFrontier []int
+			for _, i := range frontier {
+				edges := next(graph.Transactions[i].Transaction)
+				if d > depth {
+					if len(edges) > 0 {
+						graph.Transactions[i].Truncated = true
+						graph.Truncated = true
+					}
+					continue
+				}
+				for _, e := range edges {
+					graph.Edges = append(graph.Edges, e)
+					id := neighbor(e)
+					if _, ok := visited[id]; ok {
+						continue
+					}
+					visited[id] = struct{}{}
+					graph.Transactions = append(graph.Transactions, modules.TransactionGraphNode{
+						Transaction: ti.txns[id],
+						Depth:       sign * d,
+					})
+					nextFrontier = append(nextFrontier, len(graph.Transactions)-1)
+				}
+			}
+			frontier = nextFrontier
+		}
+	}
+	walk(ti.parents, func(e modules.TransactionGraphEdge) types.TransactionID { return e.Parent }, -1)
+	walk(ti.children, func(e modules.TransactionGraphEdge) types.TransactionID { return e.Child }, 1)
+	return graph, nil
+}
@@ -0,0 +1,112 @@
+package wallet
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+// checkTransactionGraph checks that the edges of a transaction graph only
+// connect transactions of the graph and that the root is the first
+// transaction.
+func checkTransactionGraph(t *testing.T, graph modules.TransactionGraph) map[types.TransactionID]modules.TransactionGraphNode {
+	t.Helper()
+	if len(graph.Transactions) == 0 || graph.Transactions[0].Transaction.TransactionID != graph.Root || graph.Transactions[0].Depth != 0 {
+		t.Fatal("graph doesn't start with the root")
+	}
+	nodes := make(map[types.TransactionID]modules.TransactionGraphNode)
+	for _, node := range graph.Transactions {
+		nodes[node.Transaction.TransactionID] = node
+	}
+	for _, e := range graph.Edges {
+		parent, ok1 := nodes[e.Parent]
+		child, ok2 := nodes[e.Child]
+		if !ok1 || !ok2 {
+			t.Fatal("edge connects transactions that are not in the graph:", e)
+		}
+		if parent.Depth+1 != child.Depth {
+			t.Fatal("edge skips a level of the graph:", e)
+		}
+	}
+	return nodes
+}
+
+// TestTransactionGraph checks that the transaction graph contains the parents
+// and children of a transaction up to the requested depth.
+func TestTransactionGraph(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Invalid depths and unknown transactions are rejected.
+	if _, err := wt.wallet.TransactionGraph(types.TransactionID{}, -1); err != errInvalidTransactionGraphDepth {
+		t.Fatal("expected errInvalidTransactionGraphDepth, got", err)
+	}
+	if _, err := wt.wallet.TransactionGraph(types.TransactionID{}, maxTransactionGraphDepth+1); err != errInvalidTransactionGraphDepth {
+		t.Fatal("expected errInvalidTransactionGraphDepth, got", err)
+	}
+	if _, err := wt.wallet.TransactionGraph(types.TransactionID{}, 0); err != errUnknownTransaction {
+		t.Fatal("expected errUnknownTransaction, got", err)
+	}
+
+	// Sending coins creates a parent transaction that funds the transaction
+	// with the outputs, which is funded by miner payouts in turn.
+	txns, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 2 {
+		t.Fatal("expected a parent and a child transaction, got", len(txns))
+	}
+	parentID, childID := txns[0].ID(), txns[1].ID()
+
+	// With a depth of 1, only the parent is included and the traversal is
+	// truncated at the parent.
+	graph, err := wt.wallet.TransactionGraph(childID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := checkTransactionGraph(t, graph)
+	if len(nodes) != 2 || nodes[parentID].Depth != -1 {
+		t.Fatal("expected the parent at depth -1, got", graph.Transactions)
+	}
+	if !graph.Truncated || !nodes[parentID].Truncated || nodes[childID].Truncated {
+		t.Fatal("expected the graph to be truncated at the parent")
+	}
+
+	// With a larger depth, the miner payouts are included as well and the
+	// graph is complete.
+	graph, err = wt.wallet.TransactionGraph(childID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes = checkTransactionGraph(t, graph)
+	if len(nodes) < 3 || graph.Truncated {
+		t.Fatal("expected the complete graph, got", graph.Transactions)
+	}
+	for id, node := range nodes {
+		if id != parentID && id != childID && node.Depth != -2 {
+			t.Fatal("expected the miner payouts at depth -2, got", node.Depth)
+		}
+	}
+
+	// The graph of the parent contains the child. Confirming the transactions
+	// doesn't change the graph.
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	graph, err = wt.wallet.TransactionGraph(parentID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes = checkTransactionGraph(t, graph)
+	if nodes[childID].Depth != 1 || nodes[childID].Truncated {
+		t.Fatal("expected the child at depth 1, got", graph.Transactions)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"gitlab.com/NebulousLabs/Sia/encoding"
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/types"
+
+	"github.com/coreos/bbolt"
 )
 
 var (
@@ -78,6 +80,32 @@ func (w *Wallet) AddressUnconfirmedTransactions(uh types.UnlockHash) (pts []modu
 	return pts, err
 }
 
+// IncomingOutputs returns the siacoin outputs to an address that are created
+// by transactions in the transaction pool. Unlike
+// AddressUnconfirmedTransactions, the address doesn't need to belong to the
+// wallet, since the outputs are read from the transaction pool directly.
+func (w *Wallet) IncomingOutputs(uh types.UnlockHash) ([]modules.IncomingOutput, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer w.tg.Done()
+
+	var outputs []modules.IncomingOutput
+	for _, txn := range w.tpool.TransactionList() {
+		for i, sco := range txn.SiacoinOutputs {
+			if sco.UnlockHash != uh {
+				continue
+			}
+			outputs = append(outputs, modules.IncomingOutput{
+				ID:            txn.SiacoinOutputID(uint64(i)),
+				TransactionID: txn.ID(),
+				Value:         sco.Value,
+			})
+		}
+	}
+	return outputs, nil
+}
+
 // Transaction returns the transaction with the given id. 'False' is returned
 // if the transaction does not exist.
 func (w *Wallet) Transaction(txid types.TransactionID) (pt modules.ProcessedTransaction, found bool, err error) {
@@ -103,6 +131,48 @@ func (w *Wallet) Transaction(txid types.TransactionID) (pt modules.ProcessedTran
 	return
 }
 
+// searchProcessedTransactions returns the index of the first processed
+// transaction in bucket that was confirmed at or after height. If there is no
+// such transaction, bucket.Sequence()+1 is returned.
+func searchProcessedTransactions(bucket *bolt.Bucket, height types.BlockHeight) (index uint64, err error) {
+	cursor := bucket.Cursor()
+	nextKey := bucket.Sequence() + 1
+
+	// Database is empty
+	if nextKey == 1 {
+		return nextKey, nil
+	}
+
+	// Recover from possible panic during binary search
+	defer func() {
+		r := recover()
+		if r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	var pt modules.ProcessedTransaction
+	keyBytes := make([]byte, 8)
+	result := sort.Search(int(nextKey), func(i int) bool {
+		// Create the key for the index
+		binary.BigEndian.PutUint64(keyBytes, uint64(i))
+
+		// Retrieve the processed transaction
+		key, ptBytes := cursor.Seek(keyBytes)
+		if build.DEBUG && key == nil {
+			panic("Failed to retrieve processed Transaction by key")
+		}
+
+		// Decode the transaction
+		if err = decodeProcessedTransaction(ptBytes, &pt); build.DEBUG && err != nil {
+			panic(err)
+		}
+
+		return pt.ConfirmationHeight >= height
+	})
+	return uint64(result), err
+}
+
 // Transactions returns all transactions relevant to the wallet that were
 // confirmed in the range [startHeight, endHeight].
 func (w *Wallet) Transactions(startHeight, endHeight types.BlockHeight) (pts []modules.ProcessedTransaction, err error) {
@@ -124,60 +194,19 @@ func (w *Wallet) Transactions(startHeight, endHeight types.BlockHeight) (pts []m
 		return nil, errOutOfBounds
 	}
 
-	// Get the bucket, the largest key in it and the cursor
+	// Find the first transaction confirmed at or after startHeight.
 	bucket := w.dbTx.Bucket(bucketProcessedTransactions)
 	cursor := bucket.Cursor()
-	nextKey := bucket.Sequence() + 1
-
-	// Database is empty
-	if nextKey == 1 {
-		return
-	}
-
-	var pt modules.ProcessedTransaction
-	keyBytes := make([]byte, 8)
-	var result int
-	func() {
-		// Recover from possible panic during binary search
-		defer func() {
-			r := recover()
-			if r != nil {
-				err = fmt.Errorf("%v", r)
-			}
-		}()
-
-		// Start binary searching
-		result = sort.Search(int(nextKey), func(i int) bool {
-			// Create the key for the index
-			binary.BigEndian.PutUint64(keyBytes, uint64(i))
-
-			// Retrieve the processed transaction
-			key, ptBytes := cursor.Seek(keyBytes)
-			if build.DEBUG && key == nil {
-				panic("Failed to retrieve processed Transaction by key")
-			}
-
-			// Decode the transaction
-			if err = decodeProcessedTransaction(ptBytes, &pt); build.DEBUG && err != nil {
-				panic(err)
-			}
-
-			return pt.ConfirmationHeight >= startHeight
-		})
-	}()
-	if err != nil {
-		return
-	}
-
-	if uint64(result) == nextKey {
+	index, err := searchProcessedTransactions(bucket, startHeight)
+	if err != nil || index == bucket.Sequence()+1 {
 		// No transaction was found
 		return
 	}
 
-	// Create the key that corresponds to the result of the search
-	binary.BigEndian.PutUint64(keyBytes, uint64(result))
-
 	// Get the processed transaction and decode it
+	var pt modules.ProcessedTransaction
+	keyBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(keyBytes, index)
 	key, ptBytes := cursor.Seek(keyBytes)
 	if build.DEBUG && key == nil {
 		build.Critical("Couldn't find the processed transaction from the search.")
@@ -218,3 +247,115 @@ func (w *Wallet) UnconfirmedTransactions() ([]modules.ProcessedTransaction, erro
 	defer w.mu.RUnlock()
 	return w.unconfirmedProcessedTransactions, nil
 }
+
+// ConfirmationDepth returns the number of blocks that need to be built on top
+// of a transaction's block before the wallet treats the transaction as
+// confirmed. Until then, the transaction is confirming. A depth of zero treats
+// transactions as confirmed as soon as they are in a block.
+func (w *Wallet) ConfirmationDepth() (types.BlockHeight, error) {
+	if err := w.tg.Add(); err != nil {
+		return 0, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbGetConfirmationDepth(w.dbTx)
+}
+
+// SetConfirmationDepth sets the number of blocks that need to be built on top
+// of a transaction's block before the wallet treats the transaction as
+// confirmed. This protects the confirmed balance from reorgs. The depth is
+// persisted in the wallet's database.
+func (w *Wallet) SetConfirmationDepth(depth types.BlockHeight) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbPutConfirmationDepth(w.dbTx, depth)
+}
+
+// ConfirmingTransactions returns the transactions that are in a block but
+// don't have the wallet's confirmation depth yet.
+func (w *Wallet) ConfirmingTransactions() ([]modules.ProcessedTransaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	depth, err := dbGetConfirmationDepth(w.dbTx)
+	if err != nil {
+		w.mu.Unlock()
+		return nil, err
+	}
+	height, err := dbGetConsensusHeight(w.dbTx)
+	w.mu.Unlock()
+	if err != nil || depth == 0 {
+		return nil, err
+	}
+
+	// A transaction confirmed at height h has height-h blocks on top of it.
+	var start types.BlockHeight
+	if height >= depth {
+		start = height - depth + 1
+	}
+	return w.Transactions(start, height)
+}
+
+// Conflicts returns the siacoin inputs of the wallet's unconfirmed
+// transactions that other transactions tried to spend as well, according to
+// the transaction pool. A conflict means that a transaction of the wallet may
+// be replaced by a double spend before it is confirmed.
+func (w *Wallet) Conflicts() ([]modules.WalletConflict, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer w.tg.Done()
+	w.mu.RLock()
+	upts := append([]modules.ProcessedTransaction(nil), w.unconfirmedProcessedTransactions...)
+	w.mu.RUnlock()
+
+	// The transaction pool is queried without holding the wallet lock.
+	var conflicts []modules.WalletConflict
+	for _, upt := range upts {
+		for _, sci := range upt.Transaction.SiacoinInputs {
+			txids := w.tpool.ConflictingTransactions(sci.ParentID)
+			if len(txids) == 0 {
+				continue
+			}
+			conflicts = append(conflicts, modules.WalletConflict{
+				TransactionID:             upt.TransactionID,
+				OutputID:                  sci.ParentID,
+				ConflictingTransactionIDs: txids,
+			})
+		}
+	}
+	return conflicts, nil
+}
+
+// ReservedOutputs returns the ids of the wallet's siacoin outputs that are
+// spent by unconfirmed transactions. These outputs can't be used to fund new
+// transactions. An output is no longer reserved once the transaction spending
+// it is confirmed or dropped from the transaction pool.
+func (w *Wallet) ReservedOutputs() ([]types.SiacoinOutputID, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer w.tg.Done()
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var ids []types.SiacoinOutputID
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, input := range upt.Inputs {
+			if input.FundType == types.SpecifierSiacoinInput && input.WalletAddress {
+				ids = append(ids, types.SiacoinOutputID(input.ParentID))
+			}
+		}
+	}
+	return ids, nil
+}
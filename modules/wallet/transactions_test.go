@@ -558,3 +558,209 @@ func BenchmarkAddressTransactions(b *testing.B) {
 		}
 	})
 }
+
+// TestConflicts checks that a rejected double spend of an input of an
+// unconfirmed wallet transaction is reported as a conflict.
+func TestConflicts(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Create two transaction sets that spend the same output. The signatures
+	// don't cover the outputs and fees, so they are valid for both sets.
+	fund := types.SiacoinPrecision
+	txnBuilder, err := wt.wallet.StartTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := txnBuilder.FundSiacoins(fund); err != nil {
+		t.Fatal(err)
+	}
+	txnSet, err := txnBuilder.Sign(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doubleSpend := make([]types.Transaction, len(txnSet))
+	copy(doubleSpend, txnSet)
+	last := len(txnSet) - 1
+	txnSet[last].MinerFees = append(txnSet[last].MinerFees, fund)
+	doubleSpend[last].SiacoinOutputs = append(doubleSpend[last].SiacoinOutputs, types.SiacoinOutput{Value: fund})
+
+	if conflicts, err := wt.wallet.Conflicts(); err != nil || len(conflicts) != 0 {
+		t.Fatal("expected no conflicts", conflicts, err)
+	}
+	if err := wt.tpool.AcceptTransactionSet(txnSet); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.tpool.AcceptTransactionSet(doubleSpend); err == nil {
+		t.Fatal("double spend was accepted")
+	}
+
+	conflicts, err := wt.wallet.Conflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %v", len(conflicts))
+	}
+	c := conflicts[0]
+	if c.TransactionID != txnSet[last].ID() || c.OutputID != txnSet[last].SiacoinInputs[0].ParentID {
+		t.Fatal("wrong transaction or output reported:", c)
+	}
+	if len(c.ConflictingTransactionIDs) != 1 || c.ConflictingTransactionIDs[0] != doubleSpend[last].ID() {
+		t.Fatal("wrong conflicting transactions reported:", c.ConflictingTransactionIDs)
+	}
+}
+
+// TestReservedOutputs checks that the outputs spent by an unconfirmed
+// transaction are reserved until the transaction is confirmed.
+func TestReservedOutputs(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	if ids, err := wt.wallet.ReservedOutputs(); err != nil || len(ids) != 0 {
+		t.Fatal("expected no reserved outputs, got", ids, err)
+	}
+
+	// Every wallet input of the sent transactions should be reserved.
+	txns, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := wt.wallet.ReservedOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reserved := make(map[types.SiacoinOutputID]struct{})
+	for _, id := range ids {
+		reserved[id] = struct{}{}
+	}
+	for _, txn := range txns {
+		for _, sci := range txn.SiacoinInputs {
+			if _, ok := reserved[sci.ParentID]; !ok {
+				t.Fatal("input of unconfirmed transaction is not reserved:", sci.ParentID)
+			}
+		}
+	}
+
+	// The reservations are cleared once the transactions are confirmed.
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if ids, err := wt.wallet.ReservedOutputs(); err != nil || len(ids) != 0 {
+		t.Fatal("expected no reserved outputs after confirmation, got", ids, err)
+	}
+}
+
+// TestIncomingOutputs checks that the unconfirmed outputs to an address are
+// reported, regardless of whether the address belongs to the wallet.
+func TestIncomingOutputs(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Send coins to an address that doesn't belong to the wallet.
+	addr := types.UnlockHash{1}
+	if outputs, err := wt.wallet.IncomingOutputs(addr); err != nil || len(outputs) != 0 {
+		t.Fatal("expected no incoming outputs, got", outputs, err)
+	}
+	txns, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs, err := wt.wallet.IncomingOutputs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn := txns[len(txns)-1]
+	if len(outputs) != 1 {
+		t.Fatal("expected 1 incoming output, got", len(outputs))
+	}
+	if outputs[0].TransactionID != txn.ID() || outputs[0].ID != txn.SiacoinOutputID(0) || !outputs[0].Value.Equals(types.SiacoinPrecision) {
+		t.Fatal("incoming output doesn't match the sent transaction:", outputs[0])
+	}
+
+	// The outputs of the parent transaction go to addresses of the wallet,
+	// which are reported as well.
+	parentOutput := txns[0].SiacoinOutputs[0]
+	if outputs, err := wt.wallet.IncomingOutputs(parentOutput.UnlockHash); err != nil || len(outputs) == 0 {
+		t.Fatal("expected the output to the wallet to be reported, got", outputs, err)
+	}
+
+	// The outputs are no longer incoming once they are confirmed.
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if outputs, err := wt.wallet.IncomingOutputs(addr); err != nil || len(outputs) != 0 {
+		t.Fatal("expected no incoming outputs after confirmation, got", outputs, err)
+	}
+}
+
+// TestConfirmingTransactions checks that transactions are confirming until
+// they have the wallet's confirmation depth.
+func TestConfirmingTransactions(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// By default, transactions are confirmed right away.
+	if pts, err := wt.wallet.ConfirmingTransactions(); err != nil || len(pts) != 0 {
+		t.Fatal("expected no confirming transactions, got", len(pts), err)
+	}
+	if err := wt.wallet.SetConfirmationDepth(2); err != nil {
+		t.Fatal(err)
+	}
+	if depth, err := wt.wallet.ConfirmationDepth(); err != nil || depth != 2 {
+		t.Fatal("expected a confirmation depth of 2, got", depth, err)
+	}
+
+	txns, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txid := txns[len(txns)-1].ID()
+	isConfirming := func() bool {
+		pts, err := wt.wallet.ConfirmingTransactions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pt := range pts {
+			if pt.TransactionID == txid {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The transaction is confirming until two blocks are built on top of its
+	// block.
+	for i := 0; i < 3; i++ {
+		if _, err := wt.miner.AddBlock(); err != nil {
+			t.Fatal(err)
+		}
+		if confirming := isConfirming(); confirming != (i < 2) {
+			t.Fatalf("block %v: expected confirming to be %v", i, i < 2)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package wallet
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 
 	"gitlab.com/NebulousLabs/Sia/crypto"
 	"gitlab.com/NebulousLabs/Sia/encoding"
@@ -122,24 +124,48 @@ func (w *Wallet) loadSpendableKey(masterKey crypto.TwofishKey, sk spendableKey)
 	// solution.
 }
 
-// loadSiagKeys loads a set of siag keyfiles into the wallet, so that the
-// wallet may spend the siafunds.
-func (w *Wallet) loadSiagKeys(masterKey crypto.TwofishKey, keyfiles []string) error {
-	// Load the keyfiles from disk.
+// readSiagKeyFiles reads a set of siag keyfiles from disk.
+func readSiagKeyFiles(keyfiles []string) ([]siagKeyPair, error) {
 	if len(keyfiles) < 1 {
-		return ErrNoKeyfile
+		return nil, ErrNoKeyfile
 	}
 	skps := make([]siagKeyPair, len(keyfiles))
 	for i, keyfile := range keyfiles {
 		err := encoding.ReadFile(keyfile, &skps[i])
 		if err != nil {
-			return err
+			return nil, err
+		}
+	}
+	return skps, nil
+}
+
+// decodeSiagKeyData decodes a set of siag keys from the raw contents of siag
+// keyfiles.
+func decodeSiagKeyData(keydata [][]byte) ([]siagKeyPair, error) {
+	if len(keydata) < 1 {
+		return nil, ErrNoKeyfile
+	}
+	skps := make([]siagKeyPair, len(keydata))
+	for i, data := range keydata {
+		err := encoding.NewDecoder(bytes.NewReader(data)).Decode(&skps[i])
+		if err != nil {
+			return nil, fmt.Errorf("error while decoding key %v: %v", i, err)
 		}
+	}
+	return skps, nil
+}
 
-		if skps[i].Header != SiagFileHeader {
+// loadSiagKeys loads a set of siag keys into the wallet, so that the wallet
+// may spend the siafunds.
+func (w *Wallet) loadSiagKeys(masterKey crypto.TwofishKey, skps []siagKeyPair) error {
+	if len(skps) < 1 {
+		return ErrNoKeyfile
+	}
+	for _, skp := range skps {
+		if skp.Header != SiagFileHeader {
 			return ErrUnknownHeader
 		}
-		if skps[i].Version != SiagFileVersion {
+		if skp.Version != SiagFileVersion {
 			return ErrUnknownVersion
 		}
 	}
@@ -172,18 +198,14 @@ func (w *Wallet) loadSiagKeys(masterKey crypto.TwofishKey, keyfiles []string) er
 	return nil
 }
 
-// LoadSiagKeys loads a set of siag-generated keys into the wallet.
-func (w *Wallet) LoadSiagKeys(masterKey crypto.TwofishKey, keyfiles []string) error {
-	if err := w.tg.Add(); err != nil {
-		return err
-	}
-	defer w.tg.Done()
-
+// managedLoadSiagKeys loads a set of siag keys into the wallet and rescans the
+// blockchain for their outputs.
+func (w *Wallet) managedLoadSiagKeys(masterKey crypto.TwofishKey, skps []siagKeyPair) error {
 	// load the keys and reset the consensus change ID and height in preparation for rescan
 	err := func() error {
 		w.mu.Lock()
 		defer w.mu.Unlock()
-		err := w.loadSiagKeys(masterKey, keyfiles)
+		err := w.loadSiagKeys(masterKey, skps)
 		if err != nil {
 			return err
 		}
@@ -195,6 +217,9 @@ func (w *Wallet) LoadSiagKeys(masterKey crypto.TwofishKey, keyfiles []string) er
 			return err
 		}
 		w.unconfirmedProcessedTransactions = nil
+		if err = dbDeleteHistoryStartHeight(w.dbTx); err != nil {
+			return err
+		}
 		err = dbPutConsensusChangeID(w.dbTx, modules.ConsensusChangeBeginning)
 		if err != nil {
 			return err
@@ -221,6 +246,35 @@ func (w *Wallet) LoadSiagKeys(masterKey crypto.TwofishKey, keyfiles []string) er
 	return nil
 }
 
+// LoadSiagKeys loads a set of siag-generated keys into the wallet.
+func (w *Wallet) LoadSiagKeys(masterKey crypto.TwofishKey, keyfiles []string) error {
+	if err := w.tg.Add(); err != nil {
+		return err
+	}
+	defer w.tg.Done()
+
+	skps, err := readSiagKeyFiles(keyfiles)
+	if err != nil {
+		return err
+	}
+	return w.managedLoadSiagKeys(masterKey, skps)
+}
+
+// LoadSiagKeyData loads a set of siag-generated keys into the wallet. Each
+// element of keydata holds the contents of a siag keyfile.
+func (w *Wallet) LoadSiagKeyData(masterKey crypto.TwofishKey, keydata [][]byte) error {
+	if err := w.tg.Add(); err != nil {
+		return err
+	}
+	defer w.tg.Done()
+
+	skps, err := decodeSiagKeyData(keydata)
+	if err != nil {
+		return err
+	}
+	return w.managedLoadSiagKeys(masterKey, skps)
+}
+
 // Load033xWallet loads a v0.3.3.x wallet as an unseeded key, such that the
 // funds become spendable to the current wallet.
 func (w *Wallet) Load033xWallet(masterKey crypto.TwofishKey, filepath033x string) error {
@@ -265,6 +319,9 @@ func (w *Wallet) Load033xWallet(masterKey crypto.TwofishKey, filepath033x string
 			return err
 		}
 		w.unconfirmedProcessedTransactions = nil
+		if err = dbDeleteHistoryStartHeight(w.dbTx); err != nil {
+			return err
+		}
 		err = dbPutConsensusChangeID(w.dbTx, modules.ConsensusChangeBeginning)
 		if err != nil {
 			return err
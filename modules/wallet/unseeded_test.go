@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"io/ioutil"
 	"testing"
 
 	"gitlab.com/NebulousLabs/Sia/modules"
@@ -96,3 +97,101 @@ func TestIntegrationLoad2of3Siag(t *testing.T) {
 		t.Error("expecting balance of 6988 after sending siafunds to the void")
 	}
 }
+
+// TestIntegrationLoadSiagKeyData loads a 2 of 3 unseeded key generated by
+// siag from the contents of its keyfiles rather than from their paths.
+func TestIntegrationLoadSiagKeyData(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	var keydata [][]byte
+	for _, keyfile := range []string{"../../types/siag0of2of3.siakey", "../../types/siag1of2of3.siakey"} {
+		data, err := ioutil.ReadFile(keyfile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keydata = append(keydata, data)
+	}
+
+	// Malformed and incomplete key data should be rejected.
+	if err := wt.wallet.LoadSiagKeyData(wt.walletMasterKey, nil); err != ErrNoKeyfile {
+		t.Fatal("expected ErrNoKeyfile, got", err)
+	}
+	if err := wt.wallet.LoadSiagKeyData(wt.walletMasterKey, [][]byte{keydata[0][:10]}); err == nil {
+		t.Fatal("expected truncated key data to be rejected")
+	}
+	if err := wt.wallet.LoadSiagKeyData(wt.walletMasterKey, keydata[:1]); err != ErrInsufficientKeys {
+		t.Fatal("expected ErrInsufficientKeys, got", err)
+	}
+
+	// Load the key into the wallet.
+	err = wt.wallet.LoadSiagKeyData(wt.walletMasterKey, keydata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, siafundBal, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !siafundBal.Equals64(7000) {
+		t.Error("expecting a siafund balance of 7000 from the 2of3 key")
+	}
+}
+
+// TestIntegrationSendSiafundsMulti loads a 1 of 1 unseeded key generated by
+// siag and then sends the siafunds contained within to multiple addresses.
+func TestIntegrationSendSiafundsMulti(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Load the key into the wallet.
+	err = wt.wallet.LoadSiagKeys(wt.walletMasterKey, []string{"../../types/siag0of1of1.siakey"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Send some siafunds to two addresses in the void.
+	outputs := []types.SiafundOutput{
+		{Value: types.NewCurrency64(12), UnlockHash: types.UnlockHash{1}},
+		{Value: types.NewCurrency64(8), UnlockHash: types.UnlockHash{2}},
+	}
+	txns, err := wt.wallet.SendSiafundsMulti(outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastTxn := txns[len(txns)-1]
+	for _, sfo := range outputs {
+		found := false
+		for _, txnOutput := range lastTxn.SiafundOutputs {
+			if txnOutput.UnlockHash == sfo.UnlockHash && txnOutput.Value.Equals(sfo.Value) {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatal("transaction is missing an output for", sfo.UnlockHash)
+		}
+	}
+	_, err = wt.miner.AddBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, siafundBal, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !siafundBal.Equals64(1980) {
+		t.Error("expecting balance of 1980 after sending siafunds to the void, got", siafundBal)
+	}
+}
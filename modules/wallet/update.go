@@ -419,6 +419,16 @@ func (w *Wallet) applyHistory(tx *bolt.Tx, cc modules.ConsensusChange) error {
 			}
 		}
 
+		// Record where the history starts if this is the first block since
+		// the history was reset.
+		if _, err := dbGetHistoryStartHeight(tx); err == errNoKey {
+			if err := dbPutHistoryStartHeight(tx, consensusHeight); err != nil {
+				return errors.AddContext(err, "failed to store history start height in database")
+			}
+		} else if err != nil {
+			return errors.AddContext(err, "failed to get history start height")
+		}
+
 		pts := w.computeProcessedTransactionsFromBlock(tx, block, spentSiacoinOutputs, spentSiafundOutputs, consensusHeight)
 		for _, pt := range pts {
 			err := dbAppendProcessedTransaction(tx, pt)
@@ -439,6 +449,10 @@ func (w *Wallet) ProcessConsensusChange(cc modules.ConsensusChange) {
 	}
 	defer w.tg.Done()
 
+	// Slow down rescans if the wallet is throttled, so that they don't starve
+	// the rest of the node.
+	w.managedThrottleRescan()
+
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
@@ -0,0 +1,144 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+	"gitlab.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errInvalidVanityPrefix is returned by VanityAddress if the prefix is
+	// empty, is not hex encoded or is longer than an unlock hash.
+	errInvalidVanityPrefix = errors.New("vanity prefix must be a non-empty hex string of at most 64 characters")
+
+	// errInvalidVanityAttempts is returned by VanityAddress if the maximum
+	// number of attempts exceeds maxVanityAddressAttempts.
+	errInvalidVanityAttempts = errors.New("maximum number of attempts must be at most 100000")
+
+	// errVanityAddressTimeout is returned by VanityAddress if none of the
+	// addresses that were tried matched the prefix.
+	errVanityAddressTimeout = errors.New("timed out searching for an address matching the prefix")
+)
+
+// validVanityPrefix returns true if prefix can be matched against the hex
+// encoding of an unlock hash.
+func validVanityPrefix(prefix string) bool {
+	if len(prefix) == 0 || len(prefix) > 2*len(types.UnlockHash{}) {
+		return false
+	}
+	// Pad odd prefixes so that they can be decoded.
+	_, err := hex.DecodeString(prefix + strings.Repeat("0", len(prefix)%2))
+	return err == nil
+}
+
+// searchVanityAddress derives the spendable keys of seed, starting at index
+// start, until the unlock hash of a key starts with prefix. It returns the
+// derived keys, the last of which matches the prefix, or false if none of the
+// first maxAttempts keys match.
+func searchVanityAddress(seed modules.Seed, prefix string, start, maxAttempts uint64) ([]spendableKey, bool) {
+	var keys []spendableKey
+	for index := start; index < start+maxAttempts; index++ {
+		key := generateSpendableKey(seed, index)
+		keys = append(keys, key)
+		if strings.HasPrefix(key.UnlockConditions.UnlockHash().String(), prefix) {
+			return keys, true
+		}
+	}
+	return nil, false
+}
+
+// managedIssueVanityAddress adds keys, the spendable keys of the primary seed
+// starting at index start, to the wallet and hands out the address of the last
+// key. The seed progress is advanced past the last key. If the progress has
+// advanced past the last key since the search started, its address may have
+// been handed out already; the current progress is returned and the caller
+// has to search again.
+func (w *Wallet) managedIssueVanityAddress(start uint64, keys []spendableKey) (uint64, bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return 0, false, modules.ErrLockedWallet
+	}
+	progress, err := dbGetPrimarySeedProgress(w.dbTx)
+	if err != nil {
+		return 0, false, err
+	}
+	index := start + uint64(len(keys)) - 1
+	if progress < start || progress > index {
+		return progress, false, nil
+	}
+
+	// The keys before the current progress are tracked already. The skipped
+	// addresses were never handed out, so no rescan is needed to find funds
+	// that were sent to them.
+	for _, key := range keys[progress-start:] {
+		uh := key.UnlockConditions.UnlockHash()
+		w.keys[uh] = key
+		delete(w.lookahead, uh)
+	}
+	if err := dbPutPrimarySeedProgress(w.dbTx, index+1); err != nil {
+		return 0, false, err
+	}
+	w.regenerateLookahead(index + 1)
+	if err := dbPutIssuedAddress(w.dbTx, keys[len(keys)-1].UnlockConditions.UnlockHash()); err != nil {
+		return 0, false, err
+	}
+	return index + 1, true, w.syncDB()
+}
+
+// VanityAddress derives addresses from the primary seed, starting at the
+// current seed progress, until one of them starts with prefix. At most
+// maxAttempts addresses are tried; if maxAttempts is zero,
+// defaultVanityAddressAttempts is used. The matching address is returned
+// together with its index. The seed progress is advanced past the index, so
+// that the address is tracked by the wallet and recovered when the wallet is
+// restored from the seed. The addresses that were skipped are tracked as well
+// but are not considered handed out.
+func (w *Wallet) VanityAddress(prefix string, maxAttempts uint64) (types.UnlockConditions, uint64, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.UnlockConditions{}, 0, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	prefix = strings.ToLower(prefix)
+	if !validVanityPrefix(prefix) {
+		return types.UnlockConditions{}, 0, errInvalidVanityPrefix
+	}
+	if maxAttempts == 0 {
+		maxAttempts = defaultVanityAddressAttempts
+	} else if maxAttempts > maxVanityAddressAttempts {
+		return types.UnlockConditions{}, 0, errInvalidVanityAttempts
+	}
+
+	w.mu.Lock()
+	if !w.unlocked {
+		w.mu.Unlock()
+		return types.UnlockConditions{}, 0, modules.ErrLockedWallet
+	}
+	seed := w.primarySeed
+	progress, err := dbGetPrimarySeedProgress(w.dbTx)
+	w.mu.Unlock()
+	if err != nil {
+		return types.UnlockConditions{}, 0, err
+	}
+
+	// Deriving many keys takes a while, so the search doesn't hold the lock.
+	// If other addresses were handed out during the search, the search is
+	// repeated from the new progress.
+	for {
+		keys, found := searchVanityAddress(seed, prefix, progress, maxAttempts)
+		if !found {
+			return types.UnlockConditions{}, 0, errVanityAddressTimeout
+		}
+		start := progress
+		var issued bool
+		progress, issued, err = w.managedIssueVanityAddress(start, keys)
+		if err != nil {
+			return types.UnlockConditions{}, 0, err
+		} else if issued {
+			return keys[len(keys)-1].UnlockConditions, progress - 1, nil
+		}
+	}
+}
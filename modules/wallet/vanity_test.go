@@ -0,0 +1,99 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	"gitlab.com/NebulousLabs/Sia/modules"
+)
+
+// TestVanityAddress checks that VanityAddress returns an address matching the
+// prefix that can be derived again from its index.
+func TestVanityAddress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Invalid prefixes and attempt limits are rejected.
+	for _, prefix := range []string{"", "xyz", strings.Repeat("a", 65)} {
+		if _, _, err := wt.wallet.VanityAddress(prefix, 0); err != errInvalidVanityPrefix {
+			t.Fatalf("prefix %q: expected errInvalidVanityPrefix, got %v", prefix, err)
+		}
+	}
+	if _, _, err := wt.wallet.VanityAddress("a", maxVanityAddressAttempts+1); err != errInvalidVanityAttempts {
+		t.Fatal("expected errInvalidVanityAttempts, got", err)
+	}
+
+	// A full-length prefix is practically impossible to match.
+	if _, _, err := wt.wallet.VanityAddress(strings.Repeat("0", 64), 10); err != errVanityAddressTimeout {
+		t.Fatal("expected errVanityAddressTimeout, got", err)
+	}
+
+	progress, _, _, err := wt.wallet.PrimarySeedProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc, index, err := wt.wallet.VanityAddress("A", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(uc.UnlockHash().String(), "a") {
+		t.Fatal("address doesn't match the prefix:", uc.UnlockHash())
+	}
+	if index < progress {
+		t.Fatalf("address at index %v was already generated, progress was %v", index, progress)
+	}
+
+	// The address can be derived from its index and the seed progress was
+	// advanced past it.
+	derived, err := wt.wallet.PrimarySeedAddress(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if derived.UnlockHash() != uc.UnlockHash() {
+		t.Fatal("address doesn't match the derived address")
+	}
+	newProgress, _, _, err := wt.wallet.PrimarySeedProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newProgress != index+1 {
+		t.Fatalf("expected progress %v, got %v", index+1, newProgress)
+	}
+
+	// The address belongs to the wallet.
+	wt.wallet.mu.Lock()
+	_, tracked := wt.wallet.keys[uc.UnlockHash()]
+	issued := dbGetIssuedAddress(wt.wallet.dbTx, uc.UnlockHash())
+	wt.wallet.mu.Unlock()
+	if !tracked || !issued {
+		t.Fatal("address isn't tracked by the wallet")
+	}
+
+	// An address isn't handed out if the seed progress advanced past it
+	// during the search.
+	wt.wallet.mu.Lock()
+	seed := wt.wallet.primarySeed
+	wt.wallet.mu.Unlock()
+	keys, found := searchVanityAddress(seed, "a", newProgress, maxVanityAddressAttempts)
+	if !found {
+		t.Fatal("no address matches the prefix")
+	}
+	if _, err := wt.wallet.NextAddresses(uint64(len(keys))); err != nil {
+		t.Fatal(err)
+	}
+	progress, issued, err = wt.wallet.managedIssueVanityAddress(newProgress, keys)
+	if err != nil {
+		t.Fatal(err)
+	} else if issued {
+		t.Fatal("address was handed out twice")
+	} else if progress != newProgress+uint64(len(keys)) {
+		t.Fatalf("expected progress %v, got %v", newProgress+uint64(len(keys)), progress)
+	}
+}
@@ -33,6 +33,11 @@ const (
 var (
 	errNilConsensusSet = errors.New("wallet cannot initialize with a nil consensus set")
 	errNilTpool        = errors.New("wallet cannot initialize with a nil transaction pool")
+	errUnknownAddress  = errors.New("address does not belong to the wallet")
+
+	// errNotSingleKeyAddress is returned by SignMessage if the address can't
+	// be spent from with a single ed25519 key.
+	errNotSingleKeyAddress = errors.New("only standard single-key addresses can sign messages")
 )
 
 // spendableKey is a set of secret keys plus the corresponding unlock
@@ -104,6 +109,10 @@ type Wallet struct {
 	// initialization.
 	scanLock siasync.TryMutex
 
+	// rescanning is set while Rescan rebuilds the wallet's set of outputs.
+	// Outputs can't be spent while it is set, because the set is incomplete.
+	rescanning bool
+
 	// The wallet's ThreadGroup tells tracked functions to shut down and
 	// blocks until they have all exited before returning from Close.
 	tg threadgroup.ThreadGroup
@@ -133,6 +142,21 @@ func (w *Wallet) Height() (types.BlockHeight, error) {
 	return types.BlockHeight(height), nil
 }
 
+// ScanHeight returns the height up to which the wallet has processed consensus
+// changes. Unlike Height, it includes changes that haven't been synced to disk
+// yet. While it is below the height of the consensus set, the balances
+// reported by the wallet are provisional.
+func (w *Wallet) ScanHeight() (types.BlockHeight, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.BlockHeight(0), modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return dbGetConsensusHeight(w.dbTx)
+}
+
 // New creates a new wallet, loading any known addresses from the input file
 // name and then using the file to save in the future. Keys and addresses are
 // not loaded into the wallet during the call to 'new', but rather during the
@@ -169,6 +193,7 @@ func NewCustomWallet(cs modules.ConsensusSet, tpool modules.TransactionPool, per
 	if err != nil {
 		return nil, err
 	}
+	go w.threadedAutoDefrag()
 	return w, nil
 }
 
@@ -219,6 +244,97 @@ func (w *Wallet) AllAddresses() ([]types.UnlockHash, error) {
 	return addrs, nil
 }
 
+// UsedAddresses returns the addresses of the wallet that ever received funds in
+// a confirmed transaction, together with the siacoins and siafunds they
+// received in total. Miner payouts and siafund claims count as received
+// siacoins. Addresses are returned sorted in byte-order.
+func (w *Wallet) UsedAddresses() ([]modules.WalletUsedAddress, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	used := make(map[types.UnlockHash]*modules.WalletUsedAddress)
+	it := dbProcessedTransactionsIterator(w.dbTx)
+	for it.next() {
+		for _, output := range it.value().Outputs {
+			if !output.WalletAddress {
+				continue
+			}
+			ua, exists := used[output.RelatedAddress]
+			if !exists {
+				ua = &modules.WalletUsedAddress{Address: output.RelatedAddress}
+				used[output.RelatedAddress] = ua
+			}
+			switch output.FundType {
+			case types.SpecifierSiacoinOutput, types.SpecifierMinerPayout, types.SpecifierClaimOutput:
+				ua.SiacoinsReceived = ua.SiacoinsReceived.Add(output.Value)
+			case types.SpecifierSiafundOutput:
+				ua.SiafundsReceived = ua.SiafundsReceived.Add(output.Value)
+			}
+		}
+	}
+
+	addrs := make([]modules.WalletUsedAddress, 0, len(used))
+	for _, ua := range used {
+		addrs = append(addrs, *ua)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i].Address[:], addrs[j].Address[:]) < 0
+	})
+	return addrs, nil
+}
+
+// UnlockConditions returns the unlock conditions of an address that the wallet
+// is able to spend from. The wallet must be unlocked, since the keys of the
+// wallet are only known while it is unlocked.
+func (w *Wallet) UnlockConditions(addr types.UnlockHash) (types.UnlockConditions, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.UnlockConditions{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.unlocked {
+		return types.UnlockConditions{}, modules.ErrLockedWallet
+	}
+	sk, exists := w.keys[addr]
+	if !exists {
+		return types.UnlockConditions{}, errUnknownAddress
+	}
+	return sk.UnlockConditions, nil
+}
+
+// SignMessage signs hash with the key of an address that the wallet is able to
+// spend from. The signature covers modules.MessageSigHash(hash) rather than
+// hash itself, so that callers can't use it to obtain transaction signatures.
+// Only standard addresses with a single key and no timelock are supported.
+func (w *Wallet) SignMessage(addr types.UnlockHash, hash crypto.Hash) (types.SiaPublicKey, crypto.Signature, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.SiaPublicKey{}, crypto.Signature{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.unlocked {
+		return types.SiaPublicKey{}, crypto.Signature{}, modules.ErrLockedWallet
+	}
+	sk, exists := w.keys[addr]
+	if !exists {
+		return types.SiaPublicKey{}, crypto.Signature{}, errUnknownAddress
+	}
+	uc := sk.UnlockConditions
+	if uc.Timelock != 0 || uc.SignaturesRequired != 1 || len(uc.PublicKeys) != 1 || len(sk.SecretKeys) != 1 || uc.PublicKeys[0].Algorithm != types.SignatureEd25519 {
+		return types.SiaPublicKey{}, crypto.Signature{}, errNotSingleKeyAddress
+	}
+	return uc.PublicKeys[0], crypto.SignHash(modules.MessageSigHash(hash), sk.SecretKeys[0]), nil
+}
+
 // Rescanning reports whether the wallet is currently rescanning the
 // blockchain.
 func (w *Wallet) Rescanning() (bool, error) {
@@ -234,6 +350,83 @@ func (w *Wallet) Rescanning() (bool, error) {
 	return rescanning, nil
 }
 
+// Rescan rebuilds the wallet's outputs and transactions by replaying all
+// consensus changes from the beginning of the blockchain. The seeds and keys of
+// the wallet are kept. Outputs can't be spent until the rescan has completed.
+func (w *Wallet) Rescan() error {
+	if err := w.tg.Add(); err != nil {
+		return err
+	}
+	defer w.tg.Done()
+
+	if !w.scanLock.TryLock() {
+		return errScanInProgress
+	}
+	defer w.scanLock.Unlock()
+
+	// Reset the outputs, the transactions and the consensus change ID in
+	// preparation for the rescan. The spent outputs are kept since they track
+	// the wallet's own unconfirmed spends.
+	err := func() error {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if !w.unlocked {
+			return modules.ErrLockedWallet
+		}
+		buckets := [][]byte{
+			bucketProcessedTransactions,
+			bucketProcessedTxnIndex,
+			bucketAddrTransactions,
+			bucketSiacoinOutputs,
+			bucketSiafundOutputs,
+		}
+		for _, bucket := range buckets {
+			if err := w.dbTx.DeleteBucket(bucket); err != nil {
+				return err
+			}
+			if _, err := w.dbTx.CreateBucket(bucket); err != nil {
+				return err
+			}
+		}
+		w.unconfirmedProcessedTransactions = nil
+		if err := dbDeleteHistoryStartHeight(w.dbTx); err != nil {
+			return err
+		}
+		if err := dbPutConsensusChangeID(w.dbTx, modules.ConsensusChangeBeginning); err != nil {
+			return err
+		}
+		if err := dbPutConsensusHeight(w.dbTx, 0); err != nil {
+			return err
+		}
+		w.rescanning = true
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		w.mu.Lock()
+		w.rescanning = false
+		w.mu.Unlock()
+	}()
+	w.log.Println("INFO: Rescanning the blockchain.")
+
+	// rescan the blockchain
+	w.cs.Unsubscribe(w)
+	w.tpool.Unsubscribe(w)
+
+	done := make(chan struct{})
+	go w.rescanMessage(done)
+	defer close(done)
+
+	err = w.cs.ConsensusSetSubscribe(w, modules.ConsensusChangeBeginning, w.tg.StopChan())
+	if err != nil {
+		return err
+	}
+	w.tpool.TransactionPoolSubscribe(w)
+	return nil
+}
+
 // Settings returns the wallet's current settings
 func (w *Wallet) Settings() (modules.WalletSettings, error) {
 	if err := w.tg.Add(); err != nil {
@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"bytes"
+	"math"
 	"path/filepath"
 	"testing"
 	"time"
@@ -203,6 +205,68 @@ func TestAllAddresses(t *testing.T) {
 	}
 }
 
+// TestUsedAddresses checks that UsedAddresses reports the addresses that
+// received funds, along with the total they received.
+func TestUsedAddresses(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// The miner payouts of the wallet tester should show up.
+	used, err := wt.wallet.UsedAddresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(used) == 0 {
+		t.Fatal("expected the miner payout addresses to be used")
+	}
+
+	// An address that didn't receive anything yet should not show up.
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := uc.UnlockHash()
+	for _, ua := range used {
+		if ua.Address == addr {
+			t.Fatal("unused address was reported as used")
+		}
+	}
+
+	// Once funded and confirmed, the address should be reported with the
+	// amount it received.
+	if _, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.addBlockNoPayout(); err != nil {
+		t.Fatal(err)
+	}
+	used, err = wt.wallet.UsedAddresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for i, ua := range used {
+		if i > 0 && bytes.Compare(used[i-1].Address[:], ua.Address[:]) >= 0 {
+			t.Fatal("used addresses are not sorted")
+		}
+		if ua.Address == addr {
+			found = true
+			if !ua.SiacoinsReceived.Equals(types.SiacoinPrecision) {
+				t.Fatalf("expected %v to be received, got %v", types.SiacoinPrecision, ua.SiacoinsReceived)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("funded address was not reported as used")
+	}
+}
+
 // TestCloseWallet tries to close the wallet.
 func TestCloseWallet(t *testing.T) {
 	if testing.Short() {
@@ -290,6 +354,188 @@ func TestRescanning(t *testing.T) {
 	}
 }
 
+// TestScanHeight checks that ScanHeight follows the height of the consensus
+// set as blocks are processed.
+func TestScanHeight(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	for i := 0; i < 3; i++ {
+		scanHeight, err := wt.wallet.ScanHeight()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scanHeight != wt.cs.Height() {
+			t.Fatalf("scan height %v doesn't match consensus height %v", scanHeight, wt.cs.Height())
+		}
+		wt.addBlockNoPayout()
+	}
+}
+
+// TestUnlockConditions checks that UnlockConditions returns the unlock
+// conditions of wallet addresses only.
+func TestUnlockConditions(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc2, err := wt.wallet.UnlockConditions(uc.UnlockHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uc2.UnlockHash() != uc.UnlockHash() {
+		t.Fatal("wrong unlock conditions returned")
+	}
+
+	// Addresses that don't belong to the wallet are rejected.
+	if _, err := wt.wallet.UnlockConditions(types.UnlockHash{}); err != errUnknownAddress {
+		t.Fatal("expected errUnknownAddress, got", err)
+	}
+
+	// A locked wallet doesn't reveal any unlock conditions.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.wallet.UnlockConditions(uc.UnlockHash()); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+}
+
+// TestSignMessage checks that messages signed by the wallet can be verified
+// with the address's public key.
+func TestSignMessage(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := uc.UnlockHash()
+	hash := crypto.HashBytes([]byte("challenge"))
+	pk, sig, err := wt.wallet.SignMessage(addr, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := modules.VerifyMessageSignature(addr, pk, hash, sig); err != nil {
+		t.Fatal("valid signature was rejected:", err)
+	}
+
+	// The signature doesn't cover other hashes or addresses.
+	if err := modules.VerifyMessageSignature(addr, pk, crypto.HashBytes([]byte("other")), sig); err == nil {
+		t.Fatal("signature of a different hash was accepted")
+	}
+	if err := modules.VerifyMessageSignature(types.UnlockHash{}, pk, hash, sig); err != modules.ErrPublicKeyMismatch {
+		t.Fatal("expected ErrPublicKeyMismatch, got", err)
+	}
+
+	// The signature can't be used as a signature of the raw hash.
+	var edPK crypto.PublicKey
+	copy(edPK[:], pk.Key)
+	if err := crypto.VerifyHash(hash, edPK, sig); err == nil {
+		t.Fatal("signature is valid for the raw hash")
+	}
+
+	// Addresses that don't belong to the wallet can't sign.
+	if _, _, err := wt.wallet.SignMessage(types.UnlockHash{}, hash); err != errUnknownAddress {
+		t.Fatal("expected errUnknownAddress, got", err)
+	}
+
+	// A locked wallet can't sign.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := wt.wallet.SignMessage(addr, hash); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+}
+
+// TestRescan checks that Rescan rebuilds the wallet's outputs and that the
+// wallet can't spend outputs while a rescan is underway.
+func TestRescan(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	balance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txns, err := wt.wallet.Transactions(0, math.MaxUint64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rescanning should result in the same balance and transactions.
+	if err := wt.wallet.Rescan(); err != nil {
+		t.Fatal(err)
+	}
+	balance2, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance2.Equals(balance) {
+		t.Fatalf("balance changed from %v to %v after rescan", balance, balance2)
+	}
+	txns2, err := wt.wallet.Transactions(0, math.MaxUint64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns2) != len(txns) {
+		t.Fatalf("expected %v transactions after rescan, got %v", len(txns), len(txns2))
+	}
+
+	// Spending should be blocked while a rescan is underway.
+	wt.wallet.mu.Lock()
+	wt.wallet.rescanning = true
+	wt.wallet.mu.Unlock()
+	tb, err := wt.wallet.StartTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tb.FundSiacoins(types.SiacoinPrecision); err != errRescanning {
+		t.Fatal("expected errRescanning, got", err)
+	}
+	tb.Drop()
+	wt.wallet.mu.Lock()
+	wt.wallet.rescanning = false
+	wt.wallet.mu.Unlock()
+
+	// A locked wallet can't be rescanned.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.wallet.Rescan(); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+}
+
 // TestFutureAddressGeneration checks if the right amount of future addresses
 // is generated after calling NextAddress() or locking + unlocking the wallet.
 func TestLookaheadGeneration(t *testing.T) {
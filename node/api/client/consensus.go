@@ -13,6 +13,12 @@ func (c *Client) ConsensusGet() (cg api.ConsensusGET, err error) {
 	return
 }
 
+// ConsensusConstantsGet requests the /consensus/constants api resource
+func (c *Client) ConsensusConstantsGet() (ccg api.ConsensusConstantsGET, err error) {
+	err = c.get("/consensus/constants", &ccg)
+	return
+}
+
 // ConsensusBlocksIDGet requests the /consensus/blocks api resource
 func (c *Client) ConsensusBlocksIDGet(id types.BlockID) (cbg api.ConsensusBlocksGet, err error) {
 	err = c.get("/consensus/blocks?id="+id.String(), &cbg)
@@ -24,3 +30,21 @@ func (c *Client) ConsensusBlocksHeightGet(height types.BlockHeight) (cbg api.Con
 	err = c.get("/consensus/blocks?height="+fmt.Sprint(height), &cbg)
 	return
 }
+
+// ConsensusOutputGet requests the /consensus/output/:id api resource
+func (c *Client) ConsensusOutputGet(id types.SiacoinOutputID) (cog api.ConsensusOutputGET, err error) {
+	err = c.get("/consensus/output/"+id.String(), &cog)
+	return
+}
+
+// ConsensusBlockTimingGet requests the /consensus/blocktiming api resource
+func (c *Client) ConsensusBlockTimingGet() (cbtg api.ConsensusBlockTimingGET, err error) {
+	err = c.get("/consensus/blocktiming", &cbtg)
+	return
+}
+
+// ConsensusStatsGet requests the /consensus/stats api resource
+func (c *Client) ConsensusStatsGet() (csg api.ConsensusStatsGET, err error) {
+	err = c.get("/consensus/stats", &csg)
+	return
+}
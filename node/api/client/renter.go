@@ -9,8 +9,27 @@ import (
 
 	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/node/api"
+	"gitlab.com/NebulousLabs/Sia/types"
 )
 
+// RenterContractCancelPost uses the /renter/contract/cancel endpoint to cancel
+// a contract
+func (c *Client) RenterContractCancelPost(id types.FileContractID) (err error) {
+	values := url.Values{}
+	values.Set("id", id.String())
+	err = c.post("/renter/contract/cancel", values.Encode(), nil)
+	return
+}
+
+// RenterContractsRecoverPost uses the /renter/contracts/recover endpoint to
+// recover the renter's contracts from a seed.
+func (c *Client) RenterContractsRecoverPost(seed string) (err error) {
+	values := url.Values{}
+	values.Set("seed", seed)
+	err = c.post("/renter/contracts/recover", values.Encode(), nil)
+	return
+}
+
 // RenterContractsGet requests the /renter/contracts resource and returns
 // Contracts and ActiveContracts
 func (c *Client) RenterContractsGet() (rc api.RenterContracts, err error) {
@@ -147,6 +166,152 @@ func (c *Client) RenterCancelAllowance() (err error) {
 	return
 }
 
+// RenterAutoTopUpGet requests the /renter/autotopup endpoint's resources.
+func (c *Client) RenterAutoTopUpGet() (ratg api.RenterAutoTopUpGET, err error) {
+	err = c.get("/renter/autotopup", &ratg)
+	return
+}
+
+// RenterAutoTopUpPost uses the /renter/autotopup endpoint to set the settings
+// of the renter's automatic allowance top-up.
+func (c *Client) RenterAutoTopUpPost(threshold, amount, maxSpend types.Currency) (err error) {
+	values := url.Values{}
+	values.Set("threshold", threshold.String())
+	values.Set("amount", amount.String())
+	values.Set("maxspend", maxSpend.String())
+	err = c.post("/renter/autotopup", values.Encode(), nil)
+	return
+}
+
+// RenterMinContractsGet requests the /renter/mincontracts endpoint's
+// resources.
+func (c *Client) RenterMinContractsGet() (rmg api.RenterMinContractsGET, err error) {
+	err = c.get("/renter/mincontracts", &rmg)
+	return
+}
+
+// RenterMinContractsPost uses the /renter/mincontracts endpoint to set the
+// minimum number of contracts that need to be good for upload.
+func (c *Client) RenterMinContractsPost(minContracts uint64) (err error) {
+	values := url.Values{}
+	values.Set("mincontracts", fmt.Sprint(minContracts))
+	err = c.post("/renter/mincontracts", values.Encode(), nil)
+	return
+}
+
+// RenterPeriodAlignmentGet requests the /renter/periodalignment endpoint's
+// resources.
+func (c *Client) RenterPeriodAlignmentGet() (rpag api.RenterPeriodAlignmentGET, err error) {
+	err = c.get("/renter/periodalignment", &rpag)
+	return
+}
+
+// RenterPeriodAlignmentPost uses the /renter/periodalignment endpoint to align
+// the renter's allowance periods to multiples of the period length counted
+// from epoch.
+func (c *Client) RenterPeriodAlignmentPost(enabled bool, epoch types.BlockHeight) (err error) {
+	values := url.Values{}
+	values.Set("enabled", strconv.FormatBool(enabled))
+	values.Set("epoch", fmt.Sprint(epoch))
+	err = c.post("/renter/periodalignment", values.Encode(), nil)
+	return
+}
+
+// RenterPersistIntervalGet requests the /renter/persistinterval endpoint's
+// resources.
+func (c *Client) RenterPersistIntervalGet() (rpig api.RenterPersistIntervalGET, err error) {
+	err = c.get("/renter/persistinterval", &rpig)
+	return
+}
+
+// RenterPersistIntervalPost uses the /renter/persistinterval endpoint to set
+// the interval at which the renter flushes changes to its contracts to disk.
+func (c *Client) RenterPersistIntervalPost(interval time.Duration) (err error) {
+	values := url.Values{}
+	values.Set("interval", fmt.Sprint(uint64(interval/time.Millisecond)))
+	err = c.post("/renter/persistinterval", values.Encode(), nil)
+	return
+}
+
+// RenterPriceCeilingsGet requests the /renter/priceceilings endpoint's
+// resources.
+func (c *Client) RenterPriceCeilingsGet() (rpg api.RenterPriceCeilingsGET, err error) {
+	err = c.get("/renter/priceceilings", &rpg)
+	return
+}
+
+// RenterPriceCeilingsPost uses the /renter/priceceilings endpoint to set the
+// maximum prices of hosts that the renter forms and renews contracts with.
+func (c *Client) RenterPriceCeilingsPost(maxStoragePrice, maxDownloadPrice, maxUploadPrice types.Currency) (err error) {
+	values := url.Values{}
+	values.Set("maxstorageprice", maxStoragePrice.String())
+	values.Set("maxdownloadprice", maxDownloadPrice.String())
+	values.Set("maxuploadprice", maxUploadPrice.String())
+	err = c.post("/renter/priceceilings", values.Encode(), nil)
+	return
+}
+
+// RenterSpendingAlertGet requests the /renter/spendingalert endpoint's
+// resources.
+func (c *Client) RenterSpendingAlertGet() (rsag api.RenterSpendingAlertGET, err error) {
+	err = c.get("/renter/spendingalert", &rsag)
+	return
+}
+
+// RenterSpendingAlertPost uses the /renter/spendingalert endpoint to set the
+// fraction of the allowance that can be spent in a period before the renter
+// warns about it.
+func (c *Client) RenterSpendingAlertPost(threshold float64) (err error) {
+	values := url.Values{}
+	values.Set("threshold", strconv.FormatFloat(threshold, 'f', -1, 64))
+	err = c.post("/renter/spendingalert", values.Encode(), nil)
+	return
+}
+
+// RenterFormationCandidatesGet requests the /renter/formationcandidates
+// endpoint's resources.
+func (c *Client) RenterFormationCandidatesGet() (rfcg api.RenterFormationCandidatesGET, err error) {
+	err = c.get("/renter/formationcandidates", &rfcg)
+	return
+}
+
+// RenterFormationWorkersGet requests the /renter/formationworkers endpoint's
+// resources.
+func (c *Client) RenterFormationWorkersGet() (rfwg api.RenterFormationWorkersGET, err error) {
+	err = c.get("/renter/formationworkers", &rfwg)
+	return
+}
+
+// RenterFormationWorkersPost uses the /renter/formationworkers endpoint to set
+// the maximum number of hosts that the renter negotiates new contracts with at
+// the same time.
+func (c *Client) RenterFormationWorkersPost(workers uint64) (err error) {
+	values := url.Values{}
+	values.Set("workers", fmt.Sprint(workers))
+	err = c.post("/renter/formationworkers", values.Encode(), nil)
+	return
+}
+
+// RenterHostBlacklistGet requests the /renter/hostblacklist endpoint's
+// resources.
+func (c *Client) RenterHostBlacklistGet() (rhbg api.RenterHostBlacklistGET, err error) {
+	err = c.get("/renter/hostblacklist", &rhbg)
+	return
+}
+
+// RenterHostBlacklistPost uses the /renter/hostblacklist endpoint to set the
+// renter's host blacklist.
+func (c *Client) RenterHostBlacklistPost(hosts []types.SiaPublicKey) (err error) {
+	var keys []string
+	for _, pk := range hosts {
+		keys = append(keys, pk.String())
+	}
+	values := url.Values{}
+	values.Set("hosts", strings.Join(keys, ","))
+	err = c.post("/renter/hostblacklist", values.Encode(), nil)
+	return
+}
+
 // RenterPricesGet requests the /renter/prices endpoint's resources.
 func (c *Client) RenterPricesGet() (rpg api.RenterPricesGET, err error) {
 	err = c.get("/renter/prices", &rpg)
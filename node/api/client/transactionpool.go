@@ -23,3 +23,11 @@ func (c *Client) TransactionPoolRawPost(txn types.Transaction, parents types.Tra
 	err = c.post("/tpool/raw", values.Encode(), nil)
 	return
 }
+
+// TransactionPoolStatsGet uses the /tpool/stats endpoint to get the number and
+// size of the transactions in the transaction pool and a histogram of their
+// fees.
+func (c *Client) TransactionPoolStatsGet() (tsg api.TpoolStatsGET, err error) {
+	err = c.get("/tpool/stats", &tsg)
+	return
+}
@@ -1,11 +1,16 @@
 package client
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 
+	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/node/api"
 	"gitlab.com/NebulousLabs/Sia/types"
 )
@@ -16,6 +21,14 @@ func (c *Client) WalletAddressGet() (wag api.WalletAddressGET, err error) {
 	return
 }
 
+// WalletFreshAddressGet requests an address that the wallet has never
+// returned before and that has never received funds from the /wallet/address
+// endpoint.
+func (c *Client) WalletFreshAddressGet() (wag api.WalletAddressGET, err error) {
+	err = c.get("/wallet/address?fresh=true", &wag)
+	return
+}
+
 // WalletAddressesGet requests the wallets known addresses from the
 // /wallet/addresses endpoint.
 func (c *Client) WalletAddressesGet() (wag api.WalletAddressesGET, err error) {
@@ -33,6 +46,13 @@ func (c *Client) WalletChangePasswordPost(currentPassword, newPassword string) (
 	return
 }
 
+// WalletIncomingGet requests the /wallet/incoming/:addr endpoint to get the
+// siacoin outputs to an address that await confirmation.
+func (c *Client) WalletIncomingGet(addr types.UnlockHash) (wig api.WalletIncomingGET, err error) {
+	err = c.get("/wallet/incoming/"+addr.String(), &wig)
+	return
+}
+
 // WalletInitPost uses the /wallet/init endpoint to initialize and encrypt a
 // wallet
 func (c *Client) WalletInitPost(password string, force bool) (wip api.WalletInitPOST, err error) {
@@ -54,18 +74,225 @@ func (c *Client) WalletInitSeedPost(seed, password string, force bool) (err erro
 	return
 }
 
+// WalletAddressDeriveGet uses the /wallet/address/derive endpoint to get the
+// address that the wallet's primary seed produces at the given index.
+func (c *Client) WalletAddressDeriveGet(index uint64) (wadg api.WalletAddressDeriveGET, err error) {
+	err = c.get(fmt.Sprintf("/wallet/address/derive?index=%v", index), &wadg)
+	return
+}
+
+// WalletAddressVanityGet uses the /wallet/address/vanity endpoint to get an
+// address of the wallet's primary seed that starts with prefix, trying at most
+// maxAttempts addresses.
+func (c *Client) WalletAddressVanityGet(prefix string, maxAttempts uint64) (wavg api.WalletAddressVanityGET, err error) {
+	err = c.get(fmt.Sprintf("/wallet/address/vanity?prefix=%v&maxattempts=%v", prefix, maxAttempts), &wavg)
+	return
+}
+
+// WalletAddressMultisigPost uses the /wallet/address/multisig endpoint to
+// create an address that requires sigLimit signatures of pubkeys. If
+// includeWalletKey is set, a new key of the wallet is added to the keys.
+func (c *Client) WalletAddressMultisigPost(pubkeys []types.SiaPublicKey, sigLimit uint64, includeWalletKey bool) (wamp api.WalletAddressMultisigPOST, err error) {
+	keys := make([]string, 0, len(pubkeys))
+	for _, pk := range pubkeys {
+		keys = append(keys, pk.String())
+	}
+	values := url.Values{}
+	values.Set("pubkeys", strings.Join(keys, ","))
+	values.Set("siglimit", fmt.Sprint(sigLimit))
+	values.Set("includewalletkey", strconv.FormatBool(includeWalletKey))
+	err = c.post("/wallet/address/multisig", values.Encode(), &wamp)
+	return
+}
+
+// WalletBalanceGet requests the /wallet/balance endpoint to get the confirmed
+// balance that the wallet had at the given height.
+func (c *Client) WalletBalanceGet(height types.BlockHeight) (wbg api.WalletBalanceGET, err error) {
+	err = c.get(fmt.Sprintf("/wallet/balance?height=%v", height), &wbg)
+	return
+}
+
+// WalletBalanceDeltaGet requests the /wallet/balance/delta endpoint to get the
+// change of the wallet's confirmed balance since the given height.
+func (c *Client) WalletBalanceDeltaGet(sinceHeight types.BlockHeight) (wbdg api.WalletBalanceDeltaGET, err error) {
+	err = c.get(fmt.Sprintf("/wallet/balance/delta?sinceheight=%v", sinceHeight), &wbdg)
+	return
+}
+
+// WalletBroadcastPost uses the /wallet/broadcast endpoint to submit a signed
+// transaction set to the transaction pool.
+func (c *Client) WalletBroadcastPost(txnSet []types.Transaction) (wbp api.WalletBroadcastPOST, err error) {
+	data, err := json.Marshal(txnSet)
+	if err != nil {
+		return api.WalletBroadcastPOST{}, err
+	}
+	err = c.post("/wallet/broadcast", string(data), &wbp)
+	return
+}
+
+// WalletDefragGet requests the /wallet/defrag endpoint to get the settings of
+// the wallet's background defragmentation.
+func (c *Client) WalletDefragGet() (wdg api.WalletDefragGET, err error) {
+	err = c.get("/wallet/defrag", &wdg)
+	return
+}
+
+// WalletDefragPost uses the /wallet/defrag endpoint to change the settings of
+// the wallet's background defragmentation.
+func (c *Client) WalletDefragPost(settings modules.WalletDefragSettings) (err error) {
+	values := url.Values{}
+	values.Set("enabled", strconv.FormatBool(settings.Enabled))
+	values.Set("threshold", fmt.Sprint(settings.Threshold))
+	values.Set("targetoutputsize", settings.TargetOutputSize.String())
+	values.Set("batchsize", fmt.Sprint(settings.BatchSize))
+	err = c.post("/wallet/defrag", values.Encode(), nil)
+	return
+}
+
+// WalletConflictsGet requests the /wallet/conflicts endpoint to get the inputs
+// of the wallet's unconfirmed transactions that are being double spent.
+func (c *Client) WalletConflictsGet() (wcg api.WalletConflictsGET, err error) {
+	err = c.get("/wallet/conflicts", &wcg)
+	return
+}
+
+// WalletReservedGet requests the /wallet/reserved endpoint to get the ids of
+// the wallet's outputs that are spent by unconfirmed transactions.
+func (c *Client) WalletReservedGet() (wrg api.WalletReservedGET, err error) {
+	err = c.get("/wallet/reserved", &wrg)
+	return
+}
+
+// WalletUsedAddressesGet requests the /wallet/usedaddresses endpoint to get
+// the addresses of the wallet that received funds.
+func (c *Client) WalletUsedAddressesGet() (wuag api.WalletUsedAddressesGET, err error) {
+	err = c.get("/wallet/usedaddresses", &wuag)
+	return
+}
+
+// WalletDustThresholdGet requests the /wallet/dustthreshold endpoint to get
+// the value below which change is added to the miner fees.
+func (c *Client) WalletDustThresholdGet() (wdtg api.WalletDustThresholdGET, err error) {
+	err = c.get("/wallet/dustthreshold", &wdtg)
+	return
+}
+
+// WalletDustThresholdPost uses the /wallet/dustthreshold endpoint to set the
+// value below which change is added to the miner fees.
+func (c *Client) WalletDustThresholdPost(threshold types.Currency) (err error) {
+	values := url.Values{}
+	values.Set("changethreshold", threshold.String())
+	err = c.post("/wallet/dustthreshold", values.Encode(), nil)
+	return
+}
+
+// WalletConfirmationDepthGet requests the /wallet/confirmationdepth endpoint
+// to get the number of blocks a transaction needs on top of it to be treated as
+// confirmed.
+func (c *Client) WalletConfirmationDepthGet() (wcdg api.WalletConfirmationDepthGET, err error) {
+	err = c.get("/wallet/confirmationdepth", &wcdg)
+	return
+}
+
+// WalletConfirmationDepthPost uses the /wallet/confirmationdepth endpoint to
+// set the number of blocks a transaction needs on top of it to be treated as
+// confirmed.
+func (c *Client) WalletConfirmationDepthPost(depth types.BlockHeight) (err error) {
+	values := url.Values{}
+	values.Set("confirmationdepth", fmt.Sprint(depth))
+	err = c.post("/wallet/confirmationdepth", values.Encode(), nil)
+	return
+}
+
+// WalletCanSendGet uses the /wallet/cansend endpoint to check whether the
+// wallet can currently fund sending amount to numOutputs outputs.
+func (c *Client) WalletCanSendGet(amount types.Currency, numOutputs uint64) (wcsg api.WalletCanSendGET, err error) {
+	values := url.Values{}
+	values.Set("amount", amount.String())
+	values.Set("outputs", fmt.Sprint(numOutputs))
+	err = c.get("/wallet/cansend?"+values.Encode(), &wcsg)
+	return
+}
+
+// WalletCoinSelectGet uses the /wallet/coinselect endpoint to simulate the
+// coin selection for sending amount.
+func (c *Client) WalletCoinSelectGet(amount types.Currency) (wcsg api.WalletCoinSelectGET, err error) {
+	values := url.Values{}
+	values.Set("amount", amount.String())
+	err = c.get("/wallet/coinselect?"+values.Encode(), &wcsg)
+	return
+}
+
+// WalletFeeEstimateGet uses the /wallet/fee/estimate endpoint to estimate the
+// size and fee of a transaction sending value to numOutputs outputs.
+func (c *Client) WalletFeeEstimateGet(numOutputs uint64, value types.Currency) (wfeg api.WalletFeeEstimateGET, err error) {
+	values := url.Values{}
+	values.Set("outputs", fmt.Sprint(numOutputs))
+	values.Set("amount", value.String())
+	err = c.get("/wallet/fee/estimate?"+values.Encode(), &wfeg)
+	return
+}
+
+// WalletFeeMultiplierGet uses the /wallet/feemultiplier endpoint to return
+// the factor applied to the fee estimate when sending siacoins.
+func (c *Client) WalletFeeMultiplierGet() (wfmg api.WalletFeeMultiplierGET, err error) {
+	err = c.get("/wallet/feemultiplier", &wfmg)
+	return
+}
+
+// WalletFeeMultiplierPost uses the /wallet/feemultiplier endpoint to set the
+// factor applied to the fee estimate when sending siacoins.
+func (c *Client) WalletFeeMultiplierPost(multiplier float64) (err error) {
+	values := url.Values{}
+	values.Set("multiplier", fmt.Sprint(multiplier))
+	err = c.post("/wallet/feemultiplier", values.Encode(), nil)
+	return
+}
+
 // WalletGet requests the /wallet api resource
 func (c *Client) WalletGet() (wg api.WalletGET, err error) {
 	err = c.get("/wallet", &wg)
 	return
 }
 
+// WalletGetMinConf requests the /wallet api resource, only counting outputs
+// with at least minConf confirmations towards the confirmed siacoin balance.
+func (c *Client) WalletGetMinConf(minConf types.BlockHeight) (wg api.WalletGET, err error) {
+	err = c.get(fmt.Sprintf("/wallet?minconf=%v", minConf), &wg)
+	return
+}
+
 // WalletLockPost uses the /wallet/lock endpoint to lock the wallet.
 func (c *Client) WalletLockPost() (err error) {
 	err = c.post("/wallet/lock", "", nil)
 	return
 }
 
+// WalletLockedOutputsGet requests the /wallet/lockedoutputs endpoint to get
+// the ids of the wallet's outputs that were locked by the user.
+func (c *Client) WalletLockedOutputsGet() (wlog api.WalletLockedOutputsGET, err error) {
+	err = c.get("/wallet/lockedoutputs", &wlog)
+	return
+}
+
+// WalletLockOutputPost uses the /wallet/lockoutput endpoint to prevent an
+// output from being used to fund transactions.
+func (c *Client) WalletLockOutputPost(id types.SiacoinOutputID) (err error) {
+	values := url.Values{}
+	values.Set("id", id.String())
+	err = c.post("/wallet/lockoutput", values.Encode(), nil)
+	return
+}
+
+// WalletUnlockOutputPost uses the /wallet/unlockoutput endpoint to allow a
+// locked output to be used to fund transactions again.
+func (c *Client) WalletUnlockOutputPost(id types.SiacoinOutputID) (err error) {
+	values := url.Values{}
+	values.Set("id", id.String())
+	err = c.post("/wallet/unlockoutput", values.Encode(), nil)
+	return
+}
+
 // WalletSeedPost uses the /wallet/seed endpoint to add a seed to the wallet's list
 // of seeds.
 func (c *Client) WalletSeedPost(seed, password string) (err error) {
@@ -76,6 +303,60 @@ func (c *Client) WalletSeedPost(seed, password string) (err error) {
 	return
 }
 
+// WalletRescanGet uses the /wallet/rescan endpoint to return the progress of
+// a wallet rescan.
+func (c *Client) WalletRescanGet() (wrg api.WalletRescanGET, err error) {
+	err = c.get("/wallet/rescan", &wrg)
+	return
+}
+
+// WalletRescanPost uses the /wallet/rescan endpoint to rebuild the wallet's
+// outputs by rescanning the blockchain.
+func (c *Client) WalletRescanPost() (err error) {
+	err = c.post("/wallet/rescan", "", nil)
+	return
+}
+
+// WalletRescanThrottleGet uses the /wallet/rescan/throttle endpoint to return
+// the pause in milliseconds between two consensus changes during a rescan.
+func (c *Client) WalletRescanThrottleGet() (wrtg api.WalletRescanThrottleGET, err error) {
+	err = c.get("/wallet/rescan/throttle", &wrtg)
+	return
+}
+
+// WalletRescanThrottlePost uses the /wallet/rescan/throttle endpoint to set the
+// pause in milliseconds between two consensus changes during a rescan.
+func (c *Client) WalletRescanThrottlePost(throttle uint64) (err error) {
+	values := url.Values{}
+	values.Set("throttle", fmt.Sprint(throttle))
+	err = c.post("/wallet/rescan/throttle", values.Encode(), nil)
+	return
+}
+
+// WalletSeedAddressesGet uses the /wallet/seed/addresses endpoint to return
+// the addresses derived from the seed at the given index of the wallet's
+// seeds.
+func (c *Client) WalletSeedAddressesGet(index uint64) (wsag api.WalletSeedAddressesGET, err error) {
+	err = c.get(fmt.Sprintf("/wallet/seed/addresses?index=%v", index), &wsag)
+	return
+}
+
+// WalletSeedExportPost uses the /wallet/seed/export endpoint to return the
+// wallet's primary seed encrypted with passphrase.
+func (c *Client) WalletSeedExportPost(passphrase string) (wsep api.WalletSeedExportPOST, err error) {
+	values := url.Values{}
+	values.Set("passphrase", passphrase)
+	err = c.post("/wallet/seed/export", values.Encode(), &wsep)
+	return
+}
+
+// WalletSeedProgressGet uses the /wallet/seed/progress endpoint to return
+// the number of addresses generated from the wallet's primary seed.
+func (c *Client) WalletSeedProgressGet() (wspg api.WalletSeedProgressGET, err error) {
+	err = c.get("/wallet/seed/progress", &wspg)
+	return
+}
+
 // WalletSeedsGet uses the /wallet/seeds endpoint to return the wallet's
 // current seeds.
 func (c *Client) WalletSeedsGet() (wsg api.WalletSeedsGET, err error) {
@@ -83,6 +364,18 @@ func (c *Client) WalletSeedsGet() (wsg api.WalletSeedsGET, err error) {
 	return
 }
 
+// WalletSiacoinsTimelockedPost uses the /wallet/siacoins api endpoint to send
+// money to the owner of destKey that can't be spent before the block height
+// timelock.
+func (c *Client) WalletSiacoinsTimelockedPost(amount types.Currency, destKey types.SiaPublicKey, timelock types.BlockHeight) (wsp api.WalletSiacoinsPOST, err error) {
+	values := url.Values{}
+	values.Set("amount", amount.String())
+	values.Set("destinationkey", destKey.String())
+	values.Set("timelock", fmt.Sprint(timelock))
+	err = c.post("/wallet/siacoins", values.Encode(), &wsp)
+	return
+}
+
 // WalletSiacoinsMultiPost uses the /wallet/siacoin api endpoint to send money
 // to multiple addresses at once
 func (c *Client) WalletSiacoinsMultiPost(outputs []types.SiacoinOutput) (wsp api.WalletSiacoinsPOST, err error) {
@@ -106,6 +399,65 @@ func (c *Client) WalletSiacoinsPost(amount types.Currency, destination types.Unl
 	return
 }
 
+// WalletSiacoinsAllPost uses the /wallet/siacoins api endpoint to send the
+// whole spendable balance of the wallet to destination, minus the fee.
+func (c *Client) WalletSiacoinsAllPost(destination types.UnlockHash) (wsp api.WalletSiacoinsPOST, err error) {
+	values := url.Values{}
+	values.Set("all", "true")
+	values.Set("destination", destination.String())
+	err = c.post("/wallet/siacoins", values.Encode(), &wsp)
+	return
+}
+
+// WalletTopUpPost uses the /wallet/topup api endpoint to bring the balance of
+// an address of the wallet up to target.
+func (c *Client) WalletTopUpPost(target types.Currency, destination types.UnlockHash) (wtp api.WalletTopUpPOST, err error) {
+	values := url.Values{}
+	values.Set("target", target.String())
+	values.Set("destination", destination.String())
+	err = c.post("/wallet/topup", values.Encode(), &wtp)
+	return
+}
+
+// WalletSiacoinsArbitraryDataPost uses the /wallet/siacoins api endpoint to
+// send money to a single address with a transaction that carries the given
+// arbitrary data.
+func (c *Client) WalletSiacoinsArbitraryDataPost(amount types.Currency, destination types.UnlockHash, data []byte) (wsp api.WalletSiacoinsPOST, err error) {
+	values := url.Values{}
+	values.Set("amount", amount.String())
+	values.Set("destination", destination.String())
+	values.Set("arbitrarydata", base64.StdEncoding.EncodeToString(data))
+	err = c.post("/wallet/siacoins", values.Encode(), &wsp)
+	return
+}
+
+// WalletSiacoinsPreviewPost uses the /wallet/siacoins api endpoint to build
+// the transaction set that would send money to a single address without
+// broadcasting it.
+func (c *Client) WalletSiacoinsPreviewPost(amount types.Currency, destination types.UnlockHash) (wsp api.WalletSiacoinsPOST, err error) {
+	values := url.Values{}
+	values.Set("amount", amount.String())
+	values.Set("destination", destination.String())
+	values.Set("preview", "true")
+	err = c.post("/wallet/siacoins", values.Encode(), &wsp)
+	return
+}
+
+// WalletSiacoinsExcludingPost uses the /wallet/siacoins api endpoint to send
+// money to a single address without spending the excluded outputs.
+func (c *Client) WalletSiacoinsExcludingPost(amount types.Currency, destination types.UnlockHash, exclude []types.SiacoinOutputID) (wsp api.WalletSiacoinsPOST, err error) {
+	ids := make([]string, len(exclude))
+	for i, id := range exclude {
+		ids[i] = id.String()
+	}
+	values := url.Values{}
+	values.Set("amount", amount.String())
+	values.Set("destination", destination.String())
+	values.Set("excludeoutputs", strings.Join(ids, ","))
+	err = c.post("/wallet/siacoins", values.Encode(), &wsp)
+	return
+}
+
 // WalletSiafundsPost uses the /wallet/siafunds api endpoint to send siafunds
 // to a single address.
 func (c *Client) WalletSiafundsPost(amount types.Currency, destination types.UnlockHash) (wsp api.WalletSiafundsPOST, err error) {
@@ -116,6 +468,21 @@ func (c *Client) WalletSiafundsPost(amount types.Currency, destination types.Unl
 	return
 }
 
+// WalletSiafundsMultiPost uses the /wallet/siafunds api endpoint to send
+// siafunds to multiple addresses at once.
+func (c *Client) WalletSiafundsMultiPost(outputs []types.SiafundOutput) (wsp api.WalletSiafundsPOST, err error) {
+	var amounts, destinations []string
+	for _, sfo := range outputs {
+		amounts = append(amounts, sfo.Value.String())
+		destinations = append(destinations, sfo.UnlockHash.String())
+	}
+	values := url.Values{}
+	values.Set("amount", strings.Join(amounts, ","))
+	values.Set("destination", strings.Join(destinations, ","))
+	err = c.post("/wallet/siafunds", values.Encode(), &wsp)
+	return
+}
+
 // WalletSiagKeyPost uses the /wallet/siagkey endpoint to load a siag key into
 // the wallet.
 func (c *Client) WalletSiagKeyPost(keyfiles, password string) (err error) {
@@ -126,6 +493,31 @@ func (c *Client) WalletSiagKeyPost(keyfiles, password string) (err error) {
 	return
 }
 
+// WalletSiagKeyDataPost uses the /wallet/siagkey endpoint to load a siag key
+// into the wallet from the contents of its keyfiles.
+func (c *Client) WalletSiagKeyDataPost(keydata [][]byte, password string) (err error) {
+	encoded := make([]string, len(keydata))
+	for i, data := range keydata {
+		encoded[i] = base64.StdEncoding.EncodeToString(data)
+	}
+	values := url.Values{}
+	values.Set("keydata", strings.Join(encoded, ","))
+	values.Set("keyencoding", "base64")
+	values.Set("encryptionpassword", password)
+	err = c.post("/wallet/siagkey", values.Encode(), nil)
+	return
+}
+
+// WalletSplitPost uses the /wallet/split endpoint to split the funds of the
+// wallet into count outputs of the given value.
+func (c *Client) WalletSplitPost(count uint64, value types.Currency) (wsp api.WalletSplitPOST, err error) {
+	values := url.Values{}
+	values.Set("count", strconv.FormatUint(count, 10))
+	values.Set("value", value.String())
+	err = c.post("/wallet/split", values.Encode(), &wsp)
+	return
+}
+
 // WalletSweepPost uses the /wallet/sweep/seed endpoint to sweep a seed into
 // the current wallet.
 func (c *Client) WalletSweepPost(seed string) (wsp api.WalletSweepPOST, err error) {
@@ -143,6 +535,32 @@ func (c *Client) WalletTransactionsGet(startHeight types.BlockHeight, endHeight
 	return
 }
 
+// WalletExportGet requests the /wallet/export api resource for a certain
+// startheight and endheight and decodes the exported transactions.
+func (c *Client) WalletExportGet(startHeight types.BlockHeight, endHeight types.BlockHeight) (pts []modules.ProcessedTransaction, err error) {
+	data, err := c.getRawResponse(fmt.Sprintf("/wallet/export?startheight=%v&endheight=%v",
+		startHeight, endHeight))
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var pt modules.ProcessedTransaction
+		if err := dec.Decode(&pt); err != nil {
+			return nil, err
+		}
+		pts = append(pts, pt)
+	}
+	return pts, nil
+}
+
+// WalletTransactionsCSVGet requests the /wallet/transactions/csv api resource
+// for a certain startheight and endheight and returns the raw CSV.
+func (c *Client) WalletTransactionsCSVGet(startHeight types.BlockHeight, endHeight types.BlockHeight) ([]byte, error) {
+	return c.getRawResponse(fmt.Sprintf("/wallet/transactions/csv?startheight=%v&endheight=%v",
+		startHeight, endHeight))
+}
+
 // WalletTransactionGet requests the /wallet/transaction/:id api resource for a
 // certain TransactionID.
 func (c *Client) WalletTransactionGet(id types.TransactionID) (wtg api.WalletTransactionGETid, err error) {
@@ -150,6 +568,72 @@ func (c *Client) WalletTransactionGet(id types.TransactionID) (wtg api.WalletTra
 	return
 }
 
+// WalletTransactionBumpFeePost uses the /wallet/transaction/:id/bumpfee
+// endpoint to replace an unconfirmed transaction by one paying a higher fee. A
+// zero fee lets the wallet estimate the fee.
+func (c *Client) WalletTransactionBumpFeePost(id types.TransactionID, fee types.Currency) (wtbp api.WalletTransactionBumpFeePOST, err error) {
+	values := url.Values{}
+	if !fee.IsZero() {
+		values.Set("fee", fee.String())
+	}
+	err = c.post("/wallet/transaction/"+id.String()+"/bumpfee", values.Encode(), &wtbp)
+	return
+}
+
+// WalletTransactionGraphGet requests the /wallet/transaction/:id/graph api
+// resource to get the transactions connected to a transaction. A zero depth
+// lets the wallet choose the depth.
+func (c *Client) WalletTransactionGraphGet(id types.TransactionID, depth int) (wtgg api.WalletTransactionGraphGET, err error) {
+	values := url.Values{}
+	if depth != 0 {
+		values.Set("depth", strconv.Itoa(depth))
+	}
+	err = c.get("/wallet/transaction/"+id.String()+"/graph?"+values.Encode(), &wtgg)
+	return
+}
+
+// WalletTransactionsStatusPost uses the /wallet/transactions/status endpoint
+// to request the confirmation status of multiple transactions.
+func (c *Client) WalletTransactionsStatusPost(ids []types.TransactionID) (wtsp api.WalletTransactionsStatusPOST, err error) {
+	idStrs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		idStrs = append(idStrs, id.String())
+	}
+	values := url.Values{}
+	values.Set("ids", strings.Join(idStrs, ","))
+	err = c.post("/wallet/transactions/status", values.Encode(), &wtsp)
+	return
+}
+
+// WalletUnlockConditionsGet requests the /wallet/unlockconditions/:addr
+// endpoint and returns the unlock conditions of an address of the wallet.
+func (c *Client) WalletUnlockConditionsGet(addr types.UnlockHash) (wucg api.WalletUnlockConditionsGET, err error) {
+	err = c.get("/wallet/unlockconditions/"+addr.String(), &wucg)
+	return
+}
+
+// WalletSignMessagePost uses the /wallet/sign/message endpoint to sign a
+// message hash with the key of an address of the wallet.
+func (c *Client) WalletSignMessagePost(addr types.UnlockHash, hash crypto.Hash) (wsmp api.WalletSignMessagePOST, err error) {
+	values := url.Values{}
+	values.Set("address", addr.String())
+	values.Set("hash", hash.String())
+	err = c.post("/wallet/sign/message", values.Encode(), &wsmp)
+	return
+}
+
+// WalletVerifyMessagePost uses the /wallet/verify/message endpoint to verify
+// the signature of a message hash by an address.
+func (c *Client) WalletVerifyMessagePost(addr types.UnlockHash, publicKey string, hash crypto.Hash, signature string) (wvmp api.WalletVerifyMessagePOST, err error) {
+	values := url.Values{}
+	values.Set("address", addr.String())
+	values.Set("publickey", publicKey)
+	values.Set("hash", hash.String())
+	values.Set("signature", signature)
+	err = c.post("/wallet/verify/message", values.Encode(), &wvmp)
+	return
+}
+
 // WalletUnlockPost uses the /wallet/unlock endpoint to unlock the wallet with
 // a given encryption key. Per default this key is the seed.
 func (c *Client) WalletUnlockPost(password string) (err error) {
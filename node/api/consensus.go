@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"gitlab.com/NebulousLabs/Sia/crypto"
+	"gitlab.com/NebulousLabs/Sia/modules"
 	"gitlab.com/NebulousLabs/Sia/types"
 
 	"github.com/julienschmidt/httprouter"
@@ -15,10 +16,55 @@ import (
 // to support idiomatic json encodings.
 type ConsensusGET struct {
 	Synced       bool              `json:"synced"`
+	TipRecent    bool              `json:"tiprecent"`
 	Height       types.BlockHeight `json:"height"`
 	CurrentBlock types.BlockID     `json:"currentblock"`
 	Target       types.Target      `json:"target"`
 	Difficulty   types.Currency    `json:"difficulty"`
+
+	// PeerHeight is the highest block height that the connected peers have
+	// sent or announced. Together with Height, it indicates the progress of
+	// the blockchain download.
+	PeerHeight types.BlockHeight `json:"peerheight"`
+
+	// CumulativeWork is the sum of the difficulties of all blocks in the
+	// current fork.
+	CumulativeWork types.Currency `json:"cumulativework"`
+
+	// EarliestTimestamp and LatestTimestamp are the bounds for the
+	// timestamp of the next block.
+	EarliestTimestamp types.Timestamp `json:"earliesttimestamp"`
+	LatestTimestamp   types.Timestamp `json:"latesttimestamp"`
+
+	// MedianTimestampWindow contains the timestamps of the current block and
+	// its parents that the median-time-past is computed from, starting with
+	// the current block. MedianTimestamp is their median.
+	MedianTimestampWindow []types.Timestamp `json:"mediantimestampwindow"`
+	MedianTimestamp       types.Timestamp   `json:"mediantimestamp"`
+}
+
+// ConsensusConstantsGET contains the network parameters that the consensus set
+// was compiled with. Clients can use them to verify that they are connected to
+// the expected network.
+type ConsensusConstantsGET struct {
+	GenesisID        types.BlockID     `json:"genesisid"`
+	GenesisTimestamp types.Timestamp   `json:"genesistimestamp"`
+	BlockFrequency   types.BlockHeight `json:"blockfrequency"`
+	MaturityDelay    types.BlockHeight `json:"maturitydelay"`
+	BlockSizeLimit   uint64            `json:"blocksizelimit"`
+	SiafundCount     types.Currency    `json:"siafundcount"`
+}
+
+// ConsensusBlockTimingGET contains the receipt times of the most recent blocks
+// that extended the current chain.
+type ConsensusBlockTimingGET struct {
+	Blocks []modules.BlockTiming `json:"blocks"`
+}
+
+// ConsensusStatsGET contains the number of entries in the consensus database
+// and its size on disk.
+type ConsensusStatsGET struct {
+	modules.ConsensusStats
 }
 
 // ConsensusHeadersGET contains information from a blocks header.
@@ -26,6 +72,14 @@ type ConsensusHeadersGET struct {
 	BlockID types.BlockID `json:"blockid"`
 }
 
+// ConsensusOutputGET contains the block that created a siacoin output and
+// whether the output is still unspent.
+type ConsensusOutputGET struct {
+	BlockID types.BlockID     `json:"blockid"`
+	Height  types.BlockHeight `json:"height"`
+	Unspent bool              `json:"unspent"`
+}
+
 // ConsensusBlocksGet contains all fields of a types.Block and additional
 // fields for ID and Height.
 type ConsensusBlocksGet struct {
@@ -172,12 +226,54 @@ func consensusBlocksGetFromBlock(b types.Block, h types.BlockHeight) ConsensusBl
 func (api *API) consensusHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	cbid := api.cs.CurrentBlock().ID()
 	currentTarget, _ := api.cs.ChildTarget(cbid)
+	earliest, latest := api.cs.TimestampBounds()
+	window, median := api.cs.MedianTimestampWindow()
 	WriteJSON(w, ConsensusGET{
 		Synced:       api.cs.Synced(),
+		TipRecent:    api.cs.TipRecent(),
 		Height:       api.cs.Height(),
 		CurrentBlock: cbid,
 		Target:       currentTarget,
 		Difficulty:   currentTarget.Difficulty(),
+
+		PeerHeight: api.cs.PeerHeight(),
+
+		CumulativeWork: api.cs.CumulativeWork(),
+
+		EarliestTimestamp: earliest,
+		LatestTimestamp:   latest,
+
+		MedianTimestampWindow: window,
+		MedianTimestamp:       median,
+	})
+}
+
+// consensusBlockTimingHandler handles the API calls to /consensus/blocktiming.
+func (api *API) consensusBlockTimingHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	WriteJSON(w, ConsensusBlockTimingGET{
+		Blocks: api.cs.BlockTimings(),
+	})
+}
+
+// consensusStatsHandler handles the API calls to /consensus/stats.
+func (api *API) consensusStatsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	stats, err := api.cs.Stats()
+	if err != nil {
+		WriteError(w, Error{"error when calling /consensus/stats: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, ConsensusStatsGET{stats})
+}
+
+// consensusConstantsHandler handles the API calls to /consensus/constants.
+func (api *API) consensusConstantsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	WriteJSON(w, ConsensusConstantsGET{
+		GenesisID:        types.GenesisID,
+		GenesisTimestamp: types.GenesisTimestamp,
+		BlockFrequency:   types.BlockFrequency,
+		MaturityDelay:    types.MaturityDelay,
+		BlockSizeLimit:   types.BlockSizeLimit,
+		SiafundCount:     types.SiafundCount,
 	})
 }
 
@@ -223,6 +319,26 @@ func (api *API) consensusBlocksHandler(w http.ResponseWriter, req *http.Request,
 	WriteJSON(w, consensusBlocksGetFromBlock(b, h))
 }
 
+// consensusOutputHandler handles the API calls to /consensus/output/:id.
+func (api *API) consensusOutputHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	var id types.SiacoinOutputID
+	if err := (*crypto.Hash)(&id).LoadString(ps.ByName("id")); err != nil {
+		WriteError(w, Error{"failed to unmarshal output id: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	bid, height, exists := api.cs.SiacoinOutputBlock(id)
+	if !exists {
+		WriteError(w, Error{"output doesn't exist"}, http.StatusBadRequest)
+		return
+	}
+	_, err := api.cs.SiacoinOutput(id)
+	WriteJSON(w, ConsensusOutputGET{
+		BlockID: bid,
+		Height:  height,
+		Unspent: err == nil,
+	})
+}
+
 // consensusValidateTransactionsetHandler handles the API calls to
 // /consensus/validate/transactionset.
 func (api *API) consensusValidateTransactionsetHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
@@ -36,6 +36,41 @@ func TestIntegrationConsensusGET(t *testing.T) {
 	}
 }
 
+// TestConsensusConstantsGET probes the GET call to /consensus/constants.
+func TestConsensusConstantsGET(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	st, err := createServerTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.panicClose()
+
+	var ccg ConsensusConstantsGET
+	err = st.getAPI("/consensus/constants", &ccg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis, exists := st.server.api.cs.BlockAtHeight(0)
+	if !exists {
+		t.Fatal("genesis block not found")
+	}
+	if ccg.GenesisID != genesis.ID() {
+		t.Error("wrong genesis id returned in consensus constants GET call")
+	}
+	if ccg.GenesisTimestamp != genesis.Timestamp {
+		t.Error("wrong genesis timestamp returned in consensus constants GET call")
+	}
+	if ccg.BlockFrequency != types.BlockFrequency || ccg.MaturityDelay != types.MaturityDelay {
+		t.Error("wrong block constants returned in consensus constants GET call")
+	}
+	if ccg.BlockSizeLimit != types.BlockSizeLimit || !ccg.SiafundCount.Equals(types.SiafundCount) {
+		t.Error("wrong limits returned in consensus constants GET call")
+	}
+}
+
 // TestConsensusValidateTransactionSet probes the POST call to
 // /consensus/validate/transactionset.
 func TestConsensusValidateTransactionSet(t *testing.T) {
@@ -14,6 +14,7 @@ import (
 	"gitlab.com/NebulousLabs/Sia/types"
 
 	"github.com/julienschmidt/httprouter"
+	"gitlab.com/NebulousLabs/entropy-mnemonics"
 )
 
 var (
@@ -69,12 +70,15 @@ type (
 		Settings         modules.RenterSettings     `json:"settings"`
 		FinancialMetrics modules.ContractorSpending `json:"financialmetrics"`
 		CurrentPeriod    types.BlockHeight          `json:"currentperiod"`
+		CurrentPeriodEnd types.BlockHeight          `json:"currentperiodend"`
 	}
 
 	// RenterContract represents a contract formed by the renter.
 	RenterContract struct {
 		// Amount of contract funds that have been spent on downloads.
 		DownloadSpending types.Currency `json:"downloadspending"`
+		// Number of bytes that have been downloaded from the host.
+		DownloadedBytes uint64 `json:"downloadedbytes"`
 		// Block height that the file contract ends on.
 		EndHeight types.BlockHeight `json:"endheight"`
 		// Fees paid in order to form the file contract.
@@ -85,8 +89,13 @@ type (
 		ID types.FileContractID `json:"id"`
 		// A signed transaction containing the most recent contract revision.
 		LastTransaction types.Transaction `json:"lasttransaction"`
+		// Signals if the host is currently considered online.
+		HostOnline bool `json:"hostonline"`
 		// Address of the host the file contract was formed with.
 		NetAddress modules.NetAddress `json:"netaddress"`
+		// Funds the renter can still spend before the contract needs to be
+		// renewed, excluding fees and the host's collateral.
+		RemainingFunds types.Currency `json:"remainingfunds"`
 		// Remaining funds left for the renter to spend on uploads & downloads.
 		RenterFunds types.Currency `json:"renterfunds"`
 		// Size of the file contract, which is typically equal to the number of
@@ -107,10 +116,22 @@ type (
 		TotalCost types.Currency `json:"totalcost"`
 		// Amount of contract funds that have been spent on uploads.
 		UploadSpending types.Currency `json:"uploadspending"`
+		// Number of bytes that have been uploaded to the host.
+		UploadedBytes uint64 `json:"uploadedbytes"`
 		// Signals if contract is good for uploading data
 		GoodForUpload bool `json:"goodforupload"`
 		// Signals if contract is good for a renewal
 		GoodForRenew bool `json:"goodforrenew"`
+		// Explains why the contract is not good for uploading or renewal
+		UtilityReason string `json:"utilityreason"`
+		// Score between 0 and 1 combining the remaining funds, host status,
+		// time to expiry and utility of the contract. Zero for contracts that
+		// are no longer in use.
+		Health float64 `json:"health"`
+		// Explains why an inactive or expired contract was retired, e.g.
+		// because it expired, was canceled or was renewed. Empty for contracts
+		// that are still in use.
+		RetiredReason string `json:"retiredreason"`
 	}
 
 	// RenterContracts contains the renter's contracts.
@@ -121,6 +142,62 @@ type (
 		ExpiredContracts  []RenterContract `json:"expiredcontracts"`
 	}
 
+	// RenterAutoTopUpGET contains the settings of the renter's automatic
+	// allowance top-up.
+	RenterAutoTopUpGET struct {
+		Threshold types.Currency `json:"threshold"`
+		Amount    types.Currency `json:"amount"`
+		MaxSpend  types.Currency `json:"maxspend"`
+		Spent     types.Currency `json:"spent"`
+	}
+
+	// RenterFormationCandidatesGET contains the hosts that the renter
+	// considered in its most recent contract formation pass.
+	RenterFormationCandidatesGET struct {
+		Candidates []modules.HostCandidate `json:"candidates"`
+	}
+
+	// RenterFormationWorkersGET contains the maximum number of hosts that the
+	// renter negotiates new contracts with at the same time.
+	RenterFormationWorkersGET struct {
+		Workers uint64 `json:"workers"`
+	}
+
+	// RenterHostBlacklistGET contains the hosts that the renter will not form
+	// or renew contracts with.
+	RenterHostBlacklistGET struct {
+		Hosts []types.SiaPublicKey `json:"hosts"`
+	}
+
+	// RenterMinContractsGET contains the minimum number of contracts that
+	// need to be good for upload, and whether the renter has that many.
+	RenterMinContractsGET struct {
+		MinContracts    uint64                           `json:"mincontracts"`
+		UploadContracts uint64                           `json:"uploadcontracts"`
+		Status          modules.ContractRedundancyStatus `json:"status"`
+	}
+
+	// RenterPeriodAlignmentGET contains the settings that align the renter's
+	// allowance periods to a fixed epoch.
+	RenterPeriodAlignmentGET struct {
+		Enabled bool              `json:"enabled"`
+		Epoch   types.BlockHeight `json:"epoch"`
+	}
+
+	// RenterPersistIntervalGET contains the interval in milliseconds at which
+	// the renter flushes changes to its contracts to disk.
+	RenterPersistIntervalGET struct {
+		Interval uint64 `json:"interval"`
+	}
+
+	// RenterPriceCeilingsGET contains the maximum prices of hosts that the
+	// renter forms and renews contracts with.
+	RenterPriceCeilingsGET struct {
+		MaxStoragePrice  types.Currency `json:"maxstorageprice"`
+		MaxDownloadPrice types.Currency `json:"maxdownloadprice"`
+		MaxUploadPrice   types.Currency `json:"maxuploadprice"`
+	}
+
 	// RenterDownloadQueue contains the renter's download queue.
 	RenterDownloadQueue struct {
 		Downloads []DownloadInfo `json:"downloads"`
@@ -147,6 +224,15 @@ type (
 		modules.RenterPriceEstimation
 	}
 
+	// RenterSpendingAlertGET contains the renter's spending alert threshold
+	// and the fraction of the allowance that has been spent in the current
+	// period.
+	RenterSpendingAlertGET struct {
+		Threshold     float64 `json:"threshold"`
+		SpentFraction float64 `json:"spentfraction"`
+		Triggered     bool    `json:"triggered"`
+	}
+
 	// RenterShareASCII contains an ASCII-encoded .sia file.
 	RenterShareASCII struct {
 		ASCIIsia string `json:"asciisia"`
@@ -179,6 +265,7 @@ func (api *API) renterHandlerGET(w http.ResponseWriter, req *http.Request, _ htt
 		Settings:         settings,
 		FinancialMetrics: api.renter.PeriodSpending(),
 		CurrentPeriod:    periodStart,
+		CurrentPeriodEnd: api.renter.CurrentPeriodEnd(),
 	})
 }
 
@@ -305,7 +392,7 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 	activeContracts := []RenterContract{}
 	inactiveContracts := []RenterContract{}
 	expiredContracts := []RenterContract{}
-	for _, c := range api.renter.Contracts() {
+	for _, c := range api.renter.AllContracts() {
 		var size uint64
 		if len(c.Transaction.FileContractRevisions) != 0 {
 			size = c.Transaction.FileContractRevisions[0].NewFileSize
@@ -321,20 +408,27 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 		// Fetch utilities for contract
 		var goodForUpload bool
 		var goodForRenew bool
+		var utilityReason string
 		if utility, ok := api.renter.ContractUtility(c.HostPublicKey); ok {
 			goodForUpload = utility.GoodForUpload
 			goodForRenew = utility.GoodForRenew
+			utilityReason = utility.Reason
 		}
+		health, _ := api.renter.ContractHealth(c.ID)
 		contract := RenterContract{
 			DownloadSpending:          c.DownloadSpending,
+			DownloadedBytes:           c.DownloadedBytes,
 			EndHeight:                 c.EndHeight,
 			Fees:                      c.TxnFee.Add(c.SiafundFee).Add(c.ContractFee),
 			GoodForUpload:             goodForUpload,
 			GoodForRenew:              goodForRenew,
+			Health:                    health,
+			HostOnline:                c.HostOnline,
 			HostPublicKey:             c.HostPublicKey,
 			ID:                        c.ID,
 			LastTransaction:           c.Transaction,
 			NetAddress:                netAddress,
+			RemainingFunds:            c.RemainingFunds(),
 			RenterFunds:               c.RenterFunds,
 			Size:                      size,
 			StartHeight:               c.StartHeight,
@@ -342,6 +436,8 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 			StorageSpendingDeprecated: c.StorageSpending,
 			TotalCost:                 c.TotalCost,
 			UploadSpending:            c.UploadSpending,
+			UploadedBytes:             c.UploadedBytes,
+			UtilityReason:             utilityReason,
 		}
 		if goodForRenew {
 			activeContracts = append(activeContracts, contract)
@@ -353,6 +449,7 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 
 	// Get expired contracts
 	if expired || inactive {
+		reasons := api.renter.OldContractReasons()
 		for _, c := range api.renter.OldContracts() {
 			var size uint64
 			if len(c.Transaction.FileContractRevisions) != 0 {
@@ -369,13 +466,16 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 			// Fetch utilities for contract
 			var goodForUpload bool
 			var goodForRenew bool
+			var utilityReason string
 			if utility, ok := api.renter.ContractUtility(c.HostPublicKey); ok {
 				goodForUpload = utility.GoodForUpload
 				goodForRenew = utility.GoodForRenew
+				utilityReason = utility.Reason
 			}
 
 			contract := RenterContract{
 				DownloadSpending:          c.DownloadSpending,
+				DownloadedBytes:           c.DownloadedBytes,
 				EndHeight:                 c.EndHeight,
 				Fees:                      c.TxnFee.Add(c.SiafundFee).Add(c.ContractFee),
 				GoodForUpload:             goodForUpload,
@@ -384,6 +484,7 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 				ID:                        c.ID,
 				LastTransaction:           c.Transaction,
 				NetAddress:                netAddress,
+				RemainingFunds:            c.RemainingFunds(),
 				RenterFunds:               c.RenterFunds,
 				Size:                      size,
 				StartHeight:               c.StartHeight,
@@ -391,6 +492,9 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 				StorageSpendingDeprecated: c.StorageSpending,
 				TotalCost:                 c.TotalCost,
 				UploadSpending:            c.UploadSpending,
+				UploadedBytes:             c.UploadedBytes,
+				UtilityReason:             utilityReason,
+				RetiredReason:             reasons[c.ID],
 			}
 			if expired && c.EndHeight < blockHeight {
 				expiredContracts = append(expiredContracts, contract)
@@ -408,6 +512,305 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 	})
 }
 
+// renterContractCancelHandler handles the API call to cancel a specific Renter
+// contract.
+func (api *API) renterContractCancelHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var fcid types.FileContractID
+	if err := fcid.LoadString(req.FormValue("id")); err != nil {
+		WriteError(w, Error{"unable to parse id: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err := api.renter.CancelContract(fcid)
+	if err != nil {
+		WriteError(w, Error{"unable to cancel contract: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterContractsRecoverHandler handles the API call to recover the renter's
+// contracts from a seed.
+func (api *API) renterContractsRecoverHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	dictID := mnemonics.DictionaryID(req.FormValue("dictionary"))
+	if dictID == "" {
+		dictID = "english"
+	}
+	seed, err := modules.StringToSeed(req.FormValue("seed"), dictID)
+	if err != nil {
+		WriteError(w, Error{"unable to parse seed: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.renter.RecoverContracts(seed); err != nil {
+		WriteError(w, Error{"unable to recover contracts: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterAutoTopUpHandlerGET handles the API call to request the settings of
+// the renter's automatic allowance top-up.
+func (api *API) renterAutoTopUpHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	atu := api.renter.AutoTopUp()
+	WriteJSON(w, RenterAutoTopUpGET{
+		Threshold: atu.Threshold,
+		Amount:    atu.Amount,
+		MaxSpend:  atu.MaxSpend,
+		Spent:     atu.Spent,
+	})
+}
+
+// renterAutoTopUpHandlerPOST handles the API call to set the settings of the
+// renter's automatic allowance top-up. Settings that are not provided keep
+// their current value.
+func (api *API) renterAutoTopUpHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	atu := api.renter.AutoTopUp()
+	for _, param := range []struct {
+		name  string
+		value *types.Currency
+	}{
+		{"threshold", &atu.Threshold},
+		{"amount", &atu.Amount},
+		{"maxspend", &atu.MaxSpend},
+	} {
+		if v := req.FormValue(param.name); v != "" {
+			c, ok := scanAmount(v)
+			if !ok {
+				WriteError(w, Error{"unable to parse " + param.name}, http.StatusBadRequest)
+				return
+			}
+			*param.value = c
+		}
+	}
+	err := api.renter.SetAutoTopUp(atu)
+	if err != nil {
+		WriteError(w, Error{"unable to set auto top-up: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterPeriodAlignmentHandlerGET handles the API call to request the
+// settings that align the renter's allowance periods to a fixed epoch.
+func (api *API) renterPeriodAlignmentHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	pa := api.renter.PeriodAlignment()
+	WriteJSON(w, RenterPeriodAlignmentGET{
+		Enabled: pa.Enabled,
+		Epoch:   pa.Epoch,
+	})
+}
+
+// renterPeriodAlignmentHandlerPOST handles the API call to set the settings
+// that align the renter's allowance periods to a fixed epoch. Settings that
+// are not provided keep their current value.
+func (api *API) renterPeriodAlignmentHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	pa := api.renter.PeriodAlignment()
+	if e := req.FormValue("enabled"); e != "" {
+		enabled, err := strconv.ParseBool(e)
+		if err != nil {
+			WriteError(w, Error{"unable to parse enabled: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		pa.Enabled = enabled
+	}
+	if e := req.FormValue("epoch"); e != "" {
+		epoch, err := strconv.ParseUint(e, 10, 64)
+		if err != nil {
+			WriteError(w, Error{"unable to parse epoch: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		pa.Epoch = types.BlockHeight(epoch)
+	}
+	err := api.renter.SetPeriodAlignment(pa)
+	if err != nil {
+		WriteError(w, Error{"unable to set period alignment: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterPersistIntervalHandlerGET handles the API call to request the
+// interval at which the renter flushes changes to its contracts to disk.
+func (api *API) renterPersistIntervalHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	WriteJSON(w, RenterPersistIntervalGET{
+		Interval: uint64(api.renter.PersistInterval() / time.Millisecond),
+	})
+}
+
+// renterPersistIntervalHandlerPOST handles the API call to set the interval at
+// which the renter flushes changes to its contracts to disk.
+func (api *API) renterPersistIntervalHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	ms, err := strconv.ParseUint(req.FormValue("interval"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"unable to parse interval: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.renter.SetPersistInterval(time.Duration(ms) * time.Millisecond); err != nil {
+		WriteError(w, Error{"unable to set persist interval: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterPriceCeilingsHandlerGET handles the API call to request the maximum
+// prices of hosts that the renter forms and renews contracts with.
+func (api *API) renterPriceCeilingsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	pc := api.renter.PriceCeilings()
+	WriteJSON(w, RenterPriceCeilingsGET{
+		MaxStoragePrice:  pc.MaxStoragePrice,
+		MaxDownloadPrice: pc.MaxDownloadPrice,
+		MaxUploadPrice:   pc.MaxUploadPrice,
+	})
+}
+
+// renterPriceCeilingsHandlerPOST handles the API call to set the maximum
+// prices of hosts that the renter forms and renews contracts with. Ceilings
+// that are not provided keep their current value.
+func (api *API) renterPriceCeilingsHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	pc := api.renter.PriceCeilings()
+	for _, param := range []struct {
+		name  string
+		value *types.Currency
+	}{
+		{"maxstorageprice", &pc.MaxStoragePrice},
+		{"maxdownloadprice", &pc.MaxDownloadPrice},
+		{"maxuploadprice", &pc.MaxUploadPrice},
+	} {
+		if v := req.FormValue(param.name); v != "" {
+			c, ok := scanAmount(v)
+			if !ok {
+				WriteError(w, Error{"unable to parse " + param.name}, http.StatusBadRequest)
+				return
+			}
+			*param.value = c
+		}
+	}
+	err := api.renter.SetPriceCeilings(pc)
+	if err != nil {
+		WriteError(w, Error{"unable to set price ceilings: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterMinContractsHandlerGET handles the API call to request the minimum
+// number of contracts and whether enough contracts are good for upload.
+func (api *API) renterMinContractsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	cr := api.renter.ContractRedundancy()
+	WriteJSON(w, RenterMinContractsGET{
+		MinContracts:    cr.MinContracts,
+		UploadContracts: cr.UploadContracts,
+		Status:          cr.Status,
+	})
+}
+
+// renterMinContractsHandlerPOST handles the API call to set the minimum number
+// of contracts that need to be good for upload.
+func (api *API) renterMinContractsHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	n, err := strconv.ParseUint(req.FormValue("mincontracts"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"unable to parse mincontracts: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.renter.SetMinContracts(n); err != nil {
+		WriteError(w, Error{"unable to set minimum number of contracts: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterSpendingAlertHandlerGET handles the API call to request the spending
+// alert threshold and the fraction of the allowance that has been spent in
+// the current period.
+func (api *API) renterSpendingAlertHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	sa := api.renter.SpendingAlert()
+	WriteJSON(w, RenterSpendingAlertGET{
+		Threshold:     sa.Threshold,
+		SpentFraction: sa.SpentFraction,
+		Triggered:     sa.Triggered,
+	})
+}
+
+// renterSpendingAlertHandlerPOST handles the API call to set the fraction of
+// the allowance that can be spent in a period before the renter warns about
+// it.
+func (api *API) renterSpendingAlertHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	threshold, err := strconv.ParseFloat(req.FormValue("threshold"), 64)
+	if err != nil {
+		WriteError(w, Error{"unable to parse threshold: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.renter.SetSpendingAlertThreshold(threshold); err != nil {
+		WriteError(w, Error{"unable to set spending alert threshold: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterFormationCandidatesHandler handles the API call to request the hosts
+// that the renter considered in its most recent contract formation pass.
+func (api *API) renterFormationCandidatesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	WriteJSON(w, RenterFormationCandidatesGET{
+		Candidates: api.renter.FormationCandidates(),
+	})
+}
+
+// renterFormationWorkersHandlerGET handles the API call to request the
+// maximum number of hosts that the renter negotiates new contracts with at the
+// same time.
+func (api *API) renterFormationWorkersHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	WriteJSON(w, RenterFormationWorkersGET{
+		Workers: api.renter.FormationWorkers(),
+	})
+}
+
+// renterFormationWorkersHandlerPOST handles the API call to set the maximum
+// number of hosts that the renter negotiates new contracts with at the same
+// time.
+func (api *API) renterFormationWorkersHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	workers, err := strconv.ParseUint(req.FormValue("workers"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"unable to parse workers: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.renter.SetFormationWorkers(workers); err != nil {
+		WriteError(w, Error{"unable to set formation workers: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterHostBlacklistHandlerGET handles the API call to request the renter's
+// host blacklist.
+func (api *API) renterHostBlacklistHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	WriteJSON(w, RenterHostBlacklistGET{
+		Hosts: api.renter.HostBlacklist(),
+	})
+}
+
+// renterHostBlacklistHandlerPOST handles the API call to set the renter's host
+// blacklist. The hosts are provided as a comma separated list of public keys.
+// An empty list clears the blacklist.
+func (api *API) renterHostBlacklistHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	hosts := []types.SiaPublicKey{}
+	if h := req.FormValue("hosts"); h != "" {
+		for _, s := range strings.Split(h, ",") {
+			var pk types.SiaPublicKey
+			pk.LoadString(s)
+			if len(pk.Key) == 0 {
+				WriteError(w, Error{"unable to parse host public key: " + s}, http.StatusBadRequest)
+				return
+			}
+			hosts = append(hosts, pk)
+		}
+	}
+	err := api.renter.SetHostBlacklist(hosts)
+	if err != nil {
+		WriteError(w, Error{"unable to set host blacklist: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // renterClearDownloadsHandler handles the API call to request to clear the download queue.
 func (api *API) renterClearDownloadsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	var afterTime time.Time
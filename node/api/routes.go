@@ -22,6 +22,10 @@ func (api *API) buildHTTPRoutes(requiredUserAgent string, requiredPassword strin
 	if api.cs != nil {
 		router.GET("/consensus", api.consensusHandler)
 		router.GET("/consensus/blocks", api.consensusBlocksHandler)
+		router.GET("/consensus/blocktiming", api.consensusBlockTimingHandler)
+		router.GET("/consensus/constants", api.consensusConstantsHandler)
+		router.GET("/consensus/output/:id", api.consensusOutputHandler)
+		router.GET("/consensus/stats", api.consensusStatsHandler)
 		router.POST("/consensus/validate/transactionset", api.consensusValidateTransactionsetHandler)
 	}
 
@@ -69,12 +73,31 @@ func (api *API) buildHTTPRoutes(requiredUserAgent string, requiredPassword strin
 	if api.renter != nil {
 		router.GET("/renter", api.renterHandlerGET)
 		router.POST("/renter", RequirePassword(api.renterHandlerPOST, requiredPassword))
+		router.GET("/renter/autotopup", api.renterAutoTopUpHandlerGET)
+		router.POST("/renter/autotopup", RequirePassword(api.renterAutoTopUpHandlerPOST, requiredPassword))
+		router.POST("/renter/contract/cancel", RequirePassword(api.renterContractCancelHandler, requiredPassword))
 		router.GET("/renter/contracts", api.renterContractsHandler)
+		router.POST("/renter/contracts/recover", RequirePassword(api.renterContractsRecoverHandler, requiredPassword))
 		router.GET("/renter/downloads", api.renterDownloadsHandler)
 		router.POST("/renter/downloads/clear", RequirePassword(api.renterClearDownloadsHandler, requiredPassword))
 		router.GET("/renter/files", api.renterFilesHandler)
+		router.GET("/renter/formationcandidates", api.renterFormationCandidatesHandler)
+		router.GET("/renter/formationworkers", api.renterFormationWorkersHandlerGET)
+		router.POST("/renter/formationworkers", RequirePassword(api.renterFormationWorkersHandlerPOST, requiredPassword))
+		router.GET("/renter/hostblacklist", api.renterHostBlacklistHandlerGET)
+		router.POST("/renter/hostblacklist", RequirePassword(api.renterHostBlacklistHandlerPOST, requiredPassword))
+		router.GET("/renter/mincontracts", api.renterMinContractsHandlerGET)
+		router.POST("/renter/mincontracts", RequirePassword(api.renterMinContractsHandlerPOST, requiredPassword))
 		router.GET("/renter/file/*siapath", api.renterFileHandler)
 		router.GET("/renter/prices", api.renterPricesHandler)
+		router.GET("/renter/periodalignment", api.renterPeriodAlignmentHandlerGET)
+		router.POST("/renter/periodalignment", RequirePassword(api.renterPeriodAlignmentHandlerPOST, requiredPassword))
+		router.GET("/renter/persistinterval", api.renterPersistIntervalHandlerGET)
+		router.POST("/renter/persistinterval", RequirePassword(api.renterPersistIntervalHandlerPOST, requiredPassword))
+		router.GET("/renter/priceceilings", api.renterPriceCeilingsHandlerGET)
+		router.POST("/renter/priceceilings", RequirePassword(api.renterPriceCeilingsHandlerPOST, requiredPassword))
+		router.GET("/renter/spendingalert", api.renterSpendingAlertHandlerGET)
+		router.POST("/renter/spendingalert", RequirePassword(api.renterSpendingAlertHandlerPOST, requiredPassword))
 
 		// TODO: re-enable these routes once the new .sia format has been
 		// standardized and implemented.
@@ -103,6 +126,7 @@ func (api *API) buildHTTPRoutes(requiredUserAgent string, requiredPassword strin
 		router.GET("/tpool/raw/:id", api.tpoolRawHandlerGET)
 		router.POST("/tpool/raw", api.tpoolRawHandlerPOST)
 		router.GET("/tpool/confirmed/:id", api.tpoolConfirmedGET)
+		router.GET("/tpool/stats", api.tpoolStatsHandlerGET)
 
 		// TODO: re-enable this route once the transaction pool API has been finalized
 		//router.GET("/transactionpool/transactions", api.transactionpoolTransactionsHandler)
@@ -113,21 +137,61 @@ func (api *API) buildHTTPRoutes(requiredUserAgent string, requiredPassword strin
 		router.GET("/wallet", api.walletHandler)
 		router.POST("/wallet/033x", RequirePassword(api.wallet033xHandler, requiredPassword))
 		router.GET("/wallet/address", RequirePassword(api.walletAddressHandler, requiredPassword))
+		router.GET("/wallet/address/derive", RequirePassword(api.walletAddressDeriveHandler, requiredPassword))
+		router.POST("/wallet/address/multisig", RequirePassword(api.walletAddressMultisigHandler, requiredPassword))
+		router.GET("/wallet/address/vanity", RequirePassword(api.walletAddressVanityHandler, requiredPassword))
 		router.GET("/wallet/addresses", api.walletAddressesHandler)
 		router.GET("/wallet/backup", RequirePassword(api.walletBackupHandler, requiredPassword))
+		router.GET("/wallet/balance", api.walletBalanceHandler)
+		router.GET("/wallet/balance/delta", api.walletBalanceDeltaHandler)
+		router.POST("/wallet/broadcast", RequirePassword(api.walletBroadcastHandler, requiredPassword))
+		router.GET("/wallet/cansend", api.walletCanSendHandler)
+		router.GET("/wallet/coinselect", api.walletCoinSelectHandler)
+		router.GET("/wallet/confirmationdepth", api.walletConfirmationDepthHandlerGET)
+		router.POST("/wallet/confirmationdepth", RequirePassword(api.walletConfirmationDepthHandlerPOST, requiredPassword))
+		router.GET("/wallet/conflicts", api.walletConflictsHandler)
+		router.GET("/wallet/defrag", api.walletDefragHandlerGET)
+		router.GET("/wallet/dustthreshold", api.walletDustThresholdHandlerGET)
+		router.POST("/wallet/dustthreshold", RequirePassword(api.walletDustThresholdHandlerPOST, requiredPassword))
+		router.GET("/wallet/export", api.walletExportHandler)
+		router.POST("/wallet/defrag", RequirePassword(api.walletDefragHandlerPOST, requiredPassword))
+		router.GET("/wallet/fee/estimate", api.walletFeeEstimateHandler)
+		router.GET("/wallet/feemultiplier", api.walletFeeMultiplierHandlerGET)
+		router.POST("/wallet/feemultiplier", RequirePassword(api.walletFeeMultiplierHandlerPOST, requiredPassword))
+		router.GET("/wallet/incoming/:addr", api.walletIncomingHandler)
 		router.POST("/wallet/init", RequirePassword(api.walletInitHandler, requiredPassword))
 		router.POST("/wallet/init/seed", RequirePassword(api.walletInitSeedHandler, requiredPassword))
 		router.POST("/wallet/lock", RequirePassword(api.walletLockHandler, requiredPassword))
+		router.GET("/wallet/lockedoutputs", api.walletLockedOutputsHandler)
+		router.POST("/wallet/lockoutput", RequirePassword(api.walletLockOutputHandler, requiredPassword))
+		router.GET("/wallet/rescan", api.walletRescanHandlerGET)
+		router.POST("/wallet/rescan", RequirePassword(api.walletRescanHandlerPOST, requiredPassword))
+		router.GET("/wallet/rescan/throttle", api.walletRescanThrottleHandlerGET)
+		router.POST("/wallet/rescan/throttle", RequirePassword(api.walletRescanThrottleHandlerPOST, requiredPassword))
+		router.GET("/wallet/reserved", api.walletReservedHandler)
 		router.POST("/wallet/seed", RequirePassword(api.walletSeedHandler, requiredPassword))
+		router.GET("/wallet/seed/addresses", RequirePassword(api.walletSeedAddressesHandler, requiredPassword))
+		router.POST("/wallet/seed/export", RequirePassword(api.walletSeedExportHandler, requiredPassword))
+		router.GET("/wallet/seed/progress", RequirePassword(api.walletSeedProgressHandler, requiredPassword))
 		router.GET("/wallet/seeds", RequirePassword(api.walletSeedsHandler, requiredPassword))
+		router.POST("/wallet/sign/message", RequirePassword(api.walletSignMessageHandler, requiredPassword))
 		router.POST("/wallet/siacoins", RequirePassword(api.walletSiacoinsHandler, requiredPassword))
 		router.POST("/wallet/siafunds", RequirePassword(api.walletSiafundsHandler, requiredPassword))
 		router.POST("/wallet/siagkey", RequirePassword(api.walletSiagkeyHandler, requiredPassword))
+		router.POST("/wallet/split", RequirePassword(api.walletSplitHandler, requiredPassword))
 		router.POST("/wallet/sweep/seed", RequirePassword(api.walletSweepSeedHandler, requiredPassword))
+		router.POST("/wallet/topup", RequirePassword(api.walletTopUpHandler, requiredPassword))
 		router.GET("/wallet/transaction/:id", api.walletTransactionHandler)
+		router.POST("/wallet/transaction/:id/bumpfee", RequirePassword(api.walletTransactionBumpFeeHandler, requiredPassword))
+		router.GET("/wallet/transaction/:id/graph", api.walletTransactionGraphHandler)
 		router.GET("/wallet/transactions", api.walletTransactionsHandler)
 		router.GET("/wallet/transactions/:addr", api.walletTransactionsAddrHandler)
+		router.POST("/wallet/transactions/status", api.walletTransactionsStatusHandler)
+		router.GET("/wallet/unlockconditions/:addr", RequirePassword(api.walletUnlockConditionsHandler, requiredPassword))
+		router.POST("/wallet/unlockoutput", RequirePassword(api.walletUnlockOutputHandler, requiredPassword))
 		router.GET("/wallet/verify/address/:addr", api.walletVerifyAddressHandler)
+		router.POST("/wallet/verify/message", api.walletVerifyMessageHandler)
+		router.GET("/wallet/usedaddresses", api.walletUsedAddressesHandler)
 		router.POST("/wallet/unlock", RequirePassword(api.walletUnlockHandler, requiredPassword))
 		router.POST("/wallet/changepassword", RequirePassword(api.walletChangePasswordHandler, requiredPassword))
 	}
@@ -27,6 +27,12 @@ type (
 		Transaction []byte              `json:"transaction"`
 	}
 
+	// TpoolStatsGET contains the number and size of the transactions in the
+	// transaction pool, along with a histogram of their fees.
+	TpoolStatsGET struct {
+		modules.TransactionPoolStats
+	}
+
 	// TpoolConfirmedGET contains information about whether or not
 	// the transaction has been seen on the blockhain
 	TpoolConfirmedGET struct {
@@ -133,3 +139,9 @@ func (api *API) tpoolConfirmedGET(w http.ResponseWriter, req *http.Request, ps h
 		Confirmed: confirmed,
 	})
 }
+
+// tpoolStatsHandlerGET returns the number and size of the transactions in
+// the transaction pool and a histogram of their fees per byte.
+func (api *API) tpoolStatsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	WriteJSON(w, TpoolStatsGET{api.tpool.Stats()})
+}
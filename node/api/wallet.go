@@ -1,13 +1,21 @@
 package api
 
 import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"math"
+	"math/big"
 	"net/http"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"gitlab.com/NebulousLabs/Sia/crypto"
 	"gitlab.com/NebulousLabs/Sia/modules"
@@ -20,12 +28,15 @@ import (
 type (
 	// WalletGET contains general information about the wallet.
 	WalletGET struct {
-		Encrypted  bool              `json:"encrypted"`
-		Height     types.BlockHeight `json:"height"`
-		Rescanning bool              `json:"rescanning"`
-		Unlocked   bool              `json:"unlocked"`
+		Encrypted    bool                       `json:"encrypted"`
+		Height       types.BlockHeight          `json:"height"`
+		Rescanning   bool                       `json:"rescanning"`
+		ScanHeight   types.BlockHeight          `json:"scanheight"`
+		Unlocked     bool                       `json:"unlocked"`
+		UnlockStatus modules.WalletUnlockStatus `json:"unlockstatus"`
 
 		ConfirmedSiacoinBalance     types.Currency `json:"confirmedsiacoinbalance"`
+		PendingSiacoinBalance       types.Currency `json:"pendingsiacoinbalance"`
 		UnconfirmedOutgoingSiacoins types.Currency `json:"unconfirmedoutgoingsiacoins"`
 		UnconfirmedIncomingSiacoins types.Currency `json:"unconfirmedincomingsiacoins"`
 
@@ -33,6 +44,9 @@ type (
 		SiafundBalance      types.Currency `json:"siafundbalance"`
 
 		DustThreshold types.Currency `json:"dustthreshold"`
+
+		ConfirmationDepth      types.BlockHeight `json:"confirmationdepth"`
+		ConfirmingTransactions uint64            `json:"confirmingtransactions"`
 	}
 
 	// WalletAddressGET contains an address returned by a GET call to
@@ -41,12 +55,69 @@ type (
 		Address types.UnlockHash `json:"address"`
 	}
 
+	// WalletAddressDeriveGET contains the address that the primary seed
+	// produces at an index, returned by a GET call to /wallet/address/derive.
+	WalletAddressDeriveGET struct {
+		Address          types.UnlockHash       `json:"address"`
+		Index            uint64                 `json:"index"`
+		UnlockConditions types.UnlockConditions `json:"unlockconditions"`
+	}
+
+	// WalletAddressVanityGET contains the address matching a prefix that
+	// was found by a GET call to /wallet/address/vanity, together with its
+	// index in the primary seed.
+	WalletAddressVanityGET struct {
+		Address          types.UnlockHash       `json:"address"`
+		Index            uint64                 `json:"index"`
+		UnlockConditions types.UnlockConditions `json:"unlockconditions"`
+	}
+
+	// WalletAddressMultisigPOST contains a multisig address returned by a
+	// POST call to /wallet/address/multisig.
+	WalletAddressMultisigPOST struct {
+		Address          types.UnlockHash       `json:"address"`
+		UnlockConditions types.UnlockConditions `json:"unlockconditions"`
+	}
+
 	// WalletAddressesGET contains the list of wallet addresses returned by a
 	// GET call to /wallet/addresses.
 	WalletAddressesGET struct {
 		Addresses []types.UnlockHash `json:"addresses"`
 	}
 
+	// WalletConflictsGET contains the inputs of the wallet's unconfirmed
+	// transactions that are being double spent, returned by a GET call to
+	// /wallet/conflicts.
+	WalletConflictsGET struct {
+		Conflicts []modules.WalletConflict `json:"conflicts"`
+	}
+
+	// WalletLockedOutputsGET contains the ids of the wallet's outputs that
+	// were locked by the user, returned by a GET call to
+	// /wallet/lockedoutputs.
+	WalletLockedOutputsGET struct {
+		OutputIDs []types.SiacoinOutputID `json:"outputids"`
+	}
+
+	// WalletReservedGET contains the ids of the wallet's outputs that are
+	// spent by unconfirmed transactions, returned by a GET call to
+	// /wallet/reserved.
+	WalletReservedGET struct {
+		OutputIDs []types.SiacoinOutputID `json:"outputids"`
+	}
+
+	// WalletUsedAddressesGET contains the addresses of the wallet that
+	// received funds, returned by a GET call to /wallet/usedaddresses.
+	WalletUsedAddressesGET struct {
+		Addresses []modules.WalletUsedAddress `json:"addresses"`
+	}
+
+	// WalletIncomingGET contains the siacoin outputs to an address that await
+	// confirmation, returned by a GET call to /wallet/incoming/:addr.
+	WalletIncomingGET struct {
+		Outputs []modules.IncomingOutput `json:"outputs"`
+	}
+
 	// WalletInitPOST contains the primary seed that gets generated during a
 	// POST call to /wallet/init.
 	WalletInitPOST struct {
@@ -57,6 +128,25 @@ type (
 	// /wallet/siacoins.
 	WalletSiacoinsPOST struct {
 		TransactionIDs []types.TransactionID `json:"transactionids"`
+
+		// UnlockConditions are the conditions of a time-locked output. They
+		// are only set if a timelock was specified.
+		UnlockConditions *types.UnlockConditions `json:"unlockconditions,omitempty"`
+
+		// Transactions is the transaction set that would be sent. It is only
+		// set if a preview was requested.
+		Transactions []types.Transaction `json:"transactions,omitempty"`
+
+		// Amount is the amount that was sent after deducting the fee. It is
+		// only set if the whole balance was sent.
+		Amount *types.Currency `json:"amount,omitempty"`
+	}
+
+	// WalletTopUpPOST contains the amount and the transactions sent in the
+	// POST call to /wallet/topup.
+	WalletTopUpPOST struct {
+		Amount         types.Currency        `json:"amount"`
+		TransactionIDs []types.TransactionID `json:"transactionids"`
 	}
 
 	// WalletSiafundsPOST contains the transaction sent in the POST call to
@@ -65,6 +155,113 @@ type (
 		TransactionIDs []types.TransactionID `json:"transactionids"`
 	}
 
+	// WalletSplitPOST contains the transactions created by a POST call to
+	// /wallet/split.
+	WalletSplitPOST struct {
+		TransactionIDs []types.TransactionID `json:"transactionids"`
+	}
+
+	// WalletBalanceGET contains the confirmed balance of the wallet at a
+	// height returned by a GET call to /wallet/balance.
+	WalletBalanceGET struct {
+		Height                  types.BlockHeight `json:"height"`
+		ConfirmedSiacoinBalance types.Currency    `json:"confirmedsiacoinbalance"`
+		SiafundBalance          types.Currency    `json:"siafundbalance"`
+	}
+
+	// WalletBalanceDeltaGET contains the change of the wallet's confirmed
+	// balance returned by a GET call to /wallet/balance/delta.
+	WalletBalanceDeltaGET struct {
+		SinceHeight      types.BlockHeight `json:"sinceheight"`
+		Height           types.BlockHeight `json:"height"`
+		SiacoinsIncoming types.Currency    `json:"siacoinsincoming"`
+		SiacoinsOutgoing types.Currency    `json:"siacoinsoutgoing"`
+		SiafundsIncoming types.Currency    `json:"siafundsincoming"`
+		SiafundsOutgoing types.Currency    `json:"siafundsoutgoing"`
+	}
+
+	// WalletBroadcastPOST contains the IDs of the transactions that were
+	// broadcast by a POST call to /wallet/broadcast.
+	WalletBroadcastPOST struct {
+		TransactionIDs []types.TransactionID `json:"transactionids"`
+	}
+
+	// WalletDefragGET contains the settings of the wallet's background
+	// defragmentation.
+	WalletDefragGET struct {
+		Enabled          bool           `json:"enabled"`
+		Threshold        uint64         `json:"threshold"`
+		TargetOutputSize types.Currency `json:"targetoutputsize"`
+		BatchSize        uint64         `json:"batchsize"`
+	}
+
+	// WalletCanSendGET reports whether the wallet can currently fund a send
+	// and how many hastings are missing if it can't.
+	WalletCanSendGET struct {
+		CanSend   bool           `json:"cansend"`
+		Shortfall types.Currency `json:"shortfall"`
+		Fee       types.Currency `json:"fee"`
+	}
+
+	// WalletCoinSelectGET contains the result of a simulated coin selection
+	// returned by a call to /wallet/coinselect.
+	WalletCoinSelectGET struct {
+		CoinSelection modules.CoinSelection `json:"coinselection"`
+	}
+
+	// WalletConfirmationDepthGET contains the number of blocks that need to be
+	// built on top of a transaction's block before the wallet treats it as
+	// confirmed.
+	WalletConfirmationDepthGET struct {
+		ConfirmationDepth types.BlockHeight `json:"confirmationdepth"`
+	}
+
+	// WalletDustThresholdGET contains the value below which the change of a
+	// transaction is added to its miner fees instead of being refunded.
+	WalletDustThresholdGET struct {
+		ChangeThreshold types.Currency `json:"changethreshold"`
+	}
+
+	// WalletFeeEstimateGET contains the projected size and fee of a
+	// transaction set sending siacoins to multiple outputs.
+	WalletFeeEstimateGET struct {
+		Size uint64         `json:"size"`
+		Fee  types.Currency `json:"fee"`
+	}
+
+	// WalletFeeMultiplierGET contains the factor that the wallet applies to
+	// the fee estimate of the transaction pool when sending siacoins.
+	WalletFeeMultiplierGET struct {
+		Multiplier float64 `json:"multiplier"`
+	}
+
+	// WalletRescanGET contains the progress of a wallet rescan.
+	WalletRescanGET struct {
+		Rescanning      bool              `json:"rescanning"`
+		ScannedHeight   types.BlockHeight `json:"scannedheight"`
+		ConsensusHeight types.BlockHeight `json:"consensusheight"`
+		Progress        float64           `json:"progress"`
+	}
+
+	// WalletRescanThrottleGET contains the pause in milliseconds between two
+	// consensus changes that the wallet processes while rescanning.
+	WalletRescanThrottleGET struct {
+		Throttle uint64 `json:"throttle"`
+	}
+
+	// WalletSignMessagePOST contains the signature of a message hash and the
+	// public key of the address that signed it.
+	WalletSignMessagePOST struct {
+		PublicKey string `json:"publickey"`
+		Signature string `json:"signature"`
+	}
+
+	// WalletSeedExportPOST contains the encrypted primary seed returned by a
+	// call to /wallet/seed/export.
+	WalletSeedExportPOST struct {
+		EncryptedSeed string `json:"encryptedseed"`
+	}
+
 	// WalletSeedsGET contains the seeds used by the wallet.
 	WalletSeedsGET struct {
 		PrimarySeed        string   `json:"primaryseed"`
@@ -72,6 +269,21 @@ type (
 		AllSeeds           []string `json:"allseeds"`
 	}
 
+	// WalletSeedAddressesGET contains the addresses that the wallet derived
+	// from one of its seeds.
+	WalletSeedAddressesGET struct {
+		Addresses []types.UnlockHash `json:"addresses"`
+	}
+
+	// WalletSeedProgressGET contains the number of addresses generated from
+	// the primary seed and the highest index of an address that has
+	// received funds.
+	WalletSeedProgressGET struct {
+		AddressesGenerated uint64 `json:"addressesgenerated"`
+		HighestFundedIndex uint64 `json:"highestfundedindex"`
+		FundsSeen          bool   `json:"fundsseen"`
+	}
+
 	// WalletSweepPOST contains the coins and funds returned by a call to
 	// /wallet/sweep.
 	WalletSweepPOST struct {
@@ -85,6 +297,19 @@ type (
 		Transaction modules.ProcessedTransaction `json:"transaction"`
 	}
 
+	// WalletTransactionBumpFeePOST contains the IDs of the transactions that
+	// replaced the original transaction set in a call to
+	// /wallet/transaction/:id/bumpfee.
+	WalletTransactionBumpFeePOST struct {
+		TransactionIDs []types.TransactionID `json:"transactionids"`
+	}
+
+	// WalletTransactionGraphGET contains the transaction graph returned by a
+	// call to /wallet/transaction/:id/graph.
+	WalletTransactionGraphGET struct {
+		Graph modules.TransactionGraph `json:"graph"`
+	}
+
 	// WalletTransactionsGET contains the specified set of confirmed and
 	// unconfirmed transactions.
 	WalletTransactionsGET struct {
@@ -100,6 +325,37 @@ type (
 		UnconfirmedTransactions []modules.ProcessedTransaction `json:"unconfirmedtransactions"`
 	}
 
+	// WalletUnlockConditionsGET contains the unlock conditions of an address
+	// returned by a GET call to /wallet/unlockconditions/:addr.
+	WalletUnlockConditionsGET struct {
+		UnlockConditions types.UnlockConditions `json:"unlockconditions"`
+	}
+
+	// WalletTransactionStatus contains the confirmation status of a single
+	// transaction in a call to /wallet/transactions/status.
+	WalletTransactionStatus struct {
+		TransactionID types.TransactionID `json:"transactionid"`
+
+		// Status is one of "unknown", "unconfirmed" or "confirmed".
+		Status string `json:"status"`
+
+		// ConfirmationHeight is the height of the block containing the
+		// transaction. It is only set if the transaction is confirmed.
+		ConfirmationHeight types.BlockHeight `json:"confirmationheight,omitempty"`
+	}
+
+	// WalletTransactionsStatusPOST contains the confirmation statuses of the
+	// transactions passed to /wallet/transactions/status, in the same order.
+	WalletTransactionsStatusPOST struct {
+		Statuses []WalletTransactionStatus `json:"statuses"`
+	}
+
+	// WalletVerifyMessagePOST contains a bool indicating if the signature
+	// passed to /wallet/verify/message is valid.
+	WalletVerifyMessagePOST struct {
+		Valid bool `json:"valid"`
+	}
+
 	// WalletVerifyAddressGET contains a bool indicating if the address passed to
 	// /wallet/verify/address/:addr is a valid address.
 	WalletVerifyAddressGET struct {
@@ -129,6 +385,36 @@ func (api *API) walletHandler(w http.ResponseWriter, req *http.Request, _ httpro
 		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
 		return
 	}
+	confirmationDepth, err := api.wallet.ConfirmationDepth()
+	if err != nil {
+		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		return
+	}
+	confirming, err := api.wallet.ConfirmingTransactions()
+	if err != nil {
+		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		return
+	}
+	// If minconf is set, only outputs with at least minconf confirmations
+	// count towards the confirmed balance. Otherwise, outputs of confirming
+	// transactions are reported as pending.
+	var pendingBal types.Currency
+	minConf, useMinConf := uint64(confirmationDepth)+1, confirmationDepth > 0
+	if minConfStr := req.FormValue("minconf"); minConfStr != "" {
+		minConf, err = strconv.ParseUint(minConfStr, 10, 64)
+		if err != nil {
+			WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: unable to parse minconf: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		useMinConf = true
+	}
+	if useMinConf {
+		siacoinBal, pendingBal, err = api.wallet.ConfirmedSiacoinBalanceMinConf(types.BlockHeight(minConf))
+		if err != nil {
+			WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+			return
+		}
+	}
 	siacoinsOut, siacoinsIn, err := api.wallet.UnconfirmedBalance()
 	if err != nil {
 		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
@@ -159,13 +445,21 @@ func (api *API) walletHandler(w http.ResponseWriter, req *http.Request, _ httpro
 		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
 		return
 	}
+	scanHeight, err := api.wallet.ScanHeight()
+	if err != nil {
+		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		return
+	}
 	WriteJSON(w, WalletGET{
-		Encrypted:  encrypted,
-		Unlocked:   unlocked,
-		Rescanning: rescanning,
-		Height:     height,
+		Encrypted:    encrypted,
+		Unlocked:     unlocked,
+		UnlockStatus: modules.NewWalletUnlockStatus(encrypted, unlocked, rescanning),
+		Rescanning:   rescanning,
+		Height:       height,
+		ScanHeight:   scanHeight,
 
 		ConfirmedSiacoinBalance:     siacoinBal,
+		PendingSiacoinBalance:       pendingBal,
 		UnconfirmedOutgoingSiacoins: siacoinsOut,
 		UnconfirmedIncomingSiacoins: siacoinsIn,
 
@@ -173,6 +467,9 @@ func (api *API) walletHandler(w http.ResponseWriter, req *http.Request, _ httpro
 		SiacoinClaimBalance: siaclaimBal,
 
 		DustThreshold: dustThreshold,
+
+		ConfirmationDepth:      confirmationDepth,
+		ConfirmingTransactions: uint64(len(confirming)),
 	})
 }
 
@@ -201,7 +498,22 @@ func (api *API) wallet033xHandler(w http.ResponseWriter, req *http.Request, _ ht
 
 // walletAddressHandler handles API calls to /wallet/address.
 func (api *API) walletAddressHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	unlockConditions, err := api.wallet.NextAddress()
+	var fresh bool
+	if f := req.FormValue("fresh"); f != "" {
+		var err error
+		fresh, err = strconv.ParseBool(f)
+		if err != nil {
+			WriteError(w, Error{"unable to parse fresh: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	var unlockConditions types.UnlockConditions
+	var err error
+	if fresh {
+		unlockConditions, err = api.wallet.FreshAddress()
+	} else {
+		unlockConditions, err = api.wallet.NextAddress()
+	}
 	if err != nil {
 		WriteError(w, Error{"error when calling /wallet/addresses: " + err.Error()}, http.StatusBadRequest)
 		return
@@ -211,6 +523,82 @@ func (api *API) walletAddressHandler(w http.ResponseWriter, req *http.Request, _
 	})
 }
 
+// walletAddressDeriveHandler handles API calls to /wallet/address/derive.
+func (api *API) walletAddressDeriveHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	index, err := strconv.ParseUint(req.FormValue("index"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"unable to parse index: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	uc, err := api.wallet.PrimarySeedAddress(index)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/address/derive: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletAddressDeriveGET{
+		Address:          uc.UnlockHash(),
+		Index:            index,
+		UnlockConditions: uc,
+	})
+}
+
+// walletAddressVanityHandler handles API calls to /wallet/address/vanity.
+func (api *API) walletAddressVanityHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var maxAttempts uint64
+	if ma := req.FormValue("maxattempts"); ma != "" {
+		var err error
+		maxAttempts, err = strconv.ParseUint(ma, 10, 64)
+		if err != nil {
+			WriteError(w, Error{"unable to parse maxattempts: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	uc, index, err := api.wallet.VanityAddress(req.FormValue("prefix"), maxAttempts)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/address/vanity: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletAddressVanityGET{
+		Address:          uc.UnlockHash(),
+		Index:            index,
+		UnlockConditions: uc,
+	})
+}
+
+// walletAddressMultisigHandler handles API calls to /wallet/address/multisig.
+func (api *API) walletAddressMultisigHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var pubkeys []types.SiaPublicKey
+	if req.FormValue("pubkeys") != "" {
+		for _, str := range strings.Split(req.FormValue("pubkeys"), ",") {
+			var pk types.SiaPublicKey
+			pk.LoadString(str)
+			pubkeys = append(pubkeys, pk)
+		}
+	}
+	sigLimit, err := strconv.ParseUint(req.FormValue("siglimit"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/address/multisig: could not read siglimit: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var includeWalletKey bool
+	if req.FormValue("includewalletkey") != "" {
+		includeWalletKey, err = strconv.ParseBool(req.FormValue("includewalletkey"))
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/address/multisig: could not read includewalletkey: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	uc, err := api.wallet.MultisigAddress(pubkeys, sigLimit, includeWalletKey)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/address/multisig: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletAddressMultisigPOST{
+		Address:          uc.UnlockHash(),
+		UnlockConditions: uc,
+	})
+}
+
 // walletAddressHandler handles API calls to /wallet/addresses.
 func (api *API) walletAddressesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	addresses, err := api.wallet.AllAddresses()
@@ -223,6 +611,42 @@ func (api *API) walletAddressesHandler(w http.ResponseWriter, req *http.Request,
 	})
 }
 
+// walletConflictsHandler handles API calls to /wallet/conflicts.
+func (api *API) walletConflictsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	conflicts, err := api.wallet.Conflicts()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/conflicts: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletConflictsGET{
+		Conflicts: conflicts,
+	})
+}
+
+// walletReservedHandler handles API calls to /wallet/reserved.
+func (api *API) walletReservedHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	ids, err := api.wallet.ReservedOutputs()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/reserved: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletReservedGET{
+		OutputIDs: ids,
+	})
+}
+
+// walletUsedAddressesHandler handles API calls to /wallet/usedaddresses.
+func (api *API) walletUsedAddressesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	addresses, err := api.wallet.UsedAddresses()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/usedaddresses: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletUsedAddressesGET{
+		Addresses: addresses,
+	})
+}
+
 // walletBackupHandler handles API calls to /wallet/backup.
 func (api *API) walletBackupHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	destination := req.FormValue("destination")
@@ -239,6 +663,23 @@ func (api *API) walletBackupHandler(w http.ResponseWriter, req *http.Request, _
 	WriteSuccess(w)
 }
 
+// walletIncomingHandler handles API calls to /wallet/incoming/:addr.
+func (api *API) walletIncomingHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	var addr types.UnlockHash
+	if err := addr.LoadString(ps.ByName("addr")); err != nil {
+		WriteError(w, Error{"error when calling /wallet/incoming: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	outputs, err := api.wallet.IncomingOutputs(addr)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/incoming: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletIncomingGET{
+		Outputs: outputs,
+	})
+}
+
 // walletInitHandler handles API calls to /wallet/init.
 func (api *API) walletInitHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	var encryptionKey crypto.TwofishKey
@@ -333,22 +774,110 @@ func (api *API) walletSeedHandler(w http.ResponseWriter, req *http.Request, _ ht
 	WriteError(w, Error{"error when calling /wallet/seed: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
 }
 
+// walletSeedExportHandler handles API calls to /wallet/seed/export.
+func (api *API) walletSeedExportHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	passphrase := req.FormValue("passphrase")
+	if passphrase == "" {
+		WriteError(w, Error{"error when calling /wallet/seed/export: passphrase must be provided"}, http.StatusBadRequest)
+		return
+	}
+	export, err := api.wallet.ExportPrimarySeed(crypto.TwofishKey(crypto.HashObject(passphrase)))
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/seed/export: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletSeedExportPOST{
+		EncryptedSeed: hex.EncodeToString(export),
+	})
+}
+
+// decodeSiagKeyData decodes a comma-separated list of siag keys. encoding has
+// to be "hex" or "base64"; it is not guessed, since many base64 strings are
+// valid hex as well.
+func decodeSiagKeyData(keydata, encoding string) ([][]byte, error) {
+	var decode func(string) ([]byte, error)
+	switch encoding {
+	case "hex":
+		decode = hex.DecodeString
+	case "base64":
+		decode = base64.StdEncoding.DecodeString
+	default:
+		return nil, errors.New("keyencoding must be 'hex' or 'base64'")
+	}
+	var keys [][]byte
+	for i, str := range strings.Split(keydata, ",") {
+		key, err := decode(str)
+		if err != nil {
+			return nil, fmt.Errorf("keydata %v is not valid %v: %v", i, encoding, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// walletSplitHandler handles API calls to /wallet/split.
+func (api *API) walletSplitHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	count, err := strconv.ParseUint(req.FormValue("count"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"could not read 'count' from POST call to /wallet/split: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	value, ok := scanAmount(req.FormValue("value"))
+	if !ok {
+		WriteError(w, Error{"could not read 'value' from POST call to /wallet/split"}, http.StatusBadRequest)
+		return
+	}
+	txns, err := api.wallet.SplitOutputs(count, value)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/split: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	var txids []types.TransactionID
+	for _, txn := range txns {
+		txids = append(txids, txn.ID())
+	}
+	WriteJSON(w, WalletSplitPOST{
+		TransactionIDs: txids,
+	})
+}
+
 // walletSiagkeyHandler handles API calls to /wallet/siagkey.
 func (api *API) walletSiagkeyHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	// Fetch the list of keyfiles from the post body.
-	keyfiles := strings.Split(req.FormValue("keyfiles"), ",")
 	potentialKeys := encryptionKeys(req.FormValue("encryptionpassword"))
 
-	for _, keypath := range keyfiles {
-		// Check that all key paths are absolute paths.
-		if !filepath.IsAbs(keypath) {
-			WriteError(w, Error{"error when calling /wallet/siagkey: keyfiles contains a non-absolute path"}, http.StatusBadRequest)
+	// The keys are either loaded from a list of keyfiles or decoded from the
+	// post body.
+	var loadKeys func(crypto.TwofishKey) error
+	if req.FormValue("keydata") != "" {
+		if req.FormValue("keyfiles") != "" {
+			WriteError(w, Error{"error when calling /wallet/siagkey: keyfiles and keydata cannot both be specified"}, http.StatusBadRequest)
+			return
+		}
+		keydata, err := decodeSiagKeyData(req.FormValue("keydata"), req.FormValue("keyencoding"))
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/siagkey: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
+		loadKeys = func(key crypto.TwofishKey) error {
+			return api.wallet.LoadSiagKeyData(key, keydata)
+		}
+	} else {
+		// Fetch the list of keyfiles from the post body.
+		keyfiles := strings.Split(req.FormValue("keyfiles"), ",")
+		for _, keypath := range keyfiles {
+			// Check that all key paths are absolute paths.
+			if !filepath.IsAbs(keypath) {
+				WriteError(w, Error{"error when calling /wallet/siagkey: keyfiles contains a non-absolute path"}, http.StatusBadRequest)
+				return
+			}
+		}
+		loadKeys = func(key crypto.TwofishKey) error {
+			return api.wallet.LoadSiagKeys(key, keyfiles)
+		}
 	}
 
 	for _, key := range potentialKeys {
-		err := api.wallet.LoadSiagKeys(key, keyfiles)
+		err := loadKeys(key)
 		if err == nil {
 			WriteSuccess(w)
 			return
@@ -371,54 +900,603 @@ func (api *API) walletLockHandler(w http.ResponseWriter, req *http.Request, _ ht
 	WriteSuccess(w)
 }
 
-// walletSeedsHandler handles API calls to /wallet/seeds.
-func (api *API) walletSeedsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	dictionary := mnemonics.DictionaryID(req.FormValue("dictionary"))
-	if dictionary == "" {
-		dictionary = mnemonics.English
+// walletLockedOutputsHandler handles API calls to /wallet/lockedoutputs.
+func (api *API) walletLockedOutputsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	ids, err := api.wallet.LockedOutputs()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/lockedoutputs: " + err.Error()}, http.StatusBadRequest)
+		return
 	}
+	WriteJSON(w, WalletLockedOutputsGET{
+		OutputIDs: ids,
+	})
+}
 
-	// Get the primary seed information.
-	primarySeed, addrsRemaining, err := api.wallet.PrimarySeed()
+// walletLockOutputHandler handles API calls to /wallet/lockoutput.
+func (api *API) walletLockOutputHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var id types.SiacoinOutputID
+	if err := (*crypto.Hash)(&id).LoadString(req.FormValue("id")); err != nil {
+		WriteError(w, Error{"could not read id from POST call to /wallet/lockoutput: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.wallet.LockOutput(id); err != nil {
+		WriteError(w, Error{"error when calling /wallet/lockoutput: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletUnlockOutputHandler handles API calls to /wallet/unlockoutput.
+func (api *API) walletUnlockOutputHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var id types.SiacoinOutputID
+	if err := (*crypto.Hash)(&id).LoadString(req.FormValue("id")); err != nil {
+		WriteError(w, Error{"could not read id from POST call to /wallet/unlockoutput: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.wallet.UnlockOutput(id); err != nil {
+		WriteError(w, Error{"error when calling /wallet/unlockoutput: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletBalanceHandler handles API calls to /wallet/balance.
+func (api *API) walletBalanceHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	height, err := strconv.ParseUint(req.FormValue("height"), 10, 64)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{"error when calling /wallet/balance: unable to parse height: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
-	primarySeedStr, err := modules.SeedToString(primarySeed, dictionary)
+	siacoins, siafunds, err := api.wallet.ConfirmedBalanceAt(types.BlockHeight(height))
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{"error when calling /wallet/balance: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
+	WriteJSON(w, WalletBalanceGET{
+		Height:                  types.BlockHeight(height),
+		ConfirmedSiacoinBalance: siacoins,
+		SiafundBalance:          siafunds,
+	})
+}
 
-	// Get the list of seeds known to the wallet.
-	allSeeds, err := api.wallet.AllSeeds()
+// walletBalanceDeltaHandler handles API calls to /wallet/balance/delta.
+func (api *API) walletBalanceDeltaHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	sinceHeight, err := strconv.ParseUint(req.FormValue("sinceheight"), 10, 64)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{"error when calling /wallet/balance/delta: unable to parse sinceheight: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
-	var allSeedsStrs []string
-	for _, seed := range allSeeds {
-		str, err := modules.SeedToString(seed, dictionary)
-		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
-			return
-		}
-		allSeedsStrs = append(allSeedsStrs, str)
+	delta, err := api.wallet.ConfirmedBalanceDelta(types.BlockHeight(sinceHeight))
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/balance/delta: " + err.Error()}, http.StatusBadRequest)
+		return
 	}
-	WriteJSON(w, WalletSeedsGET{
-		PrimarySeed:        primarySeedStr,
-		AddressesRemaining: int(addrsRemaining),
-		AllSeeds:           allSeedsStrs,
+	WriteJSON(w, WalletBalanceDeltaGET{
+		SinceHeight:      types.BlockHeight(sinceHeight),
+		Height:           delta.Height,
+		SiacoinsIncoming: delta.SiacoinsIncoming,
+		SiacoinsOutgoing: delta.SiacoinsOutgoing,
+		SiafundsIncoming: delta.SiafundsIncoming,
+		SiafundsOutgoing: delta.SiafundsOutgoing,
 	})
 }
 
-// walletSiacoinsHandler handles API calls to /wallet/siacoins.
-func (api *API) walletSiacoinsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	var txns []types.Transaction
-	if req.FormValue("outputs") != "" {
-		// multiple amounts + destinations
-		if req.FormValue("amount") != "" || req.FormValue("destination") != "" {
-			WriteError(w, Error{"cannot supply both 'outputs' and single amount+destination pair"}, http.StatusInternalServerError)
+// walletBroadcastHandler handles API calls to /wallet/broadcast.
+func (api *API) walletBroadcastHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var txnSet []types.Transaction
+	err := json.NewDecoder(req.Body).Decode(&txnSet)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/broadcast: could not decode transaction set: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if len(txnSet) == 0 {
+		WriteError(w, Error{"error when calling /wallet/broadcast: transaction set is empty"}, http.StatusBadRequest)
+		return
+	}
+	// Check the transactions on their own first, so that the caller gets the
+	// underlying error instead of a generic rejection.
+	height := api.cs.Height()
+	txids := make([]types.TransactionID, 0, len(txnSet))
+	for _, txn := range txnSet {
+		if err := txn.StandaloneValid(height); err != nil {
+			WriteError(w, Error{fmt.Sprintf("error when calling /wallet/broadcast: transaction %v is invalid: %v", txn.ID(), err)}, http.StatusBadRequest)
+			return
+		}
+		txids = append(txids, txn.ID())
+	}
+	// The transaction pool relays the set to its peers once it is accepted.
+	err = api.tpool.AcceptTransactionSet(txnSet)
+	if err != nil && err != modules.ErrDuplicateTransactionSet {
+		WriteError(w, Error{"error when calling /wallet/broadcast: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletBroadcastPOST{
+		TransactionIDs: txids,
+	})
+}
+
+// walletDefragHandlerGET handles API calls to GET /wallet/defrag.
+func (api *API) walletDefragHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	settings, err := api.wallet.DefragSettings()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/defrag: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletDefragGET{
+		Enabled:          settings.Enabled,
+		Threshold:        settings.Threshold,
+		TargetOutputSize: settings.TargetOutputSize,
+		BatchSize:        settings.BatchSize,
+	})
+}
+
+// walletDefragHandlerPOST handles API calls to POST /wallet/defrag. Settings
+// that are not specified keep their current value.
+func (api *API) walletDefragHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	settings, err := api.wallet.DefragSettings()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/defrag: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if enabled := req.FormValue("enabled"); enabled != "" {
+		settings.Enabled, err = strconv.ParseBool(enabled)
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/defrag: could not read enabled: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if threshold := req.FormValue("threshold"); threshold != "" {
+		settings.Threshold, err = strconv.ParseUint(threshold, 10, 64)
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/defrag: could not read threshold: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if target := req.FormValue("targetoutputsize"); target != "" {
+		var ok bool
+		settings.TargetOutputSize, ok = scanAmount(target)
+		if !ok {
+			WriteError(w, Error{"error when calling /wallet/defrag: could not read targetoutputsize"}, http.StatusBadRequest)
+			return
+		}
+	}
+	if batchSize := req.FormValue("batchsize"); batchSize != "" {
+		settings.BatchSize, err = strconv.ParseUint(batchSize, 10, 64)
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/defrag: could not read batchsize: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if err := api.wallet.SetDefragSettings(settings); err != nil {
+		WriteError(w, Error{"error when calling /wallet/defrag: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletConfirmationDepthHandlerGET handles API calls to GET
+// /wallet/confirmationdepth.
+func (api *API) walletConfirmationDepthHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	depth, err := api.wallet.ConfirmationDepth()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/confirmationdepth: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletConfirmationDepthGET{
+		ConfirmationDepth: depth,
+	})
+}
+
+// walletConfirmationDepthHandlerPOST handles API calls to POST
+// /wallet/confirmationdepth.
+func (api *API) walletConfirmationDepthHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	depth, err := strconv.ParseUint(req.FormValue("confirmationdepth"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/confirmationdepth: could not read confirmationdepth: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.wallet.SetConfirmationDepth(types.BlockHeight(depth)); err != nil {
+		WriteError(w, Error{"error when calling /wallet/confirmationdepth: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletDustThresholdHandlerGET handles API calls to GET
+// /wallet/dustthreshold.
+func (api *API) walletDustThresholdHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	threshold, err := api.wallet.ChangeThreshold()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/dustthreshold: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletDustThresholdGET{
+		ChangeThreshold: threshold,
+	})
+}
+
+// walletDustThresholdHandlerPOST handles API calls to POST
+// /wallet/dustthreshold.
+func (api *API) walletDustThresholdHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	threshold, ok := scanAmount(req.FormValue("changethreshold"))
+	if !ok {
+		WriteError(w, Error{"error when calling /wallet/dustthreshold: could not read changethreshold"}, http.StatusBadRequest)
+		return
+	}
+	if err := api.wallet.SetChangeThreshold(threshold); err != nil {
+		WriteError(w, Error{"error when calling /wallet/dustthreshold: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletCanSendHandler handles API calls to /wallet/cansend.
+func (api *API) walletCanSendHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	amount, ok := scanAmount(req.FormValue("amount"))
+	if !ok {
+		WriteError(w, Error{"error when calling /wallet/cansend: could not read amount"}, http.StatusBadRequest)
+		return
+	}
+	numOutputs := uint64(1)
+	if req.FormValue("outputs") != "" {
+		var err error
+		numOutputs, err = strconv.ParseUint(req.FormValue("outputs"), 10, 64)
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/cansend: could not read outputs: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	canSend, shortfall, fee, err := api.wallet.CanSendSiacoins(amount, numOutputs)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/cansend: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletCanSendGET{
+		CanSend:   canSend,
+		Shortfall: shortfall,
+		Fee:       fee,
+	})
+}
+
+// walletCoinSelectHandler handles API calls to /wallet/coinselect.
+func (api *API) walletCoinSelectHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	amount, ok := scanAmount(req.FormValue("amount"))
+	if !ok {
+		WriteError(w, Error{"error when calling /wallet/coinselect: could not read amount"}, http.StatusBadRequest)
+		return
+	}
+	selection, err := api.wallet.SimulateCoinSelection(amount)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/coinselect: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletCoinSelectGET{
+		CoinSelection: selection,
+	})
+}
+
+// walletFeeEstimateHandler handles API calls to /wallet/fee/estimate.
+func (api *API) walletFeeEstimateHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	numOutputs, err := strconv.ParseUint(req.FormValue("outputs"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/fee/estimate: could not read outputs: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	value := types.ZeroCurrency
+	if req.FormValue("amount") != "" {
+		var ok bool
+		value, ok = scanAmount(req.FormValue("amount"))
+		if !ok {
+			WriteError(w, Error{"error when calling /wallet/fee/estimate: could not read amount"}, http.StatusBadRequest)
+			return
+		}
+	}
+	size, fee, err := api.wallet.EstimateSiacoinsMultiFee(numOutputs, value)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/fee/estimate: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletFeeEstimateGET{
+		Size: size,
+		Fee:  fee,
+	})
+}
+
+// walletFeeMultiplierHandlerGET handles API calls to GET
+// /wallet/feemultiplier.
+func (api *API) walletFeeMultiplierHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	multiplier, err := api.wallet.FeeMultiplier()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/feemultiplier: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletFeeMultiplierGET{
+		Multiplier: multiplier,
+	})
+}
+
+// walletFeeMultiplierHandlerPOST handles API calls to POST
+// /wallet/feemultiplier.
+func (api *API) walletFeeMultiplierHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	multiplier, err := strconv.ParseFloat(req.FormValue("multiplier"), 64)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/feemultiplier: could not read multiplier: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.wallet.SetFeeMultiplier(multiplier); err != nil {
+		WriteError(w, Error{"error when calling /wallet/feemultiplier: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletRescanHandlerGET handles API calls to GET /wallet/rescan.
+func (api *API) walletRescanHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	rescanning, err := api.wallet.Rescanning()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/rescan: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	scannedHeight, err := api.wallet.ScanHeight()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/rescan: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	consensusHeight := api.cs.Height()
+
+	// The progress is the percentage of the blockchain that has been scanned.
+	progress := 100.0
+	if rescanning && consensusHeight > 0 && scannedHeight < consensusHeight {
+		progress = 100 * float64(scannedHeight) / float64(consensusHeight)
+	}
+	WriteJSON(w, WalletRescanGET{
+		Rescanning:      rescanning,
+		ScannedHeight:   scannedHeight,
+		ConsensusHeight: consensusHeight,
+		Progress:        progress,
+	})
+}
+
+// walletRescanHandlerPOST handles API calls to POST /wallet/rescan.
+func (api *API) walletRescanHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if err := api.wallet.Rescan(); err != nil {
+		WriteError(w, Error{"error when calling /wallet/rescan: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletRescanThrottleHandlerGET handles API calls to GET
+// /wallet/rescan/throttle.
+func (api *API) walletRescanThrottleHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	throttle, err := api.wallet.RescanThrottle()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/rescan/throttle: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletRescanThrottleGET{
+		Throttle: uint64(throttle / time.Millisecond),
+	})
+}
+
+// walletRescanThrottleHandlerPOST handles API calls to POST
+// /wallet/rescan/throttle.
+func (api *API) walletRescanThrottleHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	ms, err := strconv.ParseUint(req.FormValue("throttle"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/rescan/throttle: could not read throttle: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.wallet.SetRescanThrottle(time.Duration(ms) * time.Millisecond); err != nil {
+		WriteError(w, Error{"error when calling /wallet/rescan/throttle: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletSignMessageHandler handles API calls to /wallet/sign/message.
+func (api *API) walletSignMessageHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var addr types.UnlockHash
+	if err := addr.LoadString(req.FormValue("address")); err != nil {
+		WriteError(w, Error{"error when calling /wallet/sign/message: could not read address: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var hash crypto.Hash
+	if err := hash.LoadString(req.FormValue("hash")); err != nil {
+		WriteError(w, Error{"error when calling /wallet/sign/message: could not read hash: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	pk, sig, err := api.wallet.SignMessage(addr, hash)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/sign/message: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletSignMessagePOST{
+		PublicKey: pk.String(),
+		Signature: hex.EncodeToString(sig[:]),
+	})
+}
+
+// walletVerifyMessageHandler handles API calls to /wallet/verify/message.
+func (api *API) walletVerifyMessageHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var addr types.UnlockHash
+	if err := addr.LoadString(req.FormValue("address")); err != nil {
+		WriteError(w, Error{"error when calling /wallet/verify/message: could not read address: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var pk types.SiaPublicKey
+	pk.LoadString(req.FormValue("publickey"))
+	if pk.Algorithm != types.SignatureEd25519 || len(pk.Key) != crypto.PublicKeySize {
+		WriteError(w, Error{"error when calling /wallet/verify/message: could not read ed25519 publickey"}, http.StatusBadRequest)
+		return
+	}
+	var hash crypto.Hash
+	if err := hash.LoadString(req.FormValue("hash")); err != nil {
+		WriteError(w, Error{"error when calling /wallet/verify/message: could not read hash: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var sig crypto.Signature
+	sigBytes, err := hex.DecodeString(req.FormValue("signature"))
+	if err != nil || len(sigBytes) != len(sig) {
+		WriteError(w, Error{"error when calling /wallet/verify/message: could not read signature"}, http.StatusBadRequest)
+		return
+	}
+	copy(sig[:], sigBytes)
+	err = modules.VerifyMessageSignature(addr, pk, hash, sig)
+	WriteJSON(w, WalletVerifyMessagePOST{Valid: err == nil})
+}
+
+// walletSeedsHandler handles API calls to /wallet/seeds.
+func (api *API) walletSeedsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	dictionary := mnemonics.DictionaryID(req.FormValue("dictionary"))
+	if dictionary == "" {
+		dictionary = mnemonics.English
+	}
+
+	// Get the primary seed information.
+	primarySeed, addrsRemaining, err := api.wallet.PrimarySeed()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	primarySeedStr, err := modules.SeedToString(primarySeed, dictionary)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	// Get the list of seeds known to the wallet.
+	allSeeds, err := api.wallet.AllSeeds()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var allSeedsStrs []string
+	for _, seed := range allSeeds {
+		str, err := modules.SeedToString(seed, dictionary)
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		allSeedsStrs = append(allSeedsStrs, str)
+	}
+	WriteJSON(w, WalletSeedsGET{
+		PrimarySeed:        primarySeedStr,
+		AddressesRemaining: int(addrsRemaining),
+		AllSeeds:           allSeedsStrs,
+	})
+}
+
+// walletSeedAddressesHandler handles API calls to /wallet/seed/addresses.
+func (api *API) walletSeedAddressesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	index, err := strconv.ParseUint(req.FormValue("index"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/seed/addresses: unable to parse index: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	allSeeds, err := api.wallet.AllSeeds()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/seed/addresses: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if index >= uint64(len(allSeeds)) {
+		WriteError(w, Error{fmt.Sprintf("error when calling /wallet/seed/addresses: index must be smaller than the number of seeds (%v)", len(allSeeds))}, http.StatusBadRequest)
+		return
+	}
+	addrs, err := api.wallet.SeedAddresses(allSeeds[index])
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/seed/addresses: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletSeedAddressesGET{
+		Addresses: addrs,
+	})
+}
+
+// walletSeedProgressHandler handles API calls to /wallet/seed/progress.
+func (api *API) walletSeedProgressHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	progress, highestFunded, funded, err := api.wallet.PrimarySeedProgress()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/seed/progress: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletSeedProgressGET{
+		AddressesGenerated: progress,
+		HighestFundedIndex: highestFunded,
+		FundsSeen:          funded,
+	})
+}
+
+// walletSiacoinsHandler handles API calls to /wallet/siacoins.
+func (api *API) walletSiacoinsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Sending the whole balance can only be combined with a destination.
+	if a := req.FormValue("all"); a != "" {
+		all, err := strconv.ParseBool(a)
+		if err != nil {
+			WriteError(w, Error{"could not read all from POST call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		if all {
+			api.walletSiacoinsAllHandler(w, req)
+			return
+		}
+	}
+
+	// Arbitrary data is optional and can only be attached to a transaction
+	// with a single amount and destination.
+	var arbData []byte
+	if arb := req.FormValue("arbitrarydata"); arb != "" {
+		if req.FormValue("outputs") != "" || req.FormValue("timelock") != "" {
+			WriteError(w, Error{"cannot supply 'arbitrarydata' together with 'outputs' or 'timelock'"}, http.StatusBadRequest)
+			return
+		}
+		var err error
+		arbData, err = base64.StdEncoding.DecodeString(arb)
+		if err != nil {
+			WriteError(w, Error{"could not decode arbitrarydata from POST call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// A preview is optional and can only be requested for a transaction with
+	// a single amount and destination.
+	var preview bool
+	if p := req.FormValue("preview"); p != "" {
+		if req.FormValue("outputs") != "" || req.FormValue("timelock") != "" || arbData != nil {
+			WriteError(w, Error{"cannot supply 'preview' together with 'outputs', 'timelock' or 'arbitrarydata'"}, http.StatusBadRequest)
+			return
+		}
+		var err error
+		preview, err = strconv.ParseBool(p)
+		if err != nil {
+			WriteError(w, Error{"could not read preview from POST call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Excluded outputs are optional and can only be supplied for a
+	// transaction with a single amount and destination.
+	var exclude []types.SiacoinOutputID
+	if eo := req.FormValue("excludeoutputs"); eo != "" {
+		if req.FormValue("outputs") != "" || req.FormValue("timelock") != "" || arbData != nil || preview {
+			WriteError(w, Error{"cannot supply 'excludeoutputs' together with 'outputs', 'timelock', 'arbitrarydata' or 'preview'"}, http.StatusBadRequest)
+			return
+		}
+		for _, str := range strings.Split(eo, ",") {
+			var id types.SiacoinOutputID
+			if err := (*crypto.Hash)(&id).LoadString(str); err != nil {
+				WriteError(w, Error{"could not read excludeoutputs from POST call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			exclude = append(exclude, id)
+		}
+	}
+
+	var txns []types.Transaction
+	if req.FormValue("outputs") != "" {
+		// multiple amounts + destinations
+		if req.FormValue("amount") != "" || req.FormValue("destination") != "" || req.FormValue("timelock") != "" {
+			WriteError(w, Error{"cannot supply both 'outputs' and single amount+destination pair"}, http.StatusInternalServerError)
 			return
 		}
 
@@ -433,6 +1511,47 @@ func (api *API) walletSiacoinsHandler(w http.ResponseWriter, req *http.Request,
 			WriteError(w, Error{"error when calling /wallet/siacoins: " + err.Error()}, http.StatusInternalServerError)
 			return
 		}
+	} else if req.FormValue("timelock") != "" {
+		// single amount + time-locked destination key
+		if req.FormValue("destination") != "" {
+			WriteError(w, Error{"cannot supply both 'destination' and 'timelock'; use 'destinationkey' instead"}, http.StatusBadRequest)
+			return
+		}
+		amount, ok := scanAmount(req.FormValue("amount"))
+		if !ok {
+			WriteError(w, Error{"could not read amount from POST call to /wallet/siacoins"}, http.StatusBadRequest)
+			return
+		}
+		timelock, err := strconv.ParseUint(req.FormValue("timelock"), 10, 64)
+		if err != nil {
+			WriteError(w, Error{"could not read timelock from POST call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		var pk types.SiaPublicKey
+		pk.LoadString(req.FormValue("destinationkey"))
+		if pk.Algorithm != types.SignatureEd25519 || len(pk.Key) != crypto.PublicKeySize {
+			WriteError(w, Error{"could not read ed25519 destinationkey from POST call to /wallet/siacoins"}, http.StatusBadRequest)
+			return
+		}
+		uc := types.UnlockConditions{
+			Timelock:           types.BlockHeight(timelock),
+			PublicKeys:         []types.SiaPublicKey{pk},
+			SignaturesRequired: 1,
+		}
+		txns, err = api.wallet.SendSiacoinsTimelocked(amount, uc)
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/siacoins: " + err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		var txids []types.TransactionID
+		for _, txn := range txns {
+			txids = append(txids, txn.ID())
+		}
+		WriteJSON(w, WalletSiacoinsPOST{
+			TransactionIDs:   txids,
+			UnlockConditions: &uc,
+		})
+		return
 	} else {
 		// single amount + destination
 		amount, ok := scanAmount(req.FormValue("amount"))
@@ -446,7 +1565,15 @@ func (api *API) walletSiacoinsHandler(w http.ResponseWriter, req *http.Request,
 			return
 		}
 
-		txns, err = api.wallet.SendSiacoins(amount, dest)
+		if preview {
+			txns, err = api.wallet.PreviewSendSiacoins(amount, dest)
+		} else if arbData != nil {
+			txns, err = api.wallet.SendSiacoinsArbitraryData(amount, dest, arbData)
+		} else if exclude != nil {
+			txns, err = api.wallet.SendSiacoinsExcluding(amount, dest, exclude)
+		} else {
+			txns, err = api.wallet.SendSiacoins(amount, dest)
+		}
 		if err != nil {
 			WriteError(w, Error{"error when calling /wallet/siacoins: " + err.Error()}, http.StatusInternalServerError)
 			return
@@ -454,29 +1581,118 @@ func (api *API) walletSiacoinsHandler(w http.ResponseWriter, req *http.Request,
 
 	}
 
+	var txids []types.TransactionID
+	for _, txn := range txns {
+		txids = append(txids, txn.ID())
+	}
+	resp := WalletSiacoinsPOST{
+		TransactionIDs: txids,
+	}
+	if preview {
+		resp.Transactions = txns
+	}
+	WriteJSON(w, resp)
+}
+
+// walletSiacoinsAllHandler handles API calls to /wallet/siacoins that send the
+// whole spendable balance of the wallet.
+func (api *API) walletSiacoinsAllHandler(w http.ResponseWriter, req *http.Request) {
+	for _, param := range []string{"amount", "outputs", "timelock", "arbitrarydata", "preview", "excludeoutputs"} {
+		if req.FormValue(param) != "" {
+			WriteError(w, Error{"cannot supply '" + param + "' together with 'all'"}, http.StatusBadRequest)
+			return
+		}
+	}
+	dest, err := scanAddress(req.FormValue("destination"))
+	if err != nil {
+		WriteError(w, Error{"could not read address from POST call to /wallet/siacoins"}, http.StatusBadRequest)
+		return
+	}
+	amount, txns, err := api.wallet.SendAllSiacoins(dest)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/siacoins: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
 	var txids []types.TransactionID
 	for _, txn := range txns {
 		txids = append(txids, txn.ID())
 	}
 	WriteJSON(w, WalletSiacoinsPOST{
 		TransactionIDs: txids,
+		Amount:         &amount,
 	})
 }
 
-// walletSiafundsHandler handles API calls to /wallet/siafunds.
-func (api *API) walletSiafundsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	amount, ok := scanAmount(req.FormValue("amount"))
+// walletTopUpHandler handles API calls to /wallet/topup.
+func (api *API) walletTopUpHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	target, ok := scanAmount(req.FormValue("target"))
 	if !ok {
-		WriteError(w, Error{"could not read 'amount' from POST call to /wallet/siafunds"}, http.StatusBadRequest)
+		WriteError(w, Error{"could not read target from POST call to /wallet/topup"}, http.StatusBadRequest)
 		return
 	}
 	dest, err := scanAddress(req.FormValue("destination"))
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/siafunds: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{"could not read address from POST call to /wallet/topup"}, http.StatusBadRequest)
+		return
+	}
+	amount, txns, err := api.wallet.TopUpAddress(dest, target)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/topup: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
+	var txids []types.TransactionID
+	for _, txn := range txns {
+		txids = append(txids, txn.ID())
+	}
+	WriteJSON(w, WalletTopUpPOST{
+		Amount:         amount,
+		TransactionIDs: txids,
+	})
+}
 
-	txns, err := api.wallet.SendSiafunds(amount, dest)
+// walletSiafundsHandler handles API calls to /wallet/siafunds. Multiple
+// destinations can be supplied as comma-separated lists of amounts and
+// destinations.
+func (api *API) walletSiafundsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	amounts := strings.Split(req.FormValue("amount"), ",")
+	dests := strings.Split(req.FormValue("destination"), ",")
+	if len(amounts) != len(dests) {
+		WriteError(w, Error{"number of amounts does not match number of destinations in POST call to /wallet/siafunds"}, http.StatusBadRequest)
+		return
+	}
+
+	var outputs []types.SiafundOutput
+	var total types.Currency
+	for i := range amounts {
+		amount, ok := scanAmount(amounts[i])
+		if !ok {
+			WriteError(w, Error{"could not read 'amount' from POST call to /wallet/siafunds"}, http.StatusBadRequest)
+			return
+		}
+		dest, err := scanAddress(dests[i])
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/siafunds: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		outputs = append(outputs, types.SiafundOutput{
+			Value:      amount,
+			UnlockHash: dest,
+		})
+		total = total.Add(amount)
+	}
+
+	// Check that the wallet holds enough siafunds.
+	_, siafundBalance, _, err := api.wallet.ConfirmedBalance()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/siafunds: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	if total.Cmp(siafundBalance) > 0 {
+		WriteError(w, Error{"error when calling /wallet/siafunds: total amount exceeds the siafund balance"}, http.StatusBadRequest)
+		return
+	}
+
+	txns, err := api.wallet.SendSiafundsMulti(outputs)
 	if err != nil {
 		WriteError(w, Error{"error when calling /wallet/siafunds: " + err.Error()}, http.StatusInternalServerError)
 		return
@@ -530,6 +1746,21 @@ func (api *API) walletTransactionHandler(w http.ResponseWriter, req *http.Reques
 		WriteError(w, Error{"error when calling /wallet/transaction/id:" + err.Error()}, http.StatusBadRequest)
 		return
 	}
+	if !ok {
+		// The transaction might not be confirmed yet, e.g. if it was just
+		// created by /wallet/siacoins or /wallet/siafunds.
+		unconfirmed, err := api.wallet.UnconfirmedTransactions()
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/transaction/id:" + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		for _, pt := range unconfirmed {
+			if pt.TransactionID == id {
+				txn, ok = pt, true
+				break
+			}
+		}
+	}
 	if !ok {
 		WriteError(w, Error{"error when calling /wallet/transaction/:id  :  transaction not found"}, http.StatusBadRequest)
 		return
@@ -539,18 +1770,88 @@ func (api *API) walletTransactionHandler(w http.ResponseWriter, req *http.Reques
 	})
 }
 
-// walletTransactionsHandler handles API calls to /wallet/transactions.
-func (api *API) walletTransactionsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+// walletTransactionBumpFeeHandler handles API calls to
+// /wallet/transaction/:id/bumpfee.
+func (api *API) walletTransactionBumpFeeHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	// Parse the id from the url.
+	var id types.TransactionID
+	jsonID := "\"" + ps.ByName("id") + "\""
+	err := id.UnmarshalJSON([]byte(jsonID))
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/transaction/:id/bumpfee: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	// The fee is optional, the wallet estimates it if it's not provided.
+	var fee types.Currency
+	if f := req.FormValue("fee"); f != "" {
+		var ok bool
+		fee, ok = scanAmount(f)
+		if !ok {
+			WriteError(w, Error{"error when calling /wallet/transaction/:id/bumpfee: could not read fee"}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	txns, err := api.wallet.BumpTransactionFee(id, fee)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/transaction/:id/bumpfee: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var txids []types.TransactionID
+	for _, txn := range txns {
+		txids = append(txids, txn.ID())
+	}
+	WriteJSON(w, WalletTransactionBumpFeePOST{
+		TransactionIDs: txids,
+	})
+}
+
+// walletTransactionGraphHandler handles API calls to
+// /wallet/transaction/:id/graph.
+func (api *API) walletTransactionGraphHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	// Parse the id from the url.
+	var id types.TransactionID
+	jsonID := "\"" + ps.ByName("id") + "\""
+	err := id.UnmarshalJSON([]byte(jsonID))
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/transaction/:id/graph: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	// The depth is optional, the wallet uses its default if it's not
+	// provided.
+	var depth int
+	if d := req.FormValue("depth"); d != "" {
+		depth, err = strconv.Atoi(d)
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/transaction/:id/graph: could not read depth"}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	graph, err := api.wallet.TransactionGraph(id, depth)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/transaction/:id/graph: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletTransactionGraphGET{
+		Graph: graph,
+	})
+}
+
+// parseTransactionsHeights parses the startheight and endheight parameters of
+// the /wallet/transactions calls. An endheight of -1 means that there is no
+// upper bound.
+func parseTransactionsHeights(req *http.Request) (types.BlockHeight, types.BlockHeight, error) {
 	startheightStr, endheightStr := req.FormValue("startheight"), req.FormValue("endheight")
 	if startheightStr == "" || endheightStr == "" {
-		WriteError(w, Error{"startheight and endheight must be provided to a /wallet/transactions call."}, http.StatusBadRequest)
-		return
+		return 0, 0, errors.New("startheight and endheight must be provided to a /wallet/transactions call.")
 	}
 	// Get the start and end blocks.
 	start, err := strconv.ParseUint(startheightStr, 10, 64)
 	if err != nil {
-		WriteError(w, Error{"parsing integer value for parameter `startheight` failed: " + err.Error()}, http.StatusBadRequest)
-		return
+		return 0, 0, errors.New("parsing integer value for parameter `startheight` failed: " + err.Error())
 	}
 	// Check if endheightStr is set to -1. If it is, we use MaxUint64 as the
 	// end. Otherwise we parse the argument as an unsigned integer.
@@ -561,10 +1862,19 @@ func (api *API) walletTransactionsHandler(w http.ResponseWriter, req *http.Reque
 		end, err = strconv.ParseUint(endheightStr, 10, 64)
 	}
 	if err != nil {
-		WriteError(w, Error{"parsing integer value for parameter `endheight` failed: " + err.Error()}, http.StatusBadRequest)
+		return 0, 0, errors.New("parsing integer value for parameter `endheight` failed: " + err.Error())
+	}
+	return types.BlockHeight(start), types.BlockHeight(end), nil
+}
+
+// walletTransactionsHandler handles API calls to /wallet/transactions.
+func (api *API) walletTransactionsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	start, end, err := parseTransactionsHeights(req)
+	if err != nil {
+		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
 		return
 	}
-	confirmedTxns, err := api.wallet.Transactions(types.BlockHeight(start), types.BlockHeight(end))
+	confirmedTxns, err := api.wallet.Transactions(start, end)
 	if err != nil {
 		WriteError(w, Error{"error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
 		return
@@ -581,9 +1891,74 @@ func (api *API) walletTransactionsHandler(w http.ResponseWriter, req *http.Reque
 	})
 }
 
+// walletExportHandler handles API calls to /wallet/export. The confirmed
+// transactions are streamed as newline-delimited JSON.
+func (api *API) walletExportHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var start, end uint64 = 0, math.MaxUint64
+	var err error
+	if startheightStr := req.FormValue("startheight"); startheightStr != "" {
+		start, err = strconv.ParseUint(startheightStr, 10, 64)
+		if err != nil {
+			WriteError(w, Error{"parsing integer value for parameter `startheight` failed: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if endheightStr := req.FormValue("endheight"); endheightStr != "" && endheightStr != "-1" {
+		end, err = strconv.ParseUint(endheightStr, 10, 64)
+		if err != nil {
+			WriteError(w, Error{"parsing integer value for parameter `endheight` failed: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Check the bounds before the response is started, so that invalid
+	// heights are reported with an error response.
+	height, err := api.wallet.ScanHeight()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/export: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if start > uint64(height) || start > end {
+		WriteError(w, Error{"error when calling /wallet/export: startheight must not be above endheight or the current height"}, http.StatusBadRequest)
+		return
+	}
+
+	// Once the first transaction has been written, the status of the
+	// response can't be changed anymore. Later errors are logged and end the
+	// stream.
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	cw := &countingWriter{w: w}
+	err = api.wallet.ExportTransactions(cw, types.BlockHeight(start), types.BlockHeight(end))
+	if err != nil && cw.n == 0 {
+		WriteError(w, Error{"error when calling /wallet/export: " + err.Error()}, http.StatusBadRequest)
+	} else if err != nil {
+		log.Printf("ERROR: /wallet/export was aborted after %v bytes: %v", cw.n, err)
+	}
+}
+
+// countingWriter wraps an io.Writer and counts the bytes written to it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer.
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
 // walletTransactionsAddrHandler handles API calls to
 // /wallet/transactions/:addr.
 func (api *API) walletTransactionsAddrHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	// /wallet/transactions/csv shares its path with this call, since the
+	// router doesn't allow a static path next to a parameter.
+	if ps.ByName("addr") == "csv" {
+		api.walletTransactionsCSVHandler(w, req, ps)
+		return
+	}
+
 	// Parse the address being input.
 	jsonAddr := "\"" + ps.ByName("addr") + "\""
 	var addr types.UnlockHash
@@ -609,6 +1984,56 @@ func (api *API) walletTransactionsAddrHandler(w http.ResponseWriter, req *http.R
 	})
 }
 
+// walletTransactionsCSVHandler handles API calls to /wallet/transactions/csv.
+// The confirmed transactions are written as CSV with one row per transaction.
+// Amounts are given in hastings.
+func (api *API) walletTransactionsCSVHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	start, end, err := parseTransactionsHeights(req)
+	if err != nil {
+		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	txns, err := api.wallet.Transactions(start, end)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/transactions/csv: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"height", "timestamp", "transactionid", "netsiacoins", "fee"})
+	for _, txn := range txns {
+		// Determine the siacoins leaving and entering the wallet.
+		var outgoing, incoming types.Currency
+		for _, input := range txn.Inputs {
+			if input.FundType == types.SpecifierSiacoinInput && input.WalletAddress {
+				outgoing = outgoing.Add(input.Value)
+			}
+		}
+		for _, output := range txn.Outputs {
+			if !output.WalletAddress {
+				continue
+			}
+			switch output.FundType {
+			case types.SpecifierSiacoinOutput, types.SpecifierMinerPayout, types.SpecifierClaimOutput:
+				incoming = incoming.Add(output.Value)
+			}
+		}
+		var fee types.Currency
+		for _, f := range txn.Transaction.MinerFees {
+			fee = fee.Add(f)
+		}
+		cw.Write([]string{
+			strconv.FormatUint(uint64(txn.ConfirmationHeight), 10),
+			strconv.FormatUint(uint64(txn.ConfirmationTimestamp), 10),
+			txn.TransactionID.String(),
+			new(big.Int).Sub(incoming.Big(), outgoing.Big()).String(),
+			fee.String(),
+		})
+	}
+	cw.Flush()
+}
+
 // walletUnlockHandler handles API calls to /wallet/unlock.
 func (api *API) walletUnlockHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	potentialKeys := encryptionKeys(req.FormValue("encryptionpassword"))
@@ -651,6 +2076,79 @@ func (api *API) walletChangePasswordHandler(w http.ResponseWriter, req *http.Req
 	WriteError(w, Error{"error when calling /wallet/changepassword: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
 }
 
+// walletUnlockConditionsHandler handles API calls to
+// /wallet/unlockconditions/:addr.
+func (api *API) walletUnlockConditionsHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	var addr types.UnlockHash
+	if err := addr.LoadString(ps.ByName("addr")); err != nil {
+		WriteError(w, Error{"error when calling /wallet/unlockconditions: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	uc, err := api.wallet.UnlockConditions(addr)
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/unlockconditions: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletUnlockConditionsGET{
+		UnlockConditions: uc,
+	})
+}
+
+// walletTransactionsStatusHandler handles API calls to
+// /wallet/transactions/status.
+func (api *API) walletTransactionsStatusHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var ids []types.TransactionID
+	for _, idStr := range strings.Split(req.FormValue("ids"), ",") {
+		if idStr == "" {
+			continue
+		}
+		var id types.TransactionID
+		if err := id.UnmarshalJSON([]byte("\"" + idStr + "\"")); err != nil {
+			WriteError(w, Error{"error when calling /wallet/transactions/status: unable to parse id " + idStr + ": " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		WriteError(w, Error{"error when calling /wallet/transactions/status: no transaction ids supplied"}, http.StatusBadRequest)
+		return
+	}
+
+	// The unconfirmed transactions are only fetched once for all ids.
+	unconfirmed, err := api.wallet.UnconfirmedTransactions()
+	if err != nil {
+		WriteError(w, Error{"error when calling /wallet/transactions/status: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	unconfirmedIDs := make(map[types.TransactionID]struct{}, len(unconfirmed))
+	for _, pt := range unconfirmed {
+		unconfirmedIDs[pt.TransactionID] = struct{}{}
+	}
+
+	statuses := make([]WalletTransactionStatus, 0, len(ids))
+	for _, id := range ids {
+		status := WalletTransactionStatus{
+			TransactionID: id,
+			Status:        "unknown",
+		}
+		pt, confirmed, err := api.wallet.Transaction(id)
+		if err != nil {
+			WriteError(w, Error{"error when calling /wallet/transactions/status: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		if confirmed {
+			status.Status = "confirmed"
+			status.ConfirmationHeight = pt.ConfirmationHeight
+		} else if _, ok := unconfirmedIDs[id]; ok {
+			status.Status = "unconfirmed"
+		}
+		statuses = append(statuses, status)
+	}
+	WriteJSON(w, WalletTransactionsStatusPOST{
+		Statuses: statuses,
+	})
+}
+
 // walletVerifyAddressHandler handles API calls to /wallet/verify/address/:addr.
 func (api *API) walletVerifyAddressHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	addrString := ps.ByName("addr")
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -85,6 +86,9 @@ func TestWalletGETEncrypted(t *testing.T) {
 	if wg.Unlocked {
 		t.Error("Wallet has never been unlocked")
 	}
+	if wg.UnlockStatus != modules.WalletUnlockStatusUnencrypted {
+		t.Errorf("expected unlock status %q, got %q", modules.WalletUnlockStatusUnencrypted, wg.UnlockStatus)
+	}
 }
 
 // TestWalletEncrypt tries to encrypt and unlock the wallet through the api
@@ -343,6 +347,9 @@ func TestWalletGETSiacoins(t *testing.T) {
 	if !wg.Unlocked {
 		t.Error("Wallet has been unlocked")
 	}
+	if wg.UnlockStatus != modules.WalletUnlockStatusUnlocked {
+		t.Errorf("expected unlock status %q, got %q", modules.WalletUnlockStatusUnlocked, wg.UnlockStatus)
+	}
 	if wg.ConfirmedSiacoinBalance.Cmp(types.CalculateCoinbase(1)) != 0 {
 		t.Error("reported wallet balance does not reflect the single block that has been mined")
 	}
@@ -1006,6 +1013,46 @@ func TestWalletRelativePathErrorSiag(t *testing.T) {
 	}
 }
 
+// TestDecodeSiagKeyData checks that the keydata of /wallet/siagkey is decoded
+// with the requested encoding.
+func TestDecodeSiagKeyData(t *testing.T) {
+	keys, err := decodeSiagKeyData("0102ff,0a0b", "hex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || !bytes.Equal(keys[0], []byte{1, 2, 255}) || !bytes.Equal(keys[1], []byte{10, 11}) {
+		t.Fatal("hex keydata was decoded incorrectly:", keys)
+	}
+	keys, err = decodeSiagKeyData("AQL/", "base64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || !bytes.Equal(keys[0], []byte{1, 2, 255}) {
+		t.Fatal("base64 keydata was decoded incorrectly:", keys)
+	}
+
+	// Base64 that only consists of hex characters must not be decoded as
+	// hex.
+	keys, err = decodeSiagKeyData("abcd1234", "base64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || !bytes.Equal(keys[0], []byte{0x69, 0xb7, 0x1d, 0xd7, 0x6d, 0xf8}) {
+		t.Fatal("ambiguous keydata was not decoded as base64:", keys)
+	}
+
+	// Malformed keydata and unknown encodings are rejected.
+	if _, err := decodeSiagKeyData("0102ff,not*valid", "base64"); err == nil {
+		t.Fatal("expected malformed keydata to be rejected")
+	}
+	if _, err := decodeSiagKeyData("AQL/", "hex"); err == nil {
+		t.Fatal("expected base64 keydata to be rejected as hex")
+	}
+	if _, err := decodeSiagKeyData("0102ff", ""); err == nil {
+		t.Fatal("expected a missing keyencoding to be rejected")
+	}
+}
+
 func TestWalletReset(t *testing.T) {
 	if testing.Short() {
 		t.SkipNow()
@@ -1209,6 +1209,77 @@ func TestRenterCancelAllowance(t *testing.T) {
 	}
 }
 
+// TestRenterCancelContract tests canceling a single contract of the renter.
+func TestRenterCancelContract(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	// Create a group for testing.
+	groupParams := siatest.GroupParams{
+		Hosts:   2,
+		Renters: 1,
+		Miners:  1,
+	}
+	tg, err := siatest.NewGroupFromTemplate(renterTestDir(t.Name()), groupParams)
+	if err != nil {
+		t.Fatal("Failed to create group: ", err)
+	}
+	defer func() {
+		if err := tg.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Grab a contract to cancel.
+	renter := tg.Renters()[0]
+	rc, err := renter.RenterContractsGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rc.ActiveContracts) != groupParams.Hosts {
+		t.Fatalf("expected %v active contracts, got %v", groupParams.Hosts, len(rc.ActiveContracts))
+	}
+	canceled := rc.ActiveContracts[0].ID
+
+	// Cancel the contract.
+	if err := renter.RenterContractCancelPost(canceled); err != nil {
+		t.Fatal(err)
+	}
+
+	// Mine a block to trigger contract maintenance. The canceled contract
+	// should stay inactive while the other one remains active.
+	if err := tg.Miners()[0].MineBlock(); err != nil {
+		t.Fatal(err)
+	}
+	err = build.Retry(200, 100*time.Millisecond, func() error {
+		rc, err := renter.RenterInactiveContractsGet()
+		if err != nil {
+			return err
+		}
+		if len(rc.InactiveContracts) != 1 {
+			return fmt.Errorf("expected 1 inactive contract, got %v", len(rc.InactiveContracts))
+		}
+		c := rc.InactiveContracts[0]
+		if c.ID != canceled {
+			return errors.New("wrong contract was marked inactive")
+		}
+		if c.GoodForUpload || c.GoodForRenew {
+			return errors.New("canceled contract should be !goodForUpload and !goodForRenew")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Canceling a contract that doesn't exist should fail.
+	if err := renter.RenterContractCancelPost(types.FileContractID{}); err == nil {
+		t.Fatal("expected error when canceling unknown contract")
+	}
+}
+
 // TestRenterContractEndHeight makes sure that the endheight of renewed
 // contracts is set properly
 func TestRenterContractEndHeight(t *testing.T) {
@@ -1,8 +1,13 @@
 package wallet
 
 import (
+	"bytes"
+	"encoding/csv"
 	"errors"
+	"fmt"
+	"math"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -108,3 +113,324 @@ func TestTransactionReorg(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestSiacoinsTransactionIDs checks that the transaction IDs returned by
+// /wallet/siacoins can immediately be queried using /wallet/transaction/:id.
+func TestSiacoinsTransactionIDs(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	// Create testing directory.
+	testdir := walletTestDir(t.Name())
+
+	// Create a miner.
+	miner, err := siatest.NewNode(siatest.Miner(filepath.Join(testdir, "miner")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := miner.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Send a txn to the miner itself.
+	uc, err := miner.WalletAddressGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wsp, err := miner.WalletSiacoinsPost(types.SiacoinPrecision, uc.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wsp.TransactionIDs) == 0 {
+		t.Fatal("no transaction ids were returned")
+	}
+
+	// The transaction should be found before it is confirmed.
+	txid := wsp.TransactionIDs[len(wsp.TransactionIDs)-1]
+	wtg, err := miner.WalletTransactionGet(txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wtg.Transaction.TransactionID != txid {
+		t.Fatal("wrong transaction returned")
+	} else if wtg.Transaction.ConfirmationHeight != types.BlockHeight(math.MaxUint64) {
+		t.Fatal("transaction shouldn't be confirmed yet")
+	}
+
+	// After mining a block the transaction should be confirmed.
+	if err := miner.MineBlock(); err != nil {
+		t.Fatal(err)
+	}
+	err = build.Retry(100, 100*time.Millisecond, func() error {
+		wtg, err := miner.WalletTransactionGet(txid)
+		if err != nil {
+			return err
+		}
+		if wtg.Transaction.ConfirmationHeight == types.BlockHeight(math.MaxUint64) {
+			return errors.New("txn isn't confirmed yet")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestWalletTransactionsStatus checks that /wallet/transactions/status reports
+// the confirmation status of multiple transactions at once.
+func TestWalletTransactionsStatus(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	// Create testing directory.
+	testdir := walletTestDir(t.Name())
+
+	// Create a miner.
+	miner, err := siatest.NewNode(siatest.Miner(filepath.Join(testdir, "miner")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := miner.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Send two transactions and confirm the first one.
+	uc, err := miner.WalletAddressGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wsp1, err := miner.WalletSiacoinsPost(types.SiacoinPrecision, uc.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := miner.MineBlock(); err != nil {
+		t.Fatal(err)
+	}
+	wsp2, err := miner.WalletSiacoinsPost(types.SiacoinPrecision, uc.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirmed := wsp1.TransactionIDs[len(wsp1.TransactionIDs)-1]
+	unconfirmed := wsp2.TransactionIDs[len(wsp2.TransactionIDs)-1]
+	unknown := types.TransactionID{1, 2, 3}
+
+	err = build.Retry(100, 100*time.Millisecond, func() error {
+		wtsp, err := miner.WalletTransactionsStatusPost([]types.TransactionID{confirmed, unconfirmed, unknown})
+		if err != nil {
+			return err
+		}
+		if len(wtsp.Statuses) != 3 {
+			return fmt.Errorf("expected 3 statuses, got %v", len(wtsp.Statuses))
+		}
+		if s := wtsp.Statuses[0]; s.TransactionID != confirmed || s.Status != "confirmed" || s.ConfirmationHeight == 0 {
+			return fmt.Errorf("wrong status for confirmed transaction: %+v", s)
+		}
+		if s := wtsp.Statuses[1]; s.TransactionID != unconfirmed || s.Status != "unconfirmed" {
+			return fmt.Errorf("wrong status for unconfirmed transaction: %+v", s)
+		}
+		if s := wtsp.Statuses[2]; s.TransactionID != unknown || s.Status != "unknown" {
+			return fmt.Errorf("wrong status for unknown transaction: %+v", s)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A request without ids is rejected.
+	if _, err := miner.WalletTransactionsStatusPost(nil); err == nil {
+		t.Fatal("expected request without ids to fail")
+	}
+}
+
+// TestWalletTransactionsCSV checks that /wallet/transactions/csv contains the
+// same transactions as /wallet/transactions.
+func TestWalletTransactionsCSV(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	// Create testing directory.
+	testdir := walletTestDir(t.Name())
+
+	// Create a miner.
+	miner, err := siatest.NewNode(siatest.Miner(filepath.Join(testdir, "miner")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := miner.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Send coins to a foreign address and confirm the transaction.
+	amount := types.SiacoinPrecision.Mul64(100)
+	wsp, err := miner.WalletSiacoinsPost(amount, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := miner.MineBlock(); err != nil {
+		t.Fatal(err)
+	}
+	sent := wsp.TransactionIDs[len(wsp.TransactionIDs)-1]
+
+	wtg, err := miner.WalletTransactionsGet(0, math.MaxInt64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := miner.WalletTransactionsCSVGet(0, math.MaxInt64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(wtg.ConfirmedTransactions)+1 {
+		t.Fatalf("expected %v rows, got %v", len(wtg.ConfirmedTransactions)+1, len(records))
+	}
+	if strings.Join(records[0], ",") != "height,timestamp,transactionid,netsiacoins,fee" {
+		t.Fatal("wrong header:", records[0])
+	}
+	var found bool
+	for i, txn := range wtg.ConfirmedTransactions {
+		record := records[i+1]
+		if record[0] != fmt.Sprint(txn.ConfirmationHeight) || record[2] != txn.TransactionID.String() {
+			t.Fatalf("row %v doesn't match transaction %v", record, txn.TransactionID)
+		}
+		// The transaction that sent the coins should show the amount plus
+		// the fee leaving the wallet.
+		if txn.TransactionID == sent {
+			found = true
+			var fee types.Currency
+			for _, f := range txn.Transaction.MinerFees {
+				fee = fee.Add(f)
+			}
+			if record[3] != "-"+amount.Add(fee).String() || record[4] != fee.String() {
+				t.Fatalf("wrong amounts for sent transaction: %v", record)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("sent transaction is missing")
+	}
+}
+
+// TestWalletBroadcast checks that /wallet/broadcast accepts signed
+// transaction sets and rejects invalid transactions with the underlying error.
+func TestWalletBroadcast(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	// Create testing directory.
+	testdir := walletTestDir(t.Name())
+
+	// Create a miner.
+	miner, err := siatest.NewNode(siatest.Miner(filepath.Join(testdir, "miner")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := miner.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Send a txn to the miner itself and fetch the signed transactions.
+	uc, err := miner.WalletAddressGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wsp, err := miner.WalletSiacoinsPost(types.SiacoinPrecision, uc.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var txnSet []types.Transaction
+	for _, txid := range wsp.TransactionIDs {
+		wtg, err := miner.WalletTransactionGet(txid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txnSet = append(txnSet, wtg.Transaction.Transaction)
+	}
+
+	// Broadcasting the set again should return its IDs.
+	wbp, err := miner.WalletBroadcastPost(txnSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wbp.TransactionIDs) != len(wsp.TransactionIDs) {
+		t.Fatalf("expected %v transaction ids, got %v", len(wsp.TransactionIDs), len(wbp.TransactionIDs))
+	}
+	for i := range wbp.TransactionIDs {
+		if wbp.TransactionIDs[i] != wsp.TransactionIDs[i] {
+			t.Fatal("wrong transaction id returned")
+		}
+	}
+
+	// A transaction with a zero value output should be rejected.
+	invalid := types.Transaction{
+		SiacoinOutputs: []types.SiacoinOutput{{Value: types.ZeroCurrency}},
+	}
+	_, err = miner.WalletBroadcastPost([]types.Transaction{invalid})
+	if err == nil || !strings.Contains(err.Error(), types.ErrZeroOutput.Error()) {
+		t.Fatal("expected ErrZeroOutput, got", err)
+	}
+}
+
+// TestWalletRescanGet checks that /wallet/rescan reports a completed scan
+// once the wallet has caught up with the blockchain.
+func TestWalletRescanGet(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	// Create testing directory.
+	testdir := walletTestDir(t.Name())
+
+	// Create a miner.
+	miner, err := siatest.NewNode(siatest.Miner(filepath.Join(testdir, "miner")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := miner.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Mine a block to make sure the wallet has processed it.
+	if err := miner.MineBlock(); err != nil {
+		t.Fatal(err)
+	}
+	wrg, err := miner.WalletRescanGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wrg.Rescanning {
+		t.Fatal("wallet shouldn't be rescanning")
+	}
+	if wrg.Progress != 100 {
+		t.Fatalf("expected progress of 100, got %v", wrg.Progress)
+	}
+	if wrg.ScannedHeight != wrg.ConsensusHeight {
+		t.Fatalf("scanned height %v doesn't match consensus height %v", wrg.ScannedHeight, wrg.ConsensusHeight)
+	}
+
+	// The main /wallet endpoint should agree.
+	wg, err := miner.WalletGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wg.Rescanning != wrg.Rescanning {
+		t.Fatal("/wallet and /wallet/rescan disagree about the rescan")
+	}
+}